@@ -1,14 +1,60 @@
 package api
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
-// Errorf formats a radish error with the specified code, returning it.
+// Errorf formats a radish error with the specified code, returning it. If the format
+// wraps an error with %w, the returned error unwraps to it.
 func Errorf(code int32, format string, a ...interface{}) error {
 	msg := fmt.Errorf(format, a...)
-	return &Error{Code: code, Message: msg.Error()}
+	err := &Error{Code: code, Message: msg.Error()}
+	if cause := errors.Unwrap(msg); cause != nil {
+		return &wrapped{err: err, cause: cause}
+	}
+	return err
 }
 
 // Error implements the error interface
 func (e *Error) Error() string {
 	return fmt.Sprintf("[%d] %s", e.Code, e.Message)
 }
+
+// Is reports whether the target is a radish error with the same code so that errors can
+// be matched with errors.Is regardless of their message or how they were wrapped.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && e != nil && t != nil && t.Code == e.Code
+}
+
+// wrapped is a radish error that keeps the error it wraps, which is not sent over the
+// wire but can be matched with errors.Is and errors.As in the process that created it.
+type wrapped struct {
+	err   *Error
+	cause error
+}
+
+// Error returns the message of the radish error.
+func (e *wrapped) Error() string {
+	return e.err.Error()
+}
+
+// Is reports whether the target is a radish error with the same code.
+func (e *wrapped) Is(target error) bool {
+	return e.err.Is(target)
+}
+
+// Unwrap returns the wrapped error.
+func (e *wrapped) Unwrap() error {
+	return e.cause
+}
+
+// As sets the target to the radish error if the target is a *Error.
+func (e *wrapped) As(target interface{}) bool {
+	if t, ok := target.(**Error); ok {
+		*t = e.err
+		return true
+	}
+	return false
+}
@@ -1,526 +1,6535 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.23.0
+// 	protoc        v3.12.3
 // source: radish.proto
 
 package api
 
 import (
-	fmt "fmt"
-	proto "github.com/golang/protobuf/proto"
-	context "golang.org/x/net/context"
+	context "context"
 	grpc "google.golang.org/grpc"
-	math "math"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+type FutureState int32
 
-type QueueRequest struct {
-	Task                 string   `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
-	Params               []byte   `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
-	Success              []byte   `protobuf:"bytes,3,opt,name=success,proto3" json:"success,omitempty"`
-	Failure              []byte   `protobuf:"bytes,4,opt,name=failure,proto3" json:"failure,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *QueueRequest) Reset()         { *m = QueueRequest{} }
-func (m *QueueRequest) String() string { return proto.CompactTextString(m) }
-func (*QueueRequest) ProtoMessage()    {}
-func (*QueueRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ec93cfcc38d8076b, []int{0}
+const (
+	FutureState_UNKNOWN   FutureState = 0
+	FutureState_SCHEDULED FutureState = 1 // the future is waiting until it is due to be queued
+	FutureState_QUEUED    FutureState = 2 // the future is waiting in the queue for a worker
+	FutureState_RUNNING   FutureState = 3 // a worker is handling the future
+	FutureState_SUCCEEDED FutureState = 4 // the future was handled successfully
+	FutureState_FAILED    FutureState = 5 // the future failed on its final attempt
+	FutureState_CANCELED  FutureState = 6 // the future was canceled before or while it was handled
+	FutureState_PENDING   FutureState = 7 // the workflow step is waiting for the steps it depends on to succeed
+)
+
+// Enum value maps for FutureState.
+var (
+	FutureState_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "SCHEDULED",
+		2: "QUEUED",
+		3: "RUNNING",
+		4: "SUCCEEDED",
+		5: "FAILED",
+		6: "CANCELED",
+		7: "PENDING",
+	}
+	FutureState_value = map[string]int32{
+		"UNKNOWN":   0,
+		"SCHEDULED": 1,
+		"QUEUED":    2,
+		"RUNNING":   3,
+		"SUCCEEDED": 4,
+		"FAILED":    5,
+		"CANCELED":  6,
+		"PENDING":   7,
+	}
+)
+
+func (x FutureState) Enum() *FutureState {
+	p := new(FutureState)
+	*p = x
+	return p
 }
 
-func (m *QueueRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_QueueRequest.Unmarshal(m, b)
+func (x FutureState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
 }
-func (m *QueueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_QueueRequest.Marshal(b, m, deterministic)
+
+func (FutureState) Descriptor() protoreflect.EnumDescriptor {
+	return file_radish_proto_enumTypes[0].Descriptor()
 }
-func (m *QueueRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_QueueRequest.Merge(m, src)
+
+func (FutureState) Type() protoreflect.EnumType {
+	return &file_radish_proto_enumTypes[0]
 }
-func (m *QueueRequest) XXX_Size() int {
-	return xxx_messageInfo_QueueRequest.Size(m)
+
+func (x FutureState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
 }
-func (m *QueueRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_QueueRequest.DiscardUnknown(m)
+
+// Deprecated: Use FutureState.Descriptor instead.
+func (FutureState) EnumDescriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{0}
 }
 
-var xxx_messageInfo_QueueRequest proto.InternalMessageInfo
+type AutoscaleRequest_Toggle int32
+
+const (
+	AutoscaleRequest_UNCHANGED AutoscaleRequest_Toggle = 0 // leave autoscaling on or off
+	AutoscaleRequest_ENABLE    AutoscaleRequest_Toggle = 1 // turn autoscaling on
+	AutoscaleRequest_DISABLE   AutoscaleRequest_Toggle = 2 // turn autoscaling off, leaving the workers as they are
+)
 
-func (m *QueueRequest) GetTask() string {
-	if m != nil {
-		return m.Task
+// Enum value maps for AutoscaleRequest_Toggle.
+var (
+	AutoscaleRequest_Toggle_name = map[int32]string{
+		0: "UNCHANGED",
+		1: "ENABLE",
+		2: "DISABLE",
 	}
-	return ""
+	AutoscaleRequest_Toggle_value = map[string]int32{
+		"UNCHANGED": 0,
+		"ENABLE":    1,
+		"DISABLE":   2,
+	}
+)
+
+func (x AutoscaleRequest_Toggle) Enum() *AutoscaleRequest_Toggle {
+	p := new(AutoscaleRequest_Toggle)
+	*p = x
+	return p
 }
 
-func (m *QueueRequest) GetParams() []byte {
-	if m != nil {
-		return m.Params
-	}
-	return nil
+func (x AutoscaleRequest_Toggle) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AutoscaleRequest_Toggle) Descriptor() protoreflect.EnumDescriptor {
+	return file_radish_proto_enumTypes[1].Descriptor()
+}
+
+func (AutoscaleRequest_Toggle) Type() protoreflect.EnumType {
+	return &file_radish_proto_enumTypes[1]
+}
+
+func (x AutoscaleRequest_Toggle) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AutoscaleRequest_Toggle.Descriptor instead.
+func (AutoscaleRequest_Toggle) EnumDescriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{6, 0}
 }
 
-func (m *QueueRequest) GetSuccess() []byte {
-	if m != nil {
-		return m.Success
+// The optional sections of the status; the summary of the queue is always returned.
+type StatusRequest_Section int32
+
+const (
+	StatusRequest_TASKS     StatusRequest_Section = 0 // the registered tasks and their counts, returned if no sections are requested
+	StatusRequest_WORKERS   StatusRequest_Section = 1 // the busy and idle workers and the connected remote workers
+	StatusRequest_IN_FLIGHT StatusRequest_Section = 2 // the futures being handled by workers
+	StatusRequest_SCHEDULER StatusRequest_Section = 3 // the futures scheduled to be queued later
+)
+
+// Enum value maps for StatusRequest_Section.
+var (
+	StatusRequest_Section_name = map[int32]string{
+		0: "TASKS",
+		1: "WORKERS",
+		2: "IN_FLIGHT",
+		3: "SCHEDULER",
 	}
-	return nil
+	StatusRequest_Section_value = map[string]int32{
+		"TASKS":     0,
+		"WORKERS":   1,
+		"IN_FLIGHT": 2,
+		"SCHEDULER": 3,
+	}
+)
+
+func (x StatusRequest_Section) Enum() *StatusRequest_Section {
+	p := new(StatusRequest_Section)
+	*p = x
+	return p
+}
+
+func (x StatusRequest_Section) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StatusRequest_Section) Descriptor() protoreflect.EnumDescriptor {
+	return file_radish_proto_enumTypes[2].Descriptor()
+}
+
+func (StatusRequest_Section) Type() protoreflect.EnumType {
+	return &file_radish_proto_enumTypes[2]
+}
+
+func (x StatusRequest_Section) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StatusRequest_Section.Descriptor instead.
+func (StatusRequest_Section) EnumDescriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{10, 0}
 }
 
-func (m *QueueRequest) GetFailure() []byte {
-	if m != nil {
-		return m.Failure
+type WorkAssignment_Kind int32
+
+const (
+	WorkAssignment_HANDLE  WorkAssignment_Kind = 0 // handle the future with the params
+	WorkAssignment_CANCEL  WorkAssignment_Kind = 1 // cancel the context of the future that is being handled
+	WorkAssignment_SUCCESS WorkAssignment_Kind = 2 // call the success callback of the task with the params
+	WorkAssignment_FAILURE WorkAssignment_Kind = 3 // call the failure callback of the task with the params and error
+)
+
+// Enum value maps for WorkAssignment_Kind.
+var (
+	WorkAssignment_Kind_name = map[int32]string{
+		0: "HANDLE",
+		1: "CANCEL",
+		2: "SUCCESS",
+		3: "FAILURE",
 	}
-	return nil
+	WorkAssignment_Kind_value = map[string]int32{
+		"HANDLE":  0,
+		"CANCEL":  1,
+		"SUCCESS": 2,
+		"FAILURE": 3,
+	}
+)
+
+func (x WorkAssignment_Kind) Enum() *WorkAssignment_Kind {
+	p := new(WorkAssignment_Kind)
+	*p = x
+	return p
 }
 
-type QueueReply struct {
-	Uuid                 []byte   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	Error                *Error   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x WorkAssignment_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
 }
 
-func (m *QueueReply) Reset()         { *m = QueueReply{} }
-func (m *QueueReply) String() string { return proto.CompactTextString(m) }
-func (*QueueReply) ProtoMessage()    {}
-func (*QueueReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ec93cfcc38d8076b, []int{1}
+func (WorkAssignment_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_radish_proto_enumTypes[3].Descriptor()
+}
+
+func (WorkAssignment_Kind) Type() protoreflect.EnumType {
+	return &file_radish_proto_enumTypes[3]
 }
 
-func (m *QueueReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_QueueReply.Unmarshal(m, b)
+func (x WorkAssignment_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
 }
-func (m *QueueReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_QueueReply.Marshal(b, m, deterministic)
+
+// Deprecated: Use WorkAssignment_Kind.Descriptor instead.
+func (WorkAssignment_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{49, 0}
 }
-func (m *QueueReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_QueueReply.Merge(m, src)
+
+type QueueRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Task           string            `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`                                                                                                  // the type of task to queue (e.g. the task name)
+	Params         []byte            `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`                                                                                              // the data to send in as an argument to the task
+	Success        []byte            `protobuf:"bytes,3,opt,name=success,proto3" json:"success,omitempty"`                                                                                            // the parameters to pass into the success callback of the task
+	Failure        []byte            `protobuf:"bytes,4,opt,name=failure,proto3" json:"failure,omitempty"`                                                                                            // the parameters to pass into the failure callback of the task
+	Callback       string            `protobuf:"bytes,5,opt,name=callback,proto3" json:"callback,omitempty"`                                                                                          // the address of a RadishCallback service to notify when the future completes
+	DryRun         bool              `protobuf:"varint,6,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                                                                               // check if the future can be queued without queueing it
+	Priority       int32             `protobuf:"varint,7,opt,name=priority,proto3" json:"priority,omitempty"`                                                                                         // futures with a higher priority are handled first (default 0)
+	Eta            int64             `protobuf:"varint,8,opt,name=eta,proto3" json:"eta,omitempty"`                                                                                                   // queue the future at this time in unix nanoseconds (default now)
+	Delay          int64             `protobuf:"varint,9,opt,name=delay,proto3" json:"delay,omitempty"`                                                                                               // queue the future after this duration in nanoseconds, ignored if eta is set
+	IdempotencyKey string            `protobuf:"bytes,10,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`                                                       // return the existing future of the task delayed with the same key instead of a duplicate
+	Metadata       map[string]string `protobuf:"bytes,11,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // out-of-band data passed to the handler and callbacks, e.g. trace ids or the tenant
+	PartitionKey   string            `protobuf:"bytes,12,opt,name=partition_key,json=partitionKey,proto3" json:"partition_key,omitempty"`                                                             // futures with the same key are handled serially in the order they were queued
 }
-func (m *QueueReply) XXX_Size() int {
-	return xxx_messageInfo_QueueReply.Size(m)
+
+func (x *QueueRequest) Reset() {
+	*x = QueueRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *QueueReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_QueueReply.DiscardUnknown(m)
+
+func (x *QueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var xxx_messageInfo_QueueReply proto.InternalMessageInfo
+func (*QueueRequest) ProtoMessage() {}
 
-func (m *QueueReply) GetUuid() []byte {
-	if m != nil {
-		return m.Uuid
+func (x *QueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueRequest.ProtoReflect.Descriptor instead.
+func (*QueueRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{0}
 }
 
-func (m *QueueReply) GetSuccess() bool {
-	if m != nil {
-		return m.Success
+func (x *QueueRequest) GetTask() string {
+	if x != nil {
+		return x.Task
 	}
-	return false
+	return ""
 }
 
-func (m *QueueReply) GetError() *Error {
-	if m != nil {
-		return m.Error
+func (x *QueueRequest) GetParams() []byte {
+	if x != nil {
+		return x.Params
 	}
 	return nil
 }
 
-type ScaleRequest struct {
-	Workers              int32    `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *QueueRequest) GetSuccess() []byte {
+	if x != nil {
+		return x.Success
+	}
+	return nil
 }
 
-func (m *ScaleRequest) Reset()         { *m = ScaleRequest{} }
-func (m *ScaleRequest) String() string { return proto.CompactTextString(m) }
-func (*ScaleRequest) ProtoMessage()    {}
-func (*ScaleRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ec93cfcc38d8076b, []int{2}
+func (x *QueueRequest) GetFailure() []byte {
+	if x != nil {
+		return x.Failure
+	}
+	return nil
 }
 
-func (m *ScaleRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ScaleRequest.Unmarshal(m, b)
-}
-func (m *ScaleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ScaleRequest.Marshal(b, m, deterministic)
-}
-func (m *ScaleRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ScaleRequest.Merge(m, src)
+func (x *QueueRequest) GetCallback() string {
+	if x != nil {
+		return x.Callback
+	}
+	return ""
 }
-func (m *ScaleRequest) XXX_Size() int {
-	return xxx_messageInfo_ScaleRequest.Size(m)
+
+func (x *QueueRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
 }
-func (m *ScaleRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ScaleRequest.DiscardUnknown(m)
+
+func (x *QueueRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
 }
 
-var xxx_messageInfo_ScaleRequest proto.InternalMessageInfo
+func (x *QueueRequest) GetEta() int64 {
+	if x != nil {
+		return x.Eta
+	}
+	return 0
+}
 
-func (m *ScaleRequest) GetWorkers() int32 {
-	if m != nil {
-		return m.Workers
+func (x *QueueRequest) GetDelay() int64 {
+	if x != nil {
+		return x.Delay
 	}
 	return 0
 }
 
-type ScaleReply struct {
-	Workers              int32    `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`
-	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	Error                *Error   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *QueueRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
 }
 
-func (m *ScaleReply) Reset()         { *m = ScaleReply{} }
-func (m *ScaleReply) String() string { return proto.CompactTextString(m) }
-func (*ScaleReply) ProtoMessage()    {}
-func (*ScaleReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ec93cfcc38d8076b, []int{3}
+func (x *QueueRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
 }
 
-func (m *ScaleReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ScaleReply.Unmarshal(m, b)
+func (x *QueueRequest) GetPartitionKey() string {
+	if x != nil {
+		return x.PartitionKey
+	}
+	return ""
 }
-func (m *ScaleReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ScaleReply.Marshal(b, m, deterministic)
+
+type QueueReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid          []byte `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                                         // the id of the task that was created
+	Success       bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`                                  // if the queue request succeeded or failed
+	Error         *Error `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`                                       // the error if success is false
+	Outcome       string `protobuf:"bytes,4,opt,name=outcome,proto3" json:"outcome,omitempty"`                                   // if the request was a dry run, a description of what would have happened
+	QueueLength   uint64 `protobuf:"varint,5,opt,name=queue_length,json=queueLength,proto3" json:"queue_length,omitempty"`       // the number of futures waiting in the queue once the future was queued
+	EstimatedWait int64  `protobuf:"varint,6,opt,name=estimated_wait,json=estimatedWait,proto3" json:"estimated_wait,omitempty"` // nanoseconds until a worker is estimated to handle the future based on recent throughput, 0 if unknown
 }
-func (m *ScaleReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ScaleReply.Merge(m, src)
+
+func (x *QueueReply) Reset() {
+	*x = QueueReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *ScaleReply) XXX_Size() int {
-	return xxx_messageInfo_ScaleReply.Size(m)
+
+func (x *QueueReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ScaleReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_ScaleReply.DiscardUnknown(m)
+
+func (*QueueReply) ProtoMessage() {}
+
+func (x *QueueReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_ScaleReply proto.InternalMessageInfo
+// Deprecated: Use QueueReply.ProtoReflect.Descriptor instead.
+func (*QueueReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{1}
+}
 
-func (m *ScaleReply) GetWorkers() int32 {
-	if m != nil {
-		return m.Workers
+func (x *QueueReply) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
 	}
-	return 0
+	return nil
 }
 
-func (m *ScaleReply) GetSuccess() bool {
-	if m != nil {
-		return m.Success
+func (x *QueueReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
 	}
 	return false
 }
 
-func (m *ScaleReply) GetError() *Error {
-	if m != nil {
-		return m.Error
+func (x *QueueReply) GetError() *Error {
+	if x != nil {
+		return x.Error
 	}
 	return nil
 }
 
-type StatusRequest struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *QueueReply) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
 }
 
-func (m *StatusRequest) Reset()         { *m = StatusRequest{} }
-func (m *StatusRequest) String() string { return proto.CompactTextString(m) }
-func (*StatusRequest) ProtoMessage()    {}
-func (*StatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ec93cfcc38d8076b, []int{4}
+func (x *QueueReply) GetQueueLength() uint64 {
+	if x != nil {
+		return x.QueueLength
+	}
+	return 0
+}
+
+func (x *QueueReply) GetEstimatedWait() int64 {
+	if x != nil {
+		return x.EstimatedWait
+	}
+	return 0
+}
+
+type QueueBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests []*QueueRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"` // the futures to queue in one round trip
+}
+
+func (x *QueueBatchRequest) Reset() {
+	*x = QueueBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *StatusRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StatusRequest.Unmarshal(m, b)
+func (x *QueueBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *StatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StatusRequest.Marshal(b, m, deterministic)
+
+func (*QueueBatchRequest) ProtoMessage() {}
+
+func (x *QueueBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *StatusRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StatusRequest.Merge(m, src)
+
+// Deprecated: Use QueueBatchRequest.ProtoReflect.Descriptor instead.
+func (*QueueBatchRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{2}
 }
-func (m *StatusRequest) XXX_Size() int {
-	return xxx_messageInfo_StatusRequest.Size(m)
+
+func (x *QueueBatchRequest) GetRequests() []*QueueRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
 }
-func (m *StatusRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_StatusRequest.DiscardUnknown(m)
+
+type QueueBatchReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Replies []*QueueReply `protobuf:"bytes,1,rep,name=replies,proto3" json:"replies,omitempty"` // the reply to each request in the order they were sent
 }
 
-var xxx_messageInfo_StatusRequest proto.InternalMessageInfo
+func (x *QueueBatchReply) Reset() {
+	*x = QueueBatchReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-type StatusReply struct {
-	Workers              int32    `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`
-	Queue                uint64   `protobuf:"varint,2,opt,name=queue,proto3" json:"queue,omitempty"`
-	Tasks                []string `protobuf:"bytes,3,rep,name=tasks,proto3" json:"tasks,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *QueueBatchReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (m *StatusReply) Reset()         { *m = StatusReply{} }
-func (m *StatusReply) String() string { return proto.CompactTextString(m) }
-func (*StatusReply) ProtoMessage()    {}
-func (*StatusReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ec93cfcc38d8076b, []int{5}
+func (*QueueBatchReply) ProtoMessage() {}
+
+func (x *QueueBatchReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueBatchReply.ProtoReflect.Descriptor instead.
+func (*QueueBatchReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{3}
 }
 
-func (m *StatusReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StatusReply.Unmarshal(m, b)
+func (x *QueueBatchReply) GetReplies() []*QueueReply {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
 }
-func (m *StatusReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StatusReply.Marshal(b, m, deterministic)
+
+type ScaleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workers int32 `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"` // set the number of running workers to this number
+	Timeout int64 `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"` // nanoseconds to wait for removed workers to finish their futures (default until they finish)
+	Force   bool  `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`     // cancel the futures of removed workers that are still draining when the timeout expires
 }
-func (m *StatusReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StatusReply.Merge(m, src)
+
+func (x *ScaleRequest) Reset() {
+	*x = ScaleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *StatusReply) XXX_Size() int {
-	return xxx_messageInfo_StatusReply.Size(m)
+
+func (x *ScaleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *StatusReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_StatusReply.DiscardUnknown(m)
+
+func (*ScaleRequest) ProtoMessage() {}
+
+func (x *ScaleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_StatusReply proto.InternalMessageInfo
+// Deprecated: Use ScaleRequest.ProtoReflect.Descriptor instead.
+func (*ScaleRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{4}
+}
 
-func (m *StatusReply) GetWorkers() int32 {
-	if m != nil {
-		return m.Workers
+func (x *ScaleRequest) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
 	}
 	return 0
 }
 
-func (m *StatusReply) GetQueue() uint64 {
-	if m != nil {
-		return m.Queue
+func (x *ScaleRequest) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
 	}
 	return 0
 }
 
-func (m *StatusReply) GetTasks() []string {
-	if m != nil {
-		return m.Tasks
+func (x *ScaleRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
 	}
-	return nil
+	return false
 }
 
-type Error struct {
-	Code                 int32    `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
-	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type ScaleReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workers  int32  `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`   // the total number of workers now operating
+	Success  bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`   // if the scale request succeeded or failed
+	Error    *Error `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`        // the error if success is false
+	Draining int32  `protobuf:"varint,4,opt,name=draining,proto3" json:"draining,omitempty"` // the removed workers still finishing their futures when the timeout expired
 }
 
-func (m *Error) Reset()         { *m = Error{} }
-func (m *Error) String() string { return proto.CompactTextString(m) }
-func (*Error) ProtoMessage()    {}
-func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ec93cfcc38d8076b, []int{6}
+func (x *ScaleReply) Reset() {
+	*x = ScaleReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *Error) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Error.Unmarshal(m, b)
+func (x *ScaleReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+
+func (*ScaleReply) ProtoMessage() {}
+
+func (x *ScaleReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *Error) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Error.Merge(m, src)
+
+// Deprecated: Use ScaleReply.ProtoReflect.Descriptor instead.
+func (*ScaleReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{5}
 }
-func (m *Error) XXX_Size() int {
-	return xxx_messageInfo_Error.Size(m)
+
+func (x *ScaleReply) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
 }
-func (m *Error) XXX_DiscardUnknown() {
-	xxx_messageInfo_Error.DiscardUnknown(m)
+
+func (x *ScaleReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
 }
 
-var xxx_messageInfo_Error proto.InternalMessageInfo
+func (x *ScaleReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
 
-func (m *Error) GetCode() int32 {
-	if m != nil {
-		return m.Code
+func (x *ScaleReply) GetDraining() int32 {
+	if x != nil {
+		return x.Draining
 	}
 	return 0
 }
 
-func (m *Error) GetMessage() string {
-	if m != nil {
-		return m.Message
-	}
-	return ""
+type AutoscaleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Toggle     AutoscaleRequest_Toggle `protobuf:"varint,1,opt,name=toggle,proto3,enum=api.AutoscaleRequest_Toggle" json:"toggle,omitempty"` // turn autoscaling on or off
+	MinWorkers int32                   `protobuf:"varint,2,opt,name=min_workers,json=minWorkers,proto3" json:"min_workers,omitempty"`        // the minimum number of workers of the autoscale policy (0 unchanged)
+	MaxWorkers int32                   `protobuf:"varint,3,opt,name=max_workers,json=maxWorkers,proto3" json:"max_workers,omitempty"`        // the maximum number of workers of the autoscale policy (0 unchanged)
 }
 
-func init() {
-	proto.RegisterType((*QueueRequest)(nil), "api.QueueRequest")
-	proto.RegisterType((*QueueReply)(nil), "api.QueueReply")
-	proto.RegisterType((*ScaleRequest)(nil), "api.ScaleRequest")
-	proto.RegisterType((*ScaleReply)(nil), "api.ScaleReply")
-	proto.RegisterType((*StatusRequest)(nil), "api.StatusRequest")
-	proto.RegisterType((*StatusReply)(nil), "api.StatusReply")
-	proto.RegisterType((*Error)(nil), "api.Error")
-}
-
-func init() { proto.RegisterFile("radish.proto", fileDescriptor_ec93cfcc38d8076b) }
-
-var fileDescriptor_ec93cfcc38d8076b = []byte{
-	// 332 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x52, 0xc1, 0x4e, 0xc3, 0x30,
-	0x0c, 0x5d, 0xd9, 0xda, 0x31, 0xaf, 0x68, 0x10, 0x21, 0x14, 0xed, 0x34, 0xe5, 0xd4, 0x0b, 0x13,
-	0x1a, 0xe2, 0x13, 0xf8, 0x00, 0xb2, 0x2b, 0x97, 0xac, 0x0b, 0x50, 0xad, 0xa3, 0x59, 0xd2, 0x08,
-	0xed, 0x4f, 0xf8, 0x5c, 0x64, 0x27, 0x65, 0xe5, 0x00, 0x17, 0x6e, 0x7e, 0xf6, 0x8b, 0x9f, 0xfd,
-	0x62, 0xc8, 0xad, 0xda, 0x56, 0xee, 0x6d, 0x69, 0x6c, 0xd3, 0x36, 0x6c, 0xa8, 0x4c, 0x25, 0xde,
-	0x21, 0x7f, 0xf2, 0xda, 0x6b, 0xa9, 0x0f, 0x5e, 0xbb, 0x96, 0x31, 0x18, 0xb5, 0xca, 0xed, 0x78,
-	0xb2, 0x48, 0x8a, 0x89, 0xa4, 0x98, 0xdd, 0x40, 0x66, 0x94, 0x55, 0x7b, 0xc7, 0xcf, 0x16, 0x49,
-	0x91, 0xcb, 0x88, 0x18, 0x87, 0xb1, 0xf3, 0x65, 0xa9, 0x9d, 0xe3, 0x43, 0x2a, 0x74, 0x10, 0x2b,
-	0x2f, 0xaa, 0xaa, 0xbd, 0xd5, 0x7c, 0x14, 0x2a, 0x11, 0x8a, 0x67, 0x80, 0xa8, 0x67, 0xea, 0x23,
-	0xaa, 0x79, 0x5f, 0x6d, 0x49, 0x2d, 0x97, 0x14, 0xf7, 0xbb, 0xa2, 0xdc, 0xf9, 0xa9, 0xeb, 0x02,
-	0x52, 0x6d, 0x6d, 0x63, 0x49, 0x6d, 0xba, 0x82, 0xa5, 0x32, 0xd5, 0xf2, 0x11, 0x33, 0x32, 0x14,
-	0x44, 0x01, 0xf9, 0xba, 0x54, 0xf5, 0xf7, 0x36, 0x1c, 0xc6, 0x1f, 0x8d, 0xdd, 0x69, 0xeb, 0x48,
-	0x22, 0x95, 0x1d, 0x14, 0x1b, 0x80, 0xc8, 0xc4, 0x39, 0x7e, 0xe5, 0xfd, 0x6b, 0x9a, 0x19, 0x5c,
-	0xac, 0x5b, 0xd5, 0x7a, 0x17, 0xc7, 0x11, 0x6b, 0x98, 0x76, 0x89, 0xbf, 0x55, 0xaf, 0x21, 0x3d,
-	0xa0, 0x4b, 0xa4, 0x39, 0x92, 0x01, 0x60, 0x16, 0xff, 0x03, 0xdd, 0x1e, 0x16, 0x13, 0x19, 0x80,
-	0x78, 0x80, 0x94, 0x54, 0xd1, 0xcc, 0xb2, 0xd9, 0xea, 0xd8, 0x8b, 0x62, 0x94, 0xd8, 0x6b, 0xe7,
-	0xd4, 0x6b, 0x68, 0x35, 0x91, 0x1d, 0x5c, 0x7d, 0x26, 0x90, 0x49, 0x3a, 0x07, 0x76, 0x0b, 0x29,
-	0xfd, 0x09, 0xbb, 0xa2, 0x1d, 0xfa, 0xf7, 0x30, 0x9f, 0xf5, 0x53, 0xa6, 0x3e, 0x8a, 0x01, 0xd2,
-	0xc9, 0xba, 0x48, 0xef, 0x1b, 0x1e, 0xe9, 0x27, 0x67, 0xc5, 0x80, 0xdd, 0x41, 0x16, 0x96, 0x66,
-	0x2c, 0x14, 0xfb, 0x96, 0xcc, 0x2f, 0x7f, 0xe4, 0xe8, 0xc5, 0x26, 0xa3, 0xfb, 0xbc, 0xff, 0x0a,
-	0x00, 0x00, 0xff, 0xff, 0x77, 0x79, 0x34, 0x52, 0xaf, 0x02, 0x00, 0x00,
+func (x *AutoscaleRequest) Reset() {
+	*x = AutoscaleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (x *AutoscaleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (*AutoscaleRequest) ProtoMessage() {}
 
-// RadishClient is the client API for Radish service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type RadishClient interface {
-	Queue(ctx context.Context, in *QueueRequest, opts ...grpc.CallOption) (*QueueReply, error)
-	Scale(ctx context.Context, in *ScaleRequest, opts ...grpc.CallOption) (*ScaleReply, error)
-	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error)
+func (x *AutoscaleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type radishClient struct {
-	cc *grpc.ClientConn
+// Deprecated: Use AutoscaleRequest.ProtoReflect.Descriptor instead.
+func (*AutoscaleRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{6}
 }
 
-func NewRadishClient(cc *grpc.ClientConn) RadishClient {
-	return &radishClient{cc}
+func (x *AutoscaleRequest) GetToggle() AutoscaleRequest_Toggle {
+	if x != nil {
+		return x.Toggle
+	}
+	return AutoscaleRequest_UNCHANGED
 }
 
-func (c *radishClient) Queue(ctx context.Context, in *QueueRequest, opts ...grpc.CallOption) (*QueueReply, error) {
-	out := new(QueueReply)
-	err := c.cc.Invoke(ctx, "/api.Radish/Queue", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (x *AutoscaleRequest) GetMinWorkers() int32 {
+	if x != nil {
+		return x.MinWorkers
 	}
-	return out, nil
+	return 0
 }
 
-func (c *radishClient) Scale(ctx context.Context, in *ScaleRequest, opts ...grpc.CallOption) (*ScaleReply, error) {
-	out := new(ScaleReply)
-	err := c.cc.Invoke(ctx, "/api.Radish/Scale", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (x *AutoscaleRequest) GetMaxWorkers() int32 {
+	if x != nil {
+		return x.MaxWorkers
 	}
-	return out, nil
+	return 0
 }
 
-func (c *radishClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error) {
-	out := new(StatusReply)
-	err := c.cc.Invoke(ctx, "/api.Radish/Status", in, out, opts...)
-	if err != nil {
-		return nil, err
+type AutoscaleReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enabled    bool   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`                         // if autoscaling is on
+	MinWorkers int32  `protobuf:"varint,2,opt,name=min_workers,json=minWorkers,proto3" json:"min_workers,omitempty"` // the minimum number of workers of the autoscale policy
+	MaxWorkers int32  `protobuf:"varint,3,opt,name=max_workers,json=maxWorkers,proto3" json:"max_workers,omitempty"` // the maximum number of workers of the autoscale policy
+	Workers    int32  `protobuf:"varint,4,opt,name=workers,proto3" json:"workers,omitempty"`                         // the total number of workers now operating
+	Success    bool   `protobuf:"varint,5,opt,name=success,proto3" json:"success,omitempty"`                         // if the autoscale request succeeded or failed
+	Error      *Error `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`                              // the error if success is false
+}
+
+func (x *AutoscaleReply) Reset() {
+	*x = AutoscaleReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return out, nil
 }
 
-// RadishServer is the server API for Radish service.
-type RadishServer interface {
-	Queue(context.Context, *QueueRequest) (*QueueReply, error)
-	Scale(context.Context, *ScaleRequest) (*ScaleReply, error)
-	Status(context.Context, *StatusRequest) (*StatusReply, error)
+func (x *AutoscaleReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func RegisterRadishServer(s *grpc.Server, srv RadishServer) {
-	s.RegisterService(&_Radish_serviceDesc, srv)
+func (*AutoscaleReply) ProtoMessage() {}
+
+func (x *AutoscaleReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func _Radish_Queue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QueueRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+// Deprecated: Use AutoscaleReply.ProtoReflect.Descriptor instead.
+func (*AutoscaleReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AutoscaleReply) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
 	}
-	if interceptor == nil {
-		return srv.(RadishServer).Queue(ctx, in)
+	return false
+}
+
+func (x *AutoscaleReply) GetMinWorkers() int32 {
+	if x != nil {
+		return x.MinWorkers
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Radish/Queue",
+	return 0
+}
+
+func (x *AutoscaleReply) GetMaxWorkers() int32 {
+	if x != nil {
+		return x.MaxWorkers
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RadishServer).Queue(ctx, req.(*QueueRequest))
+	return 0
+}
+
+func (x *AutoscaleReply) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _Radish_Scale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func (x *AutoscaleReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AutoscaleReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type PingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{8}
+}
+
+type PingReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version   string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`      // the package version of the radish server
+	Protocol  uint32 `protobuf:"varint,2,opt,name=protocol,proto3" json:"protocol,omitempty"`   // the protocol version of the radish API implemented by the server
+	Timestamp int64  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // the current time on the server in unix nanoseconds
+}
+
+func (x *PingReply) Reset() {
+	*x = PingReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PingReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingReply) ProtoMessage() {}
+
+func (x *PingReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingReply.ProtoReflect.Descriptor instead.
+func (*PingReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PingReply) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *PingReply) GetProtocol() uint32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *PingReply) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type StatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cluster  bool                    `protobuf:"varint,1,opt,name=cluster,proto3" json:"cluster,omitempty"`                                         // also return the status of every peer in the cluster of the server
+	Sections []StatusRequest_Section `protobuf:"varint,2,rep,packed,name=sections,proto3,enum=api.StatusRequest_Section" json:"sections,omitempty"` // the sections of the status to return (default only tasks)
+	Tasks    []string                `protobuf:"bytes,3,rep,name=tasks,proto3" json:"tasks,omitempty"`                                              // only return the sections for the specified tasks (default all tasks)
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StatusRequest) GetCluster() bool {
+	if x != nil {
+		return x.Cluster
+	}
+	return false
+}
+
+func (x *StatusRequest) GetSections() []StatusRequest_Section {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+func (x *StatusRequest) GetTasks() []string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type StatusReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workers      int32            `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`                               // the total number of workers currently running
+	Queue        uint64           `protobuf:"varint,2,opt,name=queue,proto3" json:"queue,omitempty"`                                   // the number of tasks in the queue
+	Tasks        []string         `protobuf:"bytes,3,rep,name=tasks,proto3" json:"tasks,omitempty"`                                    // the names of the registered task types
+	Draining     bool             `protobuf:"varint,4,opt,name=draining,proto3" json:"draining,omitempty"`                             // if the queue is draining and not accepting new tasks
+	Paused       bool             `protobuf:"varint,5,opt,name=paused,proto3" json:"paused,omitempty"`                                 // if the workers are paused and not dequeuing tasks
+	Capacity     uint64           `protobuf:"varint,6,opt,name=capacity,proto3" json:"capacity,omitempty"`                             // the maximum number of tasks the queue can hold
+	InFlight     uint64           `protobuf:"varint,7,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`             // the number of futures being handled by workers
+	Uptime       int64            `protobuf:"varint,8,opt,name=uptime,proto3" json:"uptime,omitempty"`                                 // the nanoseconds since the queue was created
+	Version      string           `protobuf:"bytes,9,opt,name=version,proto3" json:"version,omitempty"`                                // the package version of the radish server
+	Counts       []*TaskInfo      `protobuf:"bytes,10,rep,name=counts,proto3" json:"counts,omitempty"`                                 // the futures of each registered task pending and handled since the queue started
+	Peers        []*PeerStatus    `protobuf:"bytes,11,rep,name=peers,proto3" json:"peers,omitempty"`                                   // the status of every peer in the cluster if requested
+	WorkerStatus *WorkersStatus   `protobuf:"bytes,12,opt,name=worker_status,json=workerStatus,proto3" json:"worker_status,omitempty"` // the state of the workers if the workers section was requested
+	Running      []*FutureInfo    `protobuf:"bytes,13,rep,name=running,proto3" json:"running,omitempty"`                               // the futures being handled if the in flight section was requested
+	Scheduler    *SchedulerStatus `protobuf:"bytes,14,opt,name=scheduler,proto3" json:"scheduler,omitempty"`                           // the scheduled futures if the scheduler section was requested
+	Success      bool             `protobuf:"varint,15,opt,name=success,proto3" json:"success,omitempty"`                              // if the status request succeeded or failed
+	Error        *Error           `protobuf:"bytes,16,opt,name=error,proto3" json:"error,omitempty"`                                   // the error if success is false
+}
+
+func (x *StatusReply) Reset() {
+	*x = StatusReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusReply) ProtoMessage() {}
+
+func (x *StatusReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusReply.ProtoReflect.Descriptor instead.
+func (*StatusReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StatusReply) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *StatusReply) GetQueue() uint64 {
+	if x != nil {
+		return x.Queue
+	}
+	return 0
+}
+
+func (x *StatusReply) GetTasks() []string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *StatusReply) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+func (x *StatusReply) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *StatusReply) GetCapacity() uint64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *StatusReply) GetInFlight() uint64 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *StatusReply) GetUptime() int64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+func (x *StatusReply) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *StatusReply) GetCounts() []*TaskInfo {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *StatusReply) GetPeers() []*PeerStatus {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+func (x *StatusReply) GetWorkerStatus() *WorkersStatus {
+	if x != nil {
+		return x.WorkerStatus
+	}
+	return nil
+}
+
+func (x *StatusReply) GetRunning() []*FutureInfo {
+	if x != nil {
+		return x.Running
+	}
+	return nil
+}
+
+func (x *StatusReply) GetScheduler() *SchedulerStatus {
+	if x != nil {
+		return x.Scheduler
+	}
+	return nil
+}
+
+func (x *StatusReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *StatusReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type WorkersStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Busy    int32                 `protobuf:"varint,1,opt,name=busy,proto3" json:"busy,omitempty"`      // the number of workers handling a future
+	Idle    int32                 `protobuf:"varint,2,opt,name=idle,proto3" json:"idle,omitempty"`      // the number of workers waiting for a future
+	Remote  []*RemoteWorkerStatus `protobuf:"bytes,3,rep,name=remote,proto3" json:"remote,omitempty"`   // the remote workers connected with the Work stream
+	Workers []*WorkerInfo         `protobuf:"bytes,4,rep,name=workers,proto3" json:"workers,omitempty"` // the state of each worker, only the workers handling the requested tasks if filtered
+}
+
+func (x *WorkersStatus) Reset() {
+	*x = WorkersStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkersStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkersStatus) ProtoMessage() {}
+
+func (x *WorkersStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkersStatus.ProtoReflect.Descriptor instead.
+func (*WorkersStatus) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WorkersStatus) GetBusy() int32 {
+	if x != nil {
+		return x.Busy
+	}
+	return 0
+}
+
+func (x *WorkersStatus) GetIdle() int32 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+func (x *WorkersStatus) GetRemote() []*RemoteWorkerStatus {
+	if x != nil {
+		return x.Remote
+	}
+	return nil
+}
+
+func (x *WorkersStatus) GetWorkers() []*WorkerInfo {
+	if x != nil {
+		return x.Workers
+	}
+	return nil
+}
+
+type WorkerInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`           // the id of the worker, unique for the lifetime of the queue
+	Handled uint64 `protobuf:"varint,2,opt,name=handled,proto3" json:"handled,omitempty"` // the number of futures the worker has handled
+	Busy    int64  `protobuf:"varint,3,opt,name=busy,proto3" json:"busy,omitempty"`       // the total nanoseconds the worker has spent handling futures
+	Task    string `protobuf:"bytes,4,opt,name=task,proto3" json:"task,omitempty"`        // the task of the future the worker is handling, if any
+	Future  []byte `protobuf:"bytes,5,opt,name=future,proto3" json:"future,omitempty"`    // the id of the future the worker is handling, if any
+	Started int64  `protobuf:"varint,6,opt,name=started,proto3" json:"started,omitempty"` // when the worker started handling the current future in unix nanoseconds
+	Created int64  `protobuf:"varint,7,opt,name=created,proto3" json:"created,omitempty"` // when the worker was added in unix nanoseconds
+}
+
+func (x *WorkerInfo) Reset() {
+	*x = WorkerInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerInfo) ProtoMessage() {}
+
+func (x *WorkerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerInfo.ProtoReflect.Descriptor instead.
+func (*WorkerInfo) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WorkerInfo) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetHandled() uint64 {
+	if x != nil {
+		return x.Handled
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetBusy() int64 {
+	if x != nil {
+		return x.Busy
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetFuture() []byte {
+	if x != nil {
+		return x.Future
+	}
+	return nil
+}
+
+func (x *WorkerInfo) GetStarted() int64 {
+	if x != nil {
+		return x.Started
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+type RemoteWorkerStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                          // the name the remote worker said hello with
+	Tasks    []string `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`                        // the tasks handled by the remote worker
+	InFlight int32    `protobuf:"varint,3,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"` // the number of futures assigned to the remote worker
+}
+
+func (x *RemoteWorkerStatus) Reset() {
+	*x = RemoteWorkerStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoteWorkerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteWorkerStatus) ProtoMessage() {}
+
+func (x *RemoteWorkerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteWorkerStatus.ProtoReflect.Descriptor instead.
+func (*RemoteWorkerStatus) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RemoteWorkerStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RemoteWorkerStatus) GetTasks() []string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *RemoteWorkerStatus) GetInFlight() int32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+type SchedulerStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scheduled uint64 `protobuf:"varint,1,opt,name=scheduled,proto3" json:"scheduled,omitempty"`            // the number of futures scheduled to be queued later
+	NextDue   int64  `protobuf:"varint,2,opt,name=next_due,json=nextDue,proto3" json:"next_due,omitempty"` // when the next scheduled future is due in unix nanoseconds, 0 if none
+}
+
+func (x *SchedulerStatus) Reset() {
+	*x = SchedulerStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SchedulerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerStatus) ProtoMessage() {}
+
+func (x *SchedulerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerStatus.ProtoReflect.Descriptor instead.
+func (*SchedulerStatus) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SchedulerStatus) GetScheduled() uint64 {
+	if x != nil {
+		return x.Scheduled
+	}
+	return 0
+}
+
+func (x *SchedulerStatus) GetNextDue() int64 {
+	if x != nil {
+		return x.NextDue
+	}
+	return 0
+}
+
+type PeerStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Addr   string       `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`     // the address of the peer
+	Status *StatusReply `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // the status of the peer if it could be reached
+	Error  *Error       `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`   // the error if the status of the peer could not be retrieved
+}
+
+func (x *PeerStatus) Reset() {
+	*x = PeerStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerStatus) ProtoMessage() {}
+
+func (x *PeerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerStatus.ProtoReflect.Descriptor instead.
+func (*PeerStatus) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PeerStatus) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *PeerStatus) GetStatus() *StatusReply {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *PeerStatus) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type DrainRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Shutdown bool `protobuf:"varint,1,opt,name=shutdown,proto3" json:"shutdown,omitempty"` // shutdown the queue once it has been drained
+}
+
+func (x *DrainRequest) Reset() {
+	*x = DrainRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainRequest) ProtoMessage() {}
+
+func (x *DrainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainRequest.ProtoReflect.Descriptor instead.
+func (*DrainRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DrainRequest) GetShutdown() bool {
+	if x != nil {
+		return x.Shutdown
+	}
+	return false
+}
+
+type DrainReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Queue   uint64 `protobuf:"varint,1,opt,name=queue,proto3" json:"queue,omitempty"`     // the number of tasks remaining in the queue
+	Success bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"` // if the queue is draining
+	Error   *Error `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`      // the error if success is false
+}
+
+func (x *DrainReply) Reset() {
+	*x = DrainReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrainReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainReply) ProtoMessage() {}
+
+func (x *DrainReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainReply.ProtoReflect.Descriptor instead.
+func (*DrainReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DrainReply) GetQueue() uint64 {
+	if x != nil {
+		return x.Queue
+	}
+	return 0
+}
+
+func (x *DrainReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DrainReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Settings that are zero valued are left unchanged by the reload.
+type ReloadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Load             bool   `protobuf:"varint,1,opt,name=load,proto3" json:"load,omitempty"`                                                 // load the config with the LoadConfig function as a reload signal does
+	Workers          int32  `protobuf:"varint,2,opt,name=workers,proto3" json:"workers,omitempty"`                                           // the number of workers to run
+	LogLevel         string `protobuf:"bytes,3,opt,name=log_level,json=logLevel,proto3" json:"log_level,omitempty"`                          // the level to log at
+	CautionThreshold uint32 `protobuf:"varint,4,opt,name=caution_threshold,json=cautionThreshold,proto3" json:"caution_threshold,omitempty"` // the number of messages accumulated before issuing another caution
+	TaskQuota        int32  `protobuf:"varint,5,opt,name=task_quota,json=taskQuota,proto3" json:"task_quota,omitempty"`                      // the quota of futures of each task that may be queued, -1 for unlimited
+	ClientQuota      int32  `protobuf:"varint,6,opt,name=client_quota,json=clientQuota,proto3" json:"client_quota,omitempty"`                // the quota of pending futures of each API client, -1 for unlimited
+}
+
+func (x *ReloadRequest) Reset() {
+	*x = ReloadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadRequest) ProtoMessage() {}
+
+func (x *ReloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadRequest.ProtoReflect.Descriptor instead.
+func (*ReloadRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReloadRequest) GetLoad() bool {
+	if x != nil {
+		return x.Load
+	}
+	return false
+}
+
+func (x *ReloadRequest) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *ReloadRequest) GetLogLevel() string {
+	if x != nil {
+		return x.LogLevel
+	}
+	return ""
+}
+
+func (x *ReloadRequest) GetCautionThreshold() uint32 {
+	if x != nil {
+		return x.CautionThreshold
+	}
+	return 0
+}
+
+func (x *ReloadRequest) GetTaskQuota() int32 {
+	if x != nil {
+		return x.TaskQuota
+	}
+	return 0
+}
+
+func (x *ReloadRequest) GetClientQuota() int32 {
+	if x != nil {
+		return x.ClientQuota
+	}
+	return 0
+}
+
+type ReloadReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workers          int32  `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`                                           // the number of workers after the reload
+	LogLevel         string `protobuf:"bytes,2,opt,name=log_level,json=logLevel,proto3" json:"log_level,omitempty"`                          // the log level after the reload
+	CautionThreshold uint32 `protobuf:"varint,3,opt,name=caution_threshold,json=cautionThreshold,proto3" json:"caution_threshold,omitempty"` // the caution threshold after the reload
+	TaskQuota        int32  `protobuf:"varint,4,opt,name=task_quota,json=taskQuota,proto3" json:"task_quota,omitempty"`                      // the task quota after the reload, 0 if unlimited
+	ClientQuota      int32  `protobuf:"varint,5,opt,name=client_quota,json=clientQuota,proto3" json:"client_quota,omitempty"`                // the client quota after the reload, 0 if unlimited
+	Success          bool   `protobuf:"varint,6,opt,name=success,proto3" json:"success,omitempty"`                                           // if the settings were reloaded
+	Error            *Error `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                                                // the error if success is false
+}
+
+func (x *ReloadReply) Reset() {
+	*x = ReloadReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReloadReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadReply) ProtoMessage() {}
+
+func (x *ReloadReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadReply.ProtoReflect.Descriptor instead.
+func (*ReloadReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReloadReply) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *ReloadReply) GetLogLevel() string {
+	if x != nil {
+		return x.LogLevel
+	}
+	return ""
+}
+
+func (x *ReloadReply) GetCautionThreshold() uint32 {
+	if x != nil {
+		return x.CautionThreshold
+	}
+	return 0
+}
+
+func (x *ReloadReply) GetTaskQuota() int32 {
+	if x != nil {
+		return x.TaskQuota
+	}
+	return 0
+}
+
+func (x *ReloadReply) GetClientQuota() int32 {
+	if x != nil {
+		return x.ClientQuota
+	}
+	return 0
+}
+
+func (x *ReloadReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ReloadReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type ListTasksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{21}
+}
+
+type ListTasksReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tasks []*TaskInfo `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"` // the registered tasks ordered by name
+}
+
+func (x *ListTasksReply) Reset() {
+	*x = ListTasksReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTasksReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksReply) ProtoMessage() {}
+
+func (x *ListTasksReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksReply.ProtoReflect.Descriptor instead.
+func (*ListTasksReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListTasksReply) GetTasks() []*TaskInfo {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type TaskInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`            // the name of the registered task
+	Pending   uint64 `protobuf:"varint,2,opt,name=pending,proto3" json:"pending,omitempty"`     // the number of futures of the task in the queue or being handled
+	Queued    uint64 `protobuf:"varint,3,opt,name=queued,proto3" json:"queued,omitempty"`       // the number of futures queued or scheduled since the queue started
+	Succeeded uint64 `protobuf:"varint,4,opt,name=succeeded,proto3" json:"succeeded,omitempty"` // the number of futures that succeeded since the queue started
+	Failed    uint64 `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`       // the number of futures that failed since the queue started
+	Canceled  uint64 `protobuf:"varint,6,opt,name=canceled,proto3" json:"canceled,omitempty"`   // the number of futures that were canceled since the queue started
+}
+
+func (x *TaskInfo) Reset() {
+	*x = TaskInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskInfo) ProtoMessage() {}
+
+func (x *TaskInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskInfo.ProtoReflect.Descriptor instead.
+func (*TaskInfo) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *TaskInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetPending() uint64 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetQueued() uint64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetSucceeded() uint64 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetFailed() uint64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetCanceled() uint64 {
+	if x != nil {
+		return x.Canceled
+	}
+	return 0
+}
+
+type LogsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level  string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`    // the minimum level of log entries to stream (default info)
+	Tasks  []string `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`    // only stream entries related to the specified tasks (default all entries)
+	Tail   int32    `protobuf:"varint,3,opt,name=tail,proto3" json:"tail,omitempty"`     // the number of recent log entries to send before streaming (default 0)
+	Follow bool     `protobuf:"varint,4,opt,name=follow,proto3" json:"follow,omitempty"` // continue streaming new log entries until the request is canceled
+}
+
+func (x *LogsRequest) Reset() {
+	*x = LogsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogsRequest) ProtoMessage() {}
+
+func (x *LogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogsRequest.ProtoReflect.Descriptor instead.
+func (*LogsRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LogsRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogsRequest) GetTasks() []string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *LogsRequest) GetTail() int32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+func (x *LogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+type LogEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp int64  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // the time the entry was logged in unix nanoseconds
+	Level     string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`          // the level of the log entry, e.g. info or warn
+	Task      string `protobuf:"bytes,3,opt,name=task,proto3" json:"task,omitempty"`            // the name of the task the entry is related to, if any
+	Message   string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`      // the log message
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *LogEntry) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *LogEntry) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *LogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ScriptRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Task   string `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`     // the name of the script task to register or update
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"` // the lua source of the script, which must define a handle function
+}
+
+func (x *ScriptRequest) Reset() {
+	*x = ScriptRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptRequest) ProtoMessage() {}
+
+func (x *ScriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptRequest.ProtoReflect.Descriptor instead.
+func (*ScriptRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ScriptRequest) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *ScriptRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type ScriptReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"` // if the script was registered or updated
+	Error   *Error `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`      // the error if success is false
+}
+
+func (x *ScriptReply) Reset() {
+	*x = ScriptReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScriptReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptReply) ProtoMessage() {}
+
+func (x *ScriptReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptReply.ProtoReflect.Descriptor instead.
+func (*ScriptReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ScriptReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ScriptReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type StatsHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit int32    `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // the maximum number of the most recent snapshots to return (default all)
+	Tasks []string `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`  // only include throughput for the specified tasks (default all tasks)
+}
+
+func (x *StatsHistoryRequest) Reset() {
+	*x = StatsHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsHistoryRequest) ProtoMessage() {}
+
+func (x *StatsHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsHistoryRequest.ProtoReflect.Descriptor instead.
+func (*StatsHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *StatsHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *StatsHistoryRequest) GetTasks() []string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type StatsHistoryReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Interval  int64            `protobuf:"varint,1,opt,name=interval,proto3" json:"interval,omitempty"`  // the interval between snapshots in nanoseconds
+	Snapshots []*StatsSnapshot `protobuf:"bytes,2,rep,name=snapshots,proto3" json:"snapshots,omitempty"` // snapshots from oldest to newest
+}
+
+func (x *StatsHistoryReply) Reset() {
+	*x = StatsHistoryReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsHistoryReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsHistoryReply) ProtoMessage() {}
+
+func (x *StatsHistoryReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsHistoryReply.ProtoReflect.Descriptor instead.
+func (*StatsHistoryReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *StatsHistoryReply) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *StatsHistoryReply) GetSnapshots() []*StatsSnapshot {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+type StatsSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp int64        `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // the time the snapshot was taken in unix nanoseconds
+	Workers   int32        `protobuf:"varint,2,opt,name=workers,proto3" json:"workers,omitempty"`     // the number of workers running when the snapshot was taken
+	Queue     uint64       `protobuf:"varint,3,opt,name=queue,proto3" json:"queue,omitempty"`         // the number of tasks in the queue when the snapshot was taken
+	Tasks     []*TaskStats `protobuf:"bytes,4,rep,name=tasks,proto3" json:"tasks,omitempty"`          // the throughput of each task since the previous snapshot
+}
+
+func (x *StatsSnapshot) Reset() {
+	*x = StatsSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsSnapshot) ProtoMessage() {}
+
+func (x *StatsSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsSnapshot.ProtoReflect.Descriptor instead.
+func (*StatsSnapshot) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *StatsSnapshot) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *StatsSnapshot) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *StatsSnapshot) GetQueue() uint64 {
+	if x != nil {
+		return x.Queue
+	}
+	return 0
+}
+
+func (x *StatsSnapshot) GetTasks() []*TaskStats {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type TaskStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Task      string `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`            // the name of the task
+	Succeeded uint64 `protobuf:"varint,2,opt,name=succeeded,proto3" json:"succeeded,omitempty"` // the number of futures that succeeded since the previous snapshot
+	Failed    uint64 `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`       // the number of futures that failed since the previous snapshot
+	Canceled  uint64 `protobuf:"varint,4,opt,name=canceled,proto3" json:"canceled,omitempty"`   // the number of futures that were canceled since the previous snapshot
+}
+
+func (x *TaskStats) Reset() {
+	*x = TaskStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStats) ProtoMessage() {}
+
+func (x *TaskStats) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStats.ProtoReflect.Descriptor instead.
+func (*TaskStats) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TaskStats) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *TaskStats) GetSucceeded() uint64 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *TaskStats) GetFailed() uint64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *TaskStats) GetCanceled() uint64 {
+	if x != nil {
+		return x.Canceled
+	}
+	return 0
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tasks  []string      `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`                                // only stream events of futures of the specified tasks (default all tasks)
+	States []FutureState `protobuf:"varint,2,rep,packed,name=states,proto3,enum=api.FutureState" json:"states,omitempty"` // only stream events for the specified states (default all states)
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *WatchRequest) GetTasks() []string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetStates() []FutureState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+type FutureEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid      []byte      `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                         // the id of the future
+	Task      string      `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`                         // the type of task of the future
+	State     FutureState `protobuf:"varint,3,opt,name=state,proto3,enum=api.FutureState" json:"state,omitempty"` // the lifecycle state the future transitioned to
+	Timestamp int64       `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`              // when the future transitioned to the state in unix nanoseconds
+	Attempts  int32       `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`                // the number of times the future has been handled
+	Duration  int64       `protobuf:"varint,6,opt,name=duration,proto3" json:"duration,omitempty"`                // nanoseconds spent queued for running events or running for completed events
+	Error     *Error      `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                       // the error that caused the future to fail or be canceled, if any
+}
+
+func (x *FutureEvent) Reset() {
+	*x = FutureEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FutureEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FutureEvent) ProtoMessage() {}
+
+func (x *FutureEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FutureEvent.ProtoReflect.Descriptor instead.
+func (*FutureEvent) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *FutureEvent) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *FutureEvent) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *FutureEvent) GetState() FutureState {
+	if x != nil {
+		return x.State
+	}
+	return FutureState_UNKNOWN
+}
+
+func (x *FutureEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *FutureEvent) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *FutureEvent) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *FutureEvent) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type GetFutureRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid []byte `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // the id of the future to look up
+}
+
+func (x *GetFutureRequest) Reset() {
+	*x = GetFutureRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFutureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFutureRequest) ProtoMessage() {}
+
+func (x *GetFutureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFutureRequest.ProtoReflect.Descriptor instead.
+func (*GetFutureRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetFutureRequest) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+type GetFutureReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Future  *FutureInfo `protobuf:"bytes,1,opt,name=future,proto3" json:"future,omitempty"`    // the state of the future if it was found
+	Success bool        `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"` // if the future was found
+	Error   *Error      `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`      // the error if success is false
+}
+
+func (x *GetFutureReply) Reset() {
+	*x = GetFutureReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFutureReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFutureReply) ProtoMessage() {}
+
+func (x *GetFutureReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFutureReply.ProtoReflect.Descriptor instead.
+func (*GetFutureReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetFutureReply) GetFuture() *FutureInfo {
+	if x != nil {
+		return x.Future
+	}
+	return nil
+}
+
+func (x *GetFutureReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetFutureReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type GetWorkflowRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid []byte `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // the id of the workflow
+}
+
+func (x *GetWorkflowRequest) Reset() {
+	*x = GetWorkflowRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkflowRequest) ProtoMessage() {}
+
+func (x *GetWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*GetWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetWorkflowRequest) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+type GetWorkflowReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workflow *WorkflowInfo `protobuf:"bytes,1,opt,name=workflow,proto3" json:"workflow,omitempty"` // the state of the workflow if it was found
+	Success  bool          `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`  // if the workflow was found
+	Error    *Error        `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`       // the error if success is false
+}
+
+func (x *GetWorkflowReply) Reset() {
+	*x = GetWorkflowReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetWorkflowReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkflowReply) ProtoMessage() {}
+
+func (x *GetWorkflowReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkflowReply.ProtoReflect.Descriptor instead.
+func (*GetWorkflowReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetWorkflowReply) GetWorkflow() *WorkflowInfo {
+	if x != nil {
+		return x.Workflow
+	}
+	return nil
+}
+
+func (x *GetWorkflowReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetWorkflowReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type WorkflowInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid     []byte              `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                         // the id of the workflow
+	State    FutureState         `protobuf:"varint,2,opt,name=state,proto3,enum=api.FutureState" json:"state,omitempty"` // running until every step has completed, then succeeded or failed
+	Created  int64               `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`                  // when the workflow was run in unix nanoseconds
+	Finished int64               `protobuf:"varint,4,opt,name=finished,proto3" json:"finished,omitempty"`                // when the last step of the workflow completed in unix nanoseconds
+	Steps    []*WorkflowStepInfo `protobuf:"bytes,5,rep,name=steps,proto3" json:"steps,omitempty"`                       // the steps in the order they were added to the workflow
+}
+
+func (x *WorkflowInfo) Reset() {
+	*x = WorkflowInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkflowInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowInfo) ProtoMessage() {}
+
+func (x *WorkflowInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowInfo.ProtoReflect.Descriptor instead.
+func (*WorkflowInfo) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *WorkflowInfo) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *WorkflowInfo) GetState() FutureState {
+	if x != nil {
+		return x.State
+	}
+	return FutureState_UNKNOWN
+}
+
+func (x *WorkflowInfo) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *WorkflowInfo) GetFinished() int64 {
+	if x != nil {
+		return x.Finished
+	}
+	return 0
+}
+
+func (x *WorkflowInfo) GetSteps() []*WorkflowStepInfo {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+type WorkflowStepInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                         // the unique name of the step in the workflow
+	Task  string      `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`                         // the name of the task that handles the step
+	After []string    `protobuf:"bytes,3,rep,name=after,proto3" json:"after,omitempty"`                       // the names of the steps that must succeed before the step is queued
+	Uuid  []byte      `protobuf:"bytes,4,opt,name=uuid,proto3" json:"uuid,omitempty"`                         // the id of the future of the step once it has been queued
+	State FutureState `protobuf:"varint,5,opt,name=state,proto3,enum=api.FutureState" json:"state,omitempty"` // the state of the future of the step, pending until it is queued
+	Error *Error      `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`                       // the error if the step failed or was canceled
+}
+
+func (x *WorkflowStepInfo) Reset() {
+	*x = WorkflowStepInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkflowStepInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowStepInfo) ProtoMessage() {}
+
+func (x *WorkflowStepInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowStepInfo.ProtoReflect.Descriptor instead.
+func (*WorkflowStepInfo) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *WorkflowStepInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkflowStepInfo) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *WorkflowStepInfo) GetAfter() []string {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *WorkflowStepInfo) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *WorkflowStepInfo) GetState() FutureState {
+	if x != nil {
+		return x.State
+	}
+	return FutureState_UNKNOWN
+}
+
+func (x *WorkflowStepInfo) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type GroupResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid      []byte         `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`            // the id of the group
+	Succeeded uint32         `protobuf:"varint,2,opt,name=succeeded,proto3" json:"succeeded,omitempty"` // the number of members that succeeded
+	Failed    uint32         `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`       // the number of members that failed or could not be queued
+	Canceled  uint32         `protobuf:"varint,4,opt,name=canceled,proto3" json:"canceled,omitempty"`   // the number of members that were canceled
+	Members   []*GroupMember `protobuf:"bytes,5,rep,name=members,proto3" json:"members,omitempty"`      // the members of the group in the order they were delayed
+}
+
+func (x *GroupResult) Reset() {
+	*x = GroupResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupResult) ProtoMessage() {}
+
+func (x *GroupResult) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupResult.ProtoReflect.Descriptor instead.
+func (*GroupResult) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GroupResult) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *GroupResult) GetSucceeded() uint32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *GroupResult) GetFailed() uint32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *GroupResult) GetCanceled() uint32 {
+	if x != nil {
+		return x.Canceled
+	}
+	return 0
+}
+
+func (x *GroupResult) GetMembers() []*GroupMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type GroupMember struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid   []byte      `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                         // the id of the future of the member, empty if it could not be queued
+	Task   string      `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`                         // the name of the task that handled the member
+	State  FutureState `protobuf:"varint,3,opt,name=state,proto3,enum=api.FutureState" json:"state,omitempty"` // the final state of the member
+	Error  *Error      `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                       // the error if the member failed or was canceled
+	Result []byte      `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`                     // the result of the member if its task returns results
+}
+
+func (x *GroupMember) Reset() {
+	*x = GroupMember{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupMember) ProtoMessage() {}
+
+func (x *GroupMember) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupMember.ProtoReflect.Descriptor instead.
+func (*GroupMember) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GroupMember) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *GroupMember) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *GroupMember) GetState() FutureState {
+	if x != nil {
+		return x.State
+	}
+	return FutureState_UNKNOWN
+}
+
+func (x *GroupMember) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *GroupMember) GetResult() []byte {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type CancelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid []byte `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // the id of the queued or in-flight future to cancel
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CancelRequest) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+type CancelReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"` // if the future was canceled
+	Error   *Error `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`      // the error if success is false
+}
+
+func (x *CancelReply) Reset() {
+	*x = CancelReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelReply) ProtoMessage() {}
+
+func (x *CancelReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelReply.ProtoReflect.Descriptor instead.
+func (*CancelReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CancelReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CancelReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type ResultRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid []byte `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`  // the id of the future to fetch the result of
+	Wait bool   `protobuf:"varint,2,opt,name=wait,proto3" json:"wait,omitempty"` // block until the future has completed or the request deadline
+}
+
+func (x *ResultRequest) Reset() {
+	*x = ResultRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultRequest) ProtoMessage() {}
+
+func (x *ResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultRequest.ProtoReflect.Descriptor instead.
+func (*ResultRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ResultRequest) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *ResultRequest) GetWait() bool {
+	if x != nil {
+		return x.Wait
+	}
+	return false
+}
+
+type ResultReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Future  *FutureInfo `protobuf:"bytes,1,opt,name=future,proto3" json:"future,omitempty"`    // the state of the future, including the error if it failed
+	Result  []byte      `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`    // the result returned by the handler if the future succeeded
+	Success bool        `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"` // if the future was found
+	Error   *Error      `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`      // the error if success is false
+}
+
+func (x *ResultReply) Reset() {
+	*x = ResultReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResultReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultReply) ProtoMessage() {}
+
+func (x *ResultReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultReply.ProtoReflect.Descriptor instead.
+func (*ResultReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ResultReply) GetFuture() *FutureInfo {
+	if x != nil {
+		return x.Future
+	}
+	return nil
+}
+
+func (x *ResultReply) GetResult() []byte {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ResultReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ResultReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// The first message sent by a remote worker on the Work stream must be a hello, every
+// following message is the result of an assigned future.
+type WorkerMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hello  *WorkerHello `protobuf:"bytes,1,opt,name=hello,proto3" json:"hello,omitempty"`   // the tasks the worker handles, sent when the stream is opened
+	Result *WorkResult  `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"` // the outcome of handling an assigned future
+}
+
+func (x *WorkerMessage) Reset() {
+	*x = WorkerMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerMessage) ProtoMessage() {}
+
+func (x *WorkerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerMessage.ProtoReflect.Descriptor instead.
+func (*WorkerMessage) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *WorkerMessage) GetHello() *WorkerHello {
+	if x != nil {
+		return x.Hello
+	}
+	return nil
+}
+
+func (x *WorkerMessage) GetResult() *WorkResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type WorkerHello struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                // the name of the worker, used in logs
+	Tasks       []string `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`              // the tasks the worker has registered handlers for
+	Concurrency int32    `protobuf:"varint,3,opt,name=concurrency,proto3" json:"concurrency,omitempty"` // the number of futures the worker handles at once (default 1)
+}
+
+func (x *WorkerHello) Reset() {
+	*x = WorkerHello{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerHello) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerHello) ProtoMessage() {}
+
+func (x *WorkerHello) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerHello.ProtoReflect.Descriptor instead.
+func (*WorkerHello) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *WorkerHello) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkerHello) GetTasks() []string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *WorkerHello) GetConcurrency() int32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+type WorkResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid    []byte `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`        // the id of the future that was handled
+	Success bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"` // if the handler succeeded
+	Error   *Error `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`      // the error returned by the handler if success is false
+	Result  []byte `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`    // the result returned by the handler if it succeeded
+}
+
+func (x *WorkResult) Reset() {
+	*x = WorkResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkResult) ProtoMessage() {}
+
+func (x *WorkResult) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkResult.ProtoReflect.Descriptor instead.
+func (*WorkResult) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *WorkResult) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *WorkResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *WorkResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *WorkResult) GetResult() []byte {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type WorkAssignment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind     WorkAssignment_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=api.WorkAssignment_Kind" json:"kind,omitempty"`                                                                   // what the worker should do with the future
+	Uuid     []byte              `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`                                                                                                 // the id of the future
+	Task     string              `protobuf:"bytes,3,opt,name=task,proto3" json:"task,omitempty"`                                                                                                 // the type of task of the future
+	Params   []byte              `protobuf:"bytes,4,opt,name=params,proto3" json:"params,omitempty"`                                                                                             // the params of the future or of its success or failure callback
+	Metadata map[string]string   `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // the metadata of the future
+	Attempt  int32               `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`                                                                                          // the attempt number of the future being handled
+	Error    *Error              `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                                                                                               // the error that caused the future to fail
+}
+
+func (x *WorkAssignment) Reset() {
+	*x = WorkAssignment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkAssignment) ProtoMessage() {}
+
+func (x *WorkAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkAssignment.ProtoReflect.Descriptor instead.
+func (*WorkAssignment) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *WorkAssignment) GetKind() WorkAssignment_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return WorkAssignment_HANDLE
+}
+
+func (x *WorkAssignment) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *WorkAssignment) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *WorkAssignment) GetParams() []byte {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *WorkAssignment) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *WorkAssignment) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *WorkAssignment) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type FutureInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid        []byte      `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                         // the id of the future
+	Task        string      `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`                         // the type of task of the future
+	State       FutureState `protobuf:"varint,3,opt,name=state,proto3,enum=api.FutureState" json:"state,omitempty"` // the current lifecycle state of the future
+	Attempts    int32       `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`                // the number of times the future has been handled
+	Error       *Error      `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`                       // the error that caused the future to fail, if any
+	Queued      int64       `protobuf:"varint,6,opt,name=queued,proto3" json:"queued,omitempty"`                    // when the future was queued or scheduled in unix nanoseconds
+	Started     int64       `protobuf:"varint,7,opt,name=started,proto3" json:"started,omitempty"`                  // when a worker last started handling the future in unix nanoseconds
+	Finished    int64       `protobuf:"varint,8,opt,name=finished,proto3" json:"finished,omitempty"`                // when the future completed in unix nanoseconds
+	Redelivered int32       `protobuf:"varint,9,opt,name=redelivered,proto3" json:"redelivered,omitempty"`          // the number of times the future was redelivered because the server stopped while it was being handled
+	Logs        []*LogEntry `protobuf:"bytes,10,rep,name=logs,proto3" json:"logs,omitempty"`                        // the most recent lines logged by the handler of the future if FutureLogLines is configured
+}
+
+func (x *FutureInfo) Reset() {
+	*x = FutureInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FutureInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FutureInfo) ProtoMessage() {}
+
+func (x *FutureInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FutureInfo.ProtoReflect.Descriptor instead.
+func (*FutureInfo) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *FutureInfo) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *FutureInfo) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *FutureInfo) GetState() FutureState {
+	if x != nil {
+		return x.State
+	}
+	return FutureState_UNKNOWN
+}
+
+func (x *FutureInfo) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *FutureInfo) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *FutureInfo) GetQueued() int64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *FutureInfo) GetStarted() int64 {
+	if x != nil {
+		return x.Started
+	}
+	return 0
+}
+
+func (x *FutureInfo) GetFinished() int64 {
+	if x != nil {
+		return x.Finished
+	}
+	return 0
+}
+
+func (x *FutureInfo) GetRedelivered() int32 {
+	if x != nil {
+		return x.Redelivered
+	}
+	return 0
+}
+
+func (x *FutureInfo) GetLogs() []*LogEntry {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+type DeadLetterRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuids [][]byte `protobuf:"bytes,1,rep,name=uuids,proto3" json:"uuids,omitempty"`  // only the dead letters with the specified ids (default all ids)
+	Task  string   `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`    // only the dead letters of the specified task (default all tasks)
+	Limit int32    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"` // the maximum number of the most recent dead letters to list (default all)
+}
+
+func (x *DeadLetterRequest) Reset() {
+	*x = DeadLetterRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeadLetterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetterRequest) ProtoMessage() {}
+
+func (x *DeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*DeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *DeadLetterRequest) GetUuids() [][]byte {
+	if x != nil {
+		return x.Uuids
+	}
+	return nil
+}
+
+func (x *DeadLetterRequest) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *DeadLetterRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DeadLetterReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Futures []*DeadLetter `protobuf:"bytes,1,rep,name=futures,proto3" json:"futures,omitempty"`  // the dead letters that were listed, oldest first
+	Count   int32         `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`     // the number of dead letters that were listed, re-driven, or purged
+	Success bool          `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"` // if the request succeeded or failed
+	Error   *Error        `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`      // the error if success is false
+}
+
+func (x *DeadLetterReply) Reset() {
+	*x = DeadLetterReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeadLetterReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetterReply) ProtoMessage() {}
+
+func (x *DeadLetterReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetterReply.ProtoReflect.Descriptor instead.
+func (*DeadLetterReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *DeadLetterReply) GetFutures() []*DeadLetter {
+	if x != nil {
+		return x.Futures
+	}
+	return nil
+}
+
+func (x *DeadLetterReply) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DeadLetterReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeadLetterReply) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type DeadLetter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid     []byte `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`          // the id of the future that failed
+	Task     string `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`          // the type of task of the future
+	Params   []byte `protobuf:"bytes,3,opt,name=params,proto3" json:"params,omitempty"`      // the params of the future
+	Error    *Error `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`        // the error that caused the future to fail
+	Attempts int32  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"` // the number of times the future was attempted
+	Failed   int64  `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`     // the time the future failed in unix nanoseconds
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *DeadLetter) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetParams() []byte {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type CompletedFuture struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid    []byte `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`        // the id of the future that completed
+	Task    string `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`        // the type of task that handled the future
+	Success bool   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"` // if the task succeeded or failed
+	Error   *Error `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`      // the error that caused the task to fail if success is false
+	Params  []byte `protobuf:"bytes,5,opt,name=params,proto3" json:"params,omitempty"`    // the success or failure parameters specified when the future was queued
+}
+
+func (x *CompletedFuture) Reset() {
+	*x = CompletedFuture{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CompletedFuture) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompletedFuture) ProtoMessage() {}
+
+func (x *CompletedFuture) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompletedFuture.ProtoReflect.Descriptor instead.
+func (*CompletedFuture) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *CompletedFuture) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *CompletedFuture) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *CompletedFuture) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CompletedFuture) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *CompletedFuture) GetParams() []byte {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type CompleteReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CompleteReply) Reset() {
+	*x = CompleteReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CompleteReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteReply) ProtoMessage() {}
+
+func (x *CompleteReply) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteReply.ProtoReflect.Descriptor instead.
+func (*CompleteReply) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{55}
+}
+
+type Error struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`      // the error code for identification purposes
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // a description of the error that occurred
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_radish_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_radish_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_radish_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *Error) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_radish_proto protoreflect.FileDescriptor
+
+var file_radish_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x72, 0x61, 0x64, 0x69, 0x73, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03,
+	0x61, 0x70, 0x69, 0x22, 0xaf, 0x03, 0x0a, 0x0c, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x66, 0x61, 0x69,
+	0x6c, 0x75, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x12, 0x17, 0x0a, 0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69,
+	0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x69,
+	0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x74, 0x61, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x03, 0x65, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x61, 0x79,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x27, 0x0a,
+	0x0f, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x3b, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x51,
+	0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x61, 0x72, 0x74,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc0, 0x01, 0x0a, 0x0a, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x21,
+	0x0a, 0x0c, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x71, 0x75, 0x65, 0x75, 0x65, 0x4c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x77,
+	0x61, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x65, 0x73, 0x74, 0x69, 0x6d,
+	0x61, 0x74, 0x65, 0x64, 0x57, 0x61, 0x69, 0x74, 0x22, 0x42, 0x0a, 0x11, 0x51, 0x75, 0x65, 0x75,
+	0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a,
+	0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x3c, 0x0a, 0x0f,
+	0x51, 0x75, 0x65, 0x75, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x29, 0x0a, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x22, 0x58, 0x0a, 0x0c, 0x53, 0x63,
+	0x61, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x77, 0x6f, 0x72,
+	0x6b, 0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66,
+	0x6f, 0x72, 0x63, 0x65, 0x22, 0x7e, 0x0a, 0x0a, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x72, 0x61, 0x69,
+	0x6e, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x72, 0x61, 0x69,
+	0x6e, 0x69, 0x6e, 0x67, 0x22, 0xbc, 0x01, 0x0a, 0x10, 0x41, 0x75, 0x74, 0x6f, 0x73, 0x63, 0x61,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x06, 0x74, 0x6f, 0x67,
+	0x67, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x41, 0x75, 0x74, 0x6f, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x2e, 0x54, 0x6f, 0x67, 0x67, 0x6c, 0x65, 0x52, 0x06, 0x74, 0x6f, 0x67, 0x67, 0x6c, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x69, 0x6e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x73, 0x22, 0x30, 0x0a, 0x06, 0x54, 0x6f, 0x67, 0x67, 0x6c, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x55,
+	0x4e, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x4e,
+	0x41, 0x42, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c,
+	0x45, 0x10, 0x02, 0x22, 0xc2, 0x01, 0x0a, 0x0e, 0x41, 0x75, 0x74, 0x6f, 0x73, 0x63, 0x61, 0x6c,
+	0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x69, 0x6e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x57, 0x6f, 0x72, 0x6b, 0x65,
+	0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x0d, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x5f, 0x0a, 0x09, 0x50, 0x69, 0x6e, 0x67, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xb8, 0x01, 0x0a, 0x0d, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x08, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x61, 0x73,
+	0x6b, 0x73, 0x22, 0x3f, 0x0a, 0x07, 0x53, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x09, 0x0a,
+	0x05, 0x54, 0x41, 0x53, 0x4b, 0x53, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x4f, 0x52, 0x4b,
+	0x45, 0x52, 0x53, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x5f, 0x46, 0x4c, 0x49, 0x47,
+	0x48, 0x54, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x43, 0x48, 0x45, 0x44, 0x55, 0x4c, 0x45,
+	0x52, 0x10, 0x03, 0x22, 0x94, 0x04, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x71, 0x75, 0x65, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x71, 0x75,
+	0x65, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x72, 0x61,
+	0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x72, 0x61,
+	0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x5f,
+	0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x69, 0x6e,
+	0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54,
+	0x61, 0x73, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12,
+	0x25, 0x0a, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x12, 0x37, 0x0a, 0x0d, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x0c, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x29, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x32, 0x0a, 0x09, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x09, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x93, 0x01, 0x0a, 0x0d, 0x57,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x62, 0x75, 0x73, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x62, 0x75, 0x73, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x69, 0x64, 0x6c, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x6f, 0x72,
+	0x6b, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73,
+	0x22, 0xaa, 0x01, 0x0a, 0x0a, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x75, 0x73,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x62, 0x75, 0x73, 0x79, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73,
+	0x6b, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x75, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x06, 0x66, 0x75, 0x74, 0x75, 0x72, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x22, 0x5b, 0x0a,
+	0x12, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x1b, 0x0a,
+	0x09, 0x69, 0x6e, 0x5f, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x69, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x22, 0x4a, 0x0a, 0x0f, 0x53, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x64, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6e,
+	0x65, 0x78, 0x74, 0x44, 0x75, 0x65, 0x22, 0x6c, 0x0a, 0x0a, 0x50, 0x65, 0x65, 0x72, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x12, 0x28, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x22, 0x2a, 0x0a, 0x0c, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e,
+	0x22, 0x5e, 0x0a, 0x0a, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x71, 0x75, 0x65, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x71,
+	0x75, 0x65, 0x75, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x22, 0xc9, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x04, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73,
+	0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x2b, 0x0a,
+	0x11, 0x63, 0x61, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f,
+	0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x63, 0x61, 0x75, 0x74, 0x69, 0x6f,
+	0x6e, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61,
+	0x73, 0x6b, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x74, 0x61, 0x73, 0x6b, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x22, 0xef, 0x01, 0x0a,
+	0x0b, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07,
+	0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x77,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x65,
+	0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x4c, 0x65,
+	0x76, 0x65, 0x6c, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x61, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74,
+	0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10,
+	0x63, 0x61, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64,
+	0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x61, 0x73, 0x6b, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12,
+	0x21, 0x0a, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x51, 0x75, 0x6f,
+	0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x12,
+	0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x35, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x23, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x22, 0xa2, 0x01, 0x0a, 0x08, 0x54, 0x61,
+	0x73, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x70, 0x65, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61,
+	0x69, 0x6c, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x66, 0x61, 0x69, 0x6c,
+	0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x65, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x65, 0x64, 0x22, 0x65,
+	0x0a, 0x0b, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x65,
+	0x76, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x69,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x16, 0x0a,
+	0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66,
+	0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x22, 0x6c, 0x0a, 0x08, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
+	0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x22, 0x3b, 0x0a, 0x0d, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x22, 0x49, 0x0a, 0x0b, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x41, 0x0a, 0x13, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x22, 0x61,
+	0x0a, 0x11, 0x53, 0x74, 0x61, 0x74, 0x73, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12,
+	0x30, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x73, 0x22, 0x83, 0x01, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x71,
+	0x75, 0x65, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x71, 0x75, 0x65, 0x75,
+	0x65, 0x12, 0x24, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x22, 0x71, 0x0a, 0x09, 0x54, 0x61, 0x73, 0x6b, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x75, 0x63, 0x63,
+	0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x75, 0x63,
+	0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x65, 0x64, 0x22, 0x4e, 0x0a, 0x0c, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61,
+	0x73, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73,
+	0x12, 0x28, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e,
+	0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x22, 0xd5, 0x01, 0x0a, 0x0b, 0x46,
+	0x75, 0x74, 0x75, 0x72, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61,
+	0x73, 0x6b, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x22, 0x26, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x75, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x27, 0x0a, 0x06,
+	0x66, 0x75, 0x74, 0x75, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x66,
+	0x75, 0x74, 0x75, 0x72, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12,
+	0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x28, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x7d, 0x0a, 0x10, 0x47,
+	0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x2d, 0x0a, 0x08, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xad, 0x01, 0x0a, 0x0c, 0x57,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12,
+	0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x12, 0x2b, 0x0a,
+	0x05, 0x73, 0x74, 0x65, 0x70, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x53, 0x74, 0x65, 0x70, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x05, 0x73, 0x74, 0x65, 0x70, 0x73, 0x22, 0xae, 0x01, 0x0a, 0x10, 0x57,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x53, 0x74, 0x65, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69,
+	0x64, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x9f, 0x01, 0x0a, 0x0b,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12,
+	0x1c, 0x0a, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x66,
+	0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x65,
+	0x64, 0x12, 0x2a, 0x0a, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x52, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x22, 0x97, 0x01,
+	0x0a, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x75, 0x74, 0x75, 0x72,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x20, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x23, 0x0a, 0x0d, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x49, 0x0a, 0x0b,
+	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x37, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x77, 0x61, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74,
+	0x22, 0x8a, 0x01, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x27, 0x0a, 0x06, 0x66, 0x75, 0x74, 0x75, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x06, 0x66, 0x75, 0x74, 0x75, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x60, 0x0a,
+	0x0d, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x26,
+	0x0a, 0x05, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52,
+	0x05, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x6f, 0x72,
+	0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22,
+	0x59, 0x0a, 0x0b, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x63,
+	0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x74, 0x0a, 0x0a, 0x57, 0x6f,
+	0x72, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x22, 0xf0, 0x02, 0x0a, 0x0e, 0x57, 0x6f, 0x72, 0x6b, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x41, 0x73, 0x73, 0x69,
+	0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x12, 0x3d, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x41, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x1a, 0x3b, 0x0a, 0x0d,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x38, 0x0a, 0x04, 0x4b, 0x69, 0x6e,
+	0x64, 0x12, 0x0a, 0x0a, 0x06, 0x48, 0x41, 0x4e, 0x44, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x0a, 0x0a,
+	0x06, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x55, 0x43,
+	0x43, 0x45, 0x53, 0x53, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52,
+	0x45, 0x10, 0x03, 0x22, 0xad, 0x02, 0x0a, 0x0a, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x20,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x12, 0x20,
+	0x0a, 0x0b, 0x72, 0x65, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64,
+	0x12, 0x21, 0x0a, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x6c,
+	0x6f, 0x67, 0x73, 0x22, 0x53, 0x0a, 0x11, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x75, 0x69, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x75, 0x75, 0x69, 0x64, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61,
+	0x73, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x8e, 0x01, 0x0a, 0x0f, 0x44, 0x65, 0x61,
+	0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x29, 0x0a, 0x07,
+	0x66, 0x75, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x52, 0x07,
+	0x66, 0x75, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xa2, 0x01, 0x0a, 0x0a, 0x44, 0x65,
+	0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b,
+	0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74,
+	0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x74,
+	0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x22, 0x8d,
+	0x01, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x46, 0x75, 0x74, 0x75,
+	0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0x0f,
+	0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x35, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2a, 0x78, 0x0a, 0x0b, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
+	0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x43, 0x48, 0x45, 0x44, 0x55, 0x4c, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x0a, 0x0a, 0x06, 0x51, 0x55, 0x45, 0x55, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0b, 0x0a,
+	0x07, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x55,
+	0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49,
+	0x4c, 0x45, 0x44, 0x10, 0x05, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x45,
+	0x44, 0x10, 0x06, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x07,
+	0x32, 0xd5, 0x09, 0x0a, 0x06, 0x52, 0x61, 0x64, 0x69, 0x73, 0x68, 0x12, 0x2d, 0x0a, 0x05, 0x51,
+	0x75, 0x65, 0x75, 0x65, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x51, 0x75,
+	0x65, 0x75, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x0a, 0x51, 0x75,
+	0x65, 0x75, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x51,
+	0x75, 0x65, 0x75, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x33, 0x0a, 0x07, 0x45, 0x6e, 0x71, 0x75,
+	0x65, 0x75, 0x65, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x51, 0x75, 0x65,
+	0x75, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x2d, 0x0a,
+	0x05, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x61,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x53, 0x63, 0x61, 0x6c, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x09,
+	0x41, 0x75, 0x74, 0x6f, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x41, 0x75, 0x74, 0x6f, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x6f, 0x73, 0x63, 0x61, 0x6c, 0x65,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x2a, 0x0a, 0x04, 0x50, 0x69, 0x6e,
+	0x67, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x32, 0x0a, 0x0a, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x51, 0x75,
+	0x65, 0x75, 0x65, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x72, 0x61,
+	0x69, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x06, 0x52, 0x65, 0x6c,
+	0x6f, 0x61, 0x64, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65,
+	0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x09, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x2b, 0x0a, 0x04, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x10,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x30, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x11, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x33, 0x0a, 0x09, 0x53, 0x65, 0x74, 0x53, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x0c, 0x53, 0x74,
+	0x61, 0x74, 0x73, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x18, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x41,
+	0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72,
+	0x73, 0x12, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x12, 0x44, 0x0a, 0x12, 0x52, 0x65, 0x64, 0x72, 0x69, 0x76, 0x65, 0x44, 0x65, 0x61, 0x64,
+	0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65,
+	0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x10, 0x50, 0x75, 0x72, 0x67, 0x65,
+	0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65,
+	0x74, 0x74, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x09, 0x47,
+	0x65, 0x74, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47,
+	0x65, 0x74, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72,
+	0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x57,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x0c, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x12, 0x35, 0x0a, 0x04, 0x57, 0x6f, 0x72, 0x6b, 0x12, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x13, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65,
+	0x6e, 0x74, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x32, 0x48, 0x0a, 0x0e, 0x52, 0x61, 0x64, 0x69,
+	0x73, 0x68, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x36, 0x0a, 0x08, 0x43, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d,
+	0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x46, 0x75, 0x74, 0x75, 0x72, 0x65, 0x1a, 0x12, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_radish_proto_rawDescOnce sync.Once
+	file_radish_proto_rawDescData = file_radish_proto_rawDesc
+)
+
+func file_radish_proto_rawDescGZIP() []byte {
+	file_radish_proto_rawDescOnce.Do(func() {
+		file_radish_proto_rawDescData = protoimpl.X.CompressGZIP(file_radish_proto_rawDescData)
+	})
+	return file_radish_proto_rawDescData
+}
+
+var file_radish_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_radish_proto_msgTypes = make([]protoimpl.MessageInfo, 59)
+var file_radish_proto_goTypes = []interface{}{
+	(FutureState)(0),             // 0: api.FutureState
+	(AutoscaleRequest_Toggle)(0), // 1: api.AutoscaleRequest.Toggle
+	(StatusRequest_Section)(0),   // 2: api.StatusRequest.Section
+	(WorkAssignment_Kind)(0),     // 3: api.WorkAssignment.Kind
+	(*QueueRequest)(nil),         // 4: api.QueueRequest
+	(*QueueReply)(nil),           // 5: api.QueueReply
+	(*QueueBatchRequest)(nil),    // 6: api.QueueBatchRequest
+	(*QueueBatchReply)(nil),      // 7: api.QueueBatchReply
+	(*ScaleRequest)(nil),         // 8: api.ScaleRequest
+	(*ScaleReply)(nil),           // 9: api.ScaleReply
+	(*AutoscaleRequest)(nil),     // 10: api.AutoscaleRequest
+	(*AutoscaleReply)(nil),       // 11: api.AutoscaleReply
+	(*PingRequest)(nil),          // 12: api.PingRequest
+	(*PingReply)(nil),            // 13: api.PingReply
+	(*StatusRequest)(nil),        // 14: api.StatusRequest
+	(*StatusReply)(nil),          // 15: api.StatusReply
+	(*WorkersStatus)(nil),        // 16: api.WorkersStatus
+	(*WorkerInfo)(nil),           // 17: api.WorkerInfo
+	(*RemoteWorkerStatus)(nil),   // 18: api.RemoteWorkerStatus
+	(*SchedulerStatus)(nil),      // 19: api.SchedulerStatus
+	(*PeerStatus)(nil),           // 20: api.PeerStatus
+	(*DrainRequest)(nil),         // 21: api.DrainRequest
+	(*DrainReply)(nil),           // 22: api.DrainReply
+	(*ReloadRequest)(nil),        // 23: api.ReloadRequest
+	(*ReloadReply)(nil),          // 24: api.ReloadReply
+	(*ListTasksRequest)(nil),     // 25: api.ListTasksRequest
+	(*ListTasksReply)(nil),       // 26: api.ListTasksReply
+	(*TaskInfo)(nil),             // 27: api.TaskInfo
+	(*LogsRequest)(nil),          // 28: api.LogsRequest
+	(*LogEntry)(nil),             // 29: api.LogEntry
+	(*ScriptRequest)(nil),        // 30: api.ScriptRequest
+	(*ScriptReply)(nil),          // 31: api.ScriptReply
+	(*StatsHistoryRequest)(nil),  // 32: api.StatsHistoryRequest
+	(*StatsHistoryReply)(nil),    // 33: api.StatsHistoryReply
+	(*StatsSnapshot)(nil),        // 34: api.StatsSnapshot
+	(*TaskStats)(nil),            // 35: api.TaskStats
+	(*WatchRequest)(nil),         // 36: api.WatchRequest
+	(*FutureEvent)(nil),          // 37: api.FutureEvent
+	(*GetFutureRequest)(nil),     // 38: api.GetFutureRequest
+	(*GetFutureReply)(nil),       // 39: api.GetFutureReply
+	(*GetWorkflowRequest)(nil),   // 40: api.GetWorkflowRequest
+	(*GetWorkflowReply)(nil),     // 41: api.GetWorkflowReply
+	(*WorkflowInfo)(nil),         // 42: api.WorkflowInfo
+	(*WorkflowStepInfo)(nil),     // 43: api.WorkflowStepInfo
+	(*GroupResult)(nil),          // 44: api.GroupResult
+	(*GroupMember)(nil),          // 45: api.GroupMember
+	(*CancelRequest)(nil),        // 46: api.CancelRequest
+	(*CancelReply)(nil),          // 47: api.CancelReply
+	(*ResultRequest)(nil),        // 48: api.ResultRequest
+	(*ResultReply)(nil),          // 49: api.ResultReply
+	(*WorkerMessage)(nil),        // 50: api.WorkerMessage
+	(*WorkerHello)(nil),          // 51: api.WorkerHello
+	(*WorkResult)(nil),           // 52: api.WorkResult
+	(*WorkAssignment)(nil),       // 53: api.WorkAssignment
+	(*FutureInfo)(nil),           // 54: api.FutureInfo
+	(*DeadLetterRequest)(nil),    // 55: api.DeadLetterRequest
+	(*DeadLetterReply)(nil),      // 56: api.DeadLetterReply
+	(*DeadLetter)(nil),           // 57: api.DeadLetter
+	(*CompletedFuture)(nil),      // 58: api.CompletedFuture
+	(*CompleteReply)(nil),        // 59: api.CompleteReply
+	(*Error)(nil),                // 60: api.Error
+	nil,                          // 61: api.QueueRequest.MetadataEntry
+	nil,                          // 62: api.WorkAssignment.MetadataEntry
+}
+var file_radish_proto_depIdxs = []int32{
+	61, // 0: api.QueueRequest.metadata:type_name -> api.QueueRequest.MetadataEntry
+	60, // 1: api.QueueReply.error:type_name -> api.Error
+	4,  // 2: api.QueueBatchRequest.requests:type_name -> api.QueueRequest
+	5,  // 3: api.QueueBatchReply.replies:type_name -> api.QueueReply
+	60, // 4: api.ScaleReply.error:type_name -> api.Error
+	1,  // 5: api.AutoscaleRequest.toggle:type_name -> api.AutoscaleRequest.Toggle
+	60, // 6: api.AutoscaleReply.error:type_name -> api.Error
+	2,  // 7: api.StatusRequest.sections:type_name -> api.StatusRequest.Section
+	27, // 8: api.StatusReply.counts:type_name -> api.TaskInfo
+	20, // 9: api.StatusReply.peers:type_name -> api.PeerStatus
+	16, // 10: api.StatusReply.worker_status:type_name -> api.WorkersStatus
+	54, // 11: api.StatusReply.running:type_name -> api.FutureInfo
+	19, // 12: api.StatusReply.scheduler:type_name -> api.SchedulerStatus
+	60, // 13: api.StatusReply.error:type_name -> api.Error
+	18, // 14: api.WorkersStatus.remote:type_name -> api.RemoteWorkerStatus
+	17, // 15: api.WorkersStatus.workers:type_name -> api.WorkerInfo
+	15, // 16: api.PeerStatus.status:type_name -> api.StatusReply
+	60, // 17: api.PeerStatus.error:type_name -> api.Error
+	60, // 18: api.DrainReply.error:type_name -> api.Error
+	60, // 19: api.ReloadReply.error:type_name -> api.Error
+	27, // 20: api.ListTasksReply.tasks:type_name -> api.TaskInfo
+	60, // 21: api.ScriptReply.error:type_name -> api.Error
+	34, // 22: api.StatsHistoryReply.snapshots:type_name -> api.StatsSnapshot
+	35, // 23: api.StatsSnapshot.tasks:type_name -> api.TaskStats
+	0,  // 24: api.WatchRequest.states:type_name -> api.FutureState
+	0,  // 25: api.FutureEvent.state:type_name -> api.FutureState
+	60, // 26: api.FutureEvent.error:type_name -> api.Error
+	54, // 27: api.GetFutureReply.future:type_name -> api.FutureInfo
+	60, // 28: api.GetFutureReply.error:type_name -> api.Error
+	42, // 29: api.GetWorkflowReply.workflow:type_name -> api.WorkflowInfo
+	60, // 30: api.GetWorkflowReply.error:type_name -> api.Error
+	0,  // 31: api.WorkflowInfo.state:type_name -> api.FutureState
+	43, // 32: api.WorkflowInfo.steps:type_name -> api.WorkflowStepInfo
+	0,  // 33: api.WorkflowStepInfo.state:type_name -> api.FutureState
+	60, // 34: api.WorkflowStepInfo.error:type_name -> api.Error
+	45, // 35: api.GroupResult.members:type_name -> api.GroupMember
+	0,  // 36: api.GroupMember.state:type_name -> api.FutureState
+	60, // 37: api.GroupMember.error:type_name -> api.Error
+	60, // 38: api.CancelReply.error:type_name -> api.Error
+	54, // 39: api.ResultReply.future:type_name -> api.FutureInfo
+	60, // 40: api.ResultReply.error:type_name -> api.Error
+	51, // 41: api.WorkerMessage.hello:type_name -> api.WorkerHello
+	52, // 42: api.WorkerMessage.result:type_name -> api.WorkResult
+	60, // 43: api.WorkResult.error:type_name -> api.Error
+	3,  // 44: api.WorkAssignment.kind:type_name -> api.WorkAssignment.Kind
+	62, // 45: api.WorkAssignment.metadata:type_name -> api.WorkAssignment.MetadataEntry
+	60, // 46: api.WorkAssignment.error:type_name -> api.Error
+	0,  // 47: api.FutureInfo.state:type_name -> api.FutureState
+	60, // 48: api.FutureInfo.error:type_name -> api.Error
+	29, // 49: api.FutureInfo.logs:type_name -> api.LogEntry
+	57, // 50: api.DeadLetterReply.futures:type_name -> api.DeadLetter
+	60, // 51: api.DeadLetterReply.error:type_name -> api.Error
+	60, // 52: api.DeadLetter.error:type_name -> api.Error
+	60, // 53: api.CompletedFuture.error:type_name -> api.Error
+	4,  // 54: api.Radish.Queue:input_type -> api.QueueRequest
+	6,  // 55: api.Radish.QueueBatch:input_type -> api.QueueBatchRequest
+	4,  // 56: api.Radish.Enqueue:input_type -> api.QueueRequest
+	8,  // 57: api.Radish.Scale:input_type -> api.ScaleRequest
+	10, // 58: api.Radish.Autoscale:input_type -> api.AutoscaleRequest
+	14, // 59: api.Radish.Status:input_type -> api.StatusRequest
+	12, // 60: api.Radish.Ping:input_type -> api.PingRequest
+	21, // 61: api.Radish.DrainQueue:input_type -> api.DrainRequest
+	23, // 62: api.Radish.Reload:input_type -> api.ReloadRequest
+	25, // 63: api.Radish.ListTasks:input_type -> api.ListTasksRequest
+	28, // 64: api.Radish.Logs:input_type -> api.LogsRequest
+	36, // 65: api.Radish.Watch:input_type -> api.WatchRequest
+	30, // 66: api.Radish.SetScript:input_type -> api.ScriptRequest
+	32, // 67: api.Radish.StatsHistory:input_type -> api.StatsHistoryRequest
+	55, // 68: api.Radish.ListDeadLetters:input_type -> api.DeadLetterRequest
+	55, // 69: api.Radish.RedriveDeadLetters:input_type -> api.DeadLetterRequest
+	55, // 70: api.Radish.PurgeDeadLetters:input_type -> api.DeadLetterRequest
+	38, // 71: api.Radish.GetFuture:input_type -> api.GetFutureRequest
+	40, // 72: api.Radish.GetWorkflow:input_type -> api.GetWorkflowRequest
+	48, // 73: api.Radish.Result:input_type -> api.ResultRequest
+	46, // 74: api.Radish.CancelFuture:input_type -> api.CancelRequest
+	50, // 75: api.Radish.Work:input_type -> api.WorkerMessage
+	58, // 76: api.RadishCallback.Complete:input_type -> api.CompletedFuture
+	5,  // 77: api.Radish.Queue:output_type -> api.QueueReply
+	7,  // 78: api.Radish.QueueBatch:output_type -> api.QueueBatchReply
+	5,  // 79: api.Radish.Enqueue:output_type -> api.QueueReply
+	9,  // 80: api.Radish.Scale:output_type -> api.ScaleReply
+	11, // 81: api.Radish.Autoscale:output_type -> api.AutoscaleReply
+	15, // 82: api.Radish.Status:output_type -> api.StatusReply
+	13, // 83: api.Radish.Ping:output_type -> api.PingReply
+	22, // 84: api.Radish.DrainQueue:output_type -> api.DrainReply
+	24, // 85: api.Radish.Reload:output_type -> api.ReloadReply
+	26, // 86: api.Radish.ListTasks:output_type -> api.ListTasksReply
+	29, // 87: api.Radish.Logs:output_type -> api.LogEntry
+	37, // 88: api.Radish.Watch:output_type -> api.FutureEvent
+	31, // 89: api.Radish.SetScript:output_type -> api.ScriptReply
+	33, // 90: api.Radish.StatsHistory:output_type -> api.StatsHistoryReply
+	56, // 91: api.Radish.ListDeadLetters:output_type -> api.DeadLetterReply
+	56, // 92: api.Radish.RedriveDeadLetters:output_type -> api.DeadLetterReply
+	56, // 93: api.Radish.PurgeDeadLetters:output_type -> api.DeadLetterReply
+	39, // 94: api.Radish.GetFuture:output_type -> api.GetFutureReply
+	41, // 95: api.Radish.GetWorkflow:output_type -> api.GetWorkflowReply
+	49, // 96: api.Radish.Result:output_type -> api.ResultReply
+	47, // 97: api.Radish.CancelFuture:output_type -> api.CancelReply
+	53, // 98: api.Radish.Work:output_type -> api.WorkAssignment
+	59, // 99: api.RadishCallback.Complete:output_type -> api.CompleteReply
+	77, // [77:100] is the sub-list for method output_type
+	54, // [54:77] is the sub-list for method input_type
+	54, // [54:54] is the sub-list for extension type_name
+	54, // [54:54] is the sub-list for extension extendee
+	0,  // [0:54] is the sub-list for field type_name
+}
+
+func init() { file_radish_proto_init() }
+func file_radish_proto_init() {
+	if File_radish_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_radish_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueueRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueueReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueueBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueueBatchReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScaleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScaleReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AutoscaleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AutoscaleReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PingReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkersStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoteWorkerStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SchedulerStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrainRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrainReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReloadRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReloadReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTasksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTasksReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TaskInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScriptRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScriptReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatsHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatsHistoryReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatsSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TaskStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FutureEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFutureRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFutureReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetWorkflowRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetWorkflowReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkflowInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkflowStepInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupMember); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResultRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResultReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerHello); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkAssignment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FutureInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeadLetterRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeadLetterReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeadLetter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompletedFuture); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompleteReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_radish_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Error); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_radish_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   59,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_radish_proto_goTypes,
+		DependencyIndexes: file_radish_proto_depIdxs,
+		EnumInfos:         file_radish_proto_enumTypes,
+		MessageInfos:      file_radish_proto_msgTypes,
+	}.Build()
+	File_radish_proto = out.File
+	file_radish_proto_rawDesc = nil
+	file_radish_proto_goTypes = nil
+	file_radish_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// RadishClient is the client API for Radish service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RadishClient interface {
+	Queue(ctx context.Context, in *QueueRequest, opts ...grpc.CallOption) (*QueueReply, error)
+	QueueBatch(ctx context.Context, in *QueueBatchRequest, opts ...grpc.CallOption) (*QueueBatchReply, error)
+	Enqueue(ctx context.Context, opts ...grpc.CallOption) (Radish_EnqueueClient, error)
+	Scale(ctx context.Context, in *ScaleRequest, opts ...grpc.CallOption) (*ScaleReply, error)
+	Autoscale(ctx context.Context, in *AutoscaleRequest, opts ...grpc.CallOption) (*AutoscaleReply, error)
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error)
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error)
+	DrainQueue(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainReply, error)
+	Reload(ctx context.Context, in *ReloadRequest, opts ...grpc.CallOption) (*ReloadReply, error)
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksReply, error)
+	Logs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (Radish_LogsClient, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Radish_WatchClient, error)
+	SetScript(ctx context.Context, in *ScriptRequest, opts ...grpc.CallOption) (*ScriptReply, error)
+	StatsHistory(ctx context.Context, in *StatsHistoryRequest, opts ...grpc.CallOption) (*StatsHistoryReply, error)
+	ListDeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*DeadLetterReply, error)
+	RedriveDeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*DeadLetterReply, error)
+	PurgeDeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*DeadLetterReply, error)
+	GetFuture(ctx context.Context, in *GetFutureRequest, opts ...grpc.CallOption) (*GetFutureReply, error)
+	GetWorkflow(ctx context.Context, in *GetWorkflowRequest, opts ...grpc.CallOption) (*GetWorkflowReply, error)
+	Result(ctx context.Context, in *ResultRequest, opts ...grpc.CallOption) (*ResultReply, error)
+	CancelFuture(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelReply, error)
+	Work(ctx context.Context, opts ...grpc.CallOption) (Radish_WorkClient, error)
+}
+
+type radishClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRadishClient(cc grpc.ClientConnInterface) RadishClient {
+	return &radishClient{cc}
+}
+
+func (c *radishClient) Queue(ctx context.Context, in *QueueRequest, opts ...grpc.CallOption) (*QueueReply, error) {
+	out := new(QueueReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/Queue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) QueueBatch(ctx context.Context, in *QueueBatchRequest, opts ...grpc.CallOption) (*QueueBatchReply, error) {
+	out := new(QueueBatchReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/QueueBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) Enqueue(ctx context.Context, opts ...grpc.CallOption) (Radish_EnqueueClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Radish_serviceDesc.Streams[0], "/api.Radish/Enqueue", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &radishEnqueueClient{stream}
+	return x, nil
+}
+
+type Radish_EnqueueClient interface {
+	Send(*QueueRequest) error
+	Recv() (*QueueReply, error)
+	grpc.ClientStream
+}
+
+type radishEnqueueClient struct {
+	grpc.ClientStream
+}
+
+func (x *radishEnqueueClient) Send(m *QueueRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *radishEnqueueClient) Recv() (*QueueReply, error) {
+	m := new(QueueReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *radishClient) Scale(ctx context.Context, in *ScaleRequest, opts ...grpc.CallOption) (*ScaleReply, error) {
+	out := new(ScaleReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/Scale", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) Autoscale(ctx context.Context, in *AutoscaleRequest, opts ...grpc.CallOption) (*AutoscaleReply, error) {
+	out := new(AutoscaleReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/Autoscale", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error) {
+	out := new(StatusReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error) {
+	out := new(PingReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/Ping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) DrainQueue(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainReply, error) {
+	out := new(DrainReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/DrainQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) Reload(ctx context.Context, in *ReloadRequest, opts ...grpc.CallOption) (*ReloadReply, error) {
+	out := new(ReloadReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/Reload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksReply, error) {
+	out := new(ListTasksReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/ListTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) Logs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (Radish_LogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Radish_serviceDesc.Streams[1], "/api.Radish/Logs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &radishLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Radish_LogsClient interface {
+	Recv() (*LogEntry, error)
+	grpc.ClientStream
+}
+
+type radishLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *radishLogsClient) Recv() (*LogEntry, error) {
+	m := new(LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *radishClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Radish_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Radish_serviceDesc.Streams[2], "/api.Radish/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &radishWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Radish_WatchClient interface {
+	Recv() (*FutureEvent, error)
+	grpc.ClientStream
+}
+
+type radishWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *radishWatchClient) Recv() (*FutureEvent, error) {
+	m := new(FutureEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *radishClient) SetScript(ctx context.Context, in *ScriptRequest, opts ...grpc.CallOption) (*ScriptReply, error) {
+	out := new(ScriptReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/SetScript", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) StatsHistory(ctx context.Context, in *StatsHistoryRequest, opts ...grpc.CallOption) (*StatsHistoryReply, error) {
+	out := new(StatsHistoryReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/StatsHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) ListDeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*DeadLetterReply, error) {
+	out := new(DeadLetterReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/ListDeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) RedriveDeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*DeadLetterReply, error) {
+	out := new(DeadLetterReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/RedriveDeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) PurgeDeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*DeadLetterReply, error) {
+	out := new(DeadLetterReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/PurgeDeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) GetFuture(ctx context.Context, in *GetFutureRequest, opts ...grpc.CallOption) (*GetFutureReply, error) {
+	out := new(GetFutureReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/GetFuture", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) GetWorkflow(ctx context.Context, in *GetWorkflowRequest, opts ...grpc.CallOption) (*GetWorkflowReply, error) {
+	out := new(GetWorkflowReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/GetWorkflow", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) Result(ctx context.Context, in *ResultRequest, opts ...grpc.CallOption) (*ResultReply, error) {
+	out := new(ResultReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/Result", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) CancelFuture(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelReply, error) {
+	out := new(CancelReply)
+	err := c.cc.Invoke(ctx, "/api.Radish/CancelFuture", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *radishClient) Work(ctx context.Context, opts ...grpc.CallOption) (Radish_WorkClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Radish_serviceDesc.Streams[3], "/api.Radish/Work", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &radishWorkClient{stream}
+	return x, nil
+}
+
+type Radish_WorkClient interface {
+	Send(*WorkerMessage) error
+	Recv() (*WorkAssignment, error)
+	grpc.ClientStream
+}
+
+type radishWorkClient struct {
+	grpc.ClientStream
+}
+
+func (x *radishWorkClient) Send(m *WorkerMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *radishWorkClient) Recv() (*WorkAssignment, error) {
+	m := new(WorkAssignment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RadishServer is the server API for Radish service.
+type RadishServer interface {
+	Queue(context.Context, *QueueRequest) (*QueueReply, error)
+	QueueBatch(context.Context, *QueueBatchRequest) (*QueueBatchReply, error)
+	Enqueue(Radish_EnqueueServer) error
+	Scale(context.Context, *ScaleRequest) (*ScaleReply, error)
+	Autoscale(context.Context, *AutoscaleRequest) (*AutoscaleReply, error)
+	Status(context.Context, *StatusRequest) (*StatusReply, error)
+	Ping(context.Context, *PingRequest) (*PingReply, error)
+	DrainQueue(context.Context, *DrainRequest) (*DrainReply, error)
+	Reload(context.Context, *ReloadRequest) (*ReloadReply, error)
+	ListTasks(context.Context, *ListTasksRequest) (*ListTasksReply, error)
+	Logs(*LogsRequest, Radish_LogsServer) error
+	Watch(*WatchRequest, Radish_WatchServer) error
+	SetScript(context.Context, *ScriptRequest) (*ScriptReply, error)
+	StatsHistory(context.Context, *StatsHistoryRequest) (*StatsHistoryReply, error)
+	ListDeadLetters(context.Context, *DeadLetterRequest) (*DeadLetterReply, error)
+	RedriveDeadLetters(context.Context, *DeadLetterRequest) (*DeadLetterReply, error)
+	PurgeDeadLetters(context.Context, *DeadLetterRequest) (*DeadLetterReply, error)
+	GetFuture(context.Context, *GetFutureRequest) (*GetFutureReply, error)
+	GetWorkflow(context.Context, *GetWorkflowRequest) (*GetWorkflowReply, error)
+	Result(context.Context, *ResultRequest) (*ResultReply, error)
+	CancelFuture(context.Context, *CancelRequest) (*CancelReply, error)
+	Work(Radish_WorkServer) error
+}
+
+// UnimplementedRadishServer can be embedded to have forward compatible implementations.
+type UnimplementedRadishServer struct {
+}
+
+func (*UnimplementedRadishServer) Queue(context.Context, *QueueRequest) (*QueueReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Queue not implemented")
+}
+func (*UnimplementedRadishServer) QueueBatch(context.Context, *QueueBatchRequest) (*QueueBatchReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueueBatch not implemented")
+}
+func (*UnimplementedRadishServer) Enqueue(Radish_EnqueueServer) error {
+	return status.Errorf(codes.Unimplemented, "method Enqueue not implemented")
+}
+func (*UnimplementedRadishServer) Scale(context.Context, *ScaleRequest) (*ScaleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scale not implemented")
+}
+func (*UnimplementedRadishServer) Autoscale(context.Context, *AutoscaleRequest) (*AutoscaleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Autoscale not implemented")
+}
+func (*UnimplementedRadishServer) Status(context.Context, *StatusRequest) (*StatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (*UnimplementedRadishServer) Ping(context.Context, *PingRequest) (*PingReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (*UnimplementedRadishServer) DrainQueue(context.Context, *DrainRequest) (*DrainReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainQueue not implemented")
+}
+func (*UnimplementedRadishServer) Reload(context.Context, *ReloadRequest) (*ReloadReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reload not implemented")
+}
+func (*UnimplementedRadishServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (*UnimplementedRadishServer) Logs(*LogsRequest, Radish_LogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Logs not implemented")
+}
+func (*UnimplementedRadishServer) Watch(*WatchRequest, Radish_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (*UnimplementedRadishServer) SetScript(context.Context, *ScriptRequest) (*ScriptReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetScript not implemented")
+}
+func (*UnimplementedRadishServer) StatsHistory(context.Context, *StatsHistoryRequest) (*StatsHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StatsHistory not implemented")
+}
+func (*UnimplementedRadishServer) ListDeadLetters(context.Context, *DeadLetterRequest) (*DeadLetterReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (*UnimplementedRadishServer) RedriveDeadLetters(context.Context, *DeadLetterRequest) (*DeadLetterReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedriveDeadLetters not implemented")
+}
+func (*UnimplementedRadishServer) PurgeDeadLetters(context.Context, *DeadLetterRequest) (*DeadLetterReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeDeadLetters not implemented")
+}
+func (*UnimplementedRadishServer) GetFuture(context.Context, *GetFutureRequest) (*GetFutureReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFuture not implemented")
+}
+func (*UnimplementedRadishServer) GetWorkflow(context.Context, *GetWorkflowRequest) (*GetWorkflowReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflow not implemented")
+}
+func (*UnimplementedRadishServer) Result(context.Context, *ResultRequest) (*ResultReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Result not implemented")
+}
+func (*UnimplementedRadishServer) CancelFuture(context.Context, *CancelRequest) (*CancelReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelFuture not implemented")
+}
+func (*UnimplementedRadishServer) Work(Radish_WorkServer) error {
+	return status.Errorf(codes.Unimplemented, "method Work not implemented")
+}
+
+func RegisterRadishServer(s *grpc.Server, srv RadishServer) {
+	s.RegisterService(&_Radish_serviceDesc, srv)
+}
+
+func _Radish_Queue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).Queue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/Queue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).Queue(ctx, req.(*QueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_QueueBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).QueueBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/QueueBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).QueueBatch(ctx, req.(*QueueBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_Enqueue_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RadishServer).Enqueue(&radishEnqueueServer{stream})
+}
+
+type Radish_EnqueueServer interface {
+	Send(*QueueReply) error
+	Recv() (*QueueRequest, error)
+	grpc.ServerStream
+}
+
+type radishEnqueueServer struct {
+	grpc.ServerStream
+}
+
+func (x *radishEnqueueServer) Send(m *QueueReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *radishEnqueueServer) Recv() (*QueueRequest, error) {
+	m := new(QueueRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Radish_Scale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ScaleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RadishServer).Scale(ctx, in)
+		return srv.(RadishServer).Scale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/Scale",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).Scale(ctx, req.(*ScaleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_Autoscale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AutoscaleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).Autoscale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/Autoscale",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).Autoscale(ctx, req.(*AutoscaleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_DrainQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).DrainQueue(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Radish/Scale",
+		FullMethod: "/api.Radish/DrainQueue",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RadishServer).Scale(ctx, req.(*ScaleRequest))
+		return srv.(RadishServer).DrainQueue(ctx, req.(*DrainRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Radish_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StatusRequest)
+func _Radish_Reload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RadishServer).Status(ctx, in)
+		return srv.(RadishServer).Reload(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Radish/Status",
+		FullMethod: "/api.Radish/Reload",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RadishServer).Status(ctx, req.(*StatusRequest))
+		return srv.(RadishServer).Reload(ctx, req.(*ReloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/ListTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RadishServer).Logs(m, &radishLogsServer{stream})
+}
+
+type Radish_LogsServer interface {
+	Send(*LogEntry) error
+	grpc.ServerStream
+}
+
+type radishLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *radishLogsServer) Send(m *LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Radish_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RadishServer).Watch(m, &radishWatchServer{stream})
+}
+
+type Radish_WatchServer interface {
+	Send(*FutureEvent) error
+	grpc.ServerStream
+}
+
+type radishWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *radishWatchServer) Send(m *FutureEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Radish_SetScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).SetScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/SetScript",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).SetScript(ctx, req.(*ScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_StatsHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).StatsHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/StatsHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).StatsHistory(ctx, req.(*StatsHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/ListDeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).ListDeadLetters(ctx, req.(*DeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_RedriveDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).RedriveDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/RedriveDeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).RedriveDeadLetters(ctx, req.(*DeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_PurgeDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).PurgeDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/PurgeDeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).PurgeDeadLetters(ctx, req.(*DeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_GetFuture_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFutureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).GetFuture(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/GetFuture",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).GetFuture(ctx, req.(*GetFutureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_GetWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).GetWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/GetWorkflow",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).GetWorkflow(ctx, req.(*GetWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_Result_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).Result(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/Result",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).Result(ctx, req.(*ResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Radish_CancelFuture_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishServer).CancelFuture(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Radish/CancelFuture",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishServer).CancelFuture(ctx, req.(*CancelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Radish_Work_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RadishServer).Work(&radishWorkServer{stream})
+}
+
+type Radish_WorkServer interface {
+	Send(*WorkAssignment) error
+	Recv() (*WorkerMessage, error)
+	grpc.ServerStream
+}
+
+type radishWorkServer struct {
+	grpc.ServerStream
+}
+
+func (x *radishWorkServer) Send(m *WorkAssignment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *radishWorkServer) Recv() (*WorkerMessage, error) {
+	m := new(WorkerMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Radish_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.Radish",
 	HandlerType: (*RadishServer)(nil),
@@ -529,14 +6538,169 @@ var _Radish_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Queue",
 			Handler:    _Radish_Queue_Handler,
 		},
+		{
+			MethodName: "QueueBatch",
+			Handler:    _Radish_QueueBatch_Handler,
+		},
 		{
 			MethodName: "Scale",
 			Handler:    _Radish_Scale_Handler,
 		},
+		{
+			MethodName: "Autoscale",
+			Handler:    _Radish_Autoscale_Handler,
+		},
 		{
 			MethodName: "Status",
 			Handler:    _Radish_Status_Handler,
 		},
+		{
+			MethodName: "Ping",
+			Handler:    _Radish_Ping_Handler,
+		},
+		{
+			MethodName: "DrainQueue",
+			Handler:    _Radish_DrainQueue_Handler,
+		},
+		{
+			MethodName: "Reload",
+			Handler:    _Radish_Reload_Handler,
+		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _Radish_ListTasks_Handler,
+		},
+		{
+			MethodName: "SetScript",
+			Handler:    _Radish_SetScript_Handler,
+		},
+		{
+			MethodName: "StatsHistory",
+			Handler:    _Radish_StatsHistory_Handler,
+		},
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _Radish_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "RedriveDeadLetters",
+			Handler:    _Radish_RedriveDeadLetters_Handler,
+		},
+		{
+			MethodName: "PurgeDeadLetters",
+			Handler:    _Radish_PurgeDeadLetters_Handler,
+		},
+		{
+			MethodName: "GetFuture",
+			Handler:    _Radish_GetFuture_Handler,
+		},
+		{
+			MethodName: "GetWorkflow",
+			Handler:    _Radish_GetWorkflow_Handler,
+		},
+		{
+			MethodName: "Result",
+			Handler:    _Radish_Result_Handler,
+		},
+		{
+			MethodName: "CancelFuture",
+			Handler:    _Radish_CancelFuture_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Enqueue",
+			Handler:       _Radish_Enqueue_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Logs",
+			Handler:       _Radish_Logs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Radish_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Work",
+			Handler:       _Radish_Work_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "radish.proto",
+}
+
+// RadishCallbackClient is the client API for RadishCallback service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RadishCallbackClient interface {
+	Complete(ctx context.Context, in *CompletedFuture, opts ...grpc.CallOption) (*CompleteReply, error)
+}
+
+type radishCallbackClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRadishCallbackClient(cc grpc.ClientConnInterface) RadishCallbackClient {
+	return &radishCallbackClient{cc}
+}
+
+func (c *radishCallbackClient) Complete(ctx context.Context, in *CompletedFuture, opts ...grpc.CallOption) (*CompleteReply, error) {
+	out := new(CompleteReply)
+	err := c.cc.Invoke(ctx, "/api.RadishCallback/Complete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RadishCallbackServer is the server API for RadishCallback service.
+type RadishCallbackServer interface {
+	Complete(context.Context, *CompletedFuture) (*CompleteReply, error)
+}
+
+// UnimplementedRadishCallbackServer can be embedded to have forward compatible implementations.
+type UnimplementedRadishCallbackServer struct {
+}
+
+func (*UnimplementedRadishCallbackServer) Complete(context.Context, *CompletedFuture) (*CompleteReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Complete not implemented")
+}
+
+func RegisterRadishCallbackServer(s *grpc.Server, srv RadishCallbackServer) {
+	s.RegisterService(&_RadishCallback_serviceDesc, srv)
+}
+
+func _RadishCallback_Complete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompletedFuture)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RadishCallbackServer).Complete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.RadishCallback/Complete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RadishCallbackServer).Complete(ctx, req.(*CompletedFuture))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RadishCallback_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.RadishCallback",
+	HandlerType: (*RadishCallbackServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Complete",
+			Handler:    _RadishCallback_Complete_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "radish.proto",
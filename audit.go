@@ -0,0 +1,119 @@
+package radish
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/x/out"
+)
+
+// Audit events recorded for each future.
+const (
+	AuditQueued    = "queued"    // the future was queued or scheduled by a client
+	AuditSucceeded = "succeeded" // the future completed successfully
+	AuditFailed    = "failed"    // the future failed permanently or was dropped
+	AuditCanceled  = "canceled"  // the future was canceled
+)
+
+// AuditRecord records who queued a future and what the final outcome of the future was.
+type AuditRecord struct {
+	Event    string    `json:"event"`              // queued, succeeded, failed, or canceled
+	Time     time.Time `json:"time"`               // when the event occurred
+	ID       string    `json:"id"`                 // the id of the future
+	Task     string    `json:"task"`               // the task of the future
+	Client   string    `json:"client,omitempty"`   // the identity of the API client that queued the future, empty if delayed in-process
+	Attempts int       `json:"attempts,omitempty"` // the number of times the future was handled
+	Error    string    `json:"error,omitempty"`    // the error that caused the future to fail or be canceled
+}
+
+// Auditor receives an audit record when each future is queued and when it completes,
+// e.g. to send the records to a compliance system. Audit is called synchronously by the
+// go routine that queued or handled the future, so it must be thread safe and should
+// return quickly.
+type Auditor interface {
+	Audit(record *AuditRecord) error
+}
+
+// auditLog is an Auditor that appends records to a file as newline-delimited JSON.
+type auditLog struct {
+	sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// openAuditor returns the auditor of the config, opening the AuditLog if no Auditor is
+// specified, or nil if the queue is not audited.
+func openAuditor(config *Config) (_ Auditor, err error) {
+	if config.Auditor != nil {
+		return config.Auditor, nil
+	}
+
+	if config.AuditLog == "" {
+		return nil, nil
+	}
+
+	var f *os.File
+	if f, err = os.OpenFile(config.AuditLog, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600); err != nil {
+		return nil, Errorf(CodeInvalidConfig, "could not open audit log: %s", err)
+	}
+	return &auditLog{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (a *auditLog) Audit(record *AuditRecord) error {
+	a.Lock()
+	defer a.Unlock()
+	return a.enc.Encode(record)
+}
+
+func (a *auditLog) Close() error {
+	a.Lock()
+	defer a.Unlock()
+	return a.file.Close()
+}
+
+// closeAuditLog closes the audit log opened by the queue; auditors specified in the
+// config are owned by the application.
+func (r *Radish) closeAuditLog() error {
+	if log, ok := r.auditor.(*auditLog); ok {
+		return log.Close()
+	}
+	return nil
+}
+
+// audit records the event of the future with the auditor, if any. Failures to audit are
+// logged rather than failing the future.
+func (r *Radish) audit(event string, future *Future, err error) {
+	if r.auditor == nil {
+		return
+	}
+
+	record := &AuditRecord{
+		Event:    event,
+		Time:     time.Now(),
+		ID:       future.ID.String(),
+		Task:     future.Task,
+		Client:   future.client,
+		Attempts: future.Attempts,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if err = r.auditor.Audit(record); err != nil {
+		r.logf(out.LevelWarn, future.Task, "could not audit %s future %s: %s", future.Task, future.ID, err)
+	}
+}
+
+// auditOutcome records the final outcome of the future with the auditor, if any.
+func (r *Radish) auditOutcome(future *Future, err error) {
+	switch {
+	case future.canceled:
+		r.audit(AuditCanceled, future, err)
+	case err != nil:
+		r.audit(AuditFailed, future, err)
+	default:
+		r.audit(AuditSucceeded, future, nil)
+	}
+}
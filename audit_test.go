@@ -0,0 +1,60 @@
+package radish_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	wg := new(sync.WaitGroup)
+	good := &testTask{wg: wg, name: "good"}
+	bad := &testTask{wg: wg, name: "bad", onHandle: func(id uuid.UUID, params []byte) error { return errors.New("whoops") }}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn", AuditLog: path}, good, bad)
+	require.NoError(t, err)
+
+	// The identity of the API client that queued the future is recorded
+	wg.Add(2)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ClientMetadataKey, "billing"))
+	rep, err := queue.Queue(ctx, &api.QueueRequest{Task: "good"})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+
+	id, err := queue.Delay("bad", nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+	require.NoError(t, queue.Shutdown())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	records := make(map[string]*AuditRecord)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		record := &AuditRecord{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), record))
+		records[record.Task+" "+record.Event] = record
+	}
+	require.Len(t, records, 4)
+
+	require.Equal(t, "billing", records["good queued"].Client)
+	require.Equal(t, uuid.UUID(rep.Uuid).String(), records["good succeeded"].ID)
+	require.Equal(t, 1, records["good succeeded"].Attempts)
+	require.Empty(t, records["bad queued"].Client)
+	require.Equal(t, id.String(), records["bad failed"].ID)
+	require.Equal(t, "whoops", records["bad failed"].Error)
+}
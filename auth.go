@@ -0,0 +1,77 @@
+package radish
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthMetadataKey is the gRPC metadata key that clients specify their bearer token with
+// when the radish service requires authentication, e.g. "Bearer mysecret".
+const AuthMetadataKey = "authorization"
+
+// unaryAuth authenticates the client before handling a unary request.
+func (r *Radish) unaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := r.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuth authenticates the client before handling a streaming request.
+func (r *Radish) streamAuth(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if _, err := r.authenticate(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// authenticate the client from the bearer token in the gRPC metadata if authentication
+// is configured. The shared AuthToken authorizes any client; an API key authorizes the
+// client it is assigned to, which is set as the client identity on the returned context
+// so that clients cannot claim another client's quota.
+func (r *Radish) authenticate(ctx context.Context) (context.Context, error) {
+	if r.config.AuthToken == "" && len(r.config.APIKeys) == 0 {
+		return ctx, nil
+	}
+
+	token := bearerToken(ctx)
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	if r.config.AuthToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(r.config.AuthToken)) == 1 {
+		return ctx, nil
+	}
+
+	for key, client := range r.config.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+			return context.WithValue(ctx, authKey, client), nil
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+}
+
+// bearerToken returns the token from the authorization metadata of the request.
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	vals := md.Get(AuthMetadataKey)
+	if len(vals) == 0 {
+		return ""
+	}
+
+	if parts := strings.SplitN(vals[0], " ", 2); len(parts) == 2 && strings.EqualFold(parts[0], "bearer") {
+		return strings.TrimSpace(parts[1])
+	}
+	return ""
+}
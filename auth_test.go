@@ -0,0 +1,79 @@
+package radish_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthentication(t *testing.T) {
+	conf := &Config{
+		Workers:     1,
+		NoSignals:   true,
+		AuthToken:   "supersecret",
+		APIKeys:     map[string]string{"teamAkey": "teamA"},
+		ClientQuota: 1,
+	}
+	queue, err := New(conf, &testTask{name: "block"})
+	require.NoError(t, err)
+	require.NoError(t, queue.SetWorkers(0))
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer(queue.ServerOptions()...)
+	api.RegisterRadishServer(srv, queue)
+	go srv.Serve(sock)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(sock.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := api.NewRadishClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Requests without a token or with an invalid token are rejected
+	_, err = client.Status(ctx, &api.StatusRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	bad := metadata.AppendToOutgoingContext(ctx, AuthMetadataKey, "Bearer wrong")
+	_, err = client.Scale(bad, &api.ScaleRequest{Workers: 0})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := client.Watch(bad, &api.WatchRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// The shared auth token authorizes any client
+	secret := metadata.AppendToOutgoingContext(ctx, AuthMetadataKey, "Bearer supersecret")
+	rep, err := client.Status(secret, &api.StatusRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(0), rep.Workers)
+
+	// API keys identify the client even if it claims to be another client
+	teamA := metadata.AppendToOutgoingContext(ctx, AuthMetadataKey, "bearer teamAkey", ClientMetadataKey, "teamB")
+	qrep, err := client.Queue(teamA, &api.QueueRequest{Task: "block"})
+	require.NoError(t, err)
+	require.True(t, qrep.Success)
+
+	qrep, err = client.Queue(teamA, &api.QueueRequest{Task: "block"})
+	require.NoError(t, err)
+	require.False(t, qrep.Success)
+	require.Contains(t, qrep.Error.Message, `client "teamA"`)
+
+	// API keys and clients cannot be empty
+	_, err = New(&Config{APIKeys: map[string]string{"key": ""}})
+	require.EqualError(t, err, "[1] API keys and the clients they are assigned to cannot be empty")
+}
@@ -0,0 +1,262 @@
+package radish
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+)
+
+// Default autoscaling thresholds and periods.
+const (
+	defaultScaleUpPercent   = 75.0
+	defaultScaleDownPercent = 10.0
+	defaultScaleInterval    = 5 * time.Second
+	defaultScaleCooldown    = 30 * time.Second
+)
+
+// AutoscalePolicy grows and shrinks the number of workers between MinWorkers and
+// MaxWorkers based on how full the queue is, so that operators do not have to scale the
+// workers manually during bursts. Every Interval, if the queue is at least ScaleUp percent
+// full another Step workers are added, and if it is at most ScaleDown percent full Step
+// workers are removed, waiting at least the Cooldown between changes. The zero value
+// does not autoscale the workers.
+type AutoscalePolicy struct {
+	MinWorkers int           // the minimum number of workers (default 1)
+	MaxWorkers int           // the maximum number of workers, autoscaling is enabled if greater than 0
+	ScaleUp    float64       // the percent full at or above which workers are added (default 75)
+	ScaleDown  float64       // the percent full at or below which workers are removed (default 10)
+	Step       int           // the number of workers added or removed at a time (default 1)
+	Interval   time.Duration // how often the queue is checked (default 5 seconds)
+	Cooldown   time.Duration // the minimum time between scaling the workers (default 30 seconds)
+}
+
+// enabled returns true if the policy autoscales the workers.
+func (p *AutoscalePolicy) enabled() bool {
+	return p.MaxWorkers > 0
+}
+
+// validate the autoscale policy and populate any defaults for zero valued options.
+func (p *AutoscalePolicy) validate() error {
+	if p.MaxWorkers < 0 {
+		return Errorf(CodeInvalidConfig, "autoscale max workers cannot be negative")
+	}
+
+	if !p.enabled() {
+		return nil
+	}
+
+	if p.MinWorkers <= 0 {
+		p.MinWorkers = 1
+	}
+
+	if p.MinWorkers > p.MaxWorkers {
+		return Errorf(CodeInvalidConfig, "autoscale min workers cannot be greater than max workers")
+	}
+
+	if p.ScaleUp == 0 {
+		p.ScaleUp = defaultScaleUpPercent
+	}
+
+	if p.ScaleDown == 0 {
+		p.ScaleDown = defaultScaleDownPercent
+	}
+
+	if p.ScaleDown < 0 || p.ScaleDown >= p.ScaleUp || p.ScaleUp > 100 {
+		return Errorf(CodeInvalidConfig, "autoscale thresholds must satisfy 0 <= scale down < scale up <= 100")
+	}
+
+	if p.Step <= 0 {
+		p.Step = 1
+	}
+
+	if p.Interval <= 0 {
+		p.Interval = defaultScaleInterval
+	}
+
+	if p.Cooldown <= 0 {
+		p.Cooldown = defaultScaleCooldown
+	}
+	return nil
+}
+
+// workers returns the number of workers the policy scales to from the current number
+// of workers given how full the queue is.
+func (p *AutoscalePolicy) workers(current int, percent float64) int {
+	switch {
+	case percent >= p.ScaleUp && current < p.MaxWorkers:
+		current += p.Step
+	case percent <= p.ScaleDown && current > p.MinWorkers:
+		current -= p.Step
+	}
+
+	if current > p.MaxWorkers {
+		return p.MaxWorkers
+	}
+	if current < p.MinWorkers {
+		return p.MinWorkers
+	}
+	return current
+}
+
+// autoscaler periodically scales the workers based on the queue depth while autoscaling
+// is enabled until the queue is shutdown.
+func (r *Radish) autoscaler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var scaled time.Time
+	for {
+		select {
+		case <-r.shutdown:
+			return
+		case now := <-ticker.C:
+			policy, enabled := r.autoscalePolicy()
+			if !enabled || now.Sub(scaled) < policy.Cooldown {
+				continue
+			}
+
+			if r.autoscale(&policy) {
+				scaled = now
+			}
+		}
+	}
+}
+
+// autoscalePolicy returns a copy of the current autoscale policy and whether autoscaling
+// is enabled.
+func (r *Radish) autoscalePolicy() (AutoscalePolicy, bool) {
+	r.reloadMu.RLock()
+	defer r.reloadMu.RUnlock()
+	return r.config.Autoscale, r.autoscaling
+}
+
+// SetAutoscale turns autoscaling on or off and changes the bounds of the autoscale policy
+// at runtime, e.g. so that operators can pin the number of workers during an incident
+// without redeploying. Bounds that are zero are left unchanged. Autoscaling can only be
+// enabled if the policy has MaxWorkers, and the other options of the policy are set to
+// their defaults if autoscaling was not configured. Disabling autoscaling leaves the
+// workers as they are until they are scaled.
+func (r *Radish) SetAutoscale(enabled bool, minWorkers, maxWorkers int) (err error) {
+	select {
+	case <-r.shutdown:
+		return Errorf(CodeShutdown, "cannot autoscale the workers of a queue that has been shutdown")
+	default:
+	}
+
+	if minWorkers < 0 || maxWorkers < 0 {
+		return Errorf(CodeInvalidConfig, "autoscale min and max workers cannot be negative")
+	}
+
+	r.reloadMu.Lock()
+	policy := r.config.Autoscale
+	if minWorkers > 0 {
+		policy.MinWorkers = minWorkers
+	}
+	if maxWorkers > 0 {
+		policy.MaxWorkers = maxWorkers
+	}
+
+	if enabled && !policy.enabled() {
+		r.reloadMu.Unlock()
+		return Errorf(CodeInvalidConfig, "cannot enable autoscaling without max workers")
+	}
+
+	if err = policy.validate(); err != nil {
+		r.reloadMu.Unlock()
+		return err
+	}
+
+	r.config.Autoscale, r.autoscaling = policy, enabled
+	start := enabled && !r.autoscalerUp
+	r.autoscalerUp = r.autoscalerUp || start
+	r.reloadMu.Unlock()
+
+	if start {
+		go r.autoscaler(policy.Interval)
+	}
+
+	if enabled {
+		r.logf(out.LevelStatus, "", "autoscaling enabled between %d and %d workers", policy.MinWorkers, policy.MaxWorkers)
+	} else {
+		r.logf(out.LevelStatus, "", "autoscaling disabled with %d workers", r.NumWorkers())
+	}
+	return nil
+}
+
+// Autoscale implements the RadishServer interface, turning autoscaling on or off and
+// changing the bounds of the autoscale policy as requested, replying with the current
+// autoscale settings and number of workers.
+func (r *Radish) Autoscale(ctx context.Context, in *api.AutoscaleRequest) (rep *api.AutoscaleReply, err error) {
+	rep = &api.AutoscaleReply{Success: true}
+
+	_, enabled := r.autoscalePolicy()
+	switch in.Toggle {
+	case api.AutoscaleRequest_ENABLE:
+		enabled = true
+	case api.AutoscaleRequest_DISABLE:
+		enabled = false
+	}
+
+	if err = r.SetAutoscale(enabled, int(in.MinWorkers), int(in.MaxWorkers)); err != nil {
+		rep.Success = false
+
+		var ok bool
+		if rep.Error, ok = apiError(err); !ok {
+			return nil, fmt.Errorf("could not cast error to API error: %s", err)
+		}
+	}
+
+	policy, enabled := r.autoscalePolicy()
+	rep.Enabled = enabled
+	rep.MinWorkers = int32(policy.MinWorkers)
+	rep.MaxWorkers = int32(policy.MaxWorkers)
+	rep.Workers = int32(r.NumWorkers())
+	return rep, nil
+}
+
+// autoscale the workers once, returning true if the number of workers was changed.
+func (r *Radish) autoscale(policy *AutoscalePolicy) bool {
+	queued := r.tasks.Len()
+	percent := float64(queued) / float64(r.config.QueueSize) * 100
+
+	r.Lock()
+
+	// Do not restart workers that were stopped by shutdown
+	select {
+	case <-r.shutdown:
+		r.Unlock()
+		return false
+	default:
+	}
+
+	// The queue grows while the workers are paused, which more workers would not handle
+	if r.resumed != nil {
+		r.Unlock()
+		return false
+	}
+
+	current := len(r.workers)
+	target := policy.workers(current, percent)
+	if target == current {
+		r.Unlock()
+		return false
+	}
+
+	stopped, err := r.setWorkers(target)
+	target = len(r.workers)
+	r.Unlock()
+
+	if err != nil {
+		r.logf(out.LevelWarn, "", "could not autoscale workers: %s", err)
+		return false
+	}
+
+	r.hooks.emitScale(current, target)
+
+	r.logf(out.LevelInfo, "", "autoscaled from %d to %d workers with %d futures queued (%0.0f%% full)", current, target, queued, percent)
+	waitWorkers(stopped)
+	return true
+}
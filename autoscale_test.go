@@ -0,0 +1,91 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoscale(t *testing.T) {
+	policy := AutoscalePolicy{MinWorkers: 1, MaxWorkers: 4, Interval: 5 * time.Millisecond, Cooldown: time.Millisecond}
+	queue, err := New(&Config{Workers: 1, QueueSize: 20, NoSignals: true, Autoscale: policy})
+	require.NoError(t, err)
+	require.Equal(t, 1, queue.NumWorkers())
+
+	// Block the workers so that the queue fills up
+	release := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "burst", onHandle: func(id uuid.UUID, params []byte) error {
+		<-release
+		return nil
+	}}
+	require.NoError(t, queue.Register(task))
+
+	wg.Add(20)
+	for i := 0; i < 20; i++ {
+		_, err := queue.Delay("burst", nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	// Workers are added up to the maximum while the queue is full
+	require.Eventually(t, func() bool { return queue.NumWorkers() == 4 }, time.Second, 5*time.Millisecond)
+
+	// Workers are removed down to the minimum once the queue is empty
+	close(release)
+	wg.Wait()
+	require.Eventually(t, func() bool { return queue.NumWorkers() == 1 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, queue.Shutdown())
+	require.Equal(t, 0, queue.NumWorkers())
+
+	// Invalid policies are rejected
+	_, err = New(&Config{Autoscale: AutoscalePolicy{MinWorkers: 8, MaxWorkers: 4}})
+	require.EqualError(t, err, "[1] autoscale min workers cannot be greater than max workers")
+
+	_, err = New(&Config{Autoscale: AutoscalePolicy{MaxWorkers: 4, ScaleUp: 50, ScaleDown: 60}})
+	require.EqualError(t, err, "[1] autoscale thresholds must satisfy 0 <= scale down < scale up <= 100")
+}
+
+func TestSetAutoscale(t *testing.T) {
+	queue, err := New(&Config{Workers: 2, QueueSize: 20, NoSignals: true, LogLevel: "warn"})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Autoscaling cannot be enabled without bounds
+	ctx := context.Background()
+	rep, err := queue.Autoscale(ctx, &api.AutoscaleRequest{Toggle: api.AutoscaleRequest_ENABLE})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.EqualError(t, rep.Error, "[1] cannot enable autoscaling without max workers")
+	require.False(t, rep.Enabled)
+
+	// Autoscaling is enabled with the defaults of the policy for the bounds
+	rep, err = queue.Autoscale(ctx, &api.AutoscaleRequest{Toggle: api.AutoscaleRequest_ENABLE, MinWorkers: 3, MaxWorkers: 6})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	require.True(t, rep.Enabled)
+	require.Equal(t, int32(3), rep.MinWorkers)
+	require.Equal(t, int32(6), rep.MaxWorkers)
+
+	// The bounds can be changed without toggling autoscaling
+	rep, err = queue.Autoscale(ctx, &api.AutoscaleRequest{MinWorkers: 8})
+	require.NoError(t, err)
+	require.EqualError(t, rep.Error, "[1] autoscale min workers cannot be greater than max workers")
+	require.True(t, rep.Enabled)
+	require.Equal(t, int32(3), rep.MinWorkers)
+
+	// Disabling autoscaling pins the workers so they can be scaled manually
+	rep, err = queue.Autoscale(ctx, &api.AutoscaleRequest{Toggle: api.AutoscaleRequest_DISABLE})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	require.False(t, rep.Enabled)
+	require.Equal(t, int32(6), rep.MaxWorkers)
+
+	require.NoError(t, queue.SetWorkers(10))
+	require.Equal(t, 10, queue.NumWorkers())
+}
@@ -0,0 +1,77 @@
+package radish
+
+import (
+	"context"
+	"io"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+)
+
+// FutureSpec specifies a future to delay with DelayMany.
+type FutureSpec struct {
+	Task    string // the name of the task to handle the future
+	Params  []byte // the serialized parameters of the future
+	Success []byte // the serialized parameters to pass to the success function
+	Failure []byte // the serialized parameters to pass to the failure function on error
+	Key     string // the idempotency key of the future, if any (see WithIdempotencyKey)
+}
+
+// DelayMany delays a future for each of the specs with the options on the context as
+// with DelayContext, returning the id of each future and any error that prevented it
+// from being queued in the same order as the specs. A failure to queue one future does
+// not prevent the other futures from being queued.
+func (r *Radish) DelayMany(ctx context.Context, specs []FutureSpec) (ids []uuid.UUID, errs []error) {
+	ids = make([]uuid.UUID, len(specs))
+	errs = make([]error, len(specs))
+	for i, spec := range specs {
+		sctx := ctx
+		if spec.Key != "" {
+			sctx = WithIdempotencyKey(ctx, spec.Key)
+		}
+		ids[i], errs[i] = r.DelayContext(sctx, spec.Task, spec.Params, spec.Success, spec.Failure)
+	}
+	return ids, errs
+}
+
+// QueueBatch queues multiple futures from a gRPC request in one round trip, replying to
+// each queue request in the order they were sent.
+func (r *Radish) QueueBatch(ctx context.Context, in *api.QueueBatchRequest) (rep *api.QueueBatchReply, err error) {
+	rep = &api.QueueBatchReply{Replies: make([]*api.QueueReply, 0, len(in.Requests))}
+	for _, req := range in.Requests {
+		var reply *api.QueueReply
+		if reply, err = r.Queue(ctx, req); err != nil {
+			return nil, err
+		}
+		rep.Replies = append(rep.Replies, reply)
+	}
+	return rep, nil
+}
+
+// Enqueue queues the futures of the requests that a producer streams over one stream,
+// acking each request with its reply in the order the requests were received so that
+// high volume producers avoid the overhead of a unary Queue call for every future. A
+// request that cannot be queued is acked with its error without closing the stream, and
+// the stream is not read while a request is blocked by a full queue, pushing back on the
+// producer.
+func (r *Radish) Enqueue(stream api.Radish_EnqueueServer) (err error) {
+	ctx := stream.Context()
+	for {
+		var req *api.QueueRequest
+		if req, err = stream.Recv(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		var rep *api.QueueReply
+		if rep, err = r.Queue(ctx, req); err != nil {
+			return err
+		}
+
+		if err = stream.Send(rep); err != nil {
+			return err
+		}
+	}
+}
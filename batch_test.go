@@ -0,0 +1,46 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelayMany(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "bulk"}
+	queue, err := New(&Config{Workers: 2, NoSignals: true}, task)
+	require.NoError(t, err)
+
+	wg.Add(2)
+	ids, errs := queue.DelayMany(context.Background(), []FutureSpec{
+		{Task: "bulk", Params: []byte("first")},
+		{Task: "unknown"},
+		{Task: "bulk", Params: []byte("second")},
+	})
+	require.Len(t, ids, 3)
+	require.NotNil(t, ids[0])
+	require.NoError(t, errs[0])
+	require.Nil(t, ids[1])
+	require.EqualError(t, errs[1], `[3] could not delay [3] unknown task "unknown"`)
+	require.NotNil(t, ids[2])
+	require.NoError(t, errs[2])
+
+	// Queue a batch of futures with the gRPC API
+	wg.Add(2)
+	rep, err := queue.QueueBatch(context.Background(), &api.QueueBatchRequest{Requests: []*api.QueueRequest{
+		{Task: "bulk"}, {Task: "bulk", DryRun: true}, {Task: "bulk"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, rep.Replies, 3)
+	for _, reply := range rep.Replies {
+		require.True(t, reply.Success)
+	}
+	require.NotEmpty(t, rep.Replies[1].Outcome)
+	wg.Wait()
+	require.Equal(t, int32(4), task.successes)
+}
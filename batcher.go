@@ -0,0 +1,192 @@
+package radish
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// Default number of futures coalesced into a batch and how long to wait for the batch.
+const (
+	defaultBatchSize = 100
+	defaultBatchWait = 10 * time.Millisecond
+)
+
+// BatchTask is implemented by tasks that handle many futures at once more efficiently
+// than one at a time, e.g. handlers that write to a database or call a bulk API. Workers
+// coalesce up to the BatchSize futures of the task that are dequeued within the BatchWait
+// into one call to HandleBatch, which must return an error (or nil) for each future in
+// the order of the ids. The success and failure callbacks, retries, and the outcome of
+// each future are handled separately; middleware is not applied to batches. Futures
+// waiting for their batch to fill up are in flight, and the worker leading the batch is
+// busy, so they are counted by Status, BusyWorkers, and Drain.
+type BatchTask interface {
+	HandleBatch(ids []uuid.UUID, params [][]byte) []error
+}
+
+// batcher coalesces the dequeued futures of a batch task. The worker that adds the first
+// future of a batch leads it, waiting for the batch to fill up until the batch wait has
+// elapsed, while the workers that add the other futures go back to dequeueing. The
+// worker that fills the batch handles it immediately instead.
+type batcher struct {
+	sync.Mutex
+	size    int
+	wait    time.Duration
+	pending []*Future
+	filled  chan struct{} // closed when the pending batch is handed off because it is full
+}
+
+// add the future to the pending batch, returning the batch if the worker should handle
+// it or nil if the future will be handled with the batch of another worker. The leader
+// of a batch blocks until the batch is full, the batch wait elapses, or the context is
+// done, returning nil if the batch was handed off to the worker that filled it.
+func (b *batcher) add(ctx context.Context, future *Future) []*Future {
+	b.Lock()
+	b.pending = append(b.pending, future)
+	if len(b.pending) >= b.size {
+		batch := b.pending
+		b.pending = nil
+		if b.filled != nil {
+			close(b.filled)
+			b.filled = nil
+		}
+		b.Unlock()
+		return batch
+	}
+
+	if len(b.pending) > 1 {
+		b.Unlock()
+		return nil
+	}
+
+	filled := make(chan struct{})
+	b.filled = filled
+	b.Unlock()
+
+	timer := time.NewTimer(b.wait)
+	defer timer.Stop()
+
+	select {
+	case <-filled:
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	if b.filled != filled {
+		// The batch was filled while the timer fired
+		return nil
+	}
+
+	batch := b.pending
+	b.pending, b.filled = nil, nil
+	return batch
+}
+
+// batcher returns the batcher of the task of the future if its handler is a BatchTask,
+// otherwise nil.
+func (r *Radish) batcher(task string) *batcher {
+	handler, err := r.Handler(task)
+	if err != nil {
+		return nil
+	}
+
+	if _, ok := handler.(BatchTask); !ok {
+		return nil
+	}
+
+	r.batchMu.Lock()
+	defer r.batchMu.Unlock()
+	b, ok := r.batchers[task]
+	if !ok {
+		b = &batcher{size: r.config.BatchSize, wait: r.config.BatchWait}
+		r.batchers[task] = b
+	}
+	return b
+}
+
+// handleBatch handles the coalesced futures of a batch task with one call to HandleBatch,
+// then completes, retries, or cancels each future like handle.
+func (w *worker) handleBatch(futures []*Future) {
+	name := futures[0].Task
+	handler, err := w.parent.Handler(name)
+	if err != nil {
+		// The task was unregistered while the batch was coalesced
+		for _, task := range futures {
+			w.handle(task)
+		}
+		return
+	}
+
+	start := time.Now()
+	defer w.parent.working(name, len(futures))()
+	w.setCurrent(futures[0])
+	defer w.setCurrent(nil)
+
+	// Complete the futures that were canceled while they were queued
+	batch := make([]*Future, 0, len(futures))
+	for _, task := range futures {
+		if _, ok := w.parent.inflight.start(task.ID); !ok {
+			task.canceled = true
+			w.parent.complete(task, handler, Errorf(CodeCanceled, "%s future %s was canceled", task.Task, task.ID), start)
+			continue
+		}
+		batch = append(batch, task)
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+
+	// Wait for a slot of the task and the shared resources it consumes to become available
+	releaseSlot := w.parent.acquireSlot(name)
+	defer releaseSlot()
+
+	release := w.parent.acquireResources(handler)
+	defer release()
+
+	ids := make([]uuid.UUID, 0, len(batch))
+	params := make([][]byte, 0, len(batch))
+	for _, task := range batch {
+		task.Attempts++
+		w.parent.delivered(task)
+		wait := w.parent.results.start(task)
+		pmQueueWait.WithLabelValues(task.Task).Observe(wait.Seconds())
+		w.parent.hooks.emit(Event{Type: EventStart, Future: task})
+		ids = append(ids, task.ID)
+		params = append(params, task.Params)
+	}
+
+	errs := callBatch(handler.(BatchTask), name, ids, params)
+
+	w.parent.logf(out.LevelDebug, name, "handled a batch of %d %s futures", len(batch), name)
+	for i, task := range batch {
+		w.finish(task, handler, errs[i], start)
+	}
+}
+
+// callBatch calls HandleBatch, failing every future of the batch if the handler panics
+// or does not return an error for each future.
+func callBatch(handler BatchTask, name string, ids []uuid.UUID, params [][]byte) (errs []error) {
+	defer func() {
+		var err error
+		if r := recover(); r != nil {
+			err = Errorf(CodeUnknown, "%s task panicked: %v", name, r)
+		} else if len(errs) != len(ids) {
+			err = Errorf(CodeUnknown, "%s task returned %d errors for a batch of %d futures", name, len(errs), len(ids))
+		} else {
+			return
+		}
+
+		errs = make([]error, len(ids))
+		for i := range errs {
+			errs[i] = err
+		}
+	}()
+	return handler.HandleBatch(ids, params)
+}
@@ -0,0 +1,110 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTask(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &batchTask{testTask: testTask{wg: wg, name: "bulk"}}
+
+	conf := &Config{Workers: 4, NoSignals: true, SuppressMetrics: true, LogLevel: "silent", BatchSize: 5, BatchWait: 100 * time.Millisecond}
+	queue, err := New(conf, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Futures dequeued within the batch wait are handled together, up to the batch size
+	wg.Add(12)
+	var bad uuid.UUID
+	for i := 0; i < 12; i++ {
+		params := []byte("ok")
+		if i == 7 {
+			params = []byte("bad")
+		}
+
+		id, err := queue.Delay("bulk", params, nil, nil)
+		require.NoError(t, err)
+		if i == 7 {
+			bad = id
+		}
+	}
+	wg.Wait()
+
+	task.Lock()
+	defer task.Unlock()
+	var handled int
+	for _, batch := range task.batches {
+		require.True(t, batch <= 5)
+		handled += batch
+	}
+	require.Equal(t, 12, handled)
+	require.True(t, len(task.batches) < 12, "futures were not coalesced into batches")
+
+	// Each future of the batch succeeds or fails with its own error
+	require.Equal(t, int32(11), atomic.LoadInt32(&task.successes))
+	require.Equal(t, int32(1), atomic.LoadInt32(&task.failures))
+	_, err = queue.Wait(context.Background(), bad)
+	require.EqualError(t, err, "bad params")
+}
+
+func TestBatchInFlight(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &batchTask{testTask: testTask{wg: wg, name: "bulk"}}
+
+	conf := &Config{Workers: 2, NoSignals: true, SuppressMetrics: true, LogLevel: "silent", BatchSize: 10, BatchWait: 300 * time.Millisecond}
+	queue, err := New(conf, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		_, err = queue.Delay("bulk", []byte("ok"), nil, nil)
+		require.NoError(t, err)
+	}
+
+	// The futures waiting for the batch to fill up are in flight
+	require.Eventually(t, func() bool {
+		rep, err := queue.Status(context.Background(), &api.StatusRequest{})
+		return err == nil && rep.Queue == 0 && rep.InFlight == 2
+	}, time.Second, time.Millisecond)
+	require.Equal(t, 1, queue.BusyWorkers())
+
+	// Drain waits for the batch to be handled
+	require.NoError(t, queue.Drain(context.Background()))
+	require.Equal(t, int32(2), atomic.LoadInt32(&task.successes))
+	wg.Wait()
+
+	rep, err := queue.Status(context.Background(), &api.StatusRequest{})
+	require.NoError(t, err)
+	require.Zero(t, rep.InFlight)
+}
+
+type batchTask struct {
+	testTask
+	sync.Mutex
+	batches []int
+}
+
+func (t *batchTask) HandleBatch(ids []uuid.UUID, params [][]byte) []error {
+	t.Lock()
+	t.batches = append(t.batches, len(ids))
+	t.Unlock()
+
+	errs := make([]error, len(ids))
+	for i, p := range params {
+		if string(p) == "bad" {
+			errs[i] = errors.New("bad params")
+		}
+	}
+	return errs
+}
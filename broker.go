@@ -0,0 +1,69 @@
+package radish
+
+import (
+	"context"
+
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// Broker holds the futures that are waiting to be handled by the workers. By default
+// futures are held in an in-memory priority queue (see NewMemoryBroker), but the Broker
+// config option allows alternate backends such as a disk, Redis, or SQS queue to be used
+// without modifying the worker loop. The methods of a broker are called from multiple go
+// routines and must be thread safe.
+type Broker interface {
+	// Enqueue adds the future to the broker, blocking while the broker is full until
+	// there is room or the context is done, in which case the context error is returned.
+	// If there is room the future should be added even if the context is already done so
+	// that futures can be enqueued without blocking.
+	Enqueue(ctx context.Context, future *Future) error
+
+	// Dequeue removes and returns the next future that should be handled, blocking until
+	// a future is available or the context is done, in which case the context error is
+	// returned. If a future is available it should be returned even if the context is
+	// already done so that the queue can be emptied without blocking.
+	Dequeue(ctx context.Context) (*Future, error)
+
+	// Ack is called once a dequeued future has been handled (or handed off) so that the
+	// broker can forget it; brokers with delivery guarantees may redeliver futures that
+	// were dequeued but never acknowledged.
+	Ack(id uuid.UUID) error
+
+	// Len returns the number of futures waiting in the broker.
+	Len() int
+}
+
+// enqueue a future in the broker, blocking while the broker is full until the context
+// is done or the queue is shutdown, in which case errQueueShutdown is returned.
+func (r *Radish) enqueue(ctx context.Context, future *Future) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-r.shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err = r.tasks.Enqueue(ctx, future); err != nil {
+		select {
+		case <-r.shutdown:
+			return errQueueShutdown
+		default:
+			return err
+		}
+	}
+
+	r.hooks.emit(Event{Type: EventEnqueue, Future: future})
+	return nil
+}
+
+// ack acknowledges a future that has been handled, logging any broker errors.
+func (r *Radish) ack(future *Future) {
+	if err := r.tasks.Ack(future.ID); err != nil {
+		r.logf(out.LevelWarn, future.Task, "could not acknowledge %s future %s: %s", future.Task, future.ID, err)
+	}
+}
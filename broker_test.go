@@ -0,0 +1,59 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker(t *testing.T) {
+	broker := &countingBroker{Broker: NewMemoryBroker(10)}
+	queue, err := New(&Config{Workers: 2, NoSignals: true, Broker: broker})
+	require.NoError(t, err)
+
+	wg := new(sync.WaitGroup)
+	require.NoError(t, queue.Register(&testTask{wg: wg, name: "brokered"}))
+
+	wg.Add(5)
+	for i := 0; i < 5; i++ {
+		_, err := queue.Delay("brokered", nil, nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+	require.NoError(t, queue.Shutdown())
+
+	// Every future is dequeued from the broker and acknowledged once it is handled
+	require.Equal(t, int32(5), atomic.LoadInt32(&broker.enqueued))
+	require.Equal(t, int32(5), atomic.LoadInt32(&broker.dequeued))
+	require.Equal(t, int32(5), atomic.LoadInt32(&broker.acked))
+	require.Equal(t, 0, broker.Len())
+}
+
+type countingBroker struct {
+	Broker
+	enqueued int32
+	dequeued int32
+	acked    int32
+}
+
+func (b *countingBroker) Enqueue(ctx context.Context, future *Future) error {
+	atomic.AddInt32(&b.enqueued, 1)
+	return b.Broker.Enqueue(ctx, future)
+}
+
+func (b *countingBroker) Dequeue(ctx context.Context) (future *Future, err error) {
+	if future, err = b.Broker.Dequeue(ctx); err == nil {
+		atomic.AddInt32(&b.dequeued, 1)
+	}
+	return future, err
+}
+
+func (b *countingBroker) Ack(id uuid.UUID) error {
+	atomic.AddInt32(&b.acked, 1)
+	return b.Broker.Ack(id)
+}
@@ -0,0 +1,85 @@
+package radish
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Default amount of time to wait for a callback service to acknowledge a completed future.
+const defaultCallbackTimeout = 10 * time.Second
+
+// WithCallback returns a copy of the parent context with the address of a RadishCallback
+// service. When a task is delayed using DelayContext, the address is recorded on the
+// future and the callback service is notified of its outcome once it has been handled,
+// giving remote producers the same success and failure semantics as task handlers.
+func WithCallback(parent context.Context, addr string) context.Context {
+	return context.WithValue(parent, callbackKey, addr)
+}
+
+// callbackFrom returns the callback address on the context, if any.
+func callbackFrom(ctx context.Context) string {
+	addr, _ := ctx.Value(callbackKey).(string)
+	return addr
+}
+
+// callbacks manages connections to the callback services of remote producers so that a
+// connection is not dialed for every completed future.
+type callbacks struct {
+	sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+func newCallbacks() *callbacks {
+	return &callbacks{conns: make(map[string]*grpc.ClientConn)}
+}
+
+// client returns a callback client for the address, dialing it if necessary.
+func (c *callbacks) client(addr string, config *Config) (_ api.RadishCallbackClient, err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	conn, ok := c.conns[addr]
+	if !ok {
+		opt := grpc.WithInsecure()
+		if config.CallbackTLS != nil {
+			opt = grpc.WithTransportCredentials(credentials.NewTLS(config.CallbackTLS))
+		}
+
+		if conn, err = grpc.Dial(addr, opt); err != nil {
+			return nil, err
+		}
+		c.conns[addr] = conn
+	}
+	return api.NewRadishCallbackClient(conn), nil
+}
+
+// notifyCallback delivers the outcome of the future to the callback service specified
+// when it was queued. Callbacks that cannot be delivered are logged and dropped.
+func (r *Radish) notifyCallback(future *Future, err error) {
+	req := &api.CompletedFuture{Uuid: future.ID, Task: future.Task, Success: err == nil, Params: future.Success}
+	if err != nil {
+		req.Params = future.Failure
+		if req.Error, _ = apiError(err); req.Error == nil {
+			req.Error = &api.Error{Code: CodeUnknown, Message: err.Error()}
+		}
+	}
+
+	client, err := r.callbacks.client(future.Callback, r.config)
+	if err != nil {
+		r.logf(out.LevelWarn, future.Task, "could not connect to callback %s for future %s: %s", future.Callback, future.ID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.CallbackTimeout)
+	defer cancel()
+
+	if _, err = client.Complete(ctx, req); err != nil {
+		r.logf(out.LevelWarn, future.Task, "could not deliver callback to %s for future %s: %s", future.Callback, future.ID, err)
+	}
+}
@@ -0,0 +1,184 @@
+package radish
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+)
+
+// ContextTask may be implemented by tasks that support cooperative cancellation. If a
+// task implements ContextTask, workers call HandleContext instead of Handle with a
+// context that is canceled when Cancel is called with the ID of the future. Long running
+// tasks should check the context regularly and return promptly once it is done.
+type ContextTask interface {
+	HandleContext(ctx context.Context, id uuid.UUID, params []byte) error
+}
+
+// Cancel the queued or in-flight future with the specified ID. Futures that are still
+// in the queue are not handled once they are dequeued; futures that are being handled
+// are signaled to abort by canceling the context passed to HandleContext, tasks that do
+// not implement ContextTask run to completion. In either case, unless the task succeeds
+// before it observes the cancellation, the Failure callback of the task is called with
+// an ErrCanceled error and the future is recorded as canceled rather than failed.
+// Futures that were forwarded to a peer of the cluster are canceled on the peer.
+func (r *Radish) Cancel(id uuid.UUID) error {
+	if client, peerID, ok := r.forwarded(id); ok {
+		rep, err := client.CancelFuture(r.peerContext(context.Background()), &api.CancelRequest{Uuid: peerID})
+		if err != nil {
+			return Errorf(CodeBadGateway, "could not cancel forwarded future %s: %s", id, err)
+		}
+
+		if !rep.Success {
+			return rep.Error
+		}
+		return nil
+	}
+
+	if !r.inflight.cancel(id) {
+		return Errorf(CodeNotFound, "future %s is not queued or in flight", id)
+	}
+	return nil
+}
+
+// CancelFuture cancels the queued or in-flight future from a gRPC request.
+func (r *Radish) CancelFuture(ctx context.Context, in *api.CancelRequest) (rep *api.CancelReply, err error) {
+	rep = &api.CancelReply{Success: true}
+	if err = r.Cancel(uuid.UUID(in.Uuid)); err != nil {
+		rep.Success = false
+
+		var ok bool
+		if rep.Error, ok = apiError(err); !ok {
+			return nil, fmt.Errorf("could not cast error to API error: %s", err)
+		}
+	}
+	return rep, nil
+}
+
+// inflight tracks the futures that are queued or being handled so that they can be
+// canceled by ID, keyed by the string representation of the future ID.
+type inflight struct {
+	sync.Mutex
+	queued   map[string]bool               // queued futures, true if canceled
+	running  map[string]context.CancelFunc // cancels the contexts of futures being handled
+	canceled map[string]bool               // futures being handled that have been canceled
+}
+
+func newInflight() *inflight {
+	return &inflight{
+		queued:   make(map[string]bool),
+		running:  make(map[string]context.CancelFunc),
+		canceled: make(map[string]bool),
+	}
+}
+
+// enqueue tracks a future that is being added to the queue.
+func (f *inflight) enqueue(id uuid.UUID) {
+	f.Lock()
+	f.queued[id.String()] = false
+	f.Unlock()
+}
+
+// drop stops tracking a queued future that was not added to the queue or was removed
+// from the queue without being handled (e.g. on handoff).
+func (f *inflight) drop(id uuid.UUID) {
+	f.Lock()
+	delete(f.queued, id.String())
+	f.Unlock()
+}
+
+// start tracks a dequeued future that is about to be handled, returning the context to
+// handle it with or false if the future was canceled while it was queued.
+func (f *inflight) start(id uuid.UUID) (context.Context, bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	key := id.String()
+	canceled := f.queued[key]
+	delete(f.queued, key)
+	if canceled {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.running[key] = cancel
+	return ctx, true
+}
+
+// finish stops tracking a future that has been handled, returning true if the future
+// was canceled while it was being handled.
+func (f *inflight) finish(id uuid.UUID) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	key := id.String()
+	if cancel, ok := f.running[key]; ok {
+		cancel()
+		delete(f.running, key)
+	}
+
+	canceled := f.canceled[key]
+	delete(f.canceled, key)
+	return canceled
+}
+
+// numTracked returns the number of futures that are queued, scheduled, waiting to be
+// retried, or being handled.
+func (f *inflight) numTracked() int {
+	f.Lock()
+	defer f.Unlock()
+	return len(f.queued) + len(f.running)
+}
+
+// numRunning returns the number of futures that are being handled.
+func (f *inflight) numRunning() int {
+	f.Lock()
+	defer f.Unlock()
+	return len(f.running)
+}
+
+// runningIDs returns the ids of the futures that are being handled.
+func (f *inflight) runningIDs() []uuid.UUID {
+	f.Lock()
+	defer f.Unlock()
+
+	ids := make([]uuid.UUID, 0, len(f.running))
+	for key := range f.running {
+		ids = append(ids, uuid.Parse(key))
+	}
+	return ids
+}
+
+// cancelRunning cancels every future that is being handled, returning the number of
+// futures that were canceled.
+func (f *inflight) cancelRunning() int {
+	f.Lock()
+	defer f.Unlock()
+
+	for key, cancel := range f.running {
+		f.canceled[key] = true
+		cancel()
+	}
+	return len(f.running)
+}
+
+// cancel marks the future as canceled, returning false if it is not queued or running.
+func (f *inflight) cancel(id uuid.UUID) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	key := id.String()
+	if _, ok := f.queued[key]; ok {
+		f.queued[key] = true
+		return true
+	}
+
+	if cancel, ok := f.running[key]; ok {
+		f.canceled[key] = true
+		cancel()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancel(t *testing.T) {
+	queue, err := New(&Config{Workers: 1, NoSignals: true})
+	require.NoError(t, err)
+
+	// The running task blocks until it is canceled
+	var errs []error
+	started := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	task := &contextTask{testTask: testTask{wg: wg, name: "slow", onFailure: func(id uuid.UUID, err error, params []byte) {
+		errs = append(errs, err)
+	}}, started: started}
+	require.NoError(t, queue.Register(task))
+
+	wg.Add(2)
+	running, err := queue.Delay("slow", nil, nil, nil)
+	require.NoError(t, err)
+	queued, err := queue.Delay("slow", nil, nil, nil)
+	require.NoError(t, err)
+
+	// Cancel the queued future then the running future
+	<-started
+	require.NoError(t, queue.Cancel(queued))
+	require.NoError(t, queue.Cancel(running))
+	wg.Wait()
+
+	require.Equal(t, int32(1), task.handled)
+	require.Equal(t, int32(2), task.failures)
+	require.EqualError(t, errs[0], "[7] slow future "+running.String()+" was canceled: context canceled")
+	require.EqualError(t, errs[1], "[7] slow future "+queued.String()+" was canceled")
+
+	// Completed and unknown futures cannot be canceled
+	require.EqualError(t, queue.Cancel(running), "[13] future "+running.String()+" is not queued or in flight")
+}
+
+func TestCancelFuture(t *testing.T) {
+	queue, err := New(&Config{Workers: 1, NoSignals: true})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	var errs []error
+	started := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	task := &contextTask{testTask: testTask{wg: wg, name: "slow", onFailure: func(id uuid.UUID, err error, params []byte) {
+		errs = append(errs, err)
+	}}, started: started}
+	require.NoError(t, queue.Register(task))
+
+	wg.Add(2)
+	running, err := queue.Delay("slow", nil, nil, nil)
+	require.NoError(t, err)
+	queued, err := queue.Delay("slow", nil, nil, nil)
+	require.NoError(t, err)
+
+	// Cancel the queued future then the running future via the API
+	<-started
+	rep, err := queue.CancelFuture(context.Background(), &api.CancelRequest{Uuid: queued})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	rep, err = queue.CancelFuture(context.Background(), &api.CancelRequest{Uuid: running})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	wg.Wait()
+
+	require.Equal(t, int32(1), task.handled)
+	require.Equal(t, int32(2), task.failures)
+	require.EqualError(t, errs[0], "[7] slow future "+running.String()+" was canceled: context canceled")
+	require.EqualError(t, errs[1], "[7] slow future "+queued.String()+" was canceled")
+
+	// Completed and unknown futures cannot be canceled, the error is returned in the reply
+	rep, err = queue.CancelFuture(context.Background(), &api.CancelRequest{Uuid: running})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, CodeNotFound, rep.Error.Code)
+
+	rep, err = queue.CancelFuture(context.Background(), &api.CancelRequest{Uuid: uuid.NewRandom()})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, CodeNotFound, rep.Error.Code)
+}
+
+type contextTask struct {
+	testTask
+	started chan struct{}
+}
+
+func (t *contextTask) HandleContext(ctx context.Context, id uuid.UUID, params []byte) error {
+	t.Handle(id, params)
+	close(t.started)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(5 * time.Second):
+		return nil
+	}
+}
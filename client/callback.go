@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+)
+
+// Callbacks implements the RadishCallback service so that remote producers can handle
+// the outcome of the futures they queue with the same success and failure semantics as
+// task handlers. Register it on a gRPC server and specify the server's address as the
+// Callback option of the client:
+//
+//	srv := grpc.NewServer()
+//	api.RegisterRadishCallbackServer(srv, &client.Callbacks{OnSuccess: success, OnFailure: failure})
+type Callbacks struct {
+	OnSuccess func(id uuid.UUID, task string, params []byte)            // called when a future succeeds with its success params
+	OnFailure func(id uuid.UUID, task string, err error, params []byte) // called when a future fails with its failure params
+}
+
+// Complete implements the RadishCallbackServer interface.
+func (c *Callbacks) Complete(ctx context.Context, in *api.CompletedFuture) (*api.CompleteReply, error) {
+	if in.Success {
+		if c.OnSuccess != nil {
+			c.OnSuccess(uuid.UUID(in.Uuid), in.Task, in.Params)
+		}
+	} else if c.OnFailure != nil {
+		c.OnFailure(uuid.UUID(in.Uuid), in.Task, replyError(in.Error), in.Params)
+	}
+	return &api.CompleteReply{}, nil
+}
@@ -0,0 +1,90 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	. "github.com/kansaslabs/radish/client"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestCallbacks(t *testing.T) {
+	queue, err := radish.New(&radish.Config{Workers: 1, NoSignals: true}, &noopTask{}, &failTask{})
+	require.NoError(t, err)
+
+	// Serve the radish queue
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	api.RegisterRadishServer(srv, queue)
+	go srv.Serve(sock)
+	defer srv.Stop()
+
+	// Serve the producer's callback service
+	type outcome struct {
+		id     uuid.UUID
+		task   string
+		err    error
+		params string
+	}
+	outcomes := make(chan outcome, 2)
+
+	cbsock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	cbsrv := grpc.NewServer()
+	api.RegisterRadishCallbackServer(cbsrv, &Callbacks{
+		OnSuccess: func(id uuid.UUID, task string, params []byte) {
+			outcomes <- outcome{id: id, task: task, params: string(params)}
+		},
+		OnFailure: func(id uuid.UUID, task string, err error, params []byte) {
+			outcomes <- outcome{id: id, task: task, err: err, params: string(params)}
+		},
+	})
+	go cbsrv.Serve(cbsock)
+	defer cbsrv.Stop()
+
+	client, err := New(&Options{Addr: sock.Addr().String(), Insecure: true, Callback: cbsock.Addr().String()})
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	id, err := client.Queue(ctx, "noop", nil, []byte("yay"), []byte("boo"))
+	require.NoError(t, err)
+
+	select {
+	case o := <-outcomes:
+		require.Equal(t, id, o.id)
+		require.Equal(t, "noop", o.task)
+		require.NoError(t, o.err)
+		require.Equal(t, "yay", o.params)
+	case <-time.After(5 * time.Second):
+		t.Fatal("success callback was not delivered")
+	}
+
+	id, err = client.Queue(ctx, "fail", nil, []byte("yay"), []byte("boo"))
+	require.NoError(t, err)
+
+	select {
+	case o := <-outcomes:
+		require.Equal(t, id, o.id)
+		require.Equal(t, "fail", o.task)
+		require.EqualError(t, o.err, "[0] whoops")
+		require.Equal(t, "boo", o.params)
+	case <-time.After(5 * time.Second):
+		t.Fatal("failure callback was not delivered")
+	}
+}
+
+type failTask struct {
+	noopTask
+}
+
+func (t *failTask) Name() string                             { return "fail" }
+func (t *failTask) Handle(id uuid.UUID, params []byte) error { return errors.New("whoops") }
@@ -0,0 +1,961 @@
+/*
+Package client provides a Go client for radish services that wraps the gRPC API with
+connection management, retries with backoff, and typed helpers for queueing tasks,
+scaling workers, and checking the status of the queue. Idempotent requests are retried
+if the service is unavailable; WithRetries and WithBackoff specify the retry behavior of
+a single request.
+
+	c, err := client.New(&client.Options{Addr: "localhost:5356", Insecure: true})
+	id, err := c.Queue(ctx, "sendEmail", []byte("jdoe@example.com"), nil, nil)
+	id, err = c.DelayAfter(ctx, "sendReminder", 24*time.Hour, []byte("jdoe@example.com"), nil, nil)
+	ids, errs, err := c.QueueMany(ctx, specs)
+	err = c.Enqueue(ctx, specCh, ack)
+	workers, err := c.Scale(ctx, 8)
+	status, err := c.Status(ctx)
+
+Errors returned by the client are either radish API errors returned by the service or
+gRPC transport errors; APIError unwraps both into a radish error so that callers can
+switch on the radish error codes:
+
+	if _, err := c.Queue(ctx, "sendEmail", params, nil, nil); err != nil {
+		if client.APIError(err).Code == radish.CodeQuotaExceeded {
+			// back off and try again later
+		}
+	}
+
+Applications that embed a radish queue in the same process can specify the queue as the
+Local option; if no address is specified, requests are handled in-process without a
+network connection, otherwise the local queue is used as a fallback if the remote radish
+service is unavailable and the request never reached it, so that tasks are not queued on
+both the service and the local queue.
+*/
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Default client options
+const (
+	DefaultTimeout = 30 * time.Second
+	DefaultRetries = 3
+	DefaultBackoff = 100 * time.Millisecond
+	maxBackoff     = 10 * time.Second
+)
+
+// Options for connecting to a radish service.
+type Options struct {
+	Addr      string         // address of the radish service to connect to, if empty the Local queue is used
+	Timeout   time.Duration  // timeout for each request if the context has no deadline (default 30 seconds)
+	Insecure  bool           // do not connect with TLS (default false)
+	TLSConfig *tls.Config    // TLS configuration for the connection (default system roots)
+	Retries   int            // number of times to retry an idempotent request when the service is unavailable (default 3, -1 for none)
+	Backoff   time.Duration  // initial backoff between retries, doubled after each retry (default 100ms)
+	Local     *radish.Radish // an in-process queue to use if Addr is empty or the service is unavailable
+	Callback  string         // the address of a RadishCallback service to notify when queued futures complete
+	Token     string         // the auth token or API key presented to the radish service as a bearer token
+}
+
+// Client wraps the radish gRPC API.
+type Client struct {
+	opts   Options
+	conn   *grpc.ClientConn
+	remote api.RadishClient
+	local  api.RadishServer
+}
+
+// New creates a radish client with the specified options, connecting to the radish
+// service if an address is specified. Note that the connection is made in the
+// background; the returned client can be used immediately.
+func New(opts *Options) (c *Client, err error) {
+	if opts == nil {
+		opts = new(Options)
+	}
+
+	c = &Client{opts: *opts}
+	if c.opts.Timeout <= 0 {
+		c.opts.Timeout = DefaultTimeout
+	}
+	if c.opts.Retries == 0 {
+		c.opts.Retries = DefaultRetries
+	}
+	if c.opts.Backoff <= 0 {
+		c.opts.Backoff = DefaultBackoff
+	}
+
+	if c.opts.Local != nil {
+		c.local = c.opts.Local
+	}
+
+	if c.opts.Addr == "" {
+		if c.local == nil {
+			return nil, fmt.Errorf("must specify either an address or a local radish queue")
+		}
+		return c, nil
+	}
+
+	dialOpts := []grpc.DialOption{grpc.WithUnaryInterceptor(unaryPeer)}
+	if c.opts.Insecure {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	} else {
+		tlsConf := c.opts.TLSConfig
+		if tlsConf == nil {
+			tlsConf = &tls.Config{}
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConf)))
+	}
+
+	if c.opts.Token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(bearerToken(c.opts.Token)))
+	}
+
+	if c.conn, err = grpc.Dial(c.opts.Addr, dialOpts...); err != nil {
+		return nil, fmt.Errorf("could not connect to %s: %s", c.opts.Addr, err)
+	}
+	c.remote = api.NewRadishClient(c.conn)
+	return c, nil
+}
+
+// bearerToken implements credentials.PerRPCCredentials to authenticate every request with
+// the radish service when it requires authentication.
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{radish.AuthMetadataKey: "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity is false so that tokens can be used with Insecure connections,
+// e.g. in development; production services should always be connected to with TLS.
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
+
+// Close the connection to the radish service.
+func (c *Client) Close() error {
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// Queue a task with the specified params, returning the id of the future. If the
+// service responds with an error, the radish API error is returned. If the context has
+// an idempotency key (see radish.WithIdempotencyKey), the id of the existing future is
+// returned if a future of the task was already queued with the same key, the priority on
+// the context (see radish.WithPriority) is used as the priority of the future, futures
+// with the same partition key on the context (see radish.WithPartitionKey) are handled in
+// order, and the metadata on the context (see radish.WithMetadata) is passed to the handler.
+func (c *Client) Queue(ctx context.Context, task string, params, success, failure []byte) (id uuid.UUID, err error) {
+	return c.queue(ctx, c.queueRequest(ctx, task, params, success, failure))
+}
+
+// Delay queues a task with the options on the context exactly as Queue does. Its
+// signature mirrors radish.Radish.DelayContext so that producers can switch between an
+// embedded queue and a remote radish service without changing how tasks are delayed.
+func (c *Client) Delay(ctx context.Context, task string, params, success, failure []byte) (id uuid.UUID, err error) {
+	return c.Queue(ctx, task, params, success, failure)
+}
+
+// DelayAt schedules a future of the task that the service queues at the specified time,
+// returning its id immediately. If the time has already passed, the future is queued
+// right away.
+func (c *Client) DelayAt(ctx context.Context, task string, at time.Time, params, success, failure []byte) (id uuid.UUID, err error) {
+	req := c.queueRequest(ctx, task, params, success, failure)
+	req.Eta = at.UnixNano()
+	return c.queue(ctx, req)
+}
+
+// DelayAfter schedules a future of the task that the service queues once the specified
+// duration has elapsed, returning its id immediately.
+func (c *Client) DelayAfter(ctx context.Context, task string, d time.Duration, params, success, failure []byte) (id uuid.UUID, err error) {
+	req := c.queueRequest(ctx, task, params, success, failure)
+	req.Delay = int64(d)
+	return c.queue(ctx, req)
+}
+
+// queueRequest creates a request to queue the task with the options on the context.
+func (c *Client) queueRequest(ctx context.Context, task string, params, success, failure []byte) *api.QueueRequest {
+	return &api.QueueRequest{
+		Task:           task,
+		Params:         params,
+		Success:        success,
+		Failure:        failure,
+		Callback:       c.opts.Callback,
+		Priority:       radish.PriorityFrom(ctx),
+		IdempotencyKey: radish.IdempotencyKeyFrom(ctx),
+		PartitionKey:   radish.PartitionKeyFrom(ctx),
+		Metadata:       radish.MetadataFrom(ctx),
+	}
+}
+
+// QueueWithEstimate queues a task exactly as Queue does, returning the reply of the
+// service with the id of the future, the length of the queue, and the estimated time the
+// future will wait for a worker so that producers can back off when the queue is busy.
+func (c *Client) QueueWithEstimate(ctx context.Context, task string, params, success, failure []byte) (rep *api.QueueReply, err error) {
+	return c.queueReply(ctx, c.queueRequest(ctx, task, params, success, failure))
+}
+
+// specRequest creates a request to queue the future specified by the spec.
+func (c *Client) specRequest(spec radish.FutureSpec) *api.QueueRequest {
+	return &api.QueueRequest{Task: spec.Task, Params: spec.Params, Success: spec.Success, Failure: spec.Failure, Callback: c.opts.Callback, IdempotencyKey: spec.Key}
+}
+
+// queue sends the queue request, returning the id of the future.
+func (c *Client) queue(ctx context.Context, req *api.QueueRequest) (id uuid.UUID, err error) {
+	var rep *api.QueueReply
+	if rep, err = c.queueReply(ctx, req); err != nil {
+		return nil, err
+	}
+	return uuid.UUID(rep.Uuid), nil
+}
+
+// queueReply sends the queue request, returning the reply if the future was queued.
+func (c *Client) queueReply(ctx context.Context, req *api.QueueRequest) (rep *api.QueueReply, err error) {
+	err = c.do(ctx, req.IdempotencyKey != "", func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.Queue(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.Queue(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !rep.Success {
+		return nil, replyError(rep.Error)
+	}
+	return rep, nil
+}
+
+// QueueMany queues a task for each of the specs in one round trip, returning the id of
+// each future and any radish API error that prevented it from being queued in the same
+// order as the specs. If the request itself fails, err is returned instead.
+func (c *Client) QueueMany(ctx context.Context, specs []radish.FutureSpec) (ids []uuid.UUID, errs []error, err error) {
+	// The batch is only retried by default if every future has an idempotency key
+	idempotent := true
+	req := &api.QueueBatchRequest{Requests: make([]*api.QueueRequest, 0, len(specs))}
+	for _, spec := range specs {
+		req.Requests = append(req.Requests, c.specRequest(spec))
+		idempotent = idempotent && spec.Key != ""
+	}
+
+	var rep *api.QueueBatchReply
+	err = c.do(ctx, idempotent, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.QueueBatch(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.QueueBatch(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids = make([]uuid.UUID, len(rep.Replies))
+	errs = make([]error, len(rep.Replies))
+	for i, reply := range rep.Replies {
+		if reply.Success {
+			ids[i] = uuid.UUID(reply.Uuid)
+		} else {
+			errs[i] = replyError(reply.Error)
+		}
+	}
+	return ids, errs, nil
+}
+
+// DryRun checks if a task with the specified params would be queued by the service
+// without queueing it, returning a description of what would happen to the future.
+func (c *Client) DryRun(ctx context.Context, task string, params []byte) (outcome string, err error) {
+	req := &api.QueueRequest{Task: task, Params: params, DryRun: true, IdempotencyKey: radish.IdempotencyKeyFrom(ctx)}
+
+	var rep *api.QueueReply
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.Queue(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.Queue(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if !rep.Success {
+		return "", replyError(rep.Error)
+	}
+	return rep.Outcome, nil
+}
+
+// Scale the number of workers on the service, returning the number of workers running.
+func (c *Client) Scale(ctx context.Context, workers int) (n int, err error) {
+	n, _, err = c.ScaleWithin(ctx, workers, 0, false)
+	return n, err
+}
+
+// ScaleWithin scales the number of workers on the service, waiting at most the timeout
+// for removed workers to finish the futures they are handling. It returns the number of
+// workers running and the number of removed workers that were still draining when the
+// timeout expired; if force is true, the futures of the draining workers are canceled.
+func (c *Client) ScaleWithin(ctx context.Context, workers int, timeout time.Duration, force bool) (n, draining int, err error) {
+	req := &api.ScaleRequest{Workers: int32(workers), Timeout: int64(timeout), Force: force}
+
+	var rep *api.ScaleReply
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.Scale(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.Scale(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !rep.Success {
+		return 0, int(rep.Draining), replyError(rep.Error)
+	}
+	return int(rep.Workers), int(rep.Draining), nil
+}
+
+// Ping checks that the radish service is reachable, returning its version and the current
+// time on the server. An error is returned with the reply if the service implements a
+// different protocol version of the radish API than the client.
+func (c *Client) Ping(ctx context.Context) (rep *api.PingReply, err error) {
+	req := &api.PingRequest{}
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.Ping(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.Ping(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if rep.Protocol != radish.ProtocolVersion {
+		return rep, radish.Errorf(radish.CodeBadGateway, "radish %s implements protocol version %d, the client requires version %d", rep.Version, rep.Protocol, radish.ProtocolVersion)
+	}
+	return rep, nil
+}
+
+// Status returns the current status of the radish queue.
+func (c *Client) Status(ctx context.Context) (rep *api.StatusReply, err error) {
+	return c.status(ctx, &api.StatusRequest{})
+}
+
+// ClusterStatus returns the status of the radish queue with the status of every peer in
+// its cluster; peers that could not be reached are returned with an error.
+func (c *Client) ClusterStatus(ctx context.Context) (rep *api.StatusReply, err error) {
+	return c.status(ctx, &api.StatusRequest{Cluster: true})
+}
+
+// DetailedStatus returns the sections of the status of the radish queue specified by the
+// request, e.g. the workers, the futures in flight, or the scheduler, optionally only
+// for the tasks of the request.
+func (c *Client) DetailedStatus(ctx context.Context, req *api.StatusRequest) (rep *api.StatusReply, err error) {
+	return c.status(ctx, req)
+}
+
+func (c *Client) status(ctx context.Context, req *api.StatusRequest) (rep *api.StatusReply, err error) {
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.Status(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.Status(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !rep.Success {
+		return nil, replyError(rep.Error)
+	}
+	return rep, nil
+}
+
+// Tasks returns the tasks registered with the radish queue ordered by name, with the
+// number of futures of each task that are pending and that have been queued, succeeded,
+// failed, or canceled since the queue started.
+func (c *Client) Tasks(ctx context.Context) (tasks []*api.TaskInfo, err error) {
+	req := &api.ListTasksRequest{}
+
+	var rep *api.ListTasksReply
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.ListTasks(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.ListTasks(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return rep.Tasks, nil
+}
+
+// Drain stops the radish service from accepting new tasks so that the tasks already in
+// the queue are handled, returning the number of tasks remaining in the queue. Poll
+// Status until the queue is empty to wait for the queue to be drained. If shutdown is
+// true, the service shuts down once the queue has been drained.
+func (c *Client) Drain(ctx context.Context, shutdown bool) (remaining uint64, err error) {
+	req := &api.DrainRequest{Shutdown: shutdown}
+
+	var rep *api.DrainReply
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.DrainQueue(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.DrainQueue(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !rep.Success {
+		return 0, replyError(rep.Error)
+	}
+	return rep.Queue, nil
+}
+
+// Autoscale turns autoscaling of the workers on the service on or off and changes the
+// bounds of its autoscale policy, returning the autoscale settings after the change.
+func (c *Client) Autoscale(ctx context.Context, req *api.AutoscaleRequest) (rep *api.AutoscaleReply, err error) {
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.Autoscale(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.Autoscale(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !rep.Success {
+		return nil, replyError(rep.Error)
+	}
+	return rep, nil
+}
+
+// Reload changes the reloadable settings of the queue without restarting it, returning
+// the settings after the reload. Settings that are zero valued in the request are left
+// unchanged unless the request loads the config of the server.
+func (c *Client) Reload(ctx context.Context, req *api.ReloadRequest) (rep *api.ReloadReply, err error) {
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.Reload(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.Reload(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !rep.Success {
+		return nil, replyError(rep.Error)
+	}
+	return rep, nil
+}
+
+// Wait blocks until the future with the specified id has completed or the context is
+// done, returning the result of the handler if the future succeeded or the error that
+// caused the future to fail or be canceled.
+func (c *Client) Wait(ctx context.Context, id uuid.UUID) (result []byte, err error) {
+	req := &api.ResultRequest{Uuid: id, Wait: true}
+
+	var rep *api.ResultReply
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.Result(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.Result(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !rep.Success {
+		return nil, replyError(rep.Error)
+	}
+
+	if rep.Future.Error != nil {
+		return nil, rep.Future.Error
+	}
+	return rep.Result, nil
+}
+
+// GetFuture returns the lifecycle state of the future with the specified id. If the
+// future is not known to the service, an ErrNotFound API error is returned.
+func (c *Client) GetFuture(ctx context.Context, id uuid.UUID) (info *api.FutureInfo, err error) {
+	req := &api.GetFutureRequest{Uuid: id}
+
+	var rep *api.GetFutureReply
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.GetFuture(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.GetFuture(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !rep.Success {
+		return nil, replyError(rep.Error)
+	}
+	return rep.Future, nil
+}
+
+// GetWorkflow returns the state of the workflow with the specified id and each of its
+// steps. If the workflow is not known to the service, an ErrNotFound API error is returned.
+func (c *Client) GetWorkflow(ctx context.Context, id uuid.UUID) (info *api.WorkflowInfo, err error) {
+	req := &api.GetWorkflowRequest{Uuid: id}
+
+	var rep *api.GetWorkflowReply
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.GetWorkflow(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.GetWorkflow(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !rep.Success {
+		return nil, replyError(rep.Error)
+	}
+	return rep.Workflow, nil
+}
+
+// Cancel the queued or in-flight future with the specified id. If the future is not
+// queued or in flight, an ErrNotFound API error is returned.
+func (c *Client) Cancel(ctx context.Context, id uuid.UUID) (err error) {
+	req := &api.CancelRequest{Uuid: id}
+
+	var rep *api.CancelReply
+	err = c.do(ctx, false, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.CancelFuture(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.CancelFuture(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if !rep.Success {
+		return replyError(rep.Error)
+	}
+	return nil
+}
+
+// StatsHistory returns up to limit of the most recent throughput snapshots recorded by
+// the radish service (all snapshots if limit is 0), optionally filtered by task name.
+func (c *Client) StatsHistory(ctx context.Context, limit int, tasks ...string) (rep *api.StatsHistoryReply, err error) {
+	req := &api.StatsHistoryRequest{Limit: int32(limit), Tasks: tasks}
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.StatsHistory(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.StatsHistory(ctx, req)
+		return err
+	})
+	return rep, err
+}
+
+// DeadLetters lists up to limit of the most recent futures in the dead letter queue of
+// the service (all dead letters if limit is 0), optionally filtered by task and ids.
+func (c *Client) DeadLetters(ctx context.Context, task string, limit int, ids ...uuid.UUID) (letters []*api.DeadLetter, err error) {
+	req := &api.DeadLetterRequest{Task: task, Limit: int32(limit), Uuids: deadLetterIDs(ids)}
+
+	var rep *api.DeadLetterReply
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.ListDeadLetters(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.ListDeadLetters(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return rep.Futures, nil
+}
+
+// Redrive enqueues the futures in the dead letter queue of the service that match the
+// task and ids again, returning the number of futures that were re-driven.
+func (c *Client) Redrive(ctx context.Context, task string, ids ...uuid.UUID) (n int, err error) {
+	req := &api.DeadLetterRequest{Task: task, Uuids: deadLetterIDs(ids)}
+
+	var rep *api.DeadLetterReply
+	err = c.do(ctx, false, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.RedriveDeadLetters(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.RedriveDeadLetters(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !rep.Success {
+		return int(rep.Count), replyError(rep.Error)
+	}
+	return int(rep.Count), nil
+}
+
+// Purge removes the futures in the dead letter queue of the service that match the task
+// and ids without handling them, returning the number of futures that were removed.
+func (c *Client) Purge(ctx context.Context, task string, ids ...uuid.UUID) (n int, err error) {
+	req := &api.DeadLetterRequest{Task: task, Uuids: deadLetterIDs(ids)}
+
+	var rep *api.DeadLetterReply
+	err = c.do(ctx, false, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.PurgeDeadLetters(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.PurgeDeadLetters(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return int(rep.Count), nil
+}
+
+func deadLetterIDs(ids []uuid.UUID) [][]byte {
+	uuids := make([][]byte, 0, len(ids))
+	for _, id := range ids {
+		uuids = append(uuids, id)
+	}
+	return uuids
+}
+
+// SetScript registers or updates the Lua script that handles the named task on the
+// radish service.
+func (c *Client) SetScript(ctx context.Context, task, source string) (err error) {
+	req := &api.ScriptRequest{Task: task, Source: source}
+
+	var rep *api.ScriptReply
+	err = c.do(ctx, true, func(ctx context.Context, remote api.RadishClient) (err error) {
+		rep, err = remote.SetScript(ctx, req)
+		return err
+	}, func(ctx context.Context, local api.RadishServer) (err error) {
+		rep, err = local.SetScript(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if !rep.Success {
+		return replyError(rep.Error)
+	}
+	return nil
+}
+
+// Logs streams log entries from the radish service that match the request, calling the
+// callback for each entry until the stream ends, the context is canceled, or the callback
+// returns an error. If the service becomes unavailable the stream is reopened as with
+// Watch, without sending the tail of recent entries again. Logs cannot be streamed from
+// a local queue.
+func (c *Client) Logs(ctx context.Context, req *api.LogsRequest, callback func(*api.LogEntry) error) (err error) {
+	if c.remote == nil {
+		return fmt.Errorf("logs can only be streamed from a remote radish service")
+	}
+
+	req = proto.Clone(req).(*api.LogsRequest)
+	return c.reconnect(ctx, func(ctx context.Context, received *bool) (err error) {
+		var stream api.Radish_LogsClient
+		if stream, err = c.remote.Logs(ctx, req); err != nil {
+			return err
+		}
+
+		for {
+			var entry *api.LogEntry
+			if entry, err = stream.Recv(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+
+			*received, req.Tail = true, 0
+			if err = callback(entry); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// Watch streams the lifecycle events of futures that match the request from the remote
+// service, calling the callback with each event until the context is canceled, the
+// service shuts down, or the callback returns an error. If the service becomes
+// unavailable the stream is reopened with backoff, retrying as many times as the
+// Retries option (or WithRetries) allows since the last event was received; events that
+// occur while the client is reconnecting are not received.
+func (c *Client) Watch(ctx context.Context, req *api.WatchRequest, callback func(*api.FutureEvent) error) (err error) {
+	if c.remote == nil {
+		return fmt.Errorf("events can only be watched on a remote radish service")
+	}
+
+	return c.reconnect(ctx, func(ctx context.Context, received *bool) (err error) {
+		var stream api.Radish_WatchClient
+		if stream, err = c.remote.Watch(ctx, req); err != nil {
+			return err
+		}
+
+		for {
+			var event *api.FutureEvent
+			if event, err = stream.Recv(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+
+			*received = true
+			if err = callback(event); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// reconnect opens a stream with the stream function, reopening it with exponential
+// backoff if the service is unavailable. The stream function sets received once a
+// message has been received, which resets the retries and backoff so that long-lived
+// streams can reconnect any number of times.
+func (c *Client) reconnect(ctx context.Context, stream func(ctx context.Context, received *bool) error) (err error) {
+	retries, initial := c.retryPolicy(ctx, true)
+	backoff := initial
+	for attempt := 0; ; attempt++ {
+		var received bool
+		if err = stream(ctx, &received); err == nil || !retryable(err) {
+			return err
+		}
+
+		if received {
+			attempt, backoff = 0, initial
+		}
+
+		if attempt >= retries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+
+		c.conn.ResetConnectBackoff()
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// do executes the request against the remote service, retrying with exponential backoff
+// if the service is unavailable and the request is idempotent or retries were specified
+// on the context. If no remote service is configured, the request is handled by the local
+// queue if one is specified. If the service remains unavailable, the request is only
+// handled by the local queue if no attempt reached the service: a request that was sent
+// may still have been handled by the service, e.g. before the connection was lost, and
+// the service and the local queue do not share idempotency keys.
+func (c *Client) do(ctx context.Context, idempotent bool, remote func(context.Context, api.RadishClient) error, local func(context.Context, api.RadishServer) error) (err error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+
+	if c.remote == nil {
+		return local(ctx, c.local)
+	}
+
+	sent := false
+	retries, backoff := c.retryPolicy(ctx, idempotent)
+	for attempt := 0; ; attempt++ {
+		p := new(peer.Peer)
+		if err = remote(context.WithValue(ctx, peerKey, p), c.remote); err == nil || !retryable(err) {
+			return err
+		}
+
+		// The peer is only set if the request was sent on a connection to the service
+		sent = sent || p.Addr != nil
+
+		if attempt >= retries {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+
+		// Reconnect right away rather than waiting out the backoff of the connection
+		c.conn.ResetConnectBackoff()
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	if c.local != nil && !sent {
+		return local(ctx, c.local)
+	}
+	return err
+}
+
+// unaryPeer records the peer of a request made with do on the peer in the context, which
+// gRPC only sets if a stream was opened on a connection to the service.
+func unaryPeer(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if p, ok := ctx.Value(peerKey).(*peer.Peer); ok {
+		opts = append(opts, grpc.Peer(p))
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// APIError unwraps an error returned by the client into a radish API error so that
+// callers can switch on the radish error codes, e.g. radish.CodeQuotaExceeded, no matter
+// whether the error was returned by the service or by the gRPC transport. Radish errors
+// are returned as is; gRPC status and context errors are mapped to the closest radish
+// error code, and any other error has the CodeUnknown code. APIError returns nil if the
+// error is nil.
+func APIError(err error) *api.Error {
+	if err == nil {
+		return nil
+	}
+
+	var e *api.Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	switch err {
+	case context.Canceled, context.DeadlineExceeded:
+		return &api.Error{Code: radish.CodeCanceled, Message: err.Error()}
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return &api.Error{Code: radish.CodeUnknown, Message: err.Error()}
+	}
+
+	code := radish.CodeUnknown
+	switch s.Code() {
+	case codes.Canceled, codes.DeadlineExceeded:
+		code = radish.CodeCanceled
+	case codes.Unavailable:
+		code = radish.CodeBadGateway
+	case codes.ResourceExhausted:
+		code = radish.CodeQuotaExceeded
+	case codes.NotFound:
+		code = radish.CodeNotFound
+	case codes.InvalidArgument:
+		code = radish.CodeInvalidParams
+	}
+	return &api.Error{Code: code, Message: s.Message()}
+}
+
+// WithRetries returns a copy of the parent context that specifies the number of times a
+// request made with the context is retried if the service is unavailable, overriding
+// the Retries option of the client; -1 disables retries. By default only idempotent
+// requests are retried, since a request that failed may still have been handled by the
+// service, e.g. tasks are only queued again if they have an idempotency key. Specify
+// retries on the context to retry other requests as well.
+func WithRetries(parent context.Context, retries int) context.Context {
+	return context.WithValue(parent, retriesKey, retries)
+}
+
+// WithBackoff returns a copy of the parent context that specifies the initial backoff
+// between retries of a request made with the context, overriding the Backoff option of
+// the client.
+func WithBackoff(parent context.Context, backoff time.Duration) context.Context {
+	return context.WithValue(parent, backoffKey, backoff)
+}
+
+// retryPolicy returns the number of retries and the initial backoff of a request made
+// with the context.
+func (c *Client) retryPolicy(ctx context.Context, idempotent bool) (retries int, backoff time.Duration) {
+	retries, ok := ctx.Value(retriesKey).(int)
+	switch {
+	case !ok && idempotent:
+		retries = c.opts.Retries
+	case !ok:
+		retries = 0
+	}
+
+	if backoff, ok = ctx.Value(backoffKey).(time.Duration); !ok || backoff <= 0 {
+		backoff = c.opts.Backoff
+	}
+	return retries, backoff
+}
+
+// contextKey is an unexported type to prevent collisions with other packages' keys.
+type contextKey uint8
+
+const (
+	retriesKey contextKey = iota
+	backoffKey
+	peerKey
+)
+
+// replyError ensures a nil API error is not returned as a non-nil error interface.
+func replyError(e *api.Error) error {
+	if e == nil {
+		return fmt.Errorf("radish request was not successful")
+	}
+	return e
+}
+
+// retryable returns true if the error indicates the service is temporarily unavailable.
+func retryable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
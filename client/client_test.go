@@ -0,0 +1,301 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	. "github.com/kansaslabs/radish/client"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLocalClient(t *testing.T) {
+	queue, err := radish.New(&radish.Config{Workers: 2}, &noopTask{})
+	require.NoError(t, err)
+
+	_, err = New(&Options{})
+	require.EqualError(t, err, "must specify either an address or a local radish queue")
+
+	client, err := New(&Options{Local: queue})
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	id, err := client.Queue(ctx, "noop", nil, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, id)
+
+	_, err = client.Queue(ctx, "unknown", nil, nil, nil)
+	require.EqualError(t, err, `[3] could not delay [3] unknown task "unknown"`)
+	require.Equal(t, radish.CodeTaskNotRegistered, APIError(err).Code)
+
+	// Futures can be scheduled to be queued later
+	id, err = client.DelayAfter(ctx, "noop", time.Hour, nil, nil, nil)
+	require.NoError(t, err)
+	info, err := client.GetFuture(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, api.FutureState_SCHEDULED, info.State)
+	require.NoError(t, client.Cancel(ctx, id))
+
+	id, err = client.DelayAt(ctx, "noop", time.Now().Add(-time.Minute), nil, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, id)
+
+	ids, errs, err := client.QueueMany(ctx, []radish.FutureSpec{{Task: "noop"}, {Task: "unknown"}, {Task: "noop"}})
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+	require.NotNil(t, ids[0])
+	require.Nil(t, ids[1])
+	require.NotNil(t, ids[2])
+	require.NoError(t, errs[0])
+	require.Error(t, errs[1])
+	require.NoError(t, errs[2])
+
+	n, err := client.Scale(ctx, 4)
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+
+	status, err := client.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), status.Workers)
+
+	ping, err := client.Ping(ctx)
+	require.NoError(t, err)
+	require.Equal(t, radish.PackageVersion, ping.Version)
+	require.Equal(t, uint32(radish.ProtocolVersion), ping.Protocol)
+
+	rep, err := client.Reload(ctx, &api.ReloadRequest{Workers: 3, TaskQuota: 10})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), rep.Workers)
+	require.Equal(t, int32(10), rep.TaskQuota)
+
+	_, err = client.Reload(ctx, &api.ReloadRequest{Load: true})
+	require.EqualError(t, err, "[1] no LoadConfig function is configured")
+}
+
+func TestRemoteClient(t *testing.T) {
+	queue, err := radish.New(&radish.Config{Workers: 2}, &noopTask{})
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	api.RegisterRadishServer(srv, queue)
+	go srv.Serve(sock)
+	defer srv.Stop()
+
+	client, err := New(&Options{Addr: sock.Addr().String(), Insecure: true, Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	id, err := client.Queue(ctx, "noop", nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, id, 16)
+
+	_, err = client.Queue(ctx, "unknown", nil, nil, nil)
+	require.EqualError(t, err, `[3] could not delay [3] unknown task "unknown"`)
+
+	n, err := client.Scale(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	status, err := client.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), status.Workers)
+
+	_, err = client.DetailedStatus(ctx, &api.StatusRequest{Sections: []api.StatusRequest_Section{42}})
+	require.EqualError(t, err, "[9] unknown status section 42")
+}
+
+func TestClientToken(t *testing.T) {
+	queue, err := radish.New(&radish.Config{Workers: 1, AuthToken: "supersecret"}, &noopTask{})
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer(queue.ServerOptions()...)
+	api.RegisterRadishServer(srv, queue)
+	go srv.Serve(sock)
+	defer srv.Stop()
+
+	ctx := context.Background()
+	client, err := New(&Options{Addr: sock.Addr().String(), Insecure: true, Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Status(ctx)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	require.Equal(t, &api.Error{Code: radish.CodeUnknown, Message: "missing bearer token"}, APIError(err))
+
+	client, err = New(&Options{Addr: sock.Addr().String(), Insecure: true, Timeout: 5 * time.Second, Token: "supersecret"})
+	require.NoError(t, err)
+	defer client.Close()
+
+	rep, err := client.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), rep.Workers)
+}
+
+func TestClientFallback(t *testing.T) {
+	queue, err := radish.New(&radish.Config{Workers: 2}, &noopTask{})
+	require.NoError(t, err)
+
+	// Reserve an address and close it so that the remote service is unavailable
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := sock.Addr().String()
+	require.NoError(t, sock.Close())
+
+	// Without a local queue the unavailable error is returned after retrying
+	client, err := New(&Options{Addr: addr, Insecure: true, Retries: 1, Backoff: time.Millisecond})
+	require.NoError(t, err)
+	_, err = client.Status(context.Background())
+	require.Error(t, err)
+	require.Equal(t, radish.CodeBadGateway, APIError(err).Code)
+	require.NoError(t, client.Close())
+
+	// With a local queue the request falls back to the in-process queue
+	client, err = New(&Options{Addr: addr, Insecure: true, Retries: -1, Local: queue})
+	require.NoError(t, err)
+	defer client.Close()
+
+	id, err := client.Queue(context.Background(), "noop", nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, id, 16)
+}
+
+func TestClientNoFallbackOnceSent(t *testing.T) {
+	local, err := radish.New(&radish.Config{Workers: 1, NoSignals: true, LogLevel: "silent"}, &noopTask{})
+	require.NoError(t, err)
+	defer local.Shutdown()
+
+	remote, err := radish.New(&radish.Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent"}, &noopTask{})
+	require.NoError(t, err)
+	defer remote.Shutdown()
+
+	// The service receives the queue request then loses the connection before replying
+	received := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info.FullMethod == "/api.Radish/Queue" {
+			close(received)
+			<-release
+		}
+		return handler(ctx, req)
+	}))
+	api.RegisterRadishServer(srv, remote)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(sock)
+
+	client, err := New(&Options{Addr: sock.Addr().String(), Insecure: true, Retries: -1, Local: local})
+	require.NoError(t, err)
+	defer client.Close()
+
+	go func() {
+		<-received
+		srv.Stop()
+	}()
+
+	// The request may have been handled by the service so it is not queued locally
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = client.Queue(ctx, "noop", nil, nil, nil)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	rep, err := local.ListTasks(context.Background(), &api.ListTasksRequest{})
+	require.NoError(t, err)
+	require.Len(t, rep.Tasks, 1)
+	require.Zero(t, rep.Tasks[0].Succeeded+rep.Tasks[0].Queued+rep.Tasks[0].Pending)
+}
+
+func TestClientRetries(t *testing.T) {
+	queue, err := radish.New(&radish.Config{Workers: 1}, &noopTask{})
+	require.NoError(t, err)
+
+	// Reserve an address and close it so that the remote service is unavailable
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := sock.Addr().String()
+	require.NoError(t, sock.Close())
+
+	client, err := New(&Options{Addr: addr, Insecure: true, Retries: 2, Backoff: 100 * time.Millisecond})
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Idempotent requests are retried with backoff
+	ctx := context.Background()
+	start := time.Now()
+	_, err = client.Status(ctx)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.True(t, time.Since(start) >= 300*time.Millisecond)
+
+	// Tasks are not queued again unless they have an idempotency key or retries are
+	// specified for the request
+	start = time.Now()
+	_, err = client.Queue(ctx, "noop", nil, nil, nil)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.True(t, time.Since(start) < 100*time.Millisecond)
+
+	start = time.Now()
+	_, err = client.Queue(WithBackoff(WithRetries(ctx, 1), 50*time.Millisecond), "noop", nil, nil, nil)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	start = time.Now()
+	_, err = client.Status(WithRetries(ctx, -1))
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.True(t, time.Since(start) < 100*time.Millisecond)
+
+	// The client reconnects once the service is available
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		sock, err := net.Listen("tcp", addr)
+		if err != nil {
+			return
+		}
+
+		srv := grpc.NewServer()
+		api.RegisterRadishServer(srv, queue)
+		go srv.Serve(sock)
+		<-done
+		srv.Stop()
+	}()
+
+	rep, err := client.Status(WithBackoff(WithRetries(ctx, 20), 50*time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), rep.Workers)
+}
+
+func TestAPIError(t *testing.T) {
+	require.Nil(t, APIError(nil))
+
+	err := radish.Errorf(radish.CodeQuotaExceeded, "too many futures")
+	require.True(t, APIError(err) == err)
+
+	require.Equal(t, &api.Error{Code: radish.CodeCanceled, Message: "context deadline exceeded"}, APIError(context.DeadlineExceeded))
+	require.Equal(t, &api.Error{Code: radish.CodeNotFound, Message: "no future"}, APIError(status.Error(codes.NotFound, "no future")))
+	require.Equal(t, &api.Error{Code: radish.CodeUnknown, Message: "whoops"}, APIError(errors.New("whoops")))
+}
+
+type noopTask struct{}
+
+func (t *noopTask) Name() string                                   { return "noop" }
+func (t *noopTask) Handle(id uuid.UUID, params []byte) error       { return nil }
+func (t *noopTask) Success(id uuid.UUID, params []byte)            {}
+func (t *noopTask) Failure(id uuid.UUID, err error, params []byte) {}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+
+	"github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+)
+
+// The maximum number of specs that Enqueue sends before their acks are received.
+const enqueueWindow = 1024
+
+// Enqueue streams a queue request for each of the specs received on the channel to the
+// service over a single stream, which is much cheaper than a Queue call per future for
+// high volume producers. The ack function is called with each spec and the id of its
+// future or the radish API error that prevented it from being queued, in the order the
+// specs were received. Enqueue returns once the channel is closed and every spec has been
+// acked, or if the context is canceled or the stream fails; specs that were sent but not
+// acked may or may not have been queued, so the stream is not retried.
+func (c *Client) Enqueue(ctx context.Context, specs <-chan radish.FutureSpec, ack func(spec radish.FutureSpec, id uuid.UUID, err error)) (err error) {
+	if c.remote == nil {
+		return c.enqueueLocal(ctx, specs, ack)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var stream api.Radish_EnqueueClient
+	if stream, err = c.remote.Enqueue(ctx); err != nil {
+		return err
+	}
+
+	// Send the specs while acks are received so the producer is not blocked by round trips
+	sent := make(chan radish.FutureSpec, enqueueWindow)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(sent)
+		for {
+			select {
+			case spec, ok := <-specs:
+				if !ok {
+					errc <- stream.CloseSend()
+					return
+				}
+
+				select {
+				case sent <- spec:
+				case <-ctx.Done():
+					errc <- ctx.Err()
+					return
+				}
+
+				if err := stream.Send(c.specRequest(spec)); err != nil {
+					errc <- err
+					return
+				}
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	for spec := range sent {
+		var rep *api.QueueReply
+		if rep, err = stream.Recv(); err != nil {
+			return err
+		}
+
+		if !rep.Success {
+			ack(spec, nil, replyError(rep.Error))
+			continue
+		}
+		ack(spec, uuid.UUID(rep.Uuid), nil)
+	}
+	return <-errc
+}
+
+// enqueueLocal queues the specs with the local server one at a time.
+func (c *Client) enqueueLocal(ctx context.Context, specs <-chan radish.FutureSpec, ack func(spec radish.FutureSpec, id uuid.UUID, err error)) error {
+	for {
+		select {
+		case spec, ok := <-specs:
+			if !ok {
+				return nil
+			}
+
+			id, err := c.queue(ctx, c.specRequest(spec))
+			ack(spec, id, err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
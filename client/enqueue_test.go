@@ -0,0 +1,72 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	. "github.com/kansaslabs/radish/client"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestEnqueue(t *testing.T) {
+	queue, err := radish.New(&radish.Config{Workers: 2, NoSignals: true, LogLevel: "warn"}, &noopTask{})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	api.RegisterRadishServer(srv, queue)
+	go srv.Serve(sock)
+	defer srv.Stop()
+
+	remote, err := New(&Options{Addr: sock.Addr().String(), Insecure: true, Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer remote.Close()
+
+	local, err := New(&Options{Local: queue})
+	require.NoError(t, err)
+
+	for _, client := range []*Client{remote, local} {
+		// Every spec is acked in order, including the specs that could not be queued
+		specs := make(chan radish.FutureSpec)
+		go func() {
+			defer close(specs)
+			for i := 0; i < 100; i++ {
+				task := "noop"
+				if i == 50 {
+					task = "unknown"
+				}
+				specs <- radish.FutureSpec{Task: task, Params: []byte(fmt.Sprintf("%d", i))}
+			}
+		}()
+
+		var acked int
+		err = client.Enqueue(context.Background(), specs, func(spec radish.FutureSpec, id uuid.UUID, err error) {
+			require.Equal(t, fmt.Sprintf("%d", acked), string(spec.Params))
+			if acked == 50 {
+				require.Nil(t, id)
+				require.EqualError(t, err, `[3] could not delay [3] unknown task "unknown"`)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, id, 16)
+			}
+			acked++
+		})
+		require.NoError(t, err)
+		require.Equal(t, 100, acked)
+	}
+
+	// Enqueue returns when the context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = remote.Enqueue(ctx, make(chan radish.FutureSpec), func(radish.FutureSpec, uuid.UUID, error) {})
+	require.Error(t, err)
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+)
+
+// Work runs the process as a remote worker of the radish service, pulling the futures of
+// the tasks over a bidirectional stream and handling up to concurrency futures at once
+// with the handlers of the tasks in this process. The result of each future is streamed
+// back to the service, which completes the future, retrying it if it failed and the task
+// has a retry policy on the service; the Success and Failure callbacks of the tasks are
+// called by the worker once the service has completed the future. Futures canceled on
+// the service cancel the context passed to ContextTask and ResultTask handlers, and the
+// metadata of the future is available on the context with radish.MetadataFrom. Work
+// blocks until the context is canceled, reconnecting if the service becomes unavailable;
+// futures that are being handled when the stream is closed fail on the service.
+func (c *Client) Work(ctx context.Context, name string, concurrency int, tasks ...radish.Task) (err error) {
+	if c.remote == nil {
+		return fmt.Errorf("remote workers can only connect to a remote radish service")
+	}
+
+	if len(tasks) == 0 {
+		return radish.Errorf(radish.CodeInvalidWorkers, "remote workers must handle at least one task")
+	}
+
+	hello := &api.WorkerHello{Name: name, Concurrency: int32(concurrency)}
+	handlers := make(map[string]radish.Task, len(tasks))
+	for _, task := range tasks {
+		handlers[task.Name()] = task
+		hello.Tasks = append(hello.Tasks, task.Name())
+	}
+
+	return c.reconnect(ctx, func(ctx context.Context, received *bool) (err error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var stream api.Radish_WorkClient
+		if stream, err = c.remote.Work(ctx); err != nil {
+			return err
+		}
+
+		if err = stream.Send(&api.WorkerMessage{Hello: hello}); err != nil {
+			return err
+		}
+
+		w := &remoteWorker{stream: stream, handlers: handlers, running: make(map[string]context.CancelFunc)}
+		defer w.wg.Wait()
+		defer cancel()
+
+		for {
+			var assignment *api.WorkAssignment
+			if assignment, err = stream.Recv(); err != nil {
+				if err == io.EOF || ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+
+			*received = true
+			w.assign(ctx, assignment)
+		}
+	})
+}
+
+// remoteWorker handles the futures assigned to the process by a radish service.
+type remoteWorker struct {
+	sync.Mutex
+	stream   api.Radish_WorkClient
+	sendMu   sync.Mutex // streams do not support concurrent sends
+	handlers map[string]radish.Task
+	running  map[string]context.CancelFunc // cancels the futures being handled, by id
+	wg       sync.WaitGroup
+}
+
+func (w *remoteWorker) assign(ctx context.Context, assignment *api.WorkAssignment) {
+	id := uuid.UUID(assignment.Uuid)
+	handler, ok := w.handlers[assignment.Task]
+	if !ok {
+		if assignment.Kind == api.WorkAssignment_HANDLE {
+			w.send(&api.WorkResult{Uuid: id, Error: &api.Error{Code: radish.CodeTaskNotRegistered, Message: fmt.Sprintf("remote worker cannot handle task %q", assignment.Task)}})
+		}
+		return
+	}
+
+	switch assignment.Kind {
+	case api.WorkAssignment_HANDLE:
+		ctx, cancel := context.WithCancel(ctx)
+		for key, value := range assignment.Metadata {
+			ctx = radish.WithMetadata(ctx, key, value)
+		}
+
+		w.Lock()
+		w.running[id.String()] = cancel
+		w.Unlock()
+
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.send(w.handle(ctx, handler, id, assignment.Params))
+
+			w.Lock()
+			delete(w.running, id.String())
+			w.Unlock()
+			cancel()
+		}()
+	case api.WorkAssignment_CANCEL:
+		w.Lock()
+		if cancel, ok := w.running[id.String()]; ok {
+			cancel()
+		}
+		w.Unlock()
+	case api.WorkAssignment_SUCCESS:
+		handler.Success(id, assignment.Params)
+	case api.WorkAssignment_FAILURE:
+		var err error = assignment.Error
+		if assignment.Error == nil {
+			err = radish.Errorf(radish.CodeUnknown, "%s future %s failed", assignment.Task, id)
+		}
+		handler.Failure(id, err, assignment.Params)
+	}
+}
+
+// handle the future with the handler, returning its result.
+func (w *remoteWorker) handle(ctx context.Context, handler radish.Task, id uuid.UUID, params []byte) (result *api.WorkResult) {
+	result = &api.WorkResult{Uuid: id}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Success, result.Result = false, nil
+			result.Error = &api.Error{Code: radish.CodeUnknown, Message: fmt.Sprintf("%s task panicked: %v", handler.Name(), r)}
+		}
+	}()
+
+	var err error
+	switch task := handler.(type) {
+	case radish.ResultTask:
+		result.Result, err = task.HandleResult(ctx, id, params)
+	case radish.ContextTask:
+		err = task.HandleContext(ctx, id, params)
+	default:
+		err = handler.Handle(id, params)
+	}
+
+	if err != nil {
+		result.Error = APIError(err)
+		return result
+	}
+
+	result.Success = true
+	return result
+}
+
+// send the result to the service, ignoring errors since the service fails the futures
+// assigned to the worker if the stream is closed.
+func (w *remoteWorker) send(result *api.WorkResult) {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+	w.stream.Send(&api.WorkerMessage{Result: result})
+}
@@ -0,0 +1,93 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	. "github.com/kansaslabs/radish/client"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestWork(t *testing.T) {
+	queue, err := radish.New(&radish.Config{Workers: 1, NoSignals: true, LogLevel: "warn"}, &noopTask{})
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	api.RegisterRadishServer(srv, queue)
+	go srv.Serve(sock)
+	defer srv.Stop()
+
+	client, err := New(&Options{Addr: sock.Addr().String(), Insecure: true})
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Tasks handled by local handlers cannot be handled by remote workers
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = client.Work(ctx, "worker", 2, &noopTask{})
+	require.Contains(t, err.Error(), `[2] task named "noop" is handled by a local handler`)
+
+	// The remote worker adds workers to the queue until it disconnects
+	task := &echoTask{failures: make(chan string, 1)}
+	done := make(chan error, 1)
+	go func() { done <- client.Work(ctx, "worker", 2, task) }()
+
+	require.Eventually(t, func() bool { return queue.NumWorkers() == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	// Futures are handled by the remote worker with the metadata of the future
+	id, err := queue.DelayContext(radish.WithMetadata(ctx, "trace", "abc"), "echo", []byte("hello"), nil, nil)
+	require.NoError(t, err)
+	result, err := queue.Wait(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "hello abc", string(result))
+
+	// Errors are streamed back and the failure callback is called by the remote worker
+	id, err = queue.Delay("echo", nil, nil, []byte("boo"))
+	require.NoError(t, err)
+	_, err = queue.Wait(ctx, id)
+	require.EqualError(t, err, "[0] no params")
+
+	select {
+	case params := <-task.failures:
+		require.Equal(t, "boo", params)
+	case <-time.After(5 * time.Second):
+		t.Fatal("failure callback was not called by the remote worker")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+	require.Eventually(t, func() bool { return queue.NumWorkers() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Futures of remote tasks fail if no remote workers are connected
+	id, err = queue.Delay("echo", []byte("hello"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Wait(context.Background(), id)
+	require.EqualError(t, err, `[4] no remote workers are connected to handle task "echo"`)
+}
+
+type echoTask struct {
+	noopTask
+	failures chan string
+}
+
+func (t *echoTask) Name() string { return "echo" }
+
+func (t *echoTask) HandleResult(ctx context.Context, id uuid.UUID, params []byte) ([]byte, error) {
+	if len(params) == 0 {
+		return nil, errors.New("no params")
+	}
+	return []byte(string(params) + " " + radish.MetadataFrom(ctx)["trace"]), nil
+}
+
+func (t *echoTask) Failure(id uuid.UUID, err error, params []byte) {
+	t.failures <- string(params)
+}
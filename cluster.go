@@ -0,0 +1,320 @@
+package radish
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// Default amount of time between polls of the status of the peers of the cluster.
+const defaultPeerInterval = 5 * time.Second
+
+// peers manages connections to the other radish servers in the cluster so that a
+// connection is not dialed for every request.
+type peers struct {
+	sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+func newPeers() *peers {
+	return &peers{conns: make(map[string]*grpc.ClientConn)}
+}
+
+// client returns a client of the peer at the address, dialing it if necessary.
+func (p *peers) client(addr string, config *Config) (_ api.RadishClient, err error) {
+	p.Lock()
+	defer p.Unlock()
+
+	conn, ok := p.conns[addr]
+	if !ok {
+		opt := grpc.WithInsecure()
+		if config.PeerTLS != nil {
+			opt = grpc.WithTransportCredentials(credentials.NewTLS(config.PeerTLS))
+		}
+
+		if conn, err = grpc.Dial(addr, opt); err != nil {
+			return nil, err
+		}
+		p.conns[addr] = conn
+	}
+	return api.NewRadishClient(conn), nil
+}
+
+// forwards records the futures forwarded to peers of the cluster so that they can still
+// be waited on, looked up, and canceled with the id they were delayed with.
+type forwards struct {
+	sync.Mutex
+	ids map[string]forward
+}
+
+// forward is the peer a future was forwarded to and the id the peer assigned to it.
+type forward struct {
+	addr    string
+	id      uuid.UUID
+	expires time.Time
+}
+
+func newForwards() *forwards {
+	return &forwards{ids: make(map[string]forward)}
+}
+
+func (f *forwards) add(id uuid.UUID, fwd forward) {
+	f.Lock()
+	f.ids[id.String()] = fwd
+	f.Unlock()
+}
+
+func (f *forwards) get(id uuid.UUID) (fwd forward, ok bool) {
+	f.Lock()
+	defer f.Unlock()
+	fwd, ok = f.ids[id.String()]
+	return fwd, ok
+}
+
+// sweep removes the forwards that have expired, returning the number removed.
+func (f *forwards) sweep(now time.Time) (n int) {
+	f.Lock()
+	defer f.Unlock()
+	for id, fwd := range f.ids {
+		if now.After(fwd.expires) {
+			delete(f.ids, id)
+			n++
+		}
+	}
+	return n
+}
+
+// close the connections to the peers.
+func (p *peers) close() {
+	p.Lock()
+	defer p.Unlock()
+	for addr, conn := range p.conns {
+		conn.Close()
+		delete(p.conns, addr)
+	}
+}
+
+// peerContext returns a context that authenticates requests to peers with the shared
+// AuthToken of the cluster, if any.
+func (r *Radish) peerContext(ctx context.Context) context.Context {
+	if r.config.AuthToken == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, AuthMetadataKey, "Bearer "+r.config.AuthToken)
+}
+
+// clusterStatus returns the status of every peer of the cluster, requested concurrently
+// with the same sections and tasks as the request.
+func (r *Radish) clusterStatus(ctx context.Context, in *api.StatusRequest) []*api.PeerStatus {
+	statuses := make([]*api.PeerStatus, len(r.config.Peers))
+	wg := new(sync.WaitGroup)
+	for i, addr := range r.config.Peers {
+		statuses[i] = &api.PeerStatus{Addr: addr}
+		wg.Add(1)
+		go func(status *api.PeerStatus) {
+			defer wg.Done()
+			client, err := r.peers.client(status.Addr, r.config)
+			if err == nil {
+				status.Status, err = client.Status(r.peerContext(ctx), &api.StatusRequest{Sections: in.Sections, Tasks: in.Tasks})
+			}
+
+			switch {
+			case err != nil:
+				status.Error = &api.Error{Code: CodeBadGateway, Message: err.Error()}
+			case !status.Status.Success:
+				status.Error = status.Status.Error
+			}
+		}(statuses[i])
+	}
+	wg.Wait()
+	return statuses
+}
+
+// balancer polls the status of the peers of the cluster every PeerInterval and forwards
+// the futures that this queue does not have idle workers for to peers with idle workers
+// until the queue is shutdown.
+func (r *Radish) balancer() {
+	ticker := time.NewTicker(r.config.PeerInterval)
+	defer ticker.Stop()
+	defer r.peers.close()
+
+	for {
+		select {
+		case <-r.shutdown:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.config.PeerInterval)
+			r.balance(ctx)
+			cancel()
+		}
+	}
+}
+
+// balance forwards the futures waiting in the queue beyond the number of idle workers to
+// the peers that have more idle workers than futures waiting in their queue.
+func (r *Radish) balance(ctx context.Context) {
+	if r.isDraining() {
+		return
+	}
+
+	idle := r.NumWorkers() - r.BusyWorkers()
+	if r.Paused() {
+		idle = 0
+	}
+
+	backlog := r.tasks.Len() - idle
+	if backlog <= 0 {
+		return
+	}
+
+	// Only the summary of the peers is needed, so request the cheap workers section
+	workers := &api.StatusRequest{Sections: []api.StatusRequest_Section{api.StatusRequest_WORKERS}}
+	for _, peer := range r.clusterStatus(ctx, workers) {
+		if peer.Status == nil || peer.Status.Draining || peer.Status.Paused {
+			continue
+		}
+
+		spare := int(peer.Status.Workers) - int(peer.Status.InFlight) - int(peer.Status.Queue)
+		if spare <= 0 {
+			continue
+		}
+		if spare > backlog {
+			spare = backlog
+		}
+
+		client, err := r.peers.client(peer.Addr, r.config)
+		if err != nil {
+			continue
+		}
+
+		n, err := r.forward(ctx, peer.Addr, client, spare)
+		if n > 0 {
+			r.logf(out.LevelInfo, "", "forwarded %d futures to idle workers of %s", n, peer.Addr)
+		}
+		if err != nil {
+			r.logf(out.LevelWarn, "", "could not forward futures to %s: %s", peer.Addr, err)
+			continue
+		}
+
+		if backlog -= n; backlog <= 0 {
+			return
+		}
+	}
+}
+
+// forward up to n of the pending futures to the peer, returning the number of futures
+// forwarded. Futures that are steps of a workflow, members of a group, children of
+// another future, or that have a partition key are tracked by this queue, so they are put
+// back on the queue rather than forwarded, as are futures that the peer did not accept.
+func (r *Radish) forward(ctx context.Context, addr string, client api.RadishClient, n int) (forwarded int, err error) {
+	// Dequeue with a done context so that only the pending futures are forwarded
+	pending, cancel := context.WithCancel(ctx)
+	cancel()
+
+	local := make([]*Future, 0)
+	defer func() {
+		for _, future := range local {
+			r.keep(future)
+		}
+	}()
+
+	for forwarded < n {
+		var future *Future
+		if future, err = r.tasks.Dequeue(pending); err != nil {
+			return forwarded, nil
+		}
+		r.releaseQuotas(future)
+
+		if future.Workflow != nil || future.Group != nil || future.Parent != nil || future.PartitionKey != "" {
+			local = append(local, future)
+			continue
+		}
+
+		var ok bool
+		if ok, err = r.sendFuture(ctx, addr, client, future); err != nil {
+			local = append(local, future)
+			return forwarded, Errorf(CodeBadGateway, "could not forward future %s: %s", future.ID, err)
+		}
+
+		if !ok {
+			local = append(local, future)
+			continue
+		}
+
+		pmTasksForwarded.WithLabelValues(future.Task).Inc()
+		forwarded++
+	}
+	return forwarded, nil
+}
+
+// sendFuture queues the dequeued future on another radish server and forgets it, except
+// for the id assigned to it by the other server so that requests with its original id
+// can be forwarded to the server. If the request fails or the server replies with an
+// error, false is returned and the caller must put the future back with keep.
+func (r *Radish) sendFuture(ctx context.Context, addr string, client api.RadishClient, future *Future) (_ bool, err error) {
+	req := &api.QueueRequest{
+		Task:           future.Task,
+		Params:         future.Params,
+		Success:        future.Success,
+		Failure:        future.Failure,
+		Callback:       future.Callback,
+		Priority:       future.Priority,
+		IdempotencyKey: future.IdempotencyKey,
+		PartitionKey:   future.PartitionKey,
+		Metadata:       future.Metadata,
+	}
+
+	ctx = r.peerContext(ctx)
+	if future.client != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, ClientMetadataKey, future.client)
+	}
+
+	var rep *api.QueueReply
+	if rep, err = client.Queue(ctx, req); err != nil {
+		return false, err
+	}
+
+	if !rep.Success {
+		r.logf(out.LevelWarn, future.Task, "%s did not accept %s future %s: %s", addr, future.Task, future.ID, rep.Error)
+		return false, nil
+	}
+
+	r.forwards.add(future.ID, forward{addr: addr, id: uuid.UUID(rep.Uuid), expires: time.Now().Add(r.config.ResultTTL)})
+	r.ack(future)
+	r.releasePartition(future)
+	r.inflight.drop(future.ID)
+	r.results.remove(future.ID)
+	r.unpersist(future)
+	return true, nil
+}
+
+// keep puts a future that was dequeued to be sent to another server back on the queue.
+func (r *Radish) keep(future *Future) {
+	if err := r.requeue(context.Background(), future); err != nil {
+		r.logf(out.LevelWarn, future.Task, "could not keep %s future %s: %s", future.Task, future.ID, err)
+	}
+	r.ack(future)
+}
+
+// forwarded returns a client of the peer that the future with the id was forwarded to and
+// the id that the peer assigned to the future, or false if the future was not forwarded.
+func (r *Radish) forwarded(id uuid.UUID) (client api.RadishClient, peerID uuid.UUID, ok bool) {
+	var fwd forward
+	if fwd, ok = r.forwards.get(id); !ok {
+		return nil, nil, false
+	}
+
+	var err error
+	if client, err = r.peers.client(fwd.addr, r.config); err != nil {
+		r.logf(out.LevelWarn, "", "could not connect to %s for forwarded future %s: %s", fwd.addr, id, err)
+		return nil, nil, false
+	}
+	return client, fwd.id, true
+}
@@ -0,0 +1,140 @@
+package radish_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCluster(t *testing.T) {
+	// The peer has idle workers to handle the futures of the overloaded queue
+	var handled int32
+	wg := new(sync.WaitGroup)
+	peer, err := New(&Config{Workers: 4, NoSignals: true, SuppressMetrics: true, LogLevel: "warn"}, &testTask{wg: wg, name: "work", onHandle: func(id uuid.UUID, params []byte) error {
+		atomic.AddInt32(&handled, 1)
+		return nil
+	}})
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer peer.Shutdown()
+
+	// The only worker of the overloaded queue is blocked
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	blocking := &testTask{wg: wg, name: "work", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	conf := &Config{Workers: 1, NoSignals: true, LogLevel: "warn", Peers: []string{sock.Addr().String(), "127.0.0.1:1"}, PeerInterval: 20 * time.Millisecond}
+	queue, err := New(conf, blocking)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+	go peer.Serve(sock)
+
+	wg.Add(1)
+	_, err = queue.Delay("work", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	// The futures waiting for the blocked worker are forwarded to the idle peer
+	wg.Add(4)
+	ids := make([]uuid.UUID, 0, 4)
+	for i := 0; i < 4; i++ {
+		id, err := queue.Delay("work", nil, nil, nil)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&handled) == 4 }, 2*time.Second, 10*time.Millisecond)
+
+	// The forwarded futures can still be waited on and looked up with their original ids
+	for _, id := range ids {
+		_, err = queue.Wait(context.Background(), id)
+		require.NoError(t, err)
+
+		future, err := queue.GetFuture(context.Background(), &api.GetFutureRequest{Uuid: id})
+		require.NoError(t, err)
+		require.True(t, future.Success)
+		require.Equal(t, api.FutureState_SUCCEEDED, future.Future.State)
+		require.True(t, uuid.Equal(id, future.Future.Uuid))
+	}
+
+	close(release)
+	wg.Wait()
+
+	// The status of every peer is returned with the status of the cluster
+	rep, err := queue.Status(context.Background(), &api.StatusRequest{Cluster: true})
+	require.NoError(t, err)
+	require.Len(t, rep.Peers, 2)
+	require.Equal(t, sock.Addr().String(), rep.Peers[0].Addr)
+	require.Equal(t, int32(4), rep.Peers[0].Status.Workers)
+	require.Nil(t, rep.Peers[0].Error)
+	require.Nil(t, rep.Peers[1].Status)
+	require.Equal(t, CodeBadGateway, rep.Peers[1].Error.Code)
+
+	rep, err = queue.Status(context.Background(), &api.StatusRequest{})
+	require.NoError(t, err)
+	require.Empty(t, rep.Peers)
+}
+
+func TestClusterRejected(t *testing.T) {
+	// The peer has idle workers but rejects the params of the futures
+	var remote, local int32
+	wg := new(sync.WaitGroup)
+	peer, err := New(&Config{Workers: 4, NoSignals: true, SuppressMetrics: true, LogLevel: "silent", MaxParamSize: 1}, &testTask{wg: wg, name: "work", onHandle: func(id uuid.UUID, params []byte) error {
+		atomic.AddInt32(&remote, 1)
+		return nil
+	}})
+	require.NoError(t, err)
+	defer peer.Shutdown()
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	blocking := &testTask{wg: wg, name: "work", onHandle: func(id uuid.UUID, params []byte) error {
+		if atomic.AddInt32(&local, 1) == 1 {
+			started <- struct{}{}
+			<-release
+		}
+		return nil
+	}}
+
+	conf := &Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent", Peers: []string{sock.Addr().String()}, PeerInterval: 10 * time.Millisecond}
+	queue, err := New(conf, blocking)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+	go peer.Serve(sock)
+
+	wg.Add(5)
+	_, err = queue.Delay("work", []byte("params"), nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	for i := 0; i < 4; i++ {
+		_, err = queue.Delay("work", []byte("params"), nil, nil)
+		require.NoError(t, err)
+	}
+
+	// Give the balancer a few rounds to try to forward the futures
+	time.Sleep(100 * time.Millisecond)
+
+	// The rejected futures are kept and handled locally
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(5), atomic.LoadInt32(&local))
+	require.Zero(t, atomic.LoadInt32(&remote))
+}
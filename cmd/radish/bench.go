@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/radish/client"
+	"github.com/urfave/cli"
+)
+
+// bench queues tasks at the target rate for the duration, e.g. against a turnip server,
+// and prints a summary of the enqueue latency and the errors returned by the service.
+// Requests that would exceed the maximum concurrency are skipped rather than delayed so
+// that a slow service does not skew the schedule of the remaining requests.
+func bench(c *cli.Context) (err error) {
+	var task string
+	if task = c.String("task"); task == "" {
+		return cli.NewExitError("must specify a task name to enqueue with --task", 1)
+	}
+
+	rate := c.Float64("rate")
+	if rate <= 0 {
+		return cli.NewExitError("the rate must be greater than zero", 1)
+	}
+
+	duration := c.Duration("duration")
+	if duration <= 0 {
+		return cli.NewExitError("the duration must be greater than zero", 1)
+	}
+
+	concurrency := c.Int("concurrency")
+	if concurrency <= 0 {
+		return cli.NewExitError("the concurrency must be greater than zero", 1)
+	}
+
+	var params []byte
+	if p := c.String("params"); p != "" {
+		params = []byte(p)
+	}
+
+	fmt.Fprintf(os.Stderr, "queueing %s tasks at %0.1f/s for %s\n", task, rate, duration)
+
+	results := &benchResults{errors: make(map[string]int)}
+	interval := time.Duration(float64(time.Second) / rate)
+	slots := make(chan struct{}, concurrency)
+	wg := new(sync.WaitGroup)
+
+	// Schedule each request relative to the start so that the target rate is kept on average
+	started := time.Now()
+	for i := 0; ; i++ {
+		next := started.Add(time.Duration(i) * interval)
+		if next.Sub(started) >= duration {
+			break
+		}
+		time.Sleep(time.Until(next))
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			results.skip()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
+			defer cancel()
+
+			start := time.Now()
+			_, err := rc.Queue(ctx, task, params, nil, nil)
+			results.add(time.Since(start), err)
+		}()
+	}
+
+	wg.Wait()
+	return printResponse(results.summary(task, rate, time.Since(started)))
+}
+
+// benchResults collects the latency and outcome of every request made by bench.
+type benchResults struct {
+	sync.Mutex
+	latencies []time.Duration
+	failed    int
+	skipped   int
+	errors    map[string]int // the number of failed requests by error message
+}
+
+func (r *benchResults) add(latency time.Duration, err error) {
+	r.Lock()
+	defer r.Unlock()
+	r.latencies = append(r.latencies, latency)
+	if err != nil {
+		r.failed++
+		r.errors[client.APIError(err).Error()]++
+	}
+}
+
+func (r *benchResults) skip() {
+	r.Lock()
+	defer r.Unlock()
+	r.skipped++
+}
+
+// summary returns the throughput, error rate, and latency distribution of the requests.
+func (r *benchResults) summary(task string, rate float64, elapsed time.Duration) map[string]interface{} {
+	r.Lock()
+	defer r.Unlock()
+
+	requests := len(r.latencies)
+	summary := map[string]interface{}{
+		"task":        task,
+		"target_rate": rate,
+		"elapsed":     elapsed.Round(time.Millisecond).String(),
+		"requests":    requests,
+		"succeeded":   requests - r.failed,
+		"failed":      r.failed,
+		"skipped":     r.skipped,
+		"throughput":  math.Round(float64(requests)/elapsed.Seconds()*100) / 100,
+		"error_rate":  0.0,
+		"errors":      r.errors,
+		"success":     r.failed == 0,
+	}
+
+	if requests == 0 {
+		return summary
+	}
+	summary["error_rate"] = math.Round(float64(r.failed)/float64(requests)*10000) / 10000
+
+	sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+	var total time.Duration
+	for _, latency := range r.latencies {
+		total += latency
+	}
+
+	percentile := func(p float64) string {
+		i := int(math.Ceil(p*float64(requests))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return r.latencies[i].Round(time.Microsecond).String()
+	}
+
+	summary["latency"] = map[string]string{
+		"min":  r.latencies[0].Round(time.Microsecond).String(),
+		"mean": (total / time.Duration(requests)).Round(time.Microsecond).String(),
+		"p50":  percentile(0.5),
+		"p90":  percentile(0.9),
+		"p99":  percentile(0.99),
+		"max":  r.latencies[requests-1].Round(time.Microsecond).String(),
+	}
+	return summary
+}
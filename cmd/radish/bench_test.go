@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kansaslabs/radish"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchSummary(t *testing.T) {
+	results := &benchResults{errors: make(map[string]int)}
+
+	// No requests have no latency distribution
+	summary := results.summary("email", 10, time.Second)
+	require.Equal(t, 0, summary["requests"])
+	require.Equal(t, 0.0, summary["error_rate"])
+	require.Equal(t, true, summary["success"])
+	require.NotContains(t, summary, "latency")
+
+	// The latencies of 100 requests from 1ms to 100ms, 5 of which failed
+	for i := 100; i > 0; i-- {
+		var err error
+		if i%20 == 0 {
+			err = radish.Errorf(radish.CodeQueueFull, "queue is full")
+		}
+		results.add(time.Duration(i)*time.Millisecond, err)
+	}
+	results.skip()
+	results.skip()
+
+	summary = results.summary("email", 50, 2*time.Second)
+	require.Equal(t, 100, summary["requests"])
+	require.Equal(t, 95, summary["succeeded"])
+	require.Equal(t, 5, summary["failed"])
+	require.Equal(t, 2, summary["skipped"])
+	require.Equal(t, 50.0, summary["throughput"])
+	require.Equal(t, 0.05, summary["error_rate"])
+	require.Equal(t, false, summary["success"])
+	require.Equal(t, map[string]int{fmt.Sprintf("[%d] queue is full", radish.CodeQueueFull): 5}, summary["errors"])
+	require.Equal(t, map[string]string{
+		"min":  "1ms",
+		"mean": "50.5ms",
+		"p50":  "50ms",
+		"p90":  "90ms",
+		"p99":  "99ms",
+		"max":  "100ms",
+	}, summary["latency"])
+}
+
+func TestBench(t *testing.T) {
+	addr, _ := serve(t, &task{name: "noop"})
+
+	// Queue tasks at 200/s for 100ms
+	out, err := run(t, addr, "bench", "--task", "noop", "--rate", "200", "--duration", "100ms")
+	require.NoError(t, err)
+
+	summary := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(out), &summary))
+	require.Equal(t, "noop", summary["task"])
+	require.Equal(t, 20.0, summary["requests"].(float64)+summary["skipped"].(float64))
+	require.Equal(t, 0.0, summary["failed"])
+	require.Equal(t, true, summary["success"])
+	require.Contains(t, summary, "latency")
+
+	// Tasks that are not registered are reported as errors
+	out, err = run(t, addr, "bench", "--task", "unknown", "--rate", "100", "--duration", "50ms")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &summary))
+	require.Equal(t, 0.0, summary["succeeded"])
+	require.Equal(t, 1.0, summary["error_rate"])
+	require.Equal(t, false, summary["success"])
+	require.Len(t, summary["errors"], 1)
+
+	// Invalid flags are rejected before any requests are made
+	for msg, args := range map[string][]string{
+		"must specify a task name to enqueue with --task": {"bench"},
+		"the rate must be greater than zero":              {"bench", "--task", "noop", "--rate", "0"},
+		"the duration must be greater than zero":          {"bench", "--task", "noop", "--duration", "0s"},
+		"the concurrency must be greater than zero":       {"bench", "--task", "noop", "--concurrency", "0"},
+	} {
+		_, err = run(t, addr, args...)
+		require.EqualError(t, err, msg)
+	}
+}
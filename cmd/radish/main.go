@@ -6,20 +6,26 @@ task queue, and scale the radish service.
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kansaslabs/radish"
 	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/radish/client"
 	"github.com/kansaslabs/x/noplog"
+	"github.com/pborman/uuid"
 	"github.com/urfave/cli"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/grpclog"
 )
 
@@ -28,16 +34,18 @@ func init() {
 	grpclog.SetLogger(noplog.New())
 }
 
-var (
-	conn   *grpc.ClientConn
-	client api.RadishClient
-)
+var rc *client.Client
 
 func main() {
 	// Load the .env file if exists
 	godotenv.Load()
 
-	// Instantiate the CLI application
+	// Run the program
+	newApp().Run(os.Args)
+}
+
+// newApp instantiates the CLI application with its flags and commands.
+func newApp() *cli.App {
 	app := cli.NewApp()
 	app.Name = "radish"
 	app.Version = radish.PackageVersion
@@ -62,6 +70,17 @@ func main() {
 			Usage:  "do not connect with TLS, connect unsecure",
 			EnvVar: "RADISH_UNSECURE",
 		},
+		cli.StringFlag{
+			Name:   "o, output",
+			Usage:  "format of the responses: json, yaml, or table",
+			Value:  "json",
+			EnvVar: "RADISH_OUTPUT",
+		},
+		cli.StringFlag{
+			Name:   "K, token",
+			Usage:  "auth token or API key if the radish service requires authentication",
+			EnvVar: "RADISH_TOKEN",
+		},
 	}
 
 	// Define commands available to the application
@@ -88,6 +107,27 @@ func main() {
 					Name:  "f, failure",
 					Usage: "parameters to pass to the failure callback",
 				},
+				cli.StringFlag{
+					Name:  "idempotency-key",
+					Usage: "return the existing future if the task was already queued with the key",
+				},
+				cli.StringFlag{
+					Name:  "partition-key",
+					Usage: "handle the future after the futures queued before it with the same key",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "check if the task would be queued without queueing it",
+				},
+				cli.StringFlag{
+					Name:  "file",
+					Usage: "queue the newline-delimited JSON task specs in the file (- for stdin)",
+				},
+				cli.IntFlag{
+					Name:  "batch-size",
+					Usage: "number of task specs from the file to queue in each request",
+					Value: 500,
+				},
 			},
 		},
 		{
@@ -100,47 +140,284 @@ func main() {
 					Name:  "w, workers",
 					Usage: "set number of workers to handle tasks",
 				},
+				cli.DurationFlag{
+					Name:  "d, drain-timeout",
+					Usage: "wait at most this long for removed workers to finish their tasks",
+				},
+				cli.BoolFlag{
+					Name:  "f, force",
+					Usage: "cancel the tasks of removed workers still draining after the drain timeout",
+				},
+			},
+		},
+		{
+			Name:     "autoscale",
+			Usage:    "turn autoscaling of the workers on or off and change its bounds",
+			Action:   autoscale,
+			Category: "radish",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "e, enable",
+					Usage: "turn autoscaling on",
+				},
+				cli.BoolFlag{
+					Name:  "d, disable",
+					Usage: "turn autoscaling off, pinning the current number of workers",
+				},
+				cli.IntFlag{
+					Name:  "m, min",
+					Usage: "the minimum number of workers to autoscale to",
+				},
+				cli.IntFlag{
+					Name:  "M, max",
+					Usage: "the maximum number of workers to autoscale to",
+				},
 			},
 		},
+		{
+			Name:     "ping",
+			Usage:    "check connectivity and compatibility with the radish server",
+			Action:   ping,
+			Category: "radish",
+			Flags:    []cli.Flag{},
+		},
 		{
 			Name:     "status",
 			Usage:    "get the current status of the radish task queue",
 			Action:   status,
 			Category: "radish",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "c, cluster",
+					Usage: "also get the status of every peer in the cluster of the queue",
+				},
+				cli.StringSliceFlag{
+					Name:  "s, section",
+					Usage: "sections of the status to get: tasks, workers, in-flight, or scheduler (default tasks)",
+				},
+				cli.StringSliceFlag{
+					Name:  "t, task",
+					Usage: "only get the status of the specified tasks",
+				},
+			},
+		},
+		{
+			Name:     "tasks",
+			Usage:    "list the registered tasks with the number of futures handled by each",
+			Action:   tasks,
+			Category: "radish",
 			Flags:    []cli.Flag{},
 		},
+		{
+			Name:     "drain",
+			Usage:    "stop accepting new tasks and wait until the queue is empty",
+			Action:   drain,
+			Category: "radish",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "shutdown",
+					Usage: "shutdown the radish service once the queue has been drained",
+				},
+				cli.DurationFlag{
+					Name:  "i, interval",
+					Usage: "how often to check and report the progress of the drain",
+					Value: time.Second,
+				},
+				cli.DurationFlag{
+					Name:  "w, wait",
+					Usage: "maximum time to wait for the queue to drain (default forever)",
+				},
+			},
+		},
+		{
+			Name:     "reload",
+			Usage:    "change the workers, log level, or quotas without restarting the service",
+			Action:   reload,
+			Category: "radish",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "load",
+					Usage: "reload the config of the service as a reload signal does",
+				},
+				cli.IntFlag{
+					Name:  "w, workers",
+					Usage: "set the number of workers to handle tasks",
+				},
+				cli.StringFlag{
+					Name:  "l, log-level",
+					Usage: "set the level to log at",
+				},
+				cli.UintFlag{
+					Name:  "caution-threshold",
+					Usage: "set the number of messages accumulated before issuing another caution",
+				},
+				cli.IntFlag{
+					Name:  "task-quota",
+					Usage: "set the quota of futures of each task that may be queued, -1 for unlimited",
+				},
+				cli.IntFlag{
+					Name:  "client-quota",
+					Usage: "set the quota of pending futures of each client, -1 for unlimited",
+				},
+			},
+		},
+		{
+			Name:      "cancel",
+			Usage:     "cancel queued or in-flight tasks",
+			ArgsUsage: "uuid [uuid ...]",
+			Action:    cancelFutures,
+			Category:  "radish",
+			Flags:     []cli.Flag{},
+		},
+		{
+			Name:     "script",
+			Usage:    "register or update a lua script task",
+			Action:   script,
+			Category: "radish",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "t, task",
+					Usage: "name of the script task to register or update",
+				},
+				cli.StringFlag{
+					Name:  "f, file",
+					Usage: "path to the lua script that defines the handle function",
+				},
+			},
+		},
+		{
+			Name:      "deadletters",
+			Usage:     "list, re-drive, or purge futures that failed permanently",
+			ArgsUsage: "[uuid ...]",
+			Action:    deadletters,
+			Category:  "radish",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "t, task",
+					Usage: "only the dead letters of the specified task",
+				},
+				cli.IntFlag{
+					Name:  "n, limit",
+					Usage: "number of recent dead letters to list (default all)",
+				},
+				cli.BoolFlag{
+					Name:  "r, redrive",
+					Usage: "enqueue the dead letters again instead of listing them",
+				},
+				cli.BoolFlag{
+					Name:  "purge",
+					Usage: "remove the dead letters without handling them",
+				},
+			},
+		},
+		{
+			Name:     "logs",
+			Usage:    "view the log output of the radish service",
+			Action:   logs,
+			Category: "radish",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "f, follow",
+					Usage: "stream new log entries until interrupted",
+				},
+				cli.IntFlag{
+					Name:  "n, tail",
+					Usage: "number of recent log entries to show",
+					Value: 100,
+				},
+				cli.StringFlag{
+					Name:  "l, level",
+					Usage: "minimum level of log entries to show",
+					Value: "info",
+				},
+				cli.StringSliceFlag{
+					Name:  "t, task",
+					Usage: "only show log entries for the specified task(s)",
+				},
+			},
+		},
+		{
+			Name:     "watch",
+			Usage:    "live view of the queue depth, workers, and recently completed tasks",
+			Action:   watch,
+			Category: "radish",
+			Flags: []cli.Flag{
+				cli.DurationFlag{
+					Name:  "i, interval",
+					Usage: "how often to refresh the view",
+					Value: time.Second,
+				},
+				cli.IntFlag{
+					Name:  "n, recent",
+					Usage: "number of recently completed tasks to show",
+					Value: 10,
+				},
+				cli.StringSliceFlag{
+					Name:  "t, task",
+					Usage: "only show completions of the specified task(s)",
+				},
+			},
+		},
+		{
+			Name:     "bench",
+			Usage:    "queue tasks at a target rate and summarize the enqueue latency and errors",
+			Action:   bench,
+			Category: "radish",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "t, task",
+					Usage: "name of the task to enqueue",
+				},
+				cli.StringFlag{
+					Name:  "p, params",
+					Usage: "parameters to pass to the handler",
+				},
+				cli.Float64Flag{
+					Name:  "r, rate",
+					Usage: "number of tasks to queue per second",
+					Value: 100,
+				},
+				cli.DurationFlag{
+					Name:  "d, duration",
+					Usage: "how long to queue tasks for",
+					Value: time.Minute,
+				},
+				cli.IntFlag{
+					Name:  "c, concurrency",
+					Usage: "maximum number of queue requests in flight at once",
+					Value: 64,
+				},
+			},
+		},
 	}
-
-	// Run the program
-	app.Run(os.Args)
+	return app
 }
 
 func connect(c *cli.Context) (err error) {
-	opts := make([]grpc.DialOption, 0, 2)
-	opts = append(opts, grpc.WithTimeout(c.Duration("timeout")))
-
-	if c.Bool("unsecure") {
-		opts = append(opts, grpc.WithInsecure())
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	if err = setOutput(c.String("output")); err != nil {
+		return cli.NewExitError(err, 1)
 	}
 
-	if conn, err = grpc.Dial(c.String("addr"), opts...); err != nil {
-		return cli.NewExitError(fmt.Errorf("could not connect to %s: %s", c.String("addr"), err), 1)
+	opts := &client.Options{
+		Addr:     c.String("addr"),
+		Timeout:  c.Duration("timeout"),
+		Insecure: c.Bool("unsecure"),
+		Token:    c.String("token"),
 	}
 
-	client = api.NewRadishClient(conn)
+	if rc, err = client.New(opts); err != nil {
+		return cli.NewExitError(err, 1)
+	}
 	return nil
 }
 
 func cleanup(c *cli.Context) (err error) {
 	defer func() {
-		conn = nil
-		client = nil
+		rc = nil
 	}()
 
-	if conn != nil {
-		if err = conn.Close(); err != nil {
+	if rc != nil {
+		if err = rc.Close(); err != nil {
 			return cli.NewExitError(err, 1)
 		}
 	}
@@ -148,33 +425,172 @@ func cleanup(c *cli.Context) (err error) {
 }
 
 func queue(c *cli.Context) (err error) {
-	req := &api.QueueRequest{}
+	if c.String("file") != "" {
+		return queueFile(c)
+	}
+
+	var task string
+	var params, success, failure []byte
 
-	if req.Task = c.String("task"); req.Task == "" {
+	if task = c.String("task"); task == "" {
 		return cli.NewExitError("must specify a task name to enqueue with --task", 1)
 	}
 
-	if params := c.String("params"); params != "" {
-		req.Params = []byte(params)
+	if p := c.String("params"); p != "" {
+		params = []byte(p)
 	}
 
-	if success := c.String("success"); success != "" {
-		req.Success = []byte(success)
+	if s := c.String("success"); s != "" {
+		success = []byte(s)
 	}
 
-	if failure := c.String("failure"); failure != "" {
-		req.Failure = []byte(failure)
+	if f := c.String("failure"); f != "" {
+		failure = []byte(f)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
 	defer cancel()
 
+	if key := c.String("idempotency-key"); key != "" {
+		ctx = radish.WithIdempotencyKey(ctx, key)
+	}
+
+	if key := c.String("partition-key"); key != "" {
+		ctx = radish.WithPartitionKey(ctx, key)
+	}
+
+	if c.Bool("dry-run") {
+		var outcome string
+		if outcome, err = rc.DryRun(ctx, task, params); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return printResponse(map[string]interface{}{"outcome": outcome, "success": true})
+	}
+
 	var rep *api.QueueReply
-	if rep, err = client.Queue(ctx, req); err != nil {
+	if rep, err = rc.QueueWithEstimate(ctx, task, params, success, failure); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printResponse(map[string]interface{}{
+		"uuid":           uuid.UUID(rep.Uuid).String(),
+		"queue_length":   rep.QueueLength,
+		"estimated_wait": time.Duration(rep.EstimatedWait).String(),
+		"success":        true,
+	})
+}
+
+// taskSpec is a line of a file of newline-delimited JSON task specs queued by the queue
+// command. The params are passed to the task as the raw JSON value.
+type taskSpec struct {
+	Task    string          `json:"task"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Success json.RawMessage `json:"success,omitempty"`
+	Failure json.RawMessage `json:"failure,omitempty"`
+	Key     string          `json:"idempotency_key,omitempty"`
+}
+
+// queueFailure describes a task spec that could not be queued.
+type queueFailure struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// queueFile queues the task specs in a newline-delimited JSON file in batches, e.g. for
+// backfills or load tests. Specs without a task are queued with the --task flag.
+func queueFile(c *cli.Context) (err error) {
+	batchSize := c.Int("batch-size")
+	if batchSize <= 0 {
+		return cli.NewExitError("the batch size must be greater than zero", 1)
+	}
+
+	var f io.Reader = os.Stdin
+	if path := c.String("file"); path != "-" {
+		var file *os.File
+		if file, err = os.Open(path); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		defer file.Close()
+		f = file
+	}
+
+	ids := make([]string, 0)
+	failures := make([]queueFailure, 0)
+	specs := make([]radish.FutureSpec, 0, batchSize)
+	lines := make([]int, 0, batchSize)
+
+	flush := func() error {
+		if len(specs) == 0 {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
+		defer cancel()
+
+		queued, errs, err := rc.QueueMany(ctx, specs)
+		if err != nil {
+			return err
+		}
+
+		for i := range specs {
+			if errs[i] != nil {
+				failures = append(failures, queueFailure{Line: lines[i], Error: errs[i].Error()})
+				continue
+			}
+			ids = append(ids, queued[i].String())
+		}
+
+		specs, lines = specs[:0], lines[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		spec := &taskSpec{}
+		if err = json.Unmarshal(text, spec); err != nil {
+			failures = append(failures, queueFailure{Line: line, Error: fmt.Sprintf("could not parse task spec: %s", err)})
+			continue
+		}
+
+		if spec.Task == "" {
+			if spec.Task = c.String("task"); spec.Task == "" {
+				failures = append(failures, queueFailure{Line: line, Error: "no task specified"})
+				continue
+			}
+		}
+
+		specs = append(specs, radish.FutureSpec{Task: spec.Task, Params: spec.Params, Success: spec.Success, Failure: spec.Failure, Key: spec.Key})
+		lines = append(lines, line)
+		if len(specs) >= batchSize {
+			if err = flush(); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
-	return printJSONResponse(rep)
+	if err = flush(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Specs that could not be parsed are reported before the batch they were in is queued
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Line < failures[j].Line })
+	return printResponse(map[string]interface{}{
+		"queued":   len(ids),
+		"failed":   len(failures),
+		"uuids":    ids,
+		"failures": failures,
+		"success":  len(failures) == 0,
+	})
 }
 
 func scale(c *cli.Context) (err error) {
@@ -183,43 +599,396 @@ func scale(c *cli.Context) (err error) {
 		return cli.NewExitError("specify number of workers with --workers", 1)
 	}
 
-	req := &api.ScaleRequest{Workers: int32(nworkers)}
+	// Waiting for removed workers to drain is not subject to the request timeout
+	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout")+c.Duration("drain-timeout"))
+	defer cancel()
+
+	var draining int
+	if nworkers, draining, err = rc.ScaleWithin(ctx, nworkers, c.Duration("drain-timeout"), c.Bool("force")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printResponse(map[string]interface{}{"workers": nworkers, "draining": draining, "success": true})
+}
+
+func autoscale(c *cli.Context) (err error) {
+	req := &api.AutoscaleRequest{MinWorkers: int32(c.Int("min")), MaxWorkers: int32(c.Int("max"))}
+	switch {
+	case c.Bool("enable") && c.Bool("disable"):
+		return cli.NewExitError("specify either --enable or --disable, not both", 1)
+	case c.Bool("enable"):
+		req.Toggle = api.AutoscaleRequest_ENABLE
+	case c.Bool("disable"):
+		req.Toggle = api.AutoscaleRequest_DISABLE
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
+	defer cancel()
+
+	rep, err := rc.Autoscale(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return printResponse(rep)
+}
+
+func ping(c *cli.Context) (err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
 	defer cancel()
 
-	var rep *api.ScaleReply
-	if rep, err = client.Scale(ctx, req); err != nil {
+	rep, err := rc.Ping(ctx)
+	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
-	return printJSONResponse(rep)
+	return printResponse(rep)
 }
 
 func status(c *cli.Context) (err error) {
-	req := &api.StatusRequest{}
 	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
 	defer cancel()
 
+	req := &api.StatusRequest{Cluster: c.Bool("cluster"), Tasks: c.StringSlice("task")}
+	for _, name := range c.StringSlice("section") {
+		section, ok := api.StatusRequest_Section_value[strings.ToUpper(strings.Replace(name, "-", "_", -1))]
+		if !ok {
+			return cli.NewExitError(fmt.Errorf("unknown status section %q", name), 1)
+		}
+		req.Sections = append(req.Sections, api.StatusRequest_Section(section))
+	}
+
 	var rep *api.StatusReply
-	if rep, err = client.Status(ctx, req); err != nil {
+	if rep, err = rc.DetailedStatus(ctx, req); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return printResponse(rep)
+}
+
+func tasks(c *cli.Context) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
+	defer cancel()
+
+	tasks, err := rc.Tasks(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printResponse(tasks)
+}
+
+func reload(c *cli.Context) (err error) {
+	req := &api.ReloadRequest{
+		Load:             c.Bool("load"),
+		Workers:          int32(c.Int("workers")),
+		LogLevel:         c.String("log-level"),
+		CautionThreshold: uint32(c.Uint("caution-threshold")),
+		TaskQuota:        int32(c.Int("task-quota")),
+		ClientQuota:      int32(c.Int("client-quota")),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
+	defer cancel()
+
+	rep, err := rc.Reload(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return printResponse(rep)
+}
+
+func drain(c *cli.Context) (err error) {
+	interval := c.Duration("interval")
+	if interval <= 0 {
+		return cli.NewExitError("the progress interval must be greater than zero", 1)
+	}
+
+	// Waiting for the queue to drain is not subject to the request timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if wait := c.Duration("wait"); wait > 0 {
+		ctx, cancel = context.WithTimeout(ctx, wait)
+		defer cancel()
+	}
+
+	request := func(ctx context.Context) (context.Context, context.CancelFunc) {
+		return context.WithTimeout(ctx, c.GlobalDuration("timeout"))
+	}
+
+	// Stop accepting new tasks, the service is only shutdown once the queue is empty
+	rctx, rcancel := request(ctx)
+	remaining, err := rc.Drain(rctx, false)
+	rcancel()
+	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
-	return printJSONResponse(rep)
+	started := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for remaining > 0 {
+		fmt.Fprintf(os.Stderr, "draining: %d tasks remaining in the queue (%s elapsed)\n", remaining, time.Since(started).Round(time.Second))
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return cli.NewExitError(fmt.Errorf("queue was not drained with %d tasks remaining: %s", remaining, ctx.Err()), 1)
+		}
+
+		rctx, rcancel = request(ctx)
+		status, err := rc.Status(rctx)
+		rcancel()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		remaining = status.Queue
+	}
+
+	if c.Bool("shutdown") {
+		rctx, rcancel = request(ctx)
+		_, err = rc.Drain(rctx, true)
+		rcancel()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+
+	return printResponse(map[string]interface{}{"drained": true, "elapsed": time.Since(started).String(), "shutdown": c.Bool("shutdown"), "success": true})
+}
+
+func cancelFutures(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return cli.NewExitError("specify the uuid of at least one task to cancel", 1)
+	}
+
+	ids := make([]uuid.UUID, 0, c.NArg())
+	for _, arg := range c.Args() {
+		id := uuid.Parse(arg)
+		if id == nil {
+			return cli.NewExitError(fmt.Errorf("could not parse uuid %q", arg), 1)
+		}
+		ids = append(ids, id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
+	defer cancel()
+
+	for _, id := range ids {
+		if err = rc.Cancel(ctx, id); err != nil {
+			return cli.NewExitError(fmt.Errorf("could not cancel %s: %s", id, err), 1)
+		}
+	}
+
+	canceled := make([]string, 0, len(ids))
+	for _, id := range ids {
+		canceled = append(canceled, id.String())
+	}
+	return printResponse(map[string]interface{}{"canceled": canceled, "success": true})
+}
+
+func deadletters(c *cli.Context) (err error) {
+	if c.Bool("redrive") && c.Bool("purge") {
+		return cli.NewExitError("specify either --redrive or --purge, not both", 1)
+	}
+
+	ids := make([]uuid.UUID, 0, c.NArg())
+	for _, arg := range c.Args() {
+		id := uuid.Parse(arg)
+		if id == nil {
+			return cli.NewExitError(fmt.Errorf("could not parse uuid %q", arg), 1)
+		}
+		ids = append(ids, id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
+	defer cancel()
+
+	var n int
+	switch {
+	case c.Bool("redrive"):
+		if n, err = rc.Redrive(ctx, c.String("task"), ids...); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return printResponse(map[string]interface{}{"redriven": n, "success": true})
+	case c.Bool("purge"):
+		if n, err = rc.Purge(ctx, c.String("task"), ids...); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return printResponse(map[string]interface{}{"purged": n, "success": true})
+	default:
+		var letters []*api.DeadLetter
+		if letters, err = rc.DeadLetters(ctx, c.String("task"), c.Int("limit"), ids...); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return printResponse(letters)
+	}
 }
 
-//===========================================================================
-// Helper Functions
-//===========================================================================
+func script(c *cli.Context) (err error) {
+	var task string
+	if task = c.String("task"); task == "" {
+		return cli.NewExitError("must specify a task name with --task", 1)
+	}
+
+	var source []byte
+	if path := c.String("file"); path != "" {
+		if source, err = ioutil.ReadFile(path); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	} else {
+		return cli.NewExitError("must specify a lua script with --file", 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.GlobalDuration("timeout"))
+	defer cancel()
 
-// Prints a gRPC response as human readable json and returns cli exit error or nil.
-func printJSONResponse(rep interface{}) (err error) {
-	var data []byte
-	if data, err = json.MarshalIndent(rep, "", " "); err != nil {
-		err = fmt.Errorf("could not marshal radish response: %s", err)
+	if err = rc.SetScript(ctx, task, string(source)); err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
-	fmt.Println(string(data))
+	return printResponse(map[string]interface{}{"task": task, "success": true})
+}
+
+func logs(c *cli.Context) (err error) {
+	req := &api.LogsRequest{
+		Level:  c.String("level"),
+		Tasks:  c.StringSlice("task"),
+		Tail:   int32(c.Int("tail")),
+		Follow: c.Bool("follow"),
+	}
+
+	// Following the logs is not subject to the request timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if !req.Follow {
+		ctx, cancel = context.WithTimeout(ctx, c.GlobalDuration("timeout"))
+		defer cancel()
+	}
+
+	err = rc.Logs(ctx, req, func(entry *api.LogEntry) error {
+		ts := time.Unix(0, entry.Timestamp).UTC().Format(time.RFC3339)
+		if entry.Task != "" {
+			fmt.Printf("%s %-7s [%s] %s\n", ts, entry.Level, entry.Task, entry.Message)
+		} else {
+			fmt.Printf("%s %-7s %s\n", ts, entry.Level, entry.Message)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
 	return nil
 }
+
+func watch(c *cli.Context) (err error) {
+	interval := c.Duration("interval")
+	if interval <= 0 {
+		return cli.NewExitError("the refresh interval must be greater than zero", 1)
+	}
+
+	nrecent := c.Int("recent")
+	if nrecent < 0 {
+		return cli.NewExitError("the number of recent tasks cannot be negative", 1)
+	}
+
+	// Watching is not subject to the request timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Stream the completions of futures in the background
+	req := &api.WatchRequest{
+		Tasks:  c.StringSlice("task"),
+		States: []api.FutureState{api.FutureState_SUCCEEDED, api.FutureState_FAILED, api.FutureState_CANCELED},
+	}
+
+	events := make(chan *api.FutureEvent, 256)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- rc.Watch(ctx, req, func(event *api.FutureEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	view := &watchView{addr: c.GlobalString("addr"), started: time.Now(), recent: make([]*api.FutureEvent, 0, nrecent)}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			view.add(event, nrecent)
+		case <-ticker.C:
+			sctx, scancel := context.WithTimeout(ctx, c.GlobalDuration("timeout"))
+			view.status, view.err = rc.Status(sctx)
+			scancel()
+			view.render(os.Stdout)
+		case err = <-errc:
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			return nil
+		}
+	}
+}
+
+// watchView is the live view of the queue rendered by the watch command.
+type watchView struct {
+	addr      string
+	started   time.Time
+	status    *api.StatusReply
+	err       error
+	succeeded int
+	failed    int
+	canceled  int
+	recent    []*api.FutureEvent // most recent completion first
+}
+
+// add a completion to the view, keeping at most n recent completions.
+func (v *watchView) add(event *api.FutureEvent, n int) {
+	switch event.State {
+	case api.FutureState_SUCCEEDED:
+		v.succeeded++
+	case api.FutureState_FAILED:
+		v.failed++
+	case api.FutureState_CANCELED:
+		v.canceled++
+	}
+
+	v.recent = append([]*api.FutureEvent{event}, v.recent...)
+	if len(v.recent) > n {
+		v.recent = v.recent[:n]
+	}
+}
+
+// render the view, clearing the terminal so that the view refreshes in place.
+func (v *watchView) render(w io.Writer) {
+	fmt.Fprint(w, "\033[H\033[2J")
+	fmt.Fprintf(w, "radish %s at %s (watching for %s)\n\n", v.addr, time.Now().Format("15:04:05"), time.Since(v.started).Round(time.Second))
+
+	if v.err != nil {
+		fmt.Fprintf(w, "could not get status: %s\n", v.err)
+	} else if v.status != nil {
+		tasks := append([]string(nil), v.status.Tasks...)
+		sort.Strings(tasks)
+		fmt.Fprintf(w, "workers: %d    queue: %d    tasks: %s\n", v.status.Workers, v.status.Queue, strings.Join(tasks, ", "))
+	}
+	fmt.Fprintf(w, "succeeded: %d    failed: %d    canceled: %d\n\n", v.succeeded, v.failed, v.canceled)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMPLETED\tSTATE\tTASK\tID\tATTEMPTS\tDURATION")
+	for _, event := range v.recent {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n",
+			time.Unix(0, event.Timestamp).Format("15:04:05"), event.State, event.Task,
+			uuid.UUID(event.Uuid), event.Attempts, time.Duration(event.Duration).Round(time.Millisecond),
+		)
+	}
+	tw.Flush()
+}
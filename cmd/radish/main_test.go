@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestMain(m *testing.M) {
+	// Commands return exit errors, which must not exit the test binary
+	cli.OsExiter = func(int) {}
+	cli.ErrWriter = ioutil.Discard
+	os.Exit(m.Run())
+}
+
+func TestWatchView(t *testing.T) {
+	view := &watchView{addr: "localhost:5356", started: time.Now()}
+
+	// Only the most recent completions are kept, most recent first
+	ids := make([]uuid.UUID, 0, 4)
+	states := []api.FutureState{api.FutureState_SUCCEEDED, api.FutureState_FAILED, api.FutureState_SUCCEEDED, api.FutureState_CANCELED}
+	for i, state := range states {
+		id := uuid.NewRandom()
+		ids = append(ids, id)
+		view.add(&api.FutureEvent{Uuid: id, Task: "email", State: state, Attempts: int32(i + 1), Timestamp: time.Now().UnixNano(), Duration: int64(1500 * time.Millisecond)}, 3)
+	}
+
+	require.Equal(t, 2, view.succeeded)
+	require.Equal(t, 1, view.failed)
+	require.Equal(t, 1, view.canceled)
+	require.Len(t, view.recent, 3)
+	require.Equal(t, uuid.UUID(view.recent[0].Uuid), ids[3])
+	require.Equal(t, uuid.UUID(view.recent[2].Uuid), ids[1])
+
+	// The status and the recent completions are rendered
+	view.status = &api.StatusReply{Workers: 4, Queue: 12, Tasks: []string{"upload", "email"}}
+	buf := new(bytes.Buffer)
+	view.render(buf)
+
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, "\033[H\033[2J"), "the terminal is not cleared")
+	require.Contains(t, out, "radish localhost:5356 at ")
+	require.Contains(t, out, "workers: 4    queue: 12    tasks: email, upload")
+	require.Contains(t, out, "succeeded: 2    failed: 1    canceled: 1")
+	require.Contains(t, out, "COMPLETED")
+	require.Contains(t, out, ids[3].String())
+	require.NotContains(t, out, ids[0].String())
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Contains(t, lines[len(lines)-3], "CANCELED")
+	require.Contains(t, lines[len(lines)-3], "1.5s")
+
+	// Errors getting the status are rendered instead of the status
+	view.err = radish.Errorf(radish.CodeBadGateway, "service unavailable")
+	buf.Reset()
+	view.render(buf)
+	require.Contains(t, buf.String(), fmt.Sprintf("could not get status: [%d] service unavailable", radish.CodeBadGateway))
+	require.NotContains(t, buf.String(), "workers: 4")
+}
+
+func TestWatchFlags(t *testing.T) {
+	addr, _ := serve(t)
+
+	_, err := run(t, addr, "watch", "--interval", "0s")
+	require.EqualError(t, err, "the refresh interval must be greater than zero")
+
+	_, err = run(t, addr, "watch", "--recent", "-1")
+	require.EqualError(t, err, "the number of recent tasks cannot be negative")
+}
+
+// serve a radish queue with the tasks on a local port, returning the address of the
+// queue. The queue is shutdown when the test completes.
+func serve(t *testing.T, tasks ...radish.Task) (string, *radish.Radish) {
+	queue, err := radish.New(&radish.Config{Workers: 2, NoSignals: true, SuppressMetrics: true, LogLevel: "silent"}, tasks...)
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	errc := make(chan error, 1)
+	go func() { errc <- queue.Serve(sock) }()
+	t.Cleanup(func() {
+		queue.Shutdown()
+		<-errc
+	})
+	return sock.Addr().String(), queue
+}
+
+// run the CLI with the arguments against the radish service at the address, returning
+// what the command printed to stdout and the error it returned.
+func run(t *testing.T, addr string, args ...string) (string, error) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	buf := new(bytes.Buffer)
+	done := make(chan struct{})
+	go func() {
+		io.Copy(buf, r)
+		close(done)
+	}()
+
+	err = newApp().Run(append([]string{"radish", "--unsecure", "--addr", addr, "--timeout", "5s"}, args...))
+	w.Close()
+	<-done
+	return buf.String(), err
+}
+
+func TestQueueFile(t *testing.T) {
+	var mu sync.Mutex
+	params := make([]string, 0, 3)
+	wg := new(sync.WaitGroup)
+	email := &task{wg: wg, name: "email", handler: func(id uuid.UUID, p []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		params = append(params, string(p))
+		return nil
+	}}
+	addr, _ := serve(t, email)
+
+	specs := strings.Join([]string{
+		`{"task": "email", "params": {"to": "a@example.com"}}`,
+		``,
+		`{"params": {"to": "b@example.com"}}`,
+		`{"task": "email", "params": `,
+		`{"task": "unknown"}`,
+		`{"task": "email", "params": {"to": "c@example.com"}, "idempotency_key": "c"}`,
+	}, "\n")
+
+	path := filepath.Join(t.TempDir(), "specs.ndjson")
+	require.NoError(t, ioutil.WriteFile(path, []byte(specs), 0644))
+
+	// Specs without a task are queued with the task flag, in batches of the batch size
+	wg.Add(3)
+	out, err := run(t, addr, "queue", "--file", path, "--task", "email", "--batch-size", "2")
+	require.NoError(t, err)
+	wg.Wait()
+
+	rep := struct {
+		Queued   int            `json:"queued"`
+		Failed   int            `json:"failed"`
+		UUIDs    []string       `json:"uuids"`
+		Failures []queueFailure `json:"failures"`
+		Success  bool           `json:"success"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(out), &rep))
+	require.Equal(t, 3, rep.Queued)
+	require.Len(t, rep.UUIDs, 3)
+	require.False(t, rep.Success)
+
+	// Failures are reported by line in the order of the file
+	require.Equal(t, 2, rep.Failed)
+	require.Len(t, rep.Failures, 2)
+	require.Equal(t, 4, rep.Failures[0].Line)
+	require.Contains(t, rep.Failures[0].Error, "could not parse task spec")
+	require.Equal(t, 5, rep.Failures[1].Line)
+	require.Contains(t, rep.Failures[1].Error, "unknown")
+
+	sort.Strings(params)
+	require.Equal(t, []string{`{"to": "a@example.com"}`, `{"to": "b@example.com"}`, `{"to": "c@example.com"}`}, params)
+
+	// Specs without a task fail if the task flag is not specified
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"params": {}}`), 0644))
+	out, err = run(t, addr, "queue", "--file", path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(out), &rep))
+	require.Equal(t, 0, rep.Queued)
+	require.Equal(t, []queueFailure{{Line: 1, Error: "no task specified"}}, rep.Failures)
+
+	_, err = run(t, addr, "queue", "--file", path, "--batch-size", "0")
+	require.EqualError(t, err, "the batch size must be greater than zero")
+}
+
+// task is a radish task for the CLI tests that calls the handler if it is not nil and
+// marks the wait group done once each future has completed, if it is not nil.
+type task struct {
+	wg      *sync.WaitGroup
+	name    string
+	handler func(id uuid.UUID, params []byte) error
+}
+
+func (t *task) Name() string { return t.name }
+
+func (t *task) Handle(id uuid.UUID, params []byte) error {
+	if t.handler != nil {
+		return t.handler(id, params)
+	}
+	return nil
+}
+
+func (t *task) Success(id uuid.UUID, params []byte)            { t.done() }
+func (t *task) Failure(id uuid.UUID, err error, params []byte) { t.done() }
+
+func (t *task) done() {
+	if t.wg != nil {
+		t.wg.Done()
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats for the responses of the radish service.
+const (
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+)
+
+// output is the format responses are printed in, set by the global --output flag.
+var output = outputJSON
+
+// setOutput validates and sets the output format from the global --output flag.
+func setOutput(format string) error {
+	switch format = strings.ToLower(format); format {
+	case "":
+		output = outputJSON
+	case outputJSON, outputYAML, outputTable:
+		output = format
+	default:
+		return fmt.Errorf("%q is an invalid output format, use json, yaml, or table", format)
+	}
+	return nil
+}
+
+// Prints a gRPC response in the output format and returns cli exit error or nil.
+func printResponse(rep interface{}) (err error) {
+	switch output {
+	case outputYAML:
+		err = printYAML(os.Stdout, rep)
+	case outputTable:
+		err = printTable(os.Stdout, rep)
+	default:
+		err = printJSON(os.Stdout, rep)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("could not marshal radish response: %s", err)
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+// printJSON prints the response as human readable json.
+func printJSON(w io.Writer, rep interface{}) (err error) {
+	var data []byte
+	if data, err = json.MarshalIndent(rep, "", " "); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// printYAML prints the response as yaml. The response is converted to json first so
+// that the keys are the same as the json output.
+func printYAML(w io.Writer, rep interface{}) (err error) {
+	var val interface{}
+	if val, err = generic(rep); err != nil {
+		return err
+	}
+
+	var data []byte
+	if data, err = yaml.Marshal(val); err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, string(data))
+	return nil
+}
+
+// printTable prints the response as a human readable table. Responses with a known
+// structure have their own columns; other objects are printed with a column for each key
+// and lists of objects with a row for each object.
+func printTable(w io.Writer, rep interface{}) (err error) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	defer tw.Flush()
+
+	switch rep := rep.(type) {
+	case *api.StatusReply:
+		tasks := append([]string(nil), rep.Tasks...)
+		sort.Strings(tasks)
+		fmt.Fprintln(tw, "VERSION\tUPTIME\tWORKERS\tIN FLIGHT\tQUEUE\tCAPACITY\tPAUSED\tDRAINING\tTASKS")
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%t\t%t\t%s\n",
+			rep.Version, time.Duration(rep.Uptime).Round(time.Second), rep.Workers, rep.InFlight,
+			rep.Queue, rep.Capacity, rep.Paused, rep.Draining, strings.Join(tasks, ", "),
+		)
+
+		if len(rep.Peers) > 0 {
+			printPeers(tw, rep)
+		}
+		return nil
+	case []*api.TaskInfo:
+		fmt.Fprintln(tw, "TASK\tPENDING\tQUEUED\tSUCCEEDED\tFAILED\tCANCELED")
+		for _, task := range rep {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\n", task.Name, task.Pending, task.Queued, task.Succeeded, task.Failed, task.Canceled)
+		}
+		return nil
+	case []*api.DeadLetter:
+		fmt.Fprintln(tw, "UUID\tTASK\tATTEMPTS\tFAILED\tERROR")
+		for _, letter := range rep {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", uuid.UUID(letter.Uuid), letter.Task, letter.Attempts, time.Unix(0, letter.Failed).Format(time.RFC3339), letter.Error.GetMessage())
+		}
+		return nil
+	}
+
+	var val interface{}
+	if val, err = generic(rep); err != nil {
+		return err
+	}
+
+	var rows []map[string]interface{}
+	switch val := val.(type) {
+	case map[string]interface{}:
+		rows = []map[string]interface{}{val}
+	case []interface{}:
+		for _, item := range val {
+			row, ok := item.(map[string]interface{})
+			if !ok {
+				row = map[string]interface{}{"value": item}
+			}
+			rows = append(rows, row)
+		}
+	default:
+		rows = []map[string]interface{}{{"value": val}}
+	}
+
+	// The columns are the sorted union of the keys of all rows
+	keys := make(map[string]struct{})
+	for _, row := range rows {
+		for key := range row {
+			keys[key] = struct{}{}
+		}
+	}
+
+	columns := make([]string, 0, len(keys))
+	for key := range keys {
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = strings.ToUpper(col)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = cell(row[col])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return nil
+}
+
+// printPeers prints a row for the status of each peer in the cluster of the queue and a
+// row with the totals of the queue and the peers that could be reached.
+func printPeers(tw *tabwriter.Writer, rep *api.StatusReply) {
+	workers, inFlight, queue := int64(rep.Workers), rep.InFlight, rep.Queue
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "PEER\tVERSION\tWORKERS\tIN FLIGHT\tQUEUE\tPAUSED\tDRAINING\tERROR")
+	for _, peer := range rep.Peers {
+		if peer.Status == nil {
+			fmt.Fprintf(tw, "%s\t\t\t\t\t\t\t%s\n", peer.Addr, peer.Error.GetMessage())
+			continue
+		}
+
+		status := peer.Status
+		workers, inFlight, queue = workers+int64(status.Workers), inFlight+status.InFlight, queue+status.Queue
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%t\t%t\t\n", peer.Addr, status.Version, status.Workers, status.InFlight, status.Queue, status.Paused, status.Draining)
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t%d\t%d\t%d\t\t\t\n", workers, inFlight, queue)
+}
+
+// generic converts the response into maps, lists, and scalars via json.
+func generic(rep interface{}) (val interface{}, err error) {
+	var data []byte
+	if data, err = json.Marshal(rep); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// cell formats a value in a table cell, joining lists of scalars and printing nested
+// objects as compact json.
+func cell(val interface{}) string {
+	switch val := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []interface{}:
+		items := make([]string, 0, len(val))
+		for _, item := range val {
+			items = append(items, cell(item))
+		}
+		return strings.Join(items, ", ")
+	case map[string]interface{}:
+		data, _ := json.Marshal(val)
+		return string(data)
+	default:
+		return fmt.Sprint(val)
+	}
+}
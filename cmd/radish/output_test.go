@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetOutput(t *testing.T) {
+	defer setOutput(outputJSON)
+
+	for _, format := range []string{"json", "yaml", "table", "YAML"} {
+		require.NoError(t, setOutput(format))
+		require.Equal(t, strings.ToLower(format), output)
+	}
+
+	require.NoError(t, setOutput(""))
+	require.Equal(t, outputJSON, output)
+
+	require.EqualError(t, setOutput("xml"), `"xml" is an invalid output format, use json, yaml, or table`)
+}
+
+func TestPrintYAML(t *testing.T) {
+	// The keys of the yaml output are the same as the json output
+	buf := new(bytes.Buffer)
+	require.NoError(t, printYAML(buf, &api.StatusReply{Workers: 4, InFlight: 2, Tasks: []string{"email"}}))
+
+	val := make(map[string]interface{})
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &val))
+	require.Equal(t, map[string]interface{}{"workers": 4, "in_flight": 2, "tasks": []interface{}{"email"}}, val)
+}
+
+func TestPrintTable(t *testing.T) {
+	table := func(rep interface{}) [][]string {
+		buf := new(bytes.Buffer)
+		require.NoError(t, printTable(buf, rep))
+
+		rows := make([][]string, 0)
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			rows = append(rows, strings.Fields(line))
+		}
+		return rows
+	}
+
+	// Responses with a known structure have their own columns
+	rows := table([]*api.TaskInfo{{Name: "email", Pending: 1, Queued: 2, Succeeded: 3}, {Name: "upload", Failed: 4, Canceled: 5}})
+	require.Equal(t, [][]string{
+		{"TASK", "PENDING", "QUEUED", "SUCCEEDED", "FAILED", "CANCELED"},
+		{"email", "1", "2", "3", "0", "0"},
+		{"upload", "0", "0", "0", "4", "5"},
+	}, rows)
+
+	rows = table(&api.StatusReply{Version: "1.0.0", Workers: 4, InFlight: 2, Queue: 8, Capacity: 100, Tasks: []string{"upload", "email"}})
+	require.Len(t, rows, 2)
+	require.Equal(t, "VERSION", rows[0][0])
+	require.Equal(t, []string{"1.0.0", "0s", "4", "2", "8", "100", "false", "false", "email,", "upload"}, rows[1])
+
+	// Other objects have a column for each key, sorted, with nested values in one cell
+	rows = table(map[string]interface{}{"success": true, "uuids": []string{"a", "b"}, "latency": map[string]string{"p50": "1ms"}})
+	require.Equal(t, [][]string{
+		{"LATENCY", "SUCCESS", "UUIDS"},
+		{`{"p50":"1ms"}`, "true", "a,", "b"},
+	}, rows)
+
+	// Lists of objects have a row for each object and a column for the union of the keys
+	rows = table([]map[string]interface{}{{"line": 1, "error": "boom"}, {"line": 2}})
+	require.Equal(t, [][]string{
+		{"ERROR", "LINE"},
+		{"boom", "1"},
+		{"2"},
+	}, rows)
+
+	// Scalars have a single value column
+	require.Equal(t, [][]string{{"VALUE"}, {"42"}}, table(42))
+}
+
+func TestOutputFlag(t *testing.T) {
+	addr, _ := serve(t, &task{name: "email"})
+	defer setOutput(outputJSON)
+
+	out, err := run(t, addr, "--output", "yaml", "tasks")
+	require.NoError(t, err)
+
+	var tasks []map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(out), &tasks))
+	require.Len(t, tasks, 1)
+	require.Equal(t, "email", tasks[0]["name"])
+
+	out, err = run(t, addr, "--output", "table", "tasks")
+	require.NoError(t, err)
+	require.Equal(t, []string{"TASK", "PENDING", "QUEUED", "SUCCEEDED", "FAILED", "CANCELED"}, strings.Fields(strings.Split(out, "\n")[0]))
+
+	_, err = run(t, addr, "--output", "xml", "tasks")
+	require.EqualError(t, err, `"xml" is an invalid output format, use json, yaml, or table`)
+}
@@ -66,6 +66,17 @@ func main() {
 					Value:  5000,
 					EnvVar: "TURNIP_QUEUE_SIZE",
 				},
+				cli.StringFlag{
+					Name:   "o, queue-order",
+					Usage:  "handle the oldest (fifo) or newest (lifo) futures first",
+					Value:  "fifo",
+					EnvVar: "TURNIP_QUEUE_ORDER",
+				},
+				cli.IntFlag{
+					Name:   "max-param-size",
+					Usage:  "reject futures with params larger than this many bytes (0 for unlimited)",
+					EnvVar: "TURNIP_MAX_PARAM_SIZE",
+				},
 				cli.StringFlag{
 					Name:   "l, log-level",
 					Usage:  "specify verbosity of logging (trace, debug, info, caution, status, warn, silent)",
@@ -83,6 +94,16 @@ func main() {
 					Usage:  "do not run the prometheus metrics server",
 					EnvVar: "TURNIP_SUPPRESS_METRICS",
 				},
+				cli.StringSliceFlag{
+					Name:   "p, peer",
+					Usage:  "address of a turnip server in the cluster to forward futures to when overloaded",
+					EnvVar: "TURNIP_PEERS",
+				},
+				cli.StringFlag{
+					Name:   "P, profiles",
+					Usage:  "a yaml file of task profiles to serve instead of the default tasks",
+					EnvVar: "TURNIP_PROFILES",
+				},
 			},
 		},
 	}
@@ -95,22 +116,27 @@ func serve(c *cli.Context) (err error) {
 
 	conf := &radish.Config{
 		QueueSize:        c.Int("queue-size"),
+		QueueOrder:       c.String("queue-order"),
+		MaxParamSize:     c.Int("max-param-size"),
 		Workers:          c.Int("workers"),
 		Addr:             c.String("addr"),
 		MetricsAddr:      c.String("metrics-addr"),
 		SuppressMetrics:  c.Bool("no-metrics"),
 		LogLevel:         c.String("log-level"),
 		CautionThreshold: c.Uint("caution-threshold"),
+		Peers:            c.StringSlice("peer"),
 	}
 
-	// Create variable length turnip tasks
-	short := &Turnip{name: "short", minDelay: 50 * time.Millisecond, maxDelay: 1500 * time.Millisecond, errProb: 0.125}
-	medium := &Turnip{name: "medium", minDelay: 750 * time.Millisecond, maxDelay: 5 * time.Second, errProb: 0.183}
-	long := &Turnip{name: "long", minDelay: 10 * time.Second, maxDelay: 2 * time.Minute, errProb: 0.213}
-	chance := &Turnip{name: "chance", minDelay: 750 * time.Millisecond, maxDelay: 2 * time.Second, errProb: 0.523}
+	// Create variable length turnip tasks from the profiles or the default tasks
+	tasks := defaultTurnips()
+	if path := c.String("profiles"); path != "" {
+		if tasks, err = loadProfiles(path); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
 
 	var srv *radish.Radish
-	if srv, err = radish.New(conf, short, medium, long, chance); err != nil {
+	if srv, err = radish.New(conf, tasks...); err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kansaslabs/radish"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile describes a Turnip task in a profiles file so that benchmarks can model the
+// tasks of a real workload. Delays are specified as strings such as "50ms" or "2m" and
+// memory as a number of bytes with an optional KB, MB, or GB suffix.
+type Profile struct {
+	Name     string        `yaml:"name"`              // the name of the task
+	Kind     string        `yaml:"kind"`              // sleep, cpu, or memory bound work for the delay (default sleep)
+	MinDelay time.Duration `yaml:"min_delay"`         // the minimum time the task works for
+	MaxDelay time.Duration `yaml:"max_delay"`         // the random time added to the minimum delay
+	ErrProb  float64       `yaml:"error_probability"` // the probability that the task errors
+	CPUBurn  time.Duration `yaml:"cpu_burn"`          // how long the task spins on the CPU before working
+	Memory   string        `yaml:"memory"`            // the memory allocated by memory bound tasks, e.g. "64MB"
+}
+
+// defaultTurnips are the variable length tasks served if no profiles file is specified.
+func defaultTurnips() []radish.Task {
+	return []radish.Task{
+		&Turnip{name: "short", minDelay: 50 * time.Millisecond, maxDelay: 1500 * time.Millisecond, errProb: 0.125},
+		&Turnip{name: "medium", minDelay: 750 * time.Millisecond, maxDelay: 5 * time.Second, errProb: 0.183},
+		&Turnip{name: "long", minDelay: 10 * time.Second, maxDelay: 2 * time.Minute, errProb: 0.213},
+		&Turnip{name: "chance", minDelay: 750 * time.Millisecond, maxDelay: 2 * time.Second, errProb: 0.523},
+		&Turnip{name: "crunch", kind: kindCPU, minDelay: 100 * time.Millisecond, maxDelay: time.Second, errProb: 0.05},
+		&Turnip{name: "hog", kind: kindMemory, minDelay: 500 * time.Millisecond, maxDelay: 2 * time.Second, errProb: 0.05, memory: 64 << 20},
+	}
+}
+
+// loadProfiles reads a YAML list of task profiles and returns a Turnip task for each.
+func loadProfiles(path string) (tasks []radish.Task, err error) {
+	var data []byte
+	if data, err = ioutil.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	var profiles []Profile
+	if err = yaml.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("could not parse profiles file %s: %s", path, err)
+	}
+
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no task profiles defined in %s", path)
+	}
+
+	names := make(map[string]struct{}, len(profiles))
+	for i, profile := range profiles {
+		if profile.Name == "" {
+			return nil, fmt.Errorf("task profile %d does not have a name", i+1)
+		}
+
+		if _, ok := names[profile.Name]; ok {
+			return nil, fmt.Errorf("task profile %q is defined more than once", profile.Name)
+		}
+		names[profile.Name] = struct{}{}
+
+		if profile.MinDelay < 0 || profile.MaxDelay < 0 || profile.CPUBurn < 0 {
+			return nil, fmt.Errorf("task profile %q cannot have negative durations", profile.Name)
+		}
+
+		if profile.ErrProb < 0 || profile.ErrProb > 1 {
+			return nil, fmt.Errorf("task profile %q error probability must be between 0 and 1", profile.Name)
+		}
+
+		turnip := &Turnip{
+			name:     profile.Name,
+			kind:     profile.Kind,
+			minDelay: profile.MinDelay,
+			maxDelay: profile.MaxDelay,
+			errProb:  profile.ErrProb,
+			cpuBurn:  profile.CPUBurn,
+		}
+
+		switch profile.Kind {
+		case "", kindSleep, kindCPU:
+		case kindMemory:
+			if turnip.memory, err = parseSize(profile.Memory); err != nil || turnip.memory <= 0 {
+				return nil, fmt.Errorf("memory task profile %q requires a memory size such as 64MB", profile.Name)
+			}
+		default:
+			return nil, fmt.Errorf("task profile %q has unknown kind %q, use sleep, cpu, or memory", profile.Name, profile.Kind)
+		}
+
+		tasks = append(tasks, turnip)
+	}
+	return tasks, nil
+}
+
+// parseSize parses a number of bytes with an optional B, KB, MB, or GB suffix, where the
+// multiples are powers of 1024.
+func parseSize(s string) (int, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiple := 1
+	for _, unit := range []struct {
+		suffix   string
+		multiple int
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, multiple = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.multiple
+			break
+		}
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return n * multiple, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	tasks, err := loadProfiles(filepath.Join("..", "..", "examples", "profiles.yaml"))
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		names = append(names, task.Name())
+	}
+	require.Equal(t, []string{"short", "report", "flaky", "crunch", "hog"}, names)
+
+	// Durations and probabilities are parsed from the profile
+	report := tasks[1].(*Turnip)
+	require.Equal(t, 2*time.Second, report.minDelay)
+	require.Equal(t, 10*time.Second, report.maxDelay)
+	require.Equal(t, 500*time.Millisecond, report.cpuBurn)
+	require.Equal(t, 0.05, report.errProb)
+
+	short := tasks[0].(*Turnip)
+	require.Equal(t, 50*time.Millisecond, short.minDelay)
+	require.Equal(t, 1500*time.Millisecond, short.maxDelay)
+	require.Zero(t, short.cpuBurn)
+}
+
+func TestLoadProfilesErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	load := func(profiles string) error {
+		require.NoError(t, ioutil.WriteFile(path, []byte(profiles), 0644))
+		_, err := loadProfiles(path)
+		return err
+	}
+
+	require.EqualError(t, load(""), "no task profiles defined in "+path)
+	require.EqualError(t, load("- min_delay: 1s"), "task profile 1 does not have a name")
+	require.EqualError(t, load("- name: a\n- name: a"), `task profile "a" is defined more than once`)
+	require.EqualError(t, load("- name: a\n  min_delay: -1s"), `task profile "a" cannot have negative durations`)
+	require.EqualError(t, load("- name: a\n  cpu_burn: -1s"), `task profile "a" cannot have negative durations`)
+	require.EqualError(t, load("- name: a\n  error_probability: 1.5"), `task profile "a" error probability must be between 0 and 1`)
+	require.Contains(t, load("- name: a\n  min_delay: soon").Error(), "could not parse profiles file "+path)
+	require.Contains(t, load("name: a").Error(), "could not parse profiles file "+path)
+
+	_, err := loadProfiles(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
@@ -3,21 +3,35 @@ package main
 import (
 	"fmt"
 	"math/rand"
+	"runtime"
 	"time"
 
 	"github.com/kansaslabs/x/out"
 	"github.com/pborman/uuid"
 )
 
+// Kinds of work that a Turnip does for its random duration.
+const (
+	kindSleep  = "sleep"  // sleep for the duration
+	kindCPU    = "cpu"    // spin on the CPU for the duration
+	kindMemory = "memory" // allocate memory and hold it for the duration
+)
+
 // Turnip is a probabilistic mock task that sleeps for a random duration and which may
-// error with a specific probability. It does not accept any params in its handle method
-// and its callbacks are no-ops. This task is primarily designed for testing the Radish
-// task queue and benchmarking it.
+// error with a specific probability, optionally spinning on the CPU before it sleeps. It
+// does not accept any params in its handle method and its callbacks are no-ops. This task
+// is primarily designed for testing the Radish task queue and benchmarking it. CPU-bound
+// turnips burn the CPU for the duration instead of sleeping and memory-bound turnips
+// allocate and touch memory, holding it for the duration, so that scaling and garbage
+// collection can be benchmarked under realistic load.
 type Turnip struct {
 	name     string
+	kind     string
 	minDelay time.Duration
 	maxDelay time.Duration
 	errProb  float64
+	cpuBurn  time.Duration
+	memory   int
 }
 
 // Name returns the name of the task
@@ -28,14 +42,32 @@ func (t *Turnip) Name() string {
 	return "turnip"
 }
 
-// Handle sleeps for a random amount of time and returns an error with some probability.
+// Handle works for a random amount of time and returns an error with some probability.
 func (t *Turnip) Handle(id uuid.UUID, params []byte) (err error) {
-	delay := time.Duration(rand.Int63n(int64(t.maxDelay))) + t.minDelay
-	out.Info("sleeping for %s", delay)
-	time.Sleep(delay)
+	if t.cpuBurn > 0 {
+		out.Info("burning cpu for %s", t.cpuBurn)
+		burn(t.cpuBurn)
+	}
+
+	delay := t.minDelay
+	if t.maxDelay > 0 {
+		delay += time.Duration(rand.Int63n(int64(t.maxDelay)))
+	}
+
+	switch t.kind {
+	case kindCPU:
+		out.Info("burning cpu for %s", delay)
+		burn(delay)
+	case kindMemory:
+		out.Info("holding %d bytes for %s", t.memory, delay)
+		hold(t.memory, delay)
+	default:
+		out.Info("sleeping for %s", delay)
+		time.Sleep(delay)
+	}
 
 	if rand.Float64() <= t.errProb {
-		return fmt.Errorf("%s errored after %s sleep with %0.2f probability", id, delay, t.errProb)
+		return fmt.Errorf("%s errored after %s %s with %0.2f probability", id, delay, t.work(), t.errProb)
 	}
 	return nil
 }
@@ -45,3 +77,36 @@ func (t *Turnip) Success(id uuid.UUID, params []byte) {}
 
 // Failure callback is a no-op
 func (t *Turnip) Failure(id uuid.UUID, err error, params []byte) {}
+
+// burn spins on the CPU for the duration.
+func burn(d time.Duration) {
+	deadline := time.Now().Add(d)
+	for x := 1.0; time.Now().Before(deadline); {
+		for i := 0; i < 1000; i++ {
+			x = x*1.0000001 + 1
+		}
+	}
+}
+
+// hold allocates n bytes, writing to every page so that the memory is resident, and keeps
+// the memory reachable for the duration.
+func hold(n int, d time.Duration) {
+	buf := make([]byte, n)
+	for i := 0; i < len(buf); i += 4096 {
+		buf[i] = 1
+	}
+	time.Sleep(d)
+	runtime.KeepAlive(buf)
+}
+
+// work describes what the turnip does for its duration in error messages.
+func (t *Turnip) work() string {
+	switch t.kind {
+	case kindCPU:
+		return "cpu burn"
+	case kindMemory:
+		return "memory hold"
+	default:
+		return "sleep"
+	}
+}
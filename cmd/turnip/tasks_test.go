@@ -0,0 +1,105 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// Turnips log their work, which is not needed by the tests
+	out.SetLogLevel(out.LevelSilent)
+	os.Exit(m.Run())
+}
+
+func TestTurnipKinds(t *testing.T) {
+	for _, turnip := range []*Turnip{
+		{name: "sleep", minDelay: 20 * time.Millisecond},
+		{name: "cpu", kind: kindCPU, minDelay: 20 * time.Millisecond},
+		{name: "memory", kind: kindMemory, minDelay: 20 * time.Millisecond, memory: 1 << 20},
+	} {
+		// Each kind of turnip works for at least its minimum delay
+		start := time.Now()
+		require.NoError(t, turnip.Handle(uuid.NewRandom(), nil), turnip.name)
+		require.True(t, time.Since(start) >= 20*time.Millisecond, turnip.name)
+
+		// And errors with its probability, describing its work
+		turnip.errProb = 1
+		err := turnip.Handle(uuid.NewRandom(), nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), turnip.work())
+	}
+}
+
+func TestBurn(t *testing.T) {
+	// Burning spins on the CPU rather than sleeping for the duration
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	burn(50 * time.Millisecond)
+	require.True(t, time.Since(start) >= 50*time.Millisecond)
+	runtime.ReadMemStats(&after)
+	require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+}
+
+func TestHold(t *testing.T) {
+	// Holding allocates at least the memory for the duration
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	hold(16<<20, 20*time.Millisecond)
+	require.True(t, time.Since(start) >= 20*time.Millisecond)
+	runtime.ReadMemStats(&after)
+	require.GreaterOrEqual(t, after.TotalAlloc-before.TotalAlloc, uint64(16<<20))
+}
+
+func TestProfileKinds(t *testing.T) {
+	tasks, err := loadProfiles(filepath.Join("..", "..", "examples", "profiles.yaml"))
+	require.NoError(t, err)
+
+	crunch, hog := tasks[3].(*Turnip), tasks[4].(*Turnip)
+	require.Equal(t, kindCPU, crunch.kind)
+	require.Equal(t, kindMemory, hog.kind)
+	require.Equal(t, 64<<20, hog.memory)
+
+	// Memory bound profiles require a memory size and kinds must be known
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	load := func(profiles string) error {
+		require.NoError(t, ioutil.WriteFile(path, []byte(profiles), 0644))
+		_, err := loadProfiles(path)
+		return err
+	}
+
+	require.NoError(t, load("- name: a\n  kind: sleep\n- name: b\n  kind: cpu"))
+	require.EqualError(t, load("- name: a\n  kind: memory"), `memory task profile "a" requires a memory size such as 64MB`)
+	require.EqualError(t, load("- name: a\n  kind: memory\n  memory: lots"), `memory task profile "a" requires a memory size such as 64MB`)
+	require.EqualError(t, load("- name: a\n  kind: disk"), `task profile "a" has unknown kind "disk", use sleep, cpu, or memory`)
+}
+
+func TestParseSize(t *testing.T) {
+	for s, n := range map[string]int{
+		"512":   512,
+		"512B":  512,
+		"4KB":   4 << 10,
+		"64MB":  64 << 20,
+		"64 mb": 64 << 20,
+		"2GB":   2 << 30,
+		" 1kb ": 1 << 10,
+	} {
+		size, err := parseSize(s)
+		require.NoError(t, err, s)
+		require.Equal(t, n, size, s)
+	}
+
+	for _, s := range []string{"", "MB", "lots", "1.5GB", "64TB"} {
+		_, err := parseSize(s)
+		require.Error(t, err, s)
+	}
+}
@@ -0,0 +1,165 @@
+package radish
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pborman/uuid"
+)
+
+// Codec encodes and decodes the params of futures so that the serialization conventions
+// of a task live in one place rather than in the producers and the Handle method of the
+// task. Codecs must be thread safe. JSONCodec, ProtoCodec, and GobCodec are provided;
+// other formats such as msgpack can be used by wrapping a library in a Codec.
+type Codec interface {
+	Name() string                               // a short name of the format, e.g. json
+	Marshal(v interface{}) ([]byte, error)      // encode the value as params
+	Unmarshal(data []byte, v interface{}) error // decode the params into the value
+}
+
+// CodecTask is implemented by tasks that specify the codec of their params; a codec
+// registered for the task with RegisterCodec takes precedence.
+type CodecTask interface {
+	Codec() Codec
+}
+
+// Codecs for the common serialization formats. JSONCodec is the default codec of tasks.
+var (
+	JSONCodec  Codec = jsonCodec{}
+	ProtoCodec Codec = protoCodec{}
+	GobCodec   Codec = gobCodec{}
+)
+
+// RegisterCodec sets the codec of the params of the task, which is used by DelayValue and
+// Decode and is available to the handler of the task with DecodeParams. The task does not
+// have to be registered, e.g. if it is handled by a remote worker.
+func (r *Radish) RegisterCodec(task string, codec Codec) error {
+	if codec == nil {
+		return Errorf(CodeInvalidConfig, "cannot register a nil codec for task %q", task)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.codecs[task] = codec
+	return nil
+}
+
+// Codec returns the codec of the params of the task: the codec registered with
+// RegisterCodec, the codec of the handler if it implements CodecTask, or JSONCodec.
+func (r *Radish) Codec(task string) Codec {
+	r.RLock()
+	defer r.RUnlock()
+	if codec, ok := r.codecs[task]; ok {
+		return codec
+	}
+
+	if handler, ok := r.handlers[task].(CodecTask); ok {
+		if codec := handler.Codec(); codec != nil {
+			return codec
+		}
+	}
+	return JSONCodec
+}
+
+// DelayValue encodes the params with the codec of the task and delays a future of the
+// task with the options on the context as with DelayContext. Params that cannot be
+// encoded return an ErrInvalidParams error.
+func (r *Radish) DelayValue(ctx context.Context, task string, params interface{}, success, failure []byte) (id uuid.UUID, err error) {
+	var data []byte
+	if data, err = r.Encode(task, params); err != nil {
+		return nil, err
+	}
+	return r.DelayContext(ctx, task, data, success, failure)
+}
+
+// Encode the value with the codec of the task, returning an ErrInvalidParams error if
+// the value cannot be encoded.
+func (r *Radish) Encode(task string, v interface{}) (data []byte, err error) {
+	codec := r.Codec(task)
+	if data, err = codec.Marshal(v); err != nil {
+		return nil, Errorf(CodeInvalidParams, "could not encode %s params as %s: %s", task, codec.Name(), err)
+	}
+	return data, nil
+}
+
+// Decode the params of a future of the task into the value with the codec of the task,
+// returning an ErrInvalidParams error if the params cannot be decoded.
+func (r *Radish) Decode(task string, params []byte, v interface{}) error {
+	return decode(r.Codec(task), task, params, v)
+}
+
+// DecodeParams decodes the params into the value with the codec of the task of the future
+// being handled, which workers put on the context passed to ContextTask and ResultTask
+// handlers. The params are decoded as JSON if the context is not the context of a future.
+func DecodeParams(ctx context.Context, params []byte, v interface{}) error {
+	codec, ok := ctx.Value(codecKey).(*futureCodec)
+	if !ok {
+		return decode(JSONCodec, "future", params, v)
+	}
+	return decode(codec.codec, codec.task, params, v)
+}
+
+// futureCodec is the codec of the task of the future being handled on its context.
+type futureCodec struct {
+	task  string
+	codec Codec
+}
+
+// withCodec returns a copy of the parent context with the codec of the task.
+func (r *Radish) withCodec(parent context.Context, task string) context.Context {
+	return context.WithValue(parent, codecKey, &futureCodec{task: task, codec: r.Codec(task)})
+}
+
+func decode(codec Codec, task string, params []byte, v interface{}) error {
+	if err := codec.Unmarshal(params, v); err != nil {
+		return Errorf(CodeInvalidParams, "could not decode %s params as %s: %s", task, codec.Name(), err)
+	}
+	return nil
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                               { return "json" }
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// protoCodec encodes protocol buffer messages in the binary wire format.
+type protoCodec struct{}
+
+func (protoCodec) Name() string { return "protobuf" }
+
+func (protoCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a protocol buffer message", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (protoCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%T is not a protocol buffer message", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Name() string { return "gob" }
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
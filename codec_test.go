@@ -0,0 +1,94 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type notice struct {
+	To      string
+	Subject string
+}
+
+func TestCodecs(t *testing.T) {
+	var mu sync.Mutex
+	var decoded []notice
+
+	wg := new(sync.WaitGroup)
+	task := &codecTask{testTask: testTask{wg: wg, name: "email"}, handle: func(ctx context.Context, params []byte) error {
+		var msg notice
+		if err := DecodeParams(ctx, params, &msg); err != nil {
+			return err
+		}
+
+		mu.Lock()
+		decoded = append(decoded, msg)
+		mu.Unlock()
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Params are encoded and decoded as JSON by default
+	require.Equal(t, "json", queue.Codec("email").Name())
+	wg.Add(1)
+	_, err = queue.DelayValue(context.Background(), "email", notice{To: "jdoe@example.com", Subject: "hello"}, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	// The codec registered for the task is used by the producer and the handler
+	require.NoError(t, queue.RegisterCodec("email", GobCodec))
+	wg.Add(1)
+	_, err = queue.DelayValue(context.Background(), "email", notice{To: "jane@example.com", Subject: "gob"}, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	mu.Lock()
+	require.Equal(t, []notice{{To: "jdoe@example.com", Subject: "hello"}, {To: "jane@example.com", Subject: "gob"}}, decoded)
+	mu.Unlock()
+
+	// Params that cannot be decoded fail the future with an invalid params error
+	wg.Add(1)
+	id, err := queue.Delay("email", []byte("not gob"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Wait(context.Background(), id)
+	require.Contains(t, err.Error(), "[9] could not decode email params as gob")
+
+	// Tasks can specify their codec, the protobuf codec only encodes messages
+	protoTask := &codecTask{testTask: testTask{wg: wg, name: "proto"}, codec: ProtoCodec}
+	require.NoError(t, queue.Register(protoTask))
+	require.Equal(t, "protobuf", queue.Codec("proto").Name())
+
+	data, err := queue.Encode("proto", &wrappers.StringValue{Value: "hello"})
+	require.NoError(t, err)
+	msg := &wrappers.StringValue{}
+	require.NoError(t, queue.Decode("proto", data, msg))
+	require.Equal(t, "hello", msg.Value)
+
+	_, err = queue.DelayValue(context.Background(), "proto", notice{}, nil, nil)
+	require.EqualError(t, err, "[9] could not encode proto params as protobuf: radish_test.notice is not a protocol buffer message")
+	require.EqualError(t, queue.RegisterCodec("proto", nil), `[1] cannot register a nil codec for task "proto"`)
+}
+
+type codecTask struct {
+	testTask
+	codec  Codec
+	handle func(ctx context.Context, params []byte) error
+}
+
+func (t *codecTask) Codec() Codec { return t.codec }
+
+func (t *codecTask) HandleContext(ctx context.Context, id uuid.UUID, params []byte) error {
+	if t.handle == nil {
+		return nil
+	}
+	return t.handle(ctx, params)
+}
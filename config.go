@@ -1,9 +1,13 @@
 package radish
 
 import (
+	"crypto/tls"
 	"log"
+	"os"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kansaslabs/x/out"
 )
@@ -22,17 +26,138 @@ const (
 	defaultQueueSize   = 5000
 	defaultAddr        = ":5356"
 	defaultMetricsAddr = ":9090"
+	defaultFullTimeout = 5 * time.Second
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvWorkers          = "RADISH_WORKERS"
+	EnvQueueSize        = "RADISH_QUEUE_SIZE"
+	EnvAddr             = "RADISH_ADDR"
+	EnvMetricsAddr      = "RADISH_METRICS_ADDR"
+	EnvLogLevel         = "RADISH_LOG_LEVEL"
+	EnvCautionThreshold = "RADISH_CAUTION_THRESHOLD"
+	EnvSuppressMetrics  = "RADISH_SUPPRESS_METRICS"
 )
 
 // Config allows you to specify runtime options to the Radish server and job queue.
 type Config struct {
-	QueueSize        int    // specifies the size of the tasks channel, delay requests will block if the queue is full (default 5000, cannot be 0)
-	Workers          int    // the number of workers to start radish with (default is num cpus)
-	Addr             string // server address to listen on (default :5356)
-	MetricsAddr      string // address to serve prometheus metrics on (default :9090)
-	SuppressMetrics  bool   // do not register or serve prometheus metrics (default false)
-	LogLevel         string // the level to log at (default is info)
-	CautionThreshold uint   // the number of messages accumulated before issuing another caution
+	QueueSize        int               // specifies the size of the tasks channel, delay requests will block if the queue is full (default 5000, cannot be 0)
+	Broker           Broker            // the broker that holds queued futures, e.g. a disk or Redis backed queue (default an in-memory queue of QueueSize)
+	MaxParamSize     int               // the maximum size in bytes of the params of a future, larger params are rejected with an ErrInvalidParams error (default 0, unlimited)
+	QueueOrder       string            // the order futures with the same priority are handled in by the memory broker: fifo or lifo (default fifo)
+	FairScheduling   bool              // interleave the futures of each task by the TaskWeights instead of handling them in queue order (default false)
+	TaskWeights      map[string]int    // the relative share of the workers each task gets with fair scheduling, keyed by task name (default 1)
+	FullPolicy       string            // what happens when a future is delayed while the queue is full: block, block-timeout, reject, or drop-oldest (default block)
+	FullTimeout      time.Duration     // how long a future waits for room in the full queue with the block-timeout policy (default 5 seconds)
+	Workers          int               // the number of workers to start radish with (default is num cpus)
+	Addr             string            // server address to listen on (default :5356)
+	MetricsAddr      string            // address to serve prometheus metrics on (default :9090)
+	GatewayAddr      string            // address to serve the JSON/HTTP gateway on, e.g. :8080 (default none, the gateway is not served)
+	SuppressMetrics  bool              // do not register or serve prometheus metrics (default false)
+	LivenessTimeout  time.Duration     // fail /healthz if futures are waiting but no worker has dequeued one in this long (default 0, never)
+	LogLevel         string            // the level to log at (default is info)
+	CautionThreshold uint              // the number of messages accumulated before issuing another caution
+	EncryptionKey    []byte            // AES key used to encrypt payloads written to disk (16, 24, or 32 bytes, default no encryption)
+	Cipher           Cipher            // custom cipher (e.g. a KMS hook) for payloads written to disk, overrides EncryptionKey
+	DrainSignals     []os.Signal       // signals that cause Listen to drain the queue then shutdown (default none)
+	ShutdownSignals  []os.Signal       // signals that cause Listen to shutdown after tasks in flight complete (default SIGINT and SIGTERM)
+	ReloadSignals    []os.Signal       // signals that cause Listen to call the OnReload function (default SIGHUP)
+	NoSignals        bool              // do not handle any signals in Listen, e.g. if the application owns signal handling (default false)
+	ShutdownGrace    time.Duration     // how long a drain or shutdown triggered by a signal may take before futures in flight are canceled and Listen returns (default 0, wait indefinitely)
+	OnReload         func() error      // called when a reload signal is received (default none)
+	LoadConfig       ConfigLoader      // loads the config applied by ReloadConfig when a reload signal is received (default none)
+	ReusePort        bool              // set SO_REUSEPORT on the listen socket to allow a new process to take over the address (default false)
+	HandoffSignals   []os.Signal       // signals that cause Listen to hand off pending futures to a new process on the same address then shutdown (default none)
+	HandoffTimeout   time.Duration     // the amount of time to wait for a handoff triggered by a signal to complete (default 30 seconds)
+	AuthToken        string            // shared secret that API clients must present as a bearer token (default no authentication)
+	APIKeys          map[string]string // per-client API keys that authorize API requests, mapped to the identity of the client
+	ClientQuota      int               // the maximum number of pending futures each API client may have in the queue (default 0, unlimited)
+	ClientQuotas     map[string]int    // per-client overrides of the client quota, keyed by client identity
+	TaskQuota        int               // the maximum number of futures of each task that may wait in the queue (default 0, unlimited)
+	TaskQuotas       map[string]int    // per-task overrides of the task quota, keyed by task name
+	CoalesceTasks    bool              // return the id of the latest queued future of a task that has reached its quota instead of an error
+	ResultTTL        time.Duration     // how long records of completed futures are kept in memory (default 1 hour)
+	SweepInterval    time.Duration     // how often expired records of completed futures are evicted (default 1 minute)
+	MaxRecords       int               // the maximum number of future records kept in memory, evicting the oldest completed records first (default 100000)
+	FutureLogLines   int               // the number of lines logged with Logf while handling a future kept on its record (default 0, not captured)
+	AuditLog         string            // path of an append-only file the queueing and outcome of every future is recorded in as JSON lines (default none)
+	Auditor          Auditor           // receives the audit records of futures instead of the AuditLog, e.g. a compliance system hook
+	WASMRuntime      WASMRuntime       // runtime used to compile modules registered with RegisterWASM (default none)
+	Scripts          map[string]string // lua scripts to register as script tasks, keyed by task name (see RegisterScript)
+	Resources        map[string]int    // capacities of named shared resources consumed by tasks, e.g. {"db": 4, "smtp": 2}
+	BatchSize        int               // the maximum number of futures of a BatchTask handled in one call (default 100)
+	BatchWait        time.Duration     // how long a worker waits for a batch of a BatchTask to fill up before handling it (default 10 milliseconds)
+	TaskConcurrency  map[string]int    // the maximum number of futures of a task handled at once across all workers, keyed by task name (default unlimited)
+	IDs              IDGenerator       // generates the ids of new futures, e.g. UUIDv7 for sortable ids (default RandomIDs)
+	CallbackTLS      *tls.Config       // TLS configuration for connecting to callback services (default insecure)
+	CallbackTimeout  time.Duration     // how long to wait for a callback service to acknowledge a completed future (default 10 seconds)
+	StatsInterval    time.Duration     // how often throughput snapshots are recorded for the stats history (default 10 seconds)
+	StatsHistory     int               // the number of throughput snapshots kept in the stats history (default 360, 1 hour at 10 seconds)
+	Retry            RetryPolicy       // retry failed futures with exponential backoff (default no retries)
+	Autoscale        AutoscalePolicy   // grow and shrink the workers based on the queue depth (default no autoscaling)
+	DeadLetterSize   int               // the maximum number of permanently failed futures kept in the dead letter queue (default 1000, -1 to disable)
+	Storage          string            // where queued futures are kept, memory, or bolt or wal to persist them to disk until they complete (default memory)
+	StoragePath      string            // the path of the BoltDB file or write-ahead log used by bolt or wal storage (default radish.db or radish.wal)
+	CompactInterval  time.Duration     // how often the write-ahead log of wal storage is compacted (default 1 minute)
+	Peers            []string          // addresses of the other radish servers in the cluster to forward futures to when overloaded (default none)
+	PeerInterval     time.Duration     // how often the status of the peers is polled to forward futures to idle peers (default 5 seconds)
+	PeerTLS          *tls.Config       // TLS configuration for connecting to the peers of the cluster (default insecure)
+}
+
+// ConfigFromEnv returns a validated config built from the RADISH_WORKERS,
+// RADISH_QUEUE_SIZE, RADISH_ADDR, RADISH_METRICS_ADDR, RADISH_LOG_LEVEL,
+// RADISH_CAUTION_THRESHOLD, and RADISH_SUPPRESS_METRICS environment variables. Unset
+// variables are populated with their defaults, so the returned config can be modified,
+// e.g. to add a Broker or Retry policy, before it is passed to New.
+func ConfigFromEnv() (conf *Config, err error) {
+	conf = &Config{
+		Addr:        os.Getenv(EnvAddr),
+		MetricsAddr: os.Getenv(EnvMetricsAddr),
+		LogLevel:    os.Getenv(EnvLogLevel),
+	}
+
+	if conf.Workers, err = intFromEnv(EnvWorkers); err != nil {
+		return nil, err
+	}
+
+	if conf.QueueSize, err = intFromEnv(EnvQueueSize); err != nil {
+		return nil, err
+	}
+
+	var threshold int
+	if threshold, err = intFromEnv(EnvCautionThreshold); err != nil {
+		return nil, err
+	}
+	if threshold < 0 {
+		return nil, Errorf(CodeInvalidConfig, "$%s cannot be negative", EnvCautionThreshold)
+	}
+	conf.CautionThreshold = uint(threshold)
+
+	if val := os.Getenv(EnvSuppressMetrics); val != "" {
+		if conf.SuppressMetrics, err = strconv.ParseBool(val); err != nil {
+			return nil, Errorf(CodeInvalidConfig, "could not parse $%s: %q is not a boolean", EnvSuppressMetrics, val)
+		}
+	}
+
+	if err = conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// intFromEnv parses the environment variable as an integer, returning 0 if it is unset.
+func intFromEnv(key string) (int, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, Errorf(CodeInvalidConfig, "could not parse $%s: %q is not an integer", key, val)
+	}
+	return n, nil
 }
 
 // Validate the config and populate any defaults for zero valued configurations
@@ -42,6 +167,50 @@ func (c *Config) Validate() (err error) {
 		c.QueueSize = defaultQueueSize
 	}
 
+	// Handle the maximum param size
+	if c.MaxParamSize < 0 {
+		return Errorf(CodeInvalidConfig, "max param size cannot be negative")
+	}
+
+	// Handle the queue order and the broker
+	switch c.QueueOrder = strings.ToLower(c.QueueOrder); c.QueueOrder {
+	case "":
+		c.QueueOrder = OrderFIFO
+	case OrderFIFO, OrderLIFO:
+	default:
+		return Errorf(CodeInvalidConfig, "%q is an invalid queue order, use fifo or lifo", c.QueueOrder)
+	}
+
+	for task, weight := range c.TaskWeights {
+		if weight <= 0 {
+			return Errorf(CodeInvalidConfig, "the weight of task %q must be positive", task)
+		}
+	}
+
+	switch q, ok := c.Broker.(*taskQueue); {
+	case c.Broker == nil && c.FairScheduling:
+		c.Broker = newFairTaskQueue(c.QueueSize, c.TaskWeights, c.QueueOrder == OrderLIFO)
+	case c.Broker == nil:
+		c.Broker = newTaskQueue(c.QueueSize, c.QueueOrder == OrderLIFO)
+	case c.QueueOrder == OrderLIFO && (!ok || !q.futures.lifo):
+		return Errorf(CodeInvalidConfig, "the lifo queue order requires the memory broker")
+	case c.FairScheduling && (!ok || q.fair == nil):
+		return Errorf(CodeInvalidConfig, "fair scheduling requires the memory broker")
+	}
+
+	// Handle the full queue policy
+	switch c.FullPolicy = strings.ToLower(c.FullPolicy); c.FullPolicy {
+	case "":
+		c.FullPolicy = FullBlock
+	case FullBlock, FullBlockTimeout, FullReject, FullDropOldest:
+	default:
+		return Errorf(CodeInvalidConfig, "%q is an invalid full queue policy, use block, block-timeout, reject, or drop-oldest", c.FullPolicy)
+	}
+
+	if c.FullTimeout <= 0 {
+		c.FullTimeout = defaultFullTimeout
+	}
+
 	// Handle the number of workers
 	if c.Workers <= 0 {
 		c.Workers = runtime.NumCPU()
@@ -57,31 +226,179 @@ func (c *Config) Validate() (err error) {
 		c.MetricsAddr = defaultMetricsAddr
 	}
 
-	// Handle the log level
+	// Handle the log level and caution threshold
+	if err = c.validateLogging(); err != nil {
+		return err
+	}
+	c.setLogLevel()
+
+	// Handle signals, using the defaults only if no signals have been specified
+	if c.NoSignals {
+		c.DrainSignals, c.ShutdownSignals, c.ReloadSignals, c.HandoffSignals = nil, nil, nil, nil
+	} else if len(c.DrainSignals) == 0 && len(c.ShutdownSignals) == 0 && len(c.ReloadSignals) == 0 && len(c.HandoffSignals) == 0 {
+		c.ShutdownSignals = defaultShutdownSignals
+		c.ReloadSignals = defaultReloadSignals
+	}
+
+	seen := make(map[os.Signal]struct{})
+	for _, signals := range [][]os.Signal{c.DrainSignals, c.ShutdownSignals, c.ReloadSignals, c.HandoffSignals} {
+		for _, sig := range signals {
+			if _, ok := seen[sig]; ok {
+				return Errorf(CodeInvalidConfig, "signal %s cannot trigger more than one action", sig)
+			}
+			seen[sig] = struct{}{}
+		}
+	}
+
+	// Handle the handoff timeout
+	if c.HandoffTimeout <= 0 {
+		c.HandoffTimeout = defaultHandoffTimeout
+	}
+
+	// Handle the retention of completed future records
+	if c.ResultTTL <= 0 {
+		c.ResultTTL = defaultResultTTL
+	}
+
+	if c.SweepInterval <= 0 {
+		c.SweepInterval = defaultSweepInterval
+	}
+
+	if c.MaxRecords <= 0 {
+		c.MaxRecords = defaultMaxRecords
+	}
+
+	// Handle the callback timeout
+	if c.CallbackTimeout <= 0 {
+		c.CallbackTimeout = defaultCallbackTimeout
+	}
+
+	// Handle the batches of batch tasks
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+
+	if c.BatchWait <= 0 {
+		c.BatchWait = defaultBatchWait
+	}
+
+	// Handle the retry policy
+	if err = c.Retry.validate(); err != nil {
+		return err
+	}
+
+	// Handle the autoscale policy, starting with a number of workers within its bounds
+	if err = c.Autoscale.validate(); err != nil {
+		return err
+	}
+
+	if c.Autoscale.enabled() {
+		if c.Workers < c.Autoscale.MinWorkers {
+			c.Workers = c.Autoscale.MinWorkers
+		}
+		if c.Workers > c.Autoscale.MaxWorkers {
+			c.Workers = c.Autoscale.MaxWorkers
+		}
+	}
+
+	// Handle the dead letter queue
+	if c.DeadLetterSize == 0 {
+		c.DeadLetterSize = defaultDeadLetterSize
+	}
+
+	// Handle the storage of queued futures
+	switch c.Storage = strings.ToLower(c.Storage); c.Storage {
+	case "":
+		c.Storage = StorageMemory
+	case StorageMemory:
+	case StorageBolt:
+		if c.StoragePath == "" {
+			c.StoragePath = defaultStoragePath
+		}
+	case StorageWAL:
+		if c.StoragePath == "" {
+			c.StoragePath = defaultWALPath
+		}
+		if c.CompactInterval <= 0 {
+			c.CompactInterval = defaultCompactInterval
+		}
+	default:
+		return Errorf(CodeInvalidConfig, "%q is an invalid storage, use memory, bolt, or wal", c.Storage)
+	}
+
+	// Handle the cluster peers
+	if len(c.Peers) > 0 && c.PeerInterval <= 0 {
+		c.PeerInterval = defaultPeerInterval
+	}
+
+	// Handle the stats history
+	if c.StatsInterval <= 0 {
+		c.StatsInterval = defaultStatsInterval
+	}
+
+	if c.StatsHistory <= 0 {
+		c.StatsHistory = defaultStatsHistory
+	}
+
+	// Handle the future id format
+	if c.IDs == nil {
+		c.IDs = RandomIDs
+	}
+
+	// Handle resource capacities
+	for name, capacity := range c.Resources {
+		if capacity <= 0 {
+			return Errorf(CodeInvalidConfig, "resource %q must have a capacity of at least 1", name)
+		}
+	}
+
+	for task, limit := range c.TaskConcurrency {
+		if limit <= 0 {
+			return Errorf(CodeInvalidConfig, "task %q must have a concurrency of at least 1", task)
+		}
+	}
+
+	// Handle API authentication
+	for key, client := range c.APIKeys {
+		if key == "" || client == "" {
+			return Errorf(CodeInvalidConfig, "API keys and the clients they are assigned to cannot be empty")
+		}
+	}
+
+	// Handle encryption at rest
+	if c.Cipher == nil && len(c.EncryptionKey) > 0 {
+		if c.Cipher, err = NewAESCipher(c.EncryptionKey); err != nil {
+			return Errorf(CodeInvalidConfig, "invalid encryption key: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// validateLogging validates the log level and populates the defaults of the log level
+// and caution threshold; it is also used to validate the settings of a reload.
+func (c *Config) validateLogging() error {
 	if c.LogLevel == "" {
 		c.LogLevel = "info"
 	} else {
 		c.LogLevel = strings.ToLower(c.LogLevel)
 		if _, ok := logLevels[c.LogLevel]; !ok {
-			return Errorf(ErrInvalidConfig, "%q is an invalid log level, use trace, debug, info, caution, status, warn, or silent", c.LogLevel)
+			return Errorf(CodeInvalidConfig, "%q is an invalid log level, use trace, debug, info, caution, status, warn, or silent", c.LogLevel)
 		}
 	}
-	c.setLogLevel()
 
-	// Handle the caution threshold
 	if c.CautionThreshold == 0 {
 		c.CautionThreshold = out.DefaultCautionThreshold
 	}
-	c.setCautionThreshold()
-
 	return nil
 }
 
+// setLogLevel sets the log level and caution threshold of the out logger, holding the
+// logger lock so that the level is not changed while other goroutines are logging.
 func (c *Config) setLogLevel() {
-	out.Init("[radish] ", log.LstdFlags|log.LUTC)
+	outMu.Lock()
+	defer outMu.Unlock()
+	outInit.Do(func() { out.Init("[radish] ", log.LstdFlags|log.LUTC) })
 	out.SetLogLevel(logLevels[c.LogLevel])
-}
-
-func (c *Config) setCautionThreshold() {
 	out.SetCautionThreshold(c.CautionThreshold)
 }
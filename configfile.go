@@ -0,0 +1,255 @@
+package radish
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the schema of config files, which maps the options of the Config that
+// can be serialized onto snake_case keys, with nested sections for TLS, retries, and
+// autoscaling. Durations are specified as strings such as "30s" or "1h".
+type fileConfig struct {
+	QueueSize        int               `yaml:"queue_size"`
+	MaxParamSize     int               `yaml:"max_param_size"`
+	QueueOrder       string            `yaml:"queue_order"`
+	FairScheduling   bool              `yaml:"fair_scheduling"`
+	TaskWeights      map[string]int    `yaml:"task_weights"`
+	FullPolicy       string            `yaml:"full_policy"`
+	FullTimeout      time.Duration     `yaml:"full_timeout"`
+	Workers          int               `yaml:"workers"`
+	Addr             string            `yaml:"addr"`
+	MetricsAddr      string            `yaml:"metrics_addr"`
+	GatewayAddr      string            `yaml:"gateway_addr"`
+	SuppressMetrics  bool              `yaml:"suppress_metrics"`
+	LivenessTimeout  time.Duration     `yaml:"liveness_timeout"`
+	LogLevel         string            `yaml:"log_level"`
+	CautionThreshold uint              `yaml:"caution_threshold"`
+	EncryptionKey    string            `yaml:"encryption_key"`
+	NoSignals        bool              `yaml:"no_signals"`
+	ShutdownGrace    time.Duration     `yaml:"shutdown_grace"`
+	ReusePort        bool              `yaml:"reuse_port"`
+	HandoffTimeout   time.Duration     `yaml:"handoff_timeout"`
+	AuthToken        string            `yaml:"auth_token"`
+	APIKeys          map[string]string `yaml:"api_keys"`
+	ClientQuota      int               `yaml:"client_quota"`
+	ClientQuotas     map[string]int    `yaml:"client_quotas"`
+	TaskQuota        int               `yaml:"task_quota"`
+	TaskQuotas       map[string]int    `yaml:"task_quotas"`
+	CoalesceTasks    bool              `yaml:"coalesce_tasks"`
+	ResultTTL        time.Duration     `yaml:"result_ttl"`
+	SweepInterval    time.Duration     `yaml:"sweep_interval"`
+	MaxRecords       int               `yaml:"max_records"`
+	FutureLogLines   int               `yaml:"future_log_lines"`
+	AuditLog         string            `yaml:"audit_log"`
+	Scripts          map[string]string `yaml:"scripts"`
+	Resources        map[string]int    `yaml:"resources"`
+	BatchSize        int               `yaml:"batch_size"`
+	BatchWait        time.Duration     `yaml:"batch_wait"`
+	TaskConcurrency  map[string]int    `yaml:"task_concurrency"`
+	IDs              string            `yaml:"ids"`
+	CallbackTLS      *fileTLS          `yaml:"callback_tls"`
+	CallbackTimeout  time.Duration     `yaml:"callback_timeout"`
+	StatsInterval    time.Duration     `yaml:"stats_interval"`
+	StatsHistory     int               `yaml:"stats_history"`
+	Retry            fileRetry         `yaml:"retry"`
+	Autoscale        fileAutoscale     `yaml:"autoscale"`
+	DeadLetterSize   int               `yaml:"dead_letter_size"`
+	Storage          string            `yaml:"storage"`
+	StoragePath      string            `yaml:"storage_path"`
+	CompactInterval  time.Duration     `yaml:"compact_interval"`
+	Peers            []string          `yaml:"peers"`
+	PeerInterval     time.Duration     `yaml:"peer_interval"`
+	PeerTLS          *fileTLS          `yaml:"peer_tls"`
+}
+
+// fileTLS is the TLS section of config files.
+type fileTLS struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// fileRetry is the retry policy section of config files.
+type fileRetry struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	BaseDelay   time.Duration `yaml:"base_delay"`
+	Multiplier  float64       `yaml:"multiplier"`
+	MaxDelay    time.Duration `yaml:"max_delay"`
+}
+
+// fileAutoscale is the autoscaling policy section of config files.
+type fileAutoscale struct {
+	MinWorkers int           `yaml:"min_workers"`
+	MaxWorkers int           `yaml:"max_workers"`
+	ScaleUp    float64       `yaml:"scale_up"`
+	ScaleDown  float64       `yaml:"scale_down"`
+	Step       int           `yaml:"step"`
+	Interval   time.Duration `yaml:"interval"`
+	Cooldown   time.Duration `yaml:"cooldown"`
+}
+
+// Future id formats that can be specified by the ids option of config files.
+var fileIDs = map[string]IDGenerator{
+	"random": RandomIDs,
+	"uuidv7": UUIDv7,
+	"ulid":   ULIDs,
+}
+
+// LoadConfig parses the YAML (or JSON) config file at the path into a Config and validates
+// it the same way as a config passed to New, so that unspecified options are populated
+// with their defaults. Keys are the snake_case names of the config options, e.g.
+// queue_size or result_ttl, and the callback_tls, peer_tls, retry, and autoscale options are nested
+// sections; unknown keys are rejected so that typos are not silently ignored. Options
+// that cannot be serialized, such as the Broker, Cipher, or signals, can be set on the
+// returned config before it is passed to New. TOML config files are not supported.
+func LoadConfig(path string) (conf *Config, err error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, Errorf(CodeInvalidConfig, "cannot load %q: %q config files are not supported, use yaml or json", path, ext)
+	}
+
+	var data []byte
+	if data, err = ioutil.ReadFile(path); err != nil {
+		return nil, Errorf(CodeInvalidConfig, "could not read config file: %s", err)
+	}
+
+	file := &fileConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(file); err != nil && len(bytes.TrimSpace(data)) > 0 {
+		return nil, Errorf(CodeInvalidConfig, "could not parse config file %s: %s", path, err)
+	}
+
+	if conf, err = file.config(); err != nil {
+		return nil, err
+	}
+
+	if err = conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// ConfigFile returns a ConfigLoader that loads the config file at the path, e.g. so that
+// the file is reloaded when a reload signal is received.
+func ConfigFile(path string) ConfigLoader {
+	return func() (*Config, error) {
+		return LoadConfig(path)
+	}
+}
+
+// config converts the config file into a Config.
+func (f *fileConfig) config() (conf *Config, err error) {
+	conf = &Config{
+		QueueSize:        f.QueueSize,
+		MaxParamSize:     f.MaxParamSize,
+		QueueOrder:       f.QueueOrder,
+		FairScheduling:   f.FairScheduling,
+		TaskWeights:      f.TaskWeights,
+		FullPolicy:       f.FullPolicy,
+		FullTimeout:      f.FullTimeout,
+		Workers:          f.Workers,
+		Addr:             f.Addr,
+		MetricsAddr:      f.MetricsAddr,
+		GatewayAddr:      f.GatewayAddr,
+		SuppressMetrics:  f.SuppressMetrics,
+		LivenessTimeout:  f.LivenessTimeout,
+		LogLevel:         f.LogLevel,
+		CautionThreshold: f.CautionThreshold,
+		NoSignals:        f.NoSignals,
+		ShutdownGrace:    f.ShutdownGrace,
+		ReusePort:        f.ReusePort,
+		HandoffTimeout:   f.HandoffTimeout,
+		AuthToken:        f.AuthToken,
+		APIKeys:          f.APIKeys,
+		ClientQuota:      f.ClientQuota,
+		ClientQuotas:     f.ClientQuotas,
+		TaskQuota:        f.TaskQuota,
+		TaskQuotas:       f.TaskQuotas,
+		CoalesceTasks:    f.CoalesceTasks,
+		ResultTTL:        f.ResultTTL,
+		SweepInterval:    f.SweepInterval,
+		MaxRecords:       f.MaxRecords,
+		FutureLogLines:   f.FutureLogLines,
+		AuditLog:         f.AuditLog,
+		Scripts:          f.Scripts,
+		Resources:        f.Resources,
+		BatchSize:        f.BatchSize,
+		BatchWait:        f.BatchWait,
+		TaskConcurrency:  f.TaskConcurrency,
+		CallbackTimeout:  f.CallbackTimeout,
+		StatsInterval:    f.StatsInterval,
+		StatsHistory:     f.StatsHistory,
+		Retry:            RetryPolicy(f.Retry),
+		Autoscale:        AutoscalePolicy(f.Autoscale),
+		DeadLetterSize:   f.DeadLetterSize,
+		Storage:          f.Storage,
+		StoragePath:      f.StoragePath,
+		CompactInterval:  f.CompactInterval,
+		Peers:            f.Peers,
+		PeerInterval:     f.PeerInterval,
+	}
+
+	if f.EncryptionKey != "" {
+		if conf.EncryptionKey, err = base64.StdEncoding.DecodeString(f.EncryptionKey); err != nil {
+			return nil, Errorf(CodeInvalidConfig, "encryption key must be base64 encoded: %s", err)
+		}
+	}
+
+	if f.IDs != "" {
+		var ok bool
+		if conf.IDs, ok = fileIDs[strings.ToLower(f.IDs)]; !ok {
+			return nil, Errorf(CodeInvalidConfig, "%q is an invalid id format, use random, uuidv7, or ulid", f.IDs)
+		}
+	}
+
+	if f.CallbackTLS != nil {
+		if conf.CallbackTLS, err = f.CallbackTLS.config(); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.PeerTLS != nil {
+		if conf.PeerTLS, err = f.PeerTLS.config(); err != nil {
+			return nil, err
+		}
+	}
+	return conf, nil
+}
+
+// config loads the certificates of the TLS section into a TLS config.
+func (f *fileTLS) config() (conf *tls.Config, err error) {
+	conf = &tls.Config{ServerName: f.ServerName, InsecureSkipVerify: f.InsecureSkipVerify}
+
+	if f.CertFile != "" || f.KeyFile != "" {
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(f.CertFile, f.KeyFile); err != nil {
+			return nil, Errorf(CodeInvalidConfig, "could not load TLS certificate: %s", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	if f.CAFile != "" {
+		var pem []byte
+		if pem, err = ioutil.ReadFile(f.CAFile); err != nil {
+			return nil, Errorf(CodeInvalidConfig, "could not read TLS CA file: %s", err)
+		}
+
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, Errorf(CodeInvalidConfig, "no certificates found in TLS CA file %s", f.CAFile)
+		}
+	}
+	return conf, nil
+}
@@ -0,0 +1,84 @@
+package radish_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "radish-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(data), 0644))
+		return path
+	}
+
+	path := write("radish.yaml", `
+workers: 8
+queue_size: 100
+addr: 127.0.0.1:5000
+log_level: WARN
+result_ttl: 10m
+ids: uuidv7
+task_quotas:
+  emails: 50
+retry:
+  max_attempts: 3
+  base_delay: 2s
+autoscale:
+  max_workers: 16
+  interval: 1s
+callback_tls:
+  server_name: callbacks.example.com
+`)
+
+	conf, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, 8, conf.Workers)
+	require.Equal(t, 100, conf.QueueSize)
+	require.Equal(t, "127.0.0.1:5000", conf.Addr)
+	require.Equal(t, "warn", conf.LogLevel)
+	require.Equal(t, 10*time.Minute, conf.ResultTTL)
+	require.Equal(t, map[string]int{"emails": 50}, conf.TaskQuotas)
+	require.NotNil(t, conf.IDs)
+	require.Equal(t, RetryPolicy{MaxAttempts: 3, BaseDelay: 2 * time.Second, Multiplier: 2}, conf.Retry)
+	require.Equal(t, 16, conf.Autoscale.MaxWorkers)
+	require.Equal(t, time.Second, conf.Autoscale.Interval)
+	require.Equal(t, 30*time.Second, conf.Autoscale.Cooldown)
+	require.Equal(t, "callbacks.example.com", conf.CallbackTLS.ServerName)
+
+	// Defaults are populated for an empty file
+	conf, err = ConfigFile(write("empty.yml", ""))()
+	require.NoError(t, err)
+	require.Equal(t, 5000, conf.QueueSize)
+	require.Equal(t, ":5356", conf.Addr)
+
+	// Config files are validated the same way as programmatic configs
+	_, err = LoadConfig(write("invalid.yaml", "log_level: loud\n"))
+	require.Error(t, err)
+
+	_, err = LoadConfig(write("concurrency.yaml", "task_concurrency:\n  emails: 0\n"))
+	require.EqualError(t, err, `[1] task "emails" must have a concurrency of at least 1`)
+
+	_, err = LoadConfig(write("typo.yaml", "wrokers: 4\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "field wrokers not found")
+
+	_, err = LoadConfig(write("ids.yaml", "ids: sequential\n"))
+	require.EqualError(t, err, `[1] "sequential" is an invalid id format, use random, uuidv7, or ulid`)
+
+	_, err = LoadConfig(write("radish.toml", "workers = 4\n"))
+	require.EqualError(t, err, `[1] cannot load "`+filepath.Join(dir, "radish.toml")+`": ".toml" config files are not supported, use yaml or json`)
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package radish
+
+import "context"
+
+// contextKey is an unexported type to prevent collisions with other packages' keys.
+type contextKey uint8
+
+const (
+	metadataKey contextKey = iota
+	clientKey
+	callbackKey
+	parentKey
+	priorityKey
+	authKey
+	idempotencyKey
+	workflowKey
+	groupKey
+	futureLogKey
+	partitionKey
+	codecKey
+)
+
+// WithMetadata returns a copy of the parent context with the specified key/value pair
+// added to its radish metadata. When a task is delayed using DelayContext, the metadata
+// on the context is copied onto the future, allowing trace ids or request information to
+// flow from the producer to the future without being serialized into the params.
+func WithMetadata(parent context.Context, key, value string) context.Context {
+	prev, _ := parent.Value(metadataKey).(map[string]string)
+	md := make(map[string]string, len(prev)+1)
+	for k, v := range prev {
+		md[k] = v
+	}
+	md[key] = value
+	return context.WithValue(parent, metadataKey, md)
+}
+
+// WithPriority returns a copy of the parent context with the specified priority. When a
+// task is delayed using DelayContext, workers handle futures with a higher priority
+// before futures with a lower priority; futures with the same priority are handled in
+// the order they were queued. The default priority is 0 and priorities may be negative.
+func WithPriority(parent context.Context, priority int32) context.Context {
+	return context.WithValue(parent, priorityKey, priority)
+}
+
+// PriorityFrom returns the priority on the context, or 0 if no priority was specified.
+func PriorityFrom(ctx context.Context) int32 {
+	priority, _ := ctx.Value(priorityKey).(int32)
+	return priority
+}
+
+// withFutureMetadata returns a copy of the parent context with the metadata of a future so
+// that its handler and callbacks can read it with MetadataFrom.
+func withFutureMetadata(parent context.Context, md map[string]string) context.Context {
+	if len(md) == 0 {
+		return parent
+	}
+	return context.WithValue(parent, metadataKey, md)
+}
+
+// MetadataFrom returns a copy of the radish metadata on the context or nil if the
+// context does not have any metadata associated with it.
+func MetadataFrom(ctx context.Context) map[string]string {
+	md, ok := ctx.Value(metadataKey).(map[string]string)
+	if !ok || len(md) == 0 {
+		return nil
+	}
+
+	cp := make(map[string]string, len(md))
+	for k, v := range md {
+		cp[k] = v
+	}
+	return cp
+}
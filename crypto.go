@@ -0,0 +1,193 @@
+package radish
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Cipher encrypts and decrypts future payloads (params, success and failure data) before
+// they are written to disk by a persistence backend so that sensitive task data is not
+// stored in plaintext. Ciphers must be safe to use from multiple go routines.
+type Cipher interface {
+	Encrypt(plaintext []byte) (ciphertext []byte, err error)
+	Decrypt(ciphertext []byte) (plaintext []byte, err error)
+}
+
+// KMS is a hook for an external key management service that is used for envelope
+// encryption by the cipher returned from NewKMSCipher. GenerateDataKey should return a
+// new AES key both in plaintext and encrypted by the master key held by the KMS, while
+// DecryptDataKey should return the plaintext key for a previously encrypted data key.
+type KMS interface {
+	GenerateDataKey() (plaintext, encrypted []byte, err error)
+	DecryptDataKey(encrypted []byte) (plaintext []byte, err error)
+}
+
+// NewAESCipher returns an AES-GCM cipher using the specified key, which must be 16, 24,
+// or 32 bytes long to select AES-128, AES-192, or AES-256 respectively. A random nonce
+// is generated for every encryption and prepended to the ciphertext.
+func NewAESCipher(key []byte) (c Cipher, err error) {
+	var aead cipher.AEAD
+	if aead, err = newGCM(key); err != nil {
+		return nil, err
+	}
+	return &aesCipher{aead: aead}, nil
+}
+
+// NewKMSCipher returns a cipher that uses envelope encryption: every payload is encrypted
+// with AES-GCM using a fresh data key generated by the KMS and the encrypted data key is
+// stored alongside the ciphertext so that it can be decrypted by the KMS later.
+func NewKMSCipher(kms KMS) Cipher {
+	return &kmsCipher{kms: kms}
+}
+
+type aesCipher struct {
+	aead cipher.AEAD
+}
+
+// Encrypt the plaintext with a random nonce, returning nonce+ciphertext.
+func (c *aesCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	return seal(c.aead, plaintext)
+}
+
+// Decrypt ciphertext that was encrypted with the same key.
+func (c *aesCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	return open(c.aead, ciphertext)
+}
+
+type kmsCipher struct {
+	kms KMS
+}
+
+// Encrypt the plaintext with a new data key, returning the length of the encrypted data
+// key as a uvarint followed by the encrypted data key, the nonce, and the ciphertext.
+func (c *kmsCipher) Encrypt(plaintext []byte) (ciphertext []byte, err error) {
+	var key, encKey []byte
+	if key, encKey, err = c.kms.GenerateDataKey(); err != nil {
+		return nil, fmt.Errorf("could not generate data key: %s", err)
+	}
+
+	var aead cipher.AEAD
+	if aead, err = newGCM(key); err != nil {
+		return nil, err
+	}
+
+	var sealed []byte
+	if sealed, err = seal(aead, plaintext); err != nil {
+		return nil, err
+	}
+
+	ciphertext = make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(encKey)+len(sealed))
+	ciphertext = ciphertext[:binary.PutUvarint(ciphertext, uint64(len(encKey)))]
+	ciphertext = append(ciphertext, encKey...)
+	return append(ciphertext, sealed...), nil
+}
+
+// Decrypt the data key with the KMS then decrypt the ciphertext with the data key.
+func (c *kmsCipher) Decrypt(ciphertext []byte) (plaintext []byte, err error) {
+	keylen, n := binary.Uvarint(ciphertext)
+	if n <= 0 || uint64(len(ciphertext)-n) < keylen {
+		return nil, fmt.Errorf("could not decrypt: malformed envelope")
+	}
+
+	var key []byte
+	if key, err = c.kms.DecryptDataKey(ciphertext[n : n+int(keylen)]); err != nil {
+		return nil, fmt.Errorf("could not decrypt data key: %s", err)
+	}
+
+	var aead cipher.AEAD
+	if aead, err = newGCM(key); err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext[n+int(keylen):])
+}
+
+func newGCM(key []byte) (aead cipher.AEAD, err error) {
+	var block cipher.Block
+	if block, err = aes.NewCipher(key); err != nil {
+		return nil, fmt.Errorf("could not create AES cipher: %s", err)
+	}
+
+	if aead, err = cipher.NewGCM(block); err != nil {
+		return nil, fmt.Errorf("could not create GCM cipher: %s", err)
+	}
+	return aead, nil
+}
+
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("could not generate nonce: %s", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, ciphertext []byte) (plaintext []byte, err error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("could not decrypt: ciphertext too short")
+	}
+
+	nonce := ciphertext[:aead.NonceSize()]
+	if plaintext, err = aead.Open(nil, nonce, ciphertext[aead.NonceSize():], nil); err != nil {
+		return nil, fmt.Errorf("could not decrypt: %s", err)
+	}
+	return plaintext, nil
+}
+
+// encrypt returns a copy of the future whose payloads are encrypted with the cipher. If
+// the cipher is nil, the future is returned unmodified.
+func (f *Future) encrypt(c Cipher) (enc *Future, err error) {
+	if c == nil {
+		return f, nil
+	}
+
+	cp := *f
+	if cp.Params, err = encryptPayload(c, f.Params); err != nil {
+		return nil, err
+	}
+	if cp.Success, err = encryptPayload(c, f.Success); err != nil {
+		return nil, err
+	}
+	if cp.Failure, err = encryptPayload(c, f.Failure); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// decrypt returns a copy of the future whose payloads are decrypted with the cipher. If
+// the cipher is nil, the future is returned unmodified.
+func (f *Future) decrypt(c Cipher) (dec *Future, err error) {
+	if c == nil {
+		return f, nil
+	}
+
+	cp := *f
+	if cp.Params, err = decryptPayload(c, f.Params); err != nil {
+		return nil, err
+	}
+	if cp.Success, err = decryptPayload(c, f.Success); err != nil {
+		return nil, err
+	}
+	if cp.Failure, err = decryptPayload(c, f.Failure); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// nil payloads are not encrypted so that they remain nil when decrypted
+func encryptPayload(c Cipher, payload []byte) ([]byte, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	return c.Encrypt(payload)
+}
+
+func decryptPayload(c Cipher, payload []byte) ([]byte, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	return c.Decrypt(payload)
+}
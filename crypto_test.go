@@ -0,0 +1,105 @@
+package radish_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAESCipher(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	cipher, err := NewAESCipher(key)
+	require.NoError(t, err)
+
+	plaintext := []byte(`{"email": "jdoe@example.com"}`)
+	ciphertext, err := cipher.Encrypt(plaintext)
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(ciphertext, plaintext))
+
+	// Each encryption should use a different nonce
+	other, err := cipher.Encrypt(plaintext)
+	require.NoError(t, err)
+	require.NotEqual(t, ciphertext, other)
+
+	decrypted, err := cipher.Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, decrypted)
+
+	// Tampered ciphertext should not decrypt
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	_, err = cipher.Decrypt(ciphertext)
+	require.Error(t, err)
+
+	_, err = NewAESCipher([]byte("tooshort"))
+	require.Error(t, err)
+}
+
+func TestKMSCipher(t *testing.T) {
+	master, err := NewAESCipher(bytes.Repeat([]byte{0x42}, 16))
+	require.NoError(t, err)
+
+	kms := &testKMS{master: master}
+	cipher := NewKMSCipher(kms)
+
+	plaintext := []byte("sensitive task parameters")
+	ciphertext, err := cipher.Encrypt(plaintext)
+	require.NoError(t, err)
+
+	decrypted, err := cipher.Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, decrypted)
+	require.Equal(t, 1, kms.generated)
+	require.Equal(t, 1, kms.decrypted)
+
+	// KMS errors should be returned
+	kms.err = errors.New("kms unavailable")
+	_, err = cipher.Decrypt(ciphertext)
+	require.EqualError(t, err, "could not decrypt data key: kms unavailable")
+
+	_, err = cipher.Decrypt([]byte{0xff})
+	require.Error(t, err)
+}
+
+func TestConfigEncryptionKey(t *testing.T) {
+	conf := &Config{EncryptionKey: bytes.Repeat([]byte{0x1}, 24)}
+	require.NoError(t, conf.Validate())
+	require.NotNil(t, conf.Cipher)
+
+	conf = &Config{EncryptionKey: []byte("badkey")}
+	require.EqualError(t, conf.Validate(), "[1] invalid encryption key: could not create AES cipher: crypto/aes: invalid key size 6")
+}
+
+// testKMS wraps data keys with a master cipher
+type testKMS struct {
+	master    Cipher
+	generated int
+	decrypted int
+	err       error
+}
+
+func (k *testKMS) GenerateDataKey() (plaintext, encrypted []byte, err error) {
+	k.generated++
+	plaintext = make([]byte, 32)
+	if _, err = rand.Read(plaintext); err != nil {
+		return nil, nil, err
+	}
+	if encrypted, err = k.master.Encrypt(plaintext); err != nil {
+		return nil, nil, err
+	}
+	return plaintext, encrypted, nil
+}
+
+func (k *testKMS) DecryptDataKey(encrypted []byte) ([]byte, error) {
+	if k.err != nil {
+		return nil, k.err
+	}
+	k.decrypted++
+	return k.master.Decrypt(encrypted)
+}
@@ -0,0 +1,205 @@
+package radish
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// Default number of futures kept in the dead letter queue.
+const defaultDeadLetterSize = 1000
+
+// DeadLetter is a future that failed permanently, either because it failed on its final
+// attempt, its handler panicked, or its task was not registered when it was dequeued.
+type DeadLetter struct {
+	Future *Future   // the future that failed
+	Error  error     // the error that caused the future to fail
+	Failed time.Time // when the future was moved to the dead letter queue
+}
+
+// deadLetters is a bounded queue of futures that failed permanently so that they can be
+// inspected, re-driven, or purged rather than being silently dropped. When the queue is
+// full, the oldest dead letter is evicted to make room.
+type deadLetters struct {
+	sync.Mutex
+	size    int
+	letters []*DeadLetter
+}
+
+func newDeadLetters(size int) *deadLetters {
+	return &deadLetters{size: size, letters: make([]*DeadLetter, 0)}
+}
+
+// add a failed future to the dead letter queue.
+func (q *deadLetters) add(future *Future, err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.size <= 0 {
+		return
+	}
+
+	if len(q.letters) >= q.size {
+		q.letters = q.letters[1:]
+	}
+	q.letters = append(q.letters, &DeadLetter{Future: future, Error: err, Failed: time.Now()})
+}
+
+// list returns the dead letters that match the ids or task, oldest first.
+func (q *deadLetters) list(task string, ids []uuid.UUID) []*DeadLetter {
+	q.Lock()
+	defer q.Unlock()
+
+	letters := make([]*DeadLetter, 0, len(q.letters))
+	for _, letter := range q.letters {
+		if letter.matches(task, ids) {
+			letters = append(letters, letter)
+		}
+	}
+	return letters
+}
+
+// remove and return the dead letters that match the ids or task.
+func (q *deadLetters) remove(task string, ids []uuid.UUID) []*DeadLetter {
+	q.Lock()
+	defer q.Unlock()
+
+	removed := make([]*DeadLetter, 0)
+	letters := q.letters[:0]
+	for _, letter := range q.letters {
+		if letter.matches(task, ids) {
+			removed = append(removed, letter)
+		} else {
+			letters = append(letters, letter)
+		}
+	}
+	q.letters = letters
+	return removed
+}
+
+// matches returns true if the dead letter is a future of the task (or any task if task
+// is empty) and has one of the ids (or any id if no ids are specified).
+func (l *DeadLetter) matches(task string, ids []uuid.UUID) bool {
+	if task != "" && l.Future.Task != task {
+		return false
+	}
+
+	if len(ids) == 0 {
+		return true
+	}
+
+	for _, id := range ids {
+		if uuid.Equal(id, l.Future.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadLetters returns the futures in the dead letter queue that match the ids or task.
+// If task is empty, dead letters of all tasks are returned; if no ids are specified,
+// dead letters with any id are returned.
+func (r *Radish) DeadLetters(task string, ids ...uuid.UUID) []*DeadLetter {
+	return r.deadLetters.list(task, ids)
+}
+
+// Redrive removes the futures that match the ids or task from the dead letter queue and
+// enqueues them again so that they are handled from their first attempt, returning the
+// number of futures that were re-driven. Futures that could not be enqueued, e.g. if the
+// context is canceled while the queue is full, are returned to the dead letter queue.
+func (r *Radish) Redrive(ctx context.Context, task string, ids ...uuid.UUID) (n int, err error) {
+	if err = r.accepting(); err != nil {
+		return 0, err
+	}
+
+	letters := r.deadLetters.remove(task, ids)
+	for i, letter := range letters {
+		// Copy the future since the worker that failed it may still be completing it
+		future := &Future{}
+		*future = *letter.Future
+		future.Attempts, future.canceled = 0, false
+
+		r.inflight.enqueue(future.ID)
+		if err = r.requeue(ctx, future); err != nil {
+			r.inflight.drop(future.ID)
+			r.results.remove(future.ID)
+			r.unpersist(future)
+			for _, letter := range letters[i:] {
+				r.deadLetters.add(letter.Future, letter.Error)
+			}
+			return n, err
+		}
+		n++
+	}
+
+	if n > 0 {
+		r.logf(out.LevelInfo, task, "re-drove %d futures from the dead letter queue", n)
+	}
+	return n, nil
+}
+
+// Purge removes the futures that match the ids or task from the dead letter queue
+// without handling them, returning the number of futures that were removed.
+func (r *Radish) Purge(task string, ids ...uuid.UUID) int {
+	return len(r.deadLetters.remove(task, ids))
+}
+
+// ListDeadLetters returns the dead letters that match the request.
+func (r *Radish) ListDeadLetters(ctx context.Context, in *api.DeadLetterRequest) (rep *api.DeadLetterReply, err error) {
+	letters := r.DeadLetters(in.Task, deadLetterIDs(in)...)
+	if in.Limit > 0 && int(in.Limit) < len(letters) {
+		letters = letters[len(letters)-int(in.Limit):]
+	}
+
+	rep = &api.DeadLetterReply{Success: true, Count: int32(len(letters)), Futures: make([]*api.DeadLetter, 0, len(letters))}
+	for _, letter := range letters {
+		msg := &api.DeadLetter{
+			Uuid:     letter.Future.ID,
+			Task:     letter.Future.Task,
+			Params:   letter.Future.Params,
+			Attempts: int32(letter.Future.Attempts),
+			Failed:   letter.Failed.UnixNano(),
+		}
+
+		if msg.Error, _ = apiError(letter.Error); msg.Error == nil {
+			msg.Error = &api.Error{Code: CodeUnknown, Message: letter.Error.Error()}
+		}
+		rep.Futures = append(rep.Futures, msg)
+	}
+	return rep, nil
+}
+
+// RedriveDeadLetters re-drives the dead letters that match the request.
+func (r *Radish) RedriveDeadLetters(ctx context.Context, in *api.DeadLetterRequest) (rep *api.DeadLetterReply, err error) {
+	rep = &api.DeadLetterReply{Success: true}
+	n, err := r.Redrive(ctx, in.Task, deadLetterIDs(in)...)
+	rep.Count = int32(n)
+
+	if err != nil {
+		rep.Success = false
+
+		var ok bool
+		if rep.Error, ok = apiError(err); !ok {
+			return nil, err
+		}
+	}
+	return rep, nil
+}
+
+// PurgeDeadLetters purges the dead letters that match the request.
+func (r *Radish) PurgeDeadLetters(ctx context.Context, in *api.DeadLetterRequest) (rep *api.DeadLetterReply, err error) {
+	return &api.DeadLetterReply{Success: true, Count: int32(r.Purge(in.Task, deadLetterIDs(in)...))}, nil
+}
+
+// deadLetterIDs converts the ids in the request to UUIDs.
+func deadLetterIDs(in *api.DeadLetterRequest) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(in.Uuids))
+	for _, id := range in.Uuids {
+		ids = append(ids, uuid.UUID(id))
+	}
+	return ids
+}
@@ -0,0 +1,69 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetters(t *testing.T) {
+	queue, err := New(&Config{Workers: 1, NoSignals: true, DeadLetterSize: 3})
+	require.NoError(t, err)
+
+	// The task fails until it is fixed and panics on bad params
+	var fixed int32
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "flaky", onHandle: func(id uuid.UUID, params []byte) error {
+		if string(params) == "panic" {
+			panic("bad params")
+		}
+		if atomic.LoadInt32(&fixed) == 0 {
+			return errors.New("whoops!")
+		}
+		return nil
+	}}
+	require.NoError(t, queue.Register(task))
+
+	wg.Add(4)
+	ids := make([]uuid.UUID, 0, 4)
+	for _, params := range []string{"a", "panic", "b", "c"} {
+		id, err := queue.Delay("flaky", []byte(params), nil, nil)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	wg.Wait()
+
+	// The dead letter queue is bounded, evicting the oldest dead letter
+	letters := queue.DeadLetters("")
+	require.Len(t, letters, 3)
+	require.Equal(t, ids[1], letters[0].Future.ID)
+	require.EqualError(t, letters[0].Error, "[0] flaky task panicked: bad params")
+	require.EqualError(t, letters[1].Error, "whoops!")
+	require.Len(t, queue.DeadLetters("other"), 0)
+
+	rep, err := queue.ListDeadLetters(context.Background(), &api.DeadLetterRequest{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, rep.Futures, 1)
+	require.Equal(t, []byte(ids[3]), rep.Futures[0].Uuid)
+	require.Equal(t, "whoops!", rep.Futures[0].Error.Message)
+
+	// Purge the panicking future then re-drive the others once the task is fixed
+	require.Equal(t, 1, queue.Purge("", ids[1]))
+	atomic.StoreInt32(&fixed, 1)
+
+	wg.Add(2)
+	n, err := queue.Redrive(context.Background(), "flaky")
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	wg.Wait()
+
+	require.Len(t, queue.DeadLetters(""), 0)
+	require.Equal(t, int32(2), task.successes)
+}
@@ -0,0 +1,129 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainQueue(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+
+	task := &testTask{wg: wg, name: "drain"}
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn"}, task)
+	require.NoError(t, err)
+
+	_, err = queue.Delay(task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	// Draining the queue stops new tasks from being accepted
+	ctx := context.Background()
+	rep, err := queue.DrainQueue(ctx, &api.DrainRequest{})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+
+	status, err := queue.Status(ctx, &api.StatusRequest{})
+	require.NoError(t, err)
+	require.True(t, status.Draining)
+
+	_, err = queue.Delay(task.Name(), nil, nil, nil)
+	require.EqualError(t, err, "[10] queue is draining, not accepting new tasks")
+
+	// The queue is shutdown once it has been drained if requested
+	rep, err = queue.DrainQueue(ctx, &api.DrainRequest{Shutdown: true})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+
+	require.Eventually(t, func() bool { return queue.NumWorkers() == 0 }, 5*time.Second, 10*time.Millisecond)
+	require.EqualError(t, queue.Shutdown(), "[10] queue has already been shutdown")
+
+	rep, err = queue.DrainQueue(ctx, &api.DrainRequest{})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, "queue has been shutdown", rep.Error.Message)
+}
+
+func TestDrain(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+
+	task := &testTask{wg: wg, name: "drain", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn"}, task)
+	require.NoError(t, err)
+
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		_, err = queue.Delay(task.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+	<-started
+
+	// The context expires before the queue is drained
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.EqualError(t, queue.Drain(ctx), "[7] queue was not drained with 3 tasks remaining: context deadline exceeded")
+
+	_, err = queue.Delay(task.Name(), nil, nil, nil)
+	require.EqualError(t, err, "[10] queue is draining, not accepting new tasks")
+
+	// Drain returns once the futures in the queue and in flight have been handled
+	close(release)
+	require.NoError(t, queue.Drain(context.Background()))
+	require.Equal(t, int32(3), atomic.LoadInt32(&task.successes))
+	require.Equal(t, 1, queue.NumWorkers())
+	wg.Wait()
+
+	require.NoError(t, queue.Shutdown())
+	require.EqualError(t, queue.Drain(context.Background()), "[10] queue has been shutdown")
+}
+
+func TestDrainInFlight(t *testing.T) {
+	// One future is slow and the other fails once and waits to be retried
+	wg := new(sync.WaitGroup)
+	var attempts int32
+	task := &testTask{wg: wg, name: "drain", onHandle: func(id uuid.UUID, params []byte) error {
+		switch string(params) {
+		case "slow":
+			time.Sleep(100 * time.Millisecond)
+		case "retry":
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				return errors.New("try again")
+			}
+		}
+		return nil
+	}}
+
+	conf := &Config{Workers: 2, NoSignals: true, SuppressMetrics: true, LogLevel: "silent", Retry: RetryPolicy{MaxAttempts: 2, BaseDelay: 200 * time.Millisecond}}
+	queue, err := New(conf, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	wg.Add(2)
+	_, err = queue.Delay(task.Name(), []byte("slow"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Delay(task.Name(), []byte("retry"), nil, nil)
+	require.NoError(t, err)
+
+	// Drain waits for the slow future and the retry even while the queue is empty
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&attempts) == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, queue.Drain(context.Background()))
+	require.Equal(t, int32(2), atomic.LoadInt32(&task.successes))
+	require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	wg.Wait()
+}
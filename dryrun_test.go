@@ -0,0 +1,60 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+
+	// Block the worker so that queued futures remain in the queue
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	task := &testTask{wg: wg, name: "report", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, TaskQuota: 1}, task)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	outcome, err := queue.DryRun(ctx, "report", nil)
+	require.NoError(t, err)
+	require.Equal(t, "would queue report future behind 0 futures", outcome)
+
+	_, err = queue.DryRun(ctx, "unknown", nil)
+	require.EqualError(t, err, `[3] could not delay [3] unknown task "unknown"`)
+
+	// Dry runs do not queue futures
+	rep, err := queue.Queue(ctx, &api.QueueRequest{Task: "report", DryRun: true})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	require.Empty(t, rep.Uuid)
+	require.Equal(t, int32(0), task.handled)
+
+	// Dry runs check the quota headroom of the task
+	_, err = queue.Delay("report", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	_, err = queue.Delay("report", nil, nil, nil)
+	require.NoError(t, err)
+
+	rep, err = queue.Queue(ctx, &api.QueueRequest{Task: "report", DryRun: true})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, `[11] task "report" has reached its quota of 1 queued futures`, rep.Error.Error())
+
+	close(release)
+	wg.Wait()
+}
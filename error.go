@@ -1,21 +1,88 @@
 package radish
 
 import (
+	"errors"
+
 	"github.com/kansaslabs/radish/api"
 )
 
-// Error codes that are common to the radish server
+// Error codes that are common to the radish server, which are sent to clients as the
+// code of the api.Error of a reply.
 const (
-	ErrUnknown int32 = iota
-	ErrInvalidConfig
-	ErrTaskAlreadyRegistered
-	ErrTaskNotRegistered
-	ErrNoWorkers
-	ErrInvalidWorkers
-	ErrBadGateway
+	CodeUnknown int32 = iota
+	CodeInvalidConfig
+	CodeTaskAlreadyRegistered
+	CodeTaskNotRegistered
+	CodeNoWorkers
+	CodeInvalidWorkers
+	CodeBadGateway
+	CodeCanceled
+	CodeInvalidSchema
+	CodeInvalidParams
+	CodeShutdown
+	CodeQuotaExceeded
+	CodeChildFailed
+	CodeNotFound
+	CodeStorage
+	CodeQueueFull
+	CodeInvalidWorkflow
+)
+
+// Sentinel errors for each error code. Every radish error matches the sentinel error of
+// its code with errors.Is, no matter its message or how it was wrapped, so callers can
+// check for an error without parsing the code from its message:
+//
+//	if errors.Is(err, radish.ErrQueueFull) {
+//		// back off and try again later
+//	}
+var (
+	ErrUnknown               = ErrCode(CodeUnknown)
+	ErrInvalidConfig         = ErrCode(CodeInvalidConfig)
+	ErrTaskAlreadyRegistered = ErrCode(CodeTaskAlreadyRegistered)
+	ErrTaskNotRegistered     = ErrCode(CodeTaskNotRegistered)
+	ErrNoWorkers             = ErrCode(CodeNoWorkers)
+	ErrInvalidWorkers        = ErrCode(CodeInvalidWorkers)
+	ErrBadGateway            = ErrCode(CodeBadGateway)
+	ErrCanceled              = ErrCode(CodeCanceled)
+	ErrInvalidSchema         = ErrCode(CodeInvalidSchema)
+	ErrInvalidParams         = ErrCode(CodeInvalidParams)
+	ErrShutdown              = ErrCode(CodeShutdown)
+	ErrQuotaExceeded         = ErrCode(CodeQuotaExceeded)
+	ErrChildFailed           = ErrCode(CodeChildFailed)
+	ErrNotFound              = ErrCode(CodeNotFound)
+	ErrStorage               = ErrCode(CodeStorage)
+	ErrQueueFull             = ErrCode(CodeQueueFull)
+	ErrInvalidWorkflow       = ErrCode(CodeInvalidWorkflow)
 )
 
 // Errorf is a passthrough to api.Errorf, implemented here to allow for radish.Errorf calls.
+// If the format wraps an error with %w, the cause can be matched with errors.Is and
+// errors.As as well as the sentinel error of the code.
 func Errorf(code int32, format string, a ...interface{}) error {
 	return api.Errorf(code, format, a...)
 }
+
+// ErrCode returns a sentinel error for the code that matches any radish error with the
+// same code using errors.Is, e.g. for codes of newer servers that do not have a sentinel.
+func ErrCode(code int32) error {
+	return &api.Error{Code: code}
+}
+
+// CodeOf returns the code of the first radish error in the chain of wrapped errors, or
+// CodeUnknown if the error is not a radish error.
+func CodeOf(err error) int32 {
+	if e, ok := apiError(err); ok {
+		return e.Code
+	}
+	return CodeUnknown
+}
+
+// apiError returns the first radish error in the chain of wrapped errors so that it can
+// be sent in a reply.
+func apiError(err error) (*api.Error, bool) {
+	var e *api.Error
+	if errors.As(err, &e) && e != nil {
+		return e, true
+	}
+	return nil, false
+}
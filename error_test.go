@@ -0,0 +1,57 @@
+package radish_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodes(t *testing.T) {
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn"})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Radish errors match the sentinel error of their code, however they are wrapped
+	_, err = queue.Delay("missing", nil, nil, nil)
+	require.True(t, errors.Is(err, ErrTaskNotRegistered))
+	require.False(t, errors.Is(err, ErrQueueFull))
+	require.Equal(t, CodeTaskNotRegistered, CodeOf(err))
+
+	wrapped := fmt.Errorf("could not send email: %w", err)
+	require.True(t, errors.Is(wrapped, ErrTaskNotRegistered))
+	require.Equal(t, CodeTaskNotRegistered, CodeOf(wrapped))
+
+	var aerr *api.Error
+	require.True(t, errors.As(wrapped, &aerr))
+	require.Equal(t, CodeTaskNotRegistered, aerr.Code)
+
+	// Other errors do not have a radish error code
+	require.Equal(t, CodeUnknown, CodeOf(errors.New("boom")))
+	require.False(t, errors.Is(errors.New("boom"), ErrUnknown))
+}
+
+func TestErrorCause(t *testing.T) {
+	// Radish errors keep the error they wrap
+	err := Errorf(CodeStorage, "could not read future: %w", io.EOF)
+	require.EqualError(t, err, fmt.Sprintf("[%d] could not read future: EOF", CodeStorage))
+	require.True(t, errors.Is(err, ErrStorage))
+	require.True(t, errors.Is(err, io.EOF))
+	require.False(t, errors.Is(err, ErrQueueFull))
+	require.Equal(t, io.EOF, errors.Unwrap(err))
+	require.Equal(t, CodeStorage, CodeOf(err))
+
+	var aerr *api.Error
+	require.True(t, errors.As(err, &aerr))
+	require.Equal(t, CodeStorage, aerr.Code)
+	require.Equal(t, err.Error(), aerr.Error())
+
+	// Errors without a wrapped error have no cause
+	err = Errorf(CodeStorage, "could not read future")
+	require.True(t, errors.Is(err, ErrStorage))
+	require.Nil(t, errors.Unwrap(err))
+}
@@ -0,0 +1,101 @@
+package radish
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// Environment variables set for commands run by an ExecTask.
+const (
+	EnvFutureID = "RADISH_FUTURE_ID"
+	EnvTaskName = "RADISH_TASK"
+)
+
+// ExecTask is a built-in task handler that runs an external command for each future so
+// that legacy scripts can be driven by the radish queue without writing Go code. The
+// params of the future are written to the command's stdin and the future ID and task
+// name are set in its environment. A zero exit status is a success and any other status
+// is a failure, using the command's stderr as the error message. The command's stdout
+// is captured as the result of the task and passed to OnResult if it is specified. The
+// command is killed if the future is canceled or its worker is forced to stop.
+//
+//	queue.Register(&radish.ExecTask{TaskName: "resize", Command: "/usr/local/bin/resize.sh"})
+type ExecTask struct {
+	TaskName string                            // the unique name of the task
+	Command  string                            // the path to or name of the executable to run
+	Args     []string                          // arguments to pass to the command
+	Env      []string                          // additional environment variables in KEY=value form
+	Dir      string                            // the working directory of the command (default the current directory)
+	Timeout  time.Duration                     // kill the command if it runs longer than the timeout (default no timeout)
+	OnResult func(id uuid.UUID, stdout []byte) // called with the captured stdout when the command succeeds
+}
+
+// Name implements the Task interface.
+func (t *ExecTask) Name() string {
+	return t.TaskName
+}
+
+// Handle runs the command with the params on stdin.
+func (t *ExecTask) Handle(id uuid.UUID, params []byte) error {
+	return t.HandleContext(context.Background(), id, params)
+}
+
+// HandleContext runs the command with the params on stdin, killing the command if the
+// context is done, e.g. when the future is canceled or its worker is forced to stop.
+func (t *ExecTask) HandleContext(parent context.Context, id uuid.UUID, params []byte) (err error) {
+	ctx := parent
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.Command, t.Args...)
+	cmd.Dir = t.Dir
+	cmd.Stdin = bytes.NewReader(params)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), t.Env...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", EnvFutureID, id), fmt.Sprintf("%s=%s", EnvTaskName, t.TaskName))
+
+	if err = cmd.Run(); err != nil {
+		if parent.Err() != nil {
+			return parent.Err()
+		}
+
+		if ctx.Err() == context.DeadlineExceeded {
+			return Errorf(CodeCanceled, "%s command timed out after %s", t.TaskName, t.Timeout)
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return Errorf(CodeUnknown, "%s command exited with status %d: %s", t.TaskName, exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+		}
+		return Errorf(CodeUnknown, "could not run %s command: %s", t.TaskName, err)
+	}
+
+	if t.OnResult != nil {
+		t.OnResult(id, stdout.Bytes())
+	}
+	return nil
+}
+
+// Success implements the Task interface.
+func (t *ExecTask) Success(id uuid.UUID, params []byte) {
+	write(out.LevelDebug, "%s command for future %s succeeded", t.TaskName, id)
+}
+
+// Failure implements the Task interface.
+func (t *ExecTask) Failure(id uuid.UUID, err error, params []byte) {
+	write(out.LevelDebug, "%s command for future %s failed: %s", t.TaskName, id, err)
+}
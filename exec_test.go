@@ -0,0 +1,77 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecTask(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is required to test the exec task")
+	}
+
+	var result []byte
+	id := uuid.NewRandom()
+	task := &ExecTask{
+		TaskName: "shout",
+		Command:  "sh",
+		Args:     []string{"-c", `echo "$(tr a-z A-Z) $RADISH_TASK $GREETING"`},
+		Env:      []string{"GREETING=hello"},
+		OnResult: func(fid uuid.UUID, stdout []byte) {
+			require.Equal(t, id, fid)
+			result = stdout
+		},
+	}
+
+	require.Equal(t, "shout", task.Name())
+	require.NoError(t, task.Handle(id, []byte("radish")))
+	require.Equal(t, "RADISH shout hello\n", string(result))
+
+	// Non-zero exit codes are failures
+	task = &ExecTask{TaskName: "fail", Command: "sh", Args: []string{"-c", "echo whoops >&2; exit 3"}}
+	require.EqualError(t, task.Handle(id, nil), "[0] fail command exited with status 3: whoops")
+
+	// Commands that run too long are killed
+	task = &ExecTask{TaskName: "slow", Command: "sh", Args: []string{"-c", "exec sleep 5"}, Timeout: 50 * time.Millisecond}
+	require.EqualError(t, task.Handle(id, nil), "[7] slow command timed out after 50ms")
+
+	// Commands that cannot be run are failures
+	task = &ExecTask{TaskName: "missing", Command: "/does/not/exist"}
+	require.Error(t, task.Handle(id, nil))
+}
+
+func TestExecTaskCancel(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is required to test the exec task")
+	}
+
+	task := &ExecTask{TaskName: "slow", Command: "sh", Args: []string{"-c", "exec sleep 5"}}
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "silent"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	started := make(chan struct{}, 1)
+	queue.OnEvent(func(e Event) {
+		if e.Type == EventStart {
+			started <- struct{}{}
+		}
+	})
+
+	// Canceling the future kills the running command
+	id, err := queue.Delay("slow", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	start := time.Now()
+	require.NoError(t, queue.Cancel(id))
+	_, err = queue.Wait(context.Background(), id)
+	require.True(t, errors.Is(err, ErrCanceled), "expected the command to be canceled, got %v", err)
+	require.True(t, time.Since(start) < 2*time.Second, "the command was not killed when the future was canceled")
+}
@@ -0,0 +1,88 @@
+package radish
+
+import (
+	"context"
+	"expvar"
+	"sync"
+)
+
+// The queue whose state is published as the radish expvar. Only one queue can be
+// published at a time since expvars are global to the process.
+var (
+	varsQueue   *Radish
+	varsMu      sync.RWMutex
+	varsPublish sync.Once
+)
+
+// queueVars is the state of the queue published as the radish expvar.
+type queueVars struct {
+	QueueSize     int                  `json:"queue_size"`
+	QueueCapacity int                  `json:"queue_capacity"`
+	Workers       int                  `json:"workers"`
+	BusyWorkers   int                  `json:"busy_workers"`
+	Paused        bool                 `json:"paused"`
+	Draining      bool                 `json:"draining"`
+	Tasks         map[string]*taskVars `json:"tasks"`
+}
+
+// taskVars are the counters of a task published in the radish expvar.
+type taskVars struct {
+	Pending   uint64 `json:"pending"`
+	Queued    uint64 `json:"queued"`
+	Succeeded uint64 `json:"succeeded"`
+	Failed    uint64 `json:"failed"`
+	Canceled  uint64 `json:"canceled"`
+}
+
+// PublishVars publishes the queue size, worker counts, and per-task counters of the queue
+// as the "radish" expvar so that they can be read from /debug/vars without a Prometheus
+// stack, e.g. with expvarmon or curl. The metrics server publishes the queue when it
+// starts and serves /debug/vars from the default serve mux; applications that serve the
+// default serve mux themselves can call PublishVars directly. If more than one queue is
+// published, the most recently published queue is reported.
+func (r *Radish) PublishVars() {
+	varsMu.Lock()
+	varsQueue = r
+	varsMu.Unlock()
+
+	varsPublish.Do(func() {
+		expvar.Publish("radish", expvar.Func(publishedVars))
+	})
+}
+
+// publishedVars returns the state of the published queue.
+func publishedVars() interface{} {
+	varsMu.RLock()
+	r := varsQueue
+	varsMu.RUnlock()
+
+	if r == nil {
+		return nil
+	}
+	return r.vars()
+}
+
+// vars returns the current state of the queue.
+func (r *Radish) vars() *queueVars {
+	vars := &queueVars{
+		QueueSize:     r.tasks.Len(),
+		QueueCapacity: r.config.QueueSize,
+		Workers:       r.NumWorkers(),
+		BusyWorkers:   r.BusyWorkers(),
+		Paused:        r.Paused(),
+		Draining:      r.isDraining(),
+		Tasks:         make(map[string]*taskVars),
+	}
+
+	rep, _ := r.ListTasks(context.Background(), nil)
+	for _, info := range rep.Tasks {
+		vars.Tasks[info.Name] = &taskVars{
+			Pending:   info.Pending,
+			Queued:    info.Queued,
+			Succeeded: info.Succeeded,
+			Failed:    info.Failed,
+			Canceled:  info.Canceled,
+		}
+	}
+	return vars
+}
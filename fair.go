@@ -0,0 +1,102 @@
+package radish
+
+import "container/heap"
+
+// fairQueues holds the queued futures of each task in a separate heap and interleaves the
+// tasks when futures are dequeued with smooth weighted round robin, so that each task
+// with queued futures gets a share of the dequeues proportional to its weight no matter
+// how many futures of other tasks are queued. Futures of the same task are dequeued by
+// priority and then in the queue order, like the futures of the memory broker.
+type fairQueues struct {
+	lifo    bool
+	weights map[string]int         // the weight of each task, tasks without a weight have a weight of 1
+	tasks   []string               // the tasks in the order their first future was queued
+	heaps   map[string]*futureHeap // the queued futures of each task
+	current map[string]int         // the current weight of each task with queued futures
+	n       int                    // the total number of queued futures
+}
+
+func newFairQueues(weights map[string]int, lifo bool) *fairQueues {
+	return &fairQueues{
+		lifo:    lifo,
+		weights: weights,
+		heaps:   make(map[string]*futureHeap),
+		current: make(map[string]int),
+	}
+}
+
+func (f *fairQueues) push(item *queued) {
+	h, ok := f.heaps[item.future.Task]
+	if !ok {
+		h = &futureHeap{lifo: f.lifo}
+		f.heaps[item.future.Task] = h
+		f.tasks = append(f.tasks, item.future.Task)
+	}
+	heap.Push(h, item)
+	f.n++
+}
+
+// pop the next future of the task with the highest current weight; the current weight of
+// every task with queued futures grows by its weight and the selected task is reduced by
+// the total weight, which spreads the dequeues of each task evenly over the rounds.
+func (f *fairQueues) pop() *queued {
+	var next string
+	total, best := 0, 0
+	for _, task := range f.tasks {
+		if f.heaps[task].Len() == 0 {
+			continue
+		}
+
+		weight := f.weight(task)
+		total += weight
+		f.current[task] += weight
+		if next == "" || f.current[task] > best {
+			next, best = task, f.current[task]
+		}
+	}
+
+	f.current[next] -= total
+	return f.remove(next, -1)
+}
+
+// popOldest removes the future that was queued first, regardless of its task.
+func (f *fairQueues) popOldest() *queued {
+	var task string
+	oldest := -1
+	for _, t := range f.tasks {
+		for i, item := range f.heaps[t].items {
+			if oldest < 0 || item.seq < f.heaps[task].items[oldest].seq {
+				task, oldest = t, i
+			}
+		}
+	}
+	return f.remove(task, oldest)
+}
+
+// remove the item at index i of the heap of the task, or the top of the heap if i is
+// negative, resetting the current weight of the task once it has no queued futures.
+func (f *fairQueues) remove(task string, i int) (item *queued) {
+	h := f.heaps[task]
+	if i < 0 {
+		item = heap.Pop(h).(*queued)
+	} else {
+		item = heap.Remove(h, i).(*queued)
+	}
+
+	if h.Len() == 0 {
+		delete(f.current, task)
+	}
+	f.n--
+	return item
+}
+
+func (f *fairQueues) len() int {
+	return f.n
+}
+
+func (f *fairQueues) weight(task string) int {
+	if weight, ok := f.weights[task]; ok {
+		return weight
+	}
+	return 1
+}
@@ -0,0 +1,64 @@
+package radish_test
+
+import (
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFairScheduling(t *testing.T) {
+	// Block the only worker so that futures accumulate in the queue
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blocker := &testTask{wg: new(sync.WaitGroup), name: "blocker", onHandle: func(id uuid.UUID, params []byte) error {
+		close(started)
+		<-release
+		return nil
+	}}
+	blocker.wg.Add(1)
+
+	var mu sync.Mutex
+	var order []string
+	wg := new(sync.WaitGroup)
+	record := func(id uuid.UUID, params []byte) error {
+		mu.Lock()
+		order = append(order, string(params))
+		mu.Unlock()
+		return nil
+	}
+	bulk := &testTask{wg: wg, name: "bulk", onHandle: record}
+	urgent := &testTask{wg: wg, name: "urgent", onHandle: record}
+
+	conf := &Config{Workers: 1, NoSignals: true, LogLevel: "silent", FairScheduling: true, TaskWeights: map[string]int{"urgent": 2}}
+	queue, err := New(conf, blocker, bulk, urgent)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	_, err = queue.Delay("blocker", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	// A burst of bulk futures does not hold up the urgent futures queued after it
+	wg.Add(24)
+	for i := 0; i < 20; i++ {
+		_, err = queue.Delay("bulk", []byte("b"), nil, nil)
+		require.NoError(t, err)
+	}
+	for i := 0; i < 4; i++ {
+		_, err = queue.Delay("urgent", []byte("u"), nil, nil)
+		require.NoError(t, err)
+	}
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, []string{"u", "b", "u", "u", "b", "u"}, order[:6])
+
+	// Weights must be positive and fair scheduling requires the memory broker
+	_, err = New(&Config{FairScheduling: true, TaskWeights: map[string]int{"bulk": 0}})
+	require.EqualError(t, err, `[1] the weight of task "bulk" must be positive`)
+	_, err = New(&Config{FairScheduling: true, Broker: NewMemoryBroker(10)})
+	require.EqualError(t, err, "[1] fair scheduling requires the memory broker")
+}
@@ -0,0 +1,164 @@
+package radish
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// Full queue policies that determine what happens when a future is delayed while the
+// queue is full.
+const (
+	FullBlock        = "block"         // wait for room in the queue or until the context is done
+	FullBlockTimeout = "block-timeout" // wait for room in the queue for up to the FullTimeout, then return an ErrQueueFull error
+	FullReject       = "reject"        // return an ErrQueueFull error immediately
+	FullDropOldest   = "drop-oldest"   // drop the oldest queued future to make room
+)
+
+// errQueueFull is returned by push when the queue is full and the policy rejects futures.
+var errQueueFull = errors.New("queue is full")
+
+// TryDelay creates a new future and adds it to the task queue like Delay, but returns
+// an ErrQueueFull error immediately rather than blocking if the queue is full.
+func (r *Radish) TryDelay(task string, params, success, failure []byte) (id uuid.UUID, err error) {
+	return r.delay(context.Background(), task, params, success, failure, FullReject)
+}
+
+// push the future onto the task queue, applying the full queue policy if there is no
+// room in the queue. Returns errQueueShutdown if the queue is shutdown, errQueueFull if
+// the queue is full and the policy rejects futures, or the context error if the context
+// is done while blocking.
+func (r *Radish) push(ctx context.Context, future *Future, policy string) (err error) {
+	switch policy {
+	case FullBlock:
+		return r.enqueue(ctx, future)
+	case FullBlockTimeout:
+		timeout, cancel := context.WithTimeout(ctx, r.config.FullTimeout)
+		defer cancel()
+		if err = r.enqueue(timeout, future); err == context.DeadlineExceeded && ctx.Err() == nil {
+			return errQueueFull
+		}
+		return err
+	}
+
+	// Enqueue with a done context so that the future is only added if there is room
+	full, cancel := context.WithCancel(ctx)
+	cancel()
+
+	for {
+		if err = r.enqueue(full, future); err != context.Canceled {
+			return err
+		}
+
+		if policy != FullDropOldest {
+			return errQueueFull
+		}
+
+		// Drop the oldest future to make room, another producer may take the room first.
+		// If there is no future to drop, e.g. because consumers hold the futures, give up.
+		var oldest *Future
+		if oldest, err = r.dequeueOldest(full); err != nil {
+			return errQueueFull
+		}
+		r.drop(oldest)
+	}
+}
+
+// dequeueOldest dequeues the oldest future from the broker, which is the next future
+// unless the broker is a LIFO or fair memory broker.
+func (r *Radish) dequeueOldest(ctx context.Context) (*Future, error) {
+	if q, ok := r.tasks.(*taskQueue); ok && (q.futures.lifo || q.fair != nil) {
+		return q.dequeueOldest(ctx)
+	}
+	return r.tasks.Dequeue(ctx)
+}
+
+// drop a queued future to make room in the full queue, failing the future with an
+// ErrQueueFull error so that it is moved to the dead letter queue and can be re-driven.
+func (r *Radish) drop(future *Future) {
+	r.ack(future)
+	r.releaseQuotas(future)
+	r.inflight.drop(future.ID)
+
+	err := Errorf(CodeQueueFull, "%s future %s was dropped from the full queue", future.Task, future.ID)
+	r.logf(out.LevelWarn, future.Task, "%s", err)
+	pmTasksDropped.WithLabelValues(future.Task).Inc()
+
+	handler, herr := r.Handler(future.Task)
+	if herr != nil {
+		r.releasePartition(future)
+		r.deadLetters.add(future, err)
+		r.results.complete(future, err)
+		r.unpersist(future)
+		r.hooks.emit(Event{Type: EventFailure, Future: future, Err: err})
+		r.auditOutcome(future, err)
+		r.linkedCompleted(future, err)
+		return
+	}
+	r.complete(future, handler, err, time.Now())
+}
+
+// delay a future of the task, applying the full queue policy if the queue is full.
+func (r *Radish) delay(ctx context.Context, task string, params, success, failure []byte, policy string) (id uuid.UUID, err error) {
+	if task, err = r.check(ctx, task, params); err != nil {
+		return nil, err
+	}
+
+	future := r.newFuture(ctx, task, params, success, failure)
+
+	// Return the existing future if a future was delayed with the same idempotency key
+	existing, release := r.claim(future)
+	if existing != nil {
+		return existing, nil
+	}
+	defer func() { release(id) }()
+
+	// Ensure the task has not exceeded its quota of queued futures, coalescing the future
+	// into one that is already queued if configured to do so.
+	var coalesced uuid.UUID
+	if coalesced, err = r.acquireTaskQuota(future); err != nil || coalesced != nil {
+		return coalesced, err
+	}
+
+	// Ensure the API client has not exceeded its quota of pending futures
+	if err = r.acquireClientQuota(future); err != nil {
+		r.releaseTaskQuota(future)
+		return nil, err
+	}
+
+	// Persist the future before it is queued so that it is not lost on restart
+	if err = r.store.put(future, time.Time{}); err != nil {
+		r.releaseQuotas(future)
+		return nil, err
+	}
+
+	r.inflight.enqueue(future.ID)
+	r.partitions.add(future)
+	r.results.track(future, api.FutureState_QUEUED)
+	if err = r.push(ctx, future, policy); err != nil {
+		r.releaseQuotas(future)
+		r.releasePartition(future)
+		r.inflight.drop(future.ID)
+		r.results.remove(future.ID)
+		r.unpersist(future)
+		switch err {
+		case errQueueShutdown:
+			return nil, Errorf(CodeShutdown, "could not delay %s: queue has been shutdown", task)
+		case errQueueFull:
+			return nil, Errorf(CodeQueueFull, "could not delay %s: queue is full", task)
+		default:
+			return nil, Errorf(CodeCanceled, "could not delay %s: %s", task, err)
+		}
+	}
+
+	// Update the queue size and percent full
+	pmQueueSize.Set(float64(r.tasks.Len()))
+	pmPercentFull.Set(float64(r.tasks.Len()) / float64(r.config.QueueSize) * 100)
+	r.stats.queue(future.Task)
+	r.audit(AuditQueued, future, nil)
+	return future.ID, nil
+}
@@ -0,0 +1,150 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullPolicy(t *testing.T) {
+	for _, policy := range []string{FullBlock, FullDropOldest} {
+		// Block the only worker so that the queue fills up
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var errs []error
+
+		wg := new(sync.WaitGroup)
+		task := &testTask{wg: wg, name: "full", onHandle: func(id uuid.UUID, params []byte) error {
+			if string(params) == "block" {
+				close(started)
+				<-release
+			}
+			return nil
+		}, onFailure: func(id uuid.UUID, err error, params []byte) {
+			errs = append(errs, err)
+		}}
+
+		queue, err := New(&Config{Workers: 1, QueueSize: 2, NoSignals: true, FullPolicy: policy}, task)
+		require.NoError(t, err)
+
+		wg.Add(3)
+		_, err = queue.Delay("full", []byte("block"), nil, nil)
+		require.NoError(t, err)
+		<-started
+
+		oldest, err := queue.Delay("full", nil, nil, nil)
+		require.NoError(t, err)
+		_, err = queue.Delay("full", nil, nil, nil)
+		require.NoError(t, err)
+
+		// TryDelay never blocks regardless of the policy
+		_, err = queue.TryDelay("full", nil, nil, nil)
+		require.EqualError(t, err, "[15] could not delay full: queue is full")
+
+		if policy == FullDropOldest {
+			// The oldest queued future is dropped to make room
+			wg.Add(1)
+			_, err = queue.Delay("full", nil, nil, nil)
+			require.NoError(t, err)
+
+			require.Len(t, errs, 1)
+			require.EqualError(t, errs[0], "[15] full future "+oldest.String()+" was dropped from the full queue")
+
+			letters := queue.DeadLetters("full")
+			require.Len(t, letters, 1)
+			require.True(t, uuid.Equal(oldest, letters[0].Future.ID))
+		}
+
+		close(release)
+		wg.Wait()
+		require.NoError(t, queue.Shutdown())
+	}
+
+	_, err := New(&Config{FullPolicy: "panic"})
+	require.EqualError(t, err, `[1] "panic" is an invalid full queue policy, use block, block-timeout, reject, or drop-oldest`)
+}
+
+func TestFullTimeout(t *testing.T) {
+	// Block the only worker so that the queue fills up
+	started := make(chan struct{})
+	release := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "full", onHandle: func(id uuid.UUID, params []byte) error {
+		if string(params) == "block" {
+			close(started)
+			<-release
+		}
+		return nil
+	}}
+
+	conf := &Config{Workers: 1, QueueSize: 1, NoSignals: true, LogLevel: "silent", FullPolicy: FullBlockTimeout, FullTimeout: 100 * time.Millisecond}
+	queue, err := New(conf, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	wg.Add(2)
+	_, err = queue.Delay("full", []byte("block"), nil, nil)
+	require.NoError(t, err)
+	<-started
+	_, err = queue.Delay("full", nil, nil, nil)
+	require.NoError(t, err)
+
+	// The future waits for up to the timeout for room in the full queue
+	start := time.Now()
+	_, err = queue.Delay("full", nil, nil, nil)
+	require.EqualError(t, err, "[15] could not delay full: queue is full")
+	require.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	// The future is queued if room is made before the timeout
+	wg.Add(1)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		close(release)
+	}()
+	_, err = queue.DelayContext(context.Background(), "full", nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+}
+
+func TestFullDropOldestEmpty(t *testing.T) {
+	// The broker is always full but has no futures that can be dropped
+	conf := &Config{Workers: 1, NoSignals: true, LogLevel: "silent", FullPolicy: FullDropOldest, Broker: fullBroker{}}
+	queue, err := New(conf, &testTask{name: "full"})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := queue.Delay("full", nil, nil, nil)
+		errc <- err
+	}()
+
+	select {
+	case err = <-errc:
+		require.EqualError(t, err, "[15] could not delay full: queue is full")
+	case <-time.After(time.Second):
+		t.Fatal("delay did not give up when there was no future to drop")
+	}
+}
+
+// fullBroker is a broker that never has room for a future nor a future to dequeue.
+type fullBroker struct{}
+
+func (fullBroker) Enqueue(ctx context.Context, future *Future) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (fullBroker) Dequeue(ctx context.Context) (*Future, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (fullBroker) Ack(id uuid.UUID) error { return nil }
+
+func (fullBroker) Len() int { return 0 }
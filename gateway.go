@@ -0,0 +1,252 @@
+package radish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// GatewayQueueRequest is the JSON body of a request to the /v1/queue endpoint of the
+// gateway. Params and callback params are passed to the task as the raw JSON values.
+type GatewayQueueRequest struct {
+	Task      string          `json:"task"`                      // the name of the task to queue
+	Params    json.RawMessage `json:"params,omitempty"`          // the params passed to the task handler
+	Success   json.RawMessage `json:"success,omitempty"`         // the params passed to the success callback
+	Failure   json.RawMessage `json:"failure,omitempty"`         // the params passed to the failure callback
+	Callback  string          `json:"callback,omitempty"`        // the address of a RadishCallback service
+	DryRun    bool            `json:"dry_run,omitempty"`         // check if the future can be queued without queueing it
+	Priority  int32           `json:"priority,omitempty"`        // futures with a higher priority are handled first
+	ETA       int64           `json:"eta,omitempty"`             // queue the future at this time in unix nanoseconds
+	Delay     int64           `json:"delay,omitempty"`           // queue the future after this duration in nanoseconds
+	Key       string          `json:"idempotency_key,omitempty"` // futures of the task with the same key are only queued once
+	Partition string          `json:"partition_key,omitempty"`   // futures with the same key are handled serially in the order they were queued
+}
+
+// GatewayQueueReply is the JSON body of the response from the /v1/queue endpoint.
+type GatewayQueueReply struct {
+	ID      string     `json:"id,omitempty"`      // the id of the future that was queued
+	Success bool       `json:"success"`           // if the future was queued
+	Error   *api.Error `json:"error,omitempty"`   // the error if success is false
+	Outcome string     `json:"outcome,omitempty"` // if the request was a dry run, what would have happened
+}
+
+// GatewayScaleRequest is the JSON body of a request to the /v1/scale endpoint.
+type GatewayScaleRequest struct {
+	Workers int32 `json:"workers"` // set the number of running workers to this number
+}
+
+// GatewayScaleReply is the JSON body of the response from the /v1/scale endpoint.
+type GatewayScaleReply struct {
+	Workers int32      `json:"workers"`         // the total number of workers now operating
+	Success bool       `json:"success"`         // if the workers were scaled
+	Error   *api.Error `json:"error,omitempty"` // the error if success is false
+}
+
+// GatewayStatusReply is the JSON body of the response from the /v1/status endpoint.
+type GatewayStatusReply struct {
+	Workers int32    `json:"workers"` // the total number of workers currently running
+	Queue   uint64   `json:"queue"`   // the number of futures in the queue
+	Tasks   []string `json:"tasks"`   // the names of the registered tasks
+}
+
+// GatewayPingReply is the JSON body of the response from the /v1/ping endpoint.
+type GatewayPingReply struct {
+	Version   string `json:"version"`   // the package version of the radish server
+	Protocol  uint32 `json:"protocol"`  // the protocol version of the radish API
+	Timestamp int64  `json:"timestamp"` // the current time on the server in unix nanoseconds
+}
+
+// Gateway returns an http.Handler that exposes the Queue, Scale, Status, and Ping RPCs as
+// JSON over HTTP at /v1/queue (POST), /v1/scale (POST), /v1/status (GET), and /v1/ping
+// (GET) so that clients
+// that cannot use gRPC, e.g. curl, browsers, or webhooks, can queue tasks. Requests are
+// authenticated with the Authorization header and clients can identify themselves for
+// quotas with the Radish-Client header. Listen serves the gateway on the GatewayAddr if
+// it is configured.
+func (r *Radish) Gateway() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/queue", r.gatewayQueue)
+	mux.HandleFunc("/v1/scale", r.gatewayScale)
+	mux.HandleFunc("/v1/status", r.gatewayStatus)
+	mux.HandleFunc("/v1/ping", r.gatewayPing)
+	return mux
+}
+
+// serveGateway serves the JSON/HTTP gateway until the queue is shutdown.
+func (r *Radish) serveGateway(srv *http.Server) {
+	r.logf(out.LevelStatus, "", "serving the JSON/HTTP gateway at http://%s/v1", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		r.logf(out.LevelWarn, "", "could not serve the JSON/HTTP gateway: %s", err)
+	}
+}
+
+func (r *Radish) gatewayQueue(w http.ResponseWriter, req *http.Request) {
+	ctx, ok := r.gatewayContext(w, req, http.MethodPost)
+	if !ok {
+		return
+	}
+
+	in := &GatewayQueueRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, r.maxRequestSize())).Decode(in); err != nil {
+		gatewayError(w, http.StatusBadRequest, "could not decode queue request: %s", err)
+		return
+	}
+
+	// The idempotency key can also be specified with the conventional HTTP header
+	if in.Key == "" {
+		in.Key = req.Header.Get("Idempotency-Key")
+	}
+
+	rep, err := r.Queue(ctx, &api.QueueRequest{
+		Task:           in.Task,
+		Params:         in.Params,
+		Success:        in.Success,
+		Failure:        in.Failure,
+		Callback:       in.Callback,
+		DryRun:         in.DryRun,
+		Priority:       in.Priority,
+		Eta:            in.ETA,
+		Delay:          in.Delay,
+		IdempotencyKey: in.Key,
+		PartitionKey:   in.Partition,
+	})
+	if err != nil {
+		gatewayError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+
+	reply := &GatewayQueueReply{Success: rep.Success, Error: rep.Error, Outcome: rep.Outcome}
+	if len(rep.Uuid) > 0 {
+		reply.ID = uuid.UUID(rep.Uuid).String()
+	}
+	gatewayReply(w, gatewayStatusCode(rep.Error), reply)
+}
+
+func (r *Radish) gatewayScale(w http.ResponseWriter, req *http.Request) {
+	ctx, ok := r.gatewayContext(w, req, http.MethodPost)
+	if !ok {
+		return
+	}
+
+	in := &GatewayScaleRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, r.maxRequestSize())).Decode(in); err != nil {
+		gatewayError(w, http.StatusBadRequest, "could not decode scale request: %s", err)
+		return
+	}
+
+	rep, err := r.Scale(ctx, &api.ScaleRequest{Workers: in.Workers})
+	if err != nil {
+		gatewayError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	gatewayReply(w, gatewayStatusCode(rep.Error), &GatewayScaleReply{Workers: rep.Workers, Success: rep.Success, Error: rep.Error})
+}
+
+func (r *Radish) gatewayStatus(w http.ResponseWriter, req *http.Request) {
+	ctx, ok := r.gatewayContext(w, req, http.MethodGet)
+	if !ok {
+		return
+	}
+
+	rep, err := r.Status(ctx, &api.StatusRequest{})
+	if err != nil {
+		gatewayError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	gatewayReply(w, http.StatusOK, &GatewayStatusReply{Workers: rep.Workers, Queue: rep.Queue, Tasks: rep.Tasks})
+}
+
+func (r *Radish) gatewayPing(w http.ResponseWriter, req *http.Request) {
+	ctx, ok := r.gatewayContext(w, req, http.MethodGet)
+	if !ok {
+		return
+	}
+
+	rep, err := r.Ping(ctx, &api.PingRequest{})
+	if err != nil {
+		gatewayError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	gatewayReply(w, http.StatusOK, &GatewayPingReply{Version: rep.Version, Protocol: rep.Protocol, Timestamp: rep.Timestamp})
+}
+
+// gatewayContext checks the method of the request and authenticates the client, writing
+// an error response and returning false if the request cannot be handled. The returned
+// context carries the headers as gRPC metadata and the remote address as the peer so
+// that clients are identified in the same way as gRPC clients.
+func (r *Radish) gatewayContext(w http.ResponseWriter, req *http.Request, method string) (context.Context, bool) {
+	if req.Method != method {
+		w.Header().Set("Allow", method)
+		gatewayError(w, http.StatusMethodNotAllowed, "%s requests are not allowed, use %s", req.Method, method)
+		return nil, false
+	}
+
+	md := metadata.MD{}
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		md.Set(AuthMetadataKey, auth)
+	}
+	if client := req.Header.Get(ClientMetadataKey); client != "" {
+		md.Set(ClientMetadataKey, client)
+	}
+	for _, key := range []string{TraceParentKey, TraceStateKey} {
+		if vals := req.Header[http.CanonicalHeaderKey(key)]; len(vals) > 0 {
+			md.Set(key, vals...)
+		}
+	}
+
+	ctx := metadata.NewIncomingContext(req.Context(), md)
+	if addr, err := net.ResolveTCPAddr("tcp", req.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+
+	ctx, err := r.authenticate(ctx)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		gatewayError(w, http.StatusUnauthorized, "request is not authenticated")
+		return nil, false
+	}
+	return ctx, true
+}
+
+// gatewayStatusCode returns the HTTP status code of a reply with the API error.
+func gatewayStatusCode(err *api.Error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	switch err.Code {
+	case CodeTaskNotRegistered, CodeInvalidSchema, CodeInvalidParams, CodeInvalidWorkers, CodeNoWorkers:
+		return http.StatusBadRequest
+	case CodeQuotaExceeded, CodeQueueFull:
+		return http.StatusTooManyRequests
+	case CodeShutdown:
+		return http.StatusServiceUnavailable
+	case CodeCanceled:
+		return http.StatusRequestTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// gatewayReply writes the reply as JSON with the status code.
+func gatewayReply(w http.ResponseWriter, code int, reply interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(reply)
+}
+
+// gatewayError writes an API error as JSON with the status code.
+func gatewayError(w http.ResponseWriter, code int, msg string, a ...interface{}) {
+	gatewayReply(w, code, map[string]interface{}{
+		"success": false,
+		"error":   &api.Error{Code: CodeUnknown, Message: fmt.Sprintf(msg, a...)},
+	})
+}
@@ -0,0 +1,157 @@
+package radish_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGateway(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+
+	var params []byte
+	task := &testTask{wg: wg, name: "echo", onHandle: func(id uuid.UUID, p []byte) error {
+		params = p
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 2, NoSignals: true, AuthToken: "supersecret"}, task)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(queue.Gateway())
+	defer srv.Close()
+
+	do := func(method, path, token string, body interface{}, reply interface{}) int {
+		var data []byte
+		if body != nil {
+			data, err = json.Marshal(body)
+			require.NoError(t, err)
+		}
+
+		req, err := http.NewRequest(method, srv.URL+path, bytes.NewReader(data))
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		rep, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer rep.Body.Close()
+
+		if reply != nil {
+			require.NoError(t, json.NewDecoder(rep.Body).Decode(reply))
+		}
+		return rep.StatusCode
+	}
+
+	// Requests must be authenticated
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/v1/status", "", nil, nil))
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/v1/status", "wrong", nil, nil))
+	require.Equal(t, http.StatusMethodNotAllowed, do(http.MethodPost, "/v1/status", "supersecret", nil, nil))
+
+	status := &GatewayStatusReply{}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/status", "supersecret", nil, status))
+	require.Equal(t, int32(2), status.Workers)
+	require.Equal(t, []string{"echo"}, status.Tasks)
+
+	ping := &GatewayPingReply{}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/ping", "supersecret", nil, ping))
+	require.Equal(t, PackageVersion, ping.Version)
+	require.Equal(t, uint32(ProtocolVersion), ping.Protocol)
+	require.NotZero(t, ping.Timestamp)
+
+	// Queue a task with JSON params
+	queued := &GatewayQueueReply{}
+	req := &GatewayQueueRequest{Task: "echo", Params: json.RawMessage(`{"msg":"hello"}`)}
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/v1/queue", "supersecret", req, queued))
+	require.True(t, queued.Success)
+	require.NotNil(t, uuid.Parse(queued.ID))
+	wg.Wait()
+	require.Equal(t, `{"msg":"hello"}`, string(params))
+
+	// Errors are mapped to HTTP status codes
+	queued = &GatewayQueueReply{}
+	req = &GatewayQueueRequest{Task: "unknown"}
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/v1/queue", "supersecret", req, queued))
+	require.False(t, queued.Success)
+	require.Equal(t, CodeTaskNotRegistered, queued.Error.Code)
+
+	// Scale the workers
+	scaled := &GatewayScaleReply{}
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/v1/scale", "supersecret", &GatewayScaleRequest{Workers: 4}, scaled))
+	require.True(t, scaled.Success)
+	require.Equal(t, int32(4), scaled.Workers)
+	require.Equal(t, 4, queue.NumWorkers())
+}
+
+func TestGatewayMaxBody(t *testing.T) {
+	const maxParamSize = 5 * 1024 * 1024
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+
+	var size int
+	task := &testTask{wg: wg, name: "upload", onHandle: func(id uuid.UUID, p []byte) error {
+		size = len(p)
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent", MaxParamSize: maxParamSize}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	srv := httptest.NewServer(queue.Gateway())
+	defer srv.Close()
+
+	// params returns a JSON string of exactly n bytes
+	params := func(n int) json.RawMessage {
+		return json.RawMessage(`"` + strings.Repeat("a", n-2) + `"`)
+	}
+
+	post := func(body []byte) (int, *GatewayQueueReply) {
+		rep, err := http.Post(srv.URL+"/v1/queue", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer rep.Body.Close()
+
+		reply := &GatewayQueueReply{}
+		require.NoError(t, json.NewDecoder(rep.Body).Decode(reply))
+		return rep.StatusCode, reply
+	}
+
+	// Params of the maximum size are accepted although the body is larger than 4MB
+	body, err := json.Marshal(&GatewayQueueRequest{Task: "upload", Params: params(maxParamSize)})
+	require.NoError(t, err)
+	require.Greater(t, len(body), 4*1024*1024)
+
+	code, reply := post(body)
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, reply.Success)
+	wg.Wait()
+	require.Equal(t, maxParamSize, size)
+
+	// Params one byte larger are rejected by the queue rather than the gateway
+	body, err = json.Marshal(&GatewayQueueRequest{Task: "upload", Params: params(maxParamSize + 1)})
+	require.NoError(t, err)
+
+	code, reply = post(body)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.False(t, reply.Success)
+	require.Equal(t, CodeInvalidParams, reply.Error.Code)
+
+	// Bodies larger than the params and the envelope overhead are not read
+	body, err = json.Marshal(&GatewayQueueRequest{Task: "upload", Params: params(maxParamSize + 128*1024)})
+	require.NoError(t, err)
+
+	code, reply = post(body)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.False(t, reply.Success)
+	require.Contains(t, reply.Error.Message, "could not decode queue request")
+}
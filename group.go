@@ -0,0 +1,227 @@
+package radish
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// Group is a set of futures that are queued together and handled in parallel (fan-out)
+// and whose outcomes are aggregated into a single result once every member has completed
+// (fan-in). The result is passed to the OnComplete function and, if a chord task is
+// specified, to a future of the chord task as its params.
+type Group struct {
+	Members    []FutureSpec           // the futures to queue as the members of the group
+	Chord      string                 // the name of a task to queue with the result once every member completes
+	OnComplete func(*api.GroupResult) // called with the result once every member completes
+}
+
+// DelayGroup queues the members of the group with the options on the context as with
+// DelayContext, returning the id of the group and the ids of its members in the order of
+// the specs. Once every member has completed, successfully or not, the OnComplete
+// function of the group is called and a future of the chord task is queued with the
+// protocol buffer serialized api.GroupResult as its params, which aggregates the state,
+// error, and result of every member. If a member cannot be queued the first error is
+// returned but the group is still completed, counting the member as failed.
+func (r *Radish) DelayGroup(ctx context.Context, group *Group) (id uuid.UUID, members []uuid.UUID, err error) {
+	if len(group.Members) == 0 {
+		return nil, nil, Errorf(CodeInvalidParams, "group has no members")
+	}
+
+	if group.Chord != "" {
+		if _, err = r.Handler(group.Chord); err != nil {
+			return nil, nil, Errorf(CodeTaskNotRegistered, "could not delay group chord %s", err)
+		}
+	}
+
+	run := r.groups.add(ctx, r.config.IDs.NewID(), group)
+	members = make([]uuid.UUID, len(group.Members))
+	for i, spec := range group.Members {
+		mctx := context.WithValue(ctx, groupKey, groupRef{id: run.id, member: i})
+		if spec.Key != "" {
+			mctx = WithIdempotencyKey(mctx, spec.Key)
+		}
+
+		var merr error
+		if members[i], merr = r.DelayContext(mctx, spec.Task, spec.Params, spec.Success, spec.Failure); merr != nil {
+			if err == nil {
+				err = merr
+			}
+		}
+		r.groups.queued(run, i, members[i], merr)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+	return run.id, members, nil
+}
+
+// groupRef identifies the member of a group that a future is.
+type groupRef struct {
+	id     uuid.UUID
+	member int
+}
+
+// groupFrom returns the group member on the context, if any.
+func groupFrom(ctx context.Context) groupRef {
+	ref, _ := ctx.Value(groupKey).(groupRef)
+	return ref
+}
+
+// groupRun is the state of a group whose members have not all completed.
+type groupRun struct {
+	id        uuid.UUID
+	group     *Group
+	result    *api.GroupResult  // the outcome of every member, aggregated as they complete
+	remaining int               // the number of members that have not completed
+	metadata  map[string]string // metadata copied onto the future of the chord
+	priority  int32             // the priority of the future of the chord
+	client    string            // the API client that delayed the group, if any
+}
+
+// groups tracks the groups whose members have not all completed by group id.
+type groups struct {
+	sync.Mutex
+	parent *Radish
+	runs   map[string]*groupRun
+}
+
+func newGroups(r *Radish) *groups {
+	return &groups{parent: r, runs: make(map[string]*groupRun)}
+}
+
+// add a group that is being delayed with the options on the context.
+func (g *groups) add(ctx context.Context, id uuid.UUID, group *Group) *groupRun {
+	run := &groupRun{
+		id:        id,
+		group:     group,
+		result:    &api.GroupResult{Uuid: id, Members: make([]*api.GroupMember, len(group.Members))},
+		remaining: len(group.Members),
+		metadata:  MetadataFrom(ctx),
+		priority:  PriorityFrom(ctx),
+		client:    clientFrom(ctx),
+	}
+
+	for i, spec := range group.Members {
+		run.result.Members[i] = &api.GroupMember{Task: spec.Task, State: api.FutureState_QUEUED}
+	}
+
+	g.Lock()
+	g.runs[id.String()] = run
+	g.Unlock()
+	return run
+}
+
+// queued records the id of the future of the member once it has been delayed, failing
+// the member if it could not be queued.
+func (g *groups) queued(run *groupRun, i int, id uuid.UUID, err error) {
+	g.Lock()
+	if err != nil {
+		g.record(run, i, nil, api.FutureState_FAILED, err, nil)
+		g.finish(run)
+		return
+	}
+
+	// The result belongs to the completion function once the group has completed
+	if _, ok := g.runs[run.id.String()]; ok {
+		run.result.Members[i].Uuid = id
+	}
+	g.Unlock()
+}
+
+// completed is called when the future of a member completes, completing the group once
+// every member has completed.
+func (g *groups) completed(future *Future, err error) {
+	g.Lock()
+	run, ok := g.runs[future.Group.String()]
+	if !ok {
+		g.Unlock()
+		return
+	}
+
+	state := api.FutureState_SUCCEEDED
+	switch {
+	case future.canceled:
+		state = api.FutureState_CANCELED
+	case err != nil:
+		state = api.FutureState_FAILED
+	}
+
+	if future.Member < 0 || future.Member >= len(run.result.Members) {
+		g.Unlock()
+		return
+	}
+
+	g.record(run, future.Member, future.ID, state, err, future.result)
+	g.finish(run)
+}
+
+// record the outcome of the member of the group. Must hold the lock.
+func (g *groups) record(run *groupRun, i int, id uuid.UUID, state api.FutureState, err error, result []byte) {
+	member := run.result.Members[i]
+	if member.State != api.FutureState_QUEUED {
+		return
+	}
+
+	member.State, member.Result = state, result
+	if id != nil {
+		member.Uuid = id
+	}
+	switch state {
+	case api.FutureState_SUCCEEDED:
+		run.result.Succeeded++
+	case api.FutureState_CANCELED:
+		run.result.Canceled++
+	default:
+		run.result.Failed++
+	}
+
+	if err != nil {
+		var ok bool
+		if member.Error, ok = apiError(err); !ok {
+			member.Error = &api.Error{Code: CodeUnknown, Message: err.Error()}
+		}
+	}
+	run.remaining--
+}
+
+// finish the group if every member has completed, releasing the lock before calling the
+// completion function and queueing the chord. Must hold the lock.
+func (g *groups) finish(run *groupRun) {
+	if run.remaining > 0 {
+		g.Unlock()
+		return
+	}
+
+	delete(g.runs, run.id.String())
+	g.Unlock()
+
+	g.parent.logf(out.LevelDebug, "", "group %s completed: %d succeeded, %d failed, %d canceled", run.id, run.result.Succeeded, run.result.Failed, run.result.Canceled)
+	if run.group.OnComplete != nil {
+		run.group.OnComplete(run.result)
+	}
+
+	if run.group.Chord != "" {
+		go g.parent.queueChord(run)
+	}
+}
+
+// queueChord queues a future of the chord task of the group with the group result.
+func (r *Radish) queueChord(run *groupRun) {
+	params, err := proto.Marshal(run.result)
+	if err != nil {
+		r.logf(out.LevelWarn, run.group.Chord, "could not marshal the result of group %s: %s", run.id, err)
+		return
+	}
+
+	ctx := context.WithValue(context.Background(), metadataKey, run.metadata)
+	ctx = WithPriority(withClient(ctx, run.client), run.priority)
+	if _, err = r.DelayContext(ctx, run.group.Chord, params, nil, nil); err != nil {
+		r.logf(out.LevelWarn, run.group.Chord, "could not queue the chord of group %s: %s", run.id, err)
+	}
+}
@@ -0,0 +1,92 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	good := &testTask{wg: wg, name: "good"}
+	bad := &testTask{wg: wg, name: "bad", onHandle: func(id uuid.UUID, params []byte) error { return errors.New("whoops!") }}
+
+	chords := make(chan *api.GroupResult, 1)
+	chord := &testTask{wg: wg, name: "chord", onHandle: func(id uuid.UUID, params []byte) error {
+		result := new(api.GroupResult)
+		if err := proto.Unmarshal(params, result); err != nil {
+			return err
+		}
+		chords <- result
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 4, NoSignals: true, LogLevel: "warn"}, good, bad, chord)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Invalid groups are not delayed
+	ctx := context.Background()
+	_, _, err = queue.DelayGroup(ctx, &Group{})
+	require.EqualError(t, err, "[9] group has no members")
+
+	_, _, err = queue.DelayGroup(ctx, &Group{Members: []FutureSpec{{Task: "good"}}, Chord: "unknown"})
+	require.EqualError(t, err, "[3] could not delay group chord [3] unknown task \"unknown\"")
+
+	// The outcomes of the members are aggregated once every member has completed
+	results := make(chan *api.GroupResult, 1)
+	group := &Group{
+		Members: []FutureSpec{
+			{Task: "good", Params: []byte("a")},
+			{Task: "bad", Params: []byte("b")},
+			{Task: "good", Params: []byte("c")},
+		},
+		Chord:      "chord",
+		OnComplete: func(result *api.GroupResult) { results <- result },
+	}
+
+	wg.Add(4)
+	id, members, err := queue.DelayGroup(ctx, group)
+	require.NoError(t, err)
+	require.Len(t, members, 3)
+
+	var result *api.GroupResult
+	select {
+	case result = <-results:
+	case <-time.After(time.Second):
+		t.Fatal("group did not complete")
+	}
+
+	require.True(t, uuid.Equal(id, result.Uuid))
+	require.Equal(t, uint32(2), result.Succeeded)
+	require.Equal(t, uint32(1), result.Failed)
+	require.Zero(t, result.Canceled)
+	require.Len(t, result.Members, 3)
+	for i, member := range result.Members {
+		require.True(t, uuid.Equal(members[i], member.Uuid))
+		require.Equal(t, group.Members[i].Task, member.Task)
+	}
+	require.Equal(t, api.FutureState_SUCCEEDED, result.Members[0].State)
+	require.Equal(t, api.FutureState_FAILED, result.Members[1].State)
+	require.Equal(t, "whoops!", result.Members[1].Error.Message)
+	require.Equal(t, api.FutureState_SUCCEEDED, result.Members[2].State)
+
+	// The chord is queued with the result as its params
+	select {
+	case result = <-chords:
+	case <-time.After(time.Second):
+		t.Fatal("chord was not handled")
+	}
+	require.True(t, uuid.Equal(id, result.Uuid))
+	require.Equal(t, uint32(2), result.Succeeded)
+	require.Equal(t, uint32(1), result.Failed)
+	wg.Wait()
+}
@@ -0,0 +1,93 @@
+package radish
+
+import (
+	"context"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"google.golang.org/grpc"
+)
+
+// Default amount of time to wait for a handoff triggered by a signal to complete.
+const defaultHandoffTimeout = 30 * time.Second
+
+// Handoff enables zero-downtime restarts. When the queue is listening with ReusePort
+// enabled in the config, a new radish process can bind to the same address as the old
+// process. Handoff then stops accepting new tasks and stops the old server so that all
+// new requests are routed to the new process, and re-queues any futures that are still
+// pending in the old queue to the new process at the specified address. Tasks in flight
+// continue to be handled by the workers of the old process, which can then be shutdown.
+// Note that handed off futures are assigned new ids by the new process. Handoff returns
+// the number of futures that were handed off; futures that the new process does not
+// accept are put back on the queue of the old process so that they are not lost.
+func (r *Radish) Handoff(ctx context.Context, addr string) (n int, err error) {
+	// Stop accepting new tasks and stop the server before connecting to the new process
+	// so that the connection is not routed back to this process by the kernel.
+	r.Lock()
+	r.draining = true
+	srv := r.srv
+	r.Unlock()
+
+	if srv != nil {
+		srv.GracefulStop()
+	}
+
+	var conn *grpc.ClientConn
+	if conn, err = grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock()); err != nil {
+		return 0, Errorf(CodeBadGateway, "could not connect to %s for handoff: %s", addr, err)
+	}
+	defer conn.Close()
+	client := api.NewRadishClient(conn)
+
+	// Dequeue with a done context so that only the pending futures are handed off
+	pending, cancel := context.WithCancel(ctx)
+	cancel()
+
+	rejected := make([]*Future, 0)
+	defer func() {
+		for _, future := range rejected {
+			r.keep(future)
+		}
+	}()
+
+	r.logf(out.LevelStatus, "", "handing off %d pending futures to %s", r.tasks.Len(), addr)
+	for {
+		future, err := r.tasks.Dequeue(pending)
+		if err != nil {
+			r.logf(out.LevelStatus, "", "handed off %d futures to %s", n, addr)
+			if len(rejected) > 0 {
+				r.logf(out.LevelWarn, "", "%d futures were not accepted by %s and were kept", len(rejected), addr)
+			}
+			return n, nil
+		}
+		r.releaseQuotas(future)
+
+		var ok bool
+		if ok, err = r.sendFuture(ctx, addr, client, future); err != nil {
+			rejected = append(rejected, future)
+			return n, Errorf(CodeBadGateway, "could not hand off future %s: %s", future.ID, err)
+		}
+
+		if ok {
+			n++
+		} else {
+			rejected = append(rejected, future)
+		}
+	}
+}
+
+// handoff to the new process listening on the same address, then drain the futures that
+// were not handed off and shutdown.
+func (r *Radish) onHandoffSignal() {
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.HandoffTimeout)
+	defer cancel()
+
+	if _, err := r.Handoff(ctx, r.config.Addr); err != nil {
+		r.logf(out.LevelWarn, "", "handoff failed, draining the queue instead: %s", err)
+	}
+
+	// Handle the futures that were not handed off before shutting down
+	r.drain()
+	r.onShutdownSignal()
+}
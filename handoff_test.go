@@ -0,0 +1,75 @@
+package radish_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandoff(t *testing.T) {
+	// The new process only accepts futures with small params
+	var (
+		mu     sync.Mutex
+		remote []string
+		local  []string
+	)
+
+	wg := new(sync.WaitGroup)
+	next, err := New(&Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent", MaxParamSize: 1}, &testTask{wg: wg, name: "work", onHandle: func(id uuid.UUID, params []byte) error {
+		mu.Lock()
+		remote = append(remote, string(params))
+		mu.Unlock()
+		return nil
+	}})
+	require.NoError(t, err)
+	defer next.Shutdown()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	queue, err := New(&Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent"}, &testTask{wg: wg, name: "work", onHandle: func(id uuid.UUID, params []byte) error {
+		if string(params) == "block" {
+			started <- struct{}{}
+			<-release
+		}
+
+		mu.Lock()
+		local = append(local, string(params))
+		mu.Unlock()
+		return nil
+	}})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go next.Serve(sock)
+
+	// The only worker of the old process is busy while the pending futures are handed off
+	wg.Add(5)
+	_, err = queue.Delay("work", []byte("block"), nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	for _, params := range []string{"a", "rejected", "b", "too large"} {
+		_, err = queue.Delay("work", []byte(params), nil, nil)
+		require.NoError(t, err)
+	}
+
+	n, err := queue.Handoff(context.Background(), sock.Addr().String())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	// The futures the new process rejected are kept and handled by the old process
+	close(release)
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.ElementsMatch(t, []string{"a", "b"}, remote)
+	require.ElementsMatch(t, []string{"block", "rejected", "too large"}, local)
+}
@@ -0,0 +1,50 @@
+package radish
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Healthz is an http.HandlerFunc for liveness probes, e.g. from Kubernetes, that is served
+// on the metrics address by Listen. The queue is live until it has been shutdown; if a
+// LivenessTimeout is configured, the queue is also not live if futures are waiting in
+// the queue but no worker has dequeued a future within the timeout, i.e. if the workers
+// are wedged and restarting the process would allow the queue to make progress.
+func (r *Radish) Healthz(w http.ResponseWriter, req *http.Request) {
+	select {
+	case <-r.stopped:
+		probe(w, http.StatusServiceUnavailable, "queue has been shutdown")
+		return
+	default:
+	}
+
+	if r.config.LivenessTimeout > 0 && !r.alive(r.config.LivenessTimeout) {
+		probe(w, http.StatusServiceUnavailable, "workers have not handled any tasks in %s", r.config.LivenessTimeout)
+		return
+	}
+	probe(w, http.StatusOK, "ok")
+}
+
+// Readyz is an http.HandlerFunc for readiness probes, e.g. from Kubernetes, that is served
+// on the metrics address by Listen. The queue is only ready to receive requests if it
+// has at least one worker and is accepting new tasks, e.g. it is not draining or being
+// shutdown.
+func (r *Radish) Readyz(w http.ResponseWriter, req *http.Request) {
+	if err := r.accepting(); err != nil {
+		probe(w, http.StatusServiceUnavailable, "%s", err)
+		return
+	}
+
+	if r.NumWorkers() == 0 {
+		probe(w, http.StatusServiceUnavailable, "no workers are running")
+		return
+	}
+	probe(w, http.StatusOK, "ok")
+}
+
+// probe writes the status of a health probe as plain text.
+func probe(w http.ResponseWriter, code int, msg string, a ...interface{}) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	fmt.Fprintf(w, msg+"\n", a...)
+}
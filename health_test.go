@@ -0,0 +1,93 @@
+package radish_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthProbes(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	task := &testTask{wg: wg, name: "block", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LivenessTimeout: 50 * time.Millisecond}, task)
+	require.NoError(t, err)
+
+	probe := func(handler http.HandlerFunc) int {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, probe(queue.Healthz))
+	require.Equal(t, http.StatusOK, probe(queue.Readyz))
+
+	// The queue is not live if futures are waiting but the workers are wedged
+	for i := 0; i < 2; i++ {
+		_, err = queue.Delay(task.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+	<-started
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, http.StatusServiceUnavailable, probe(queue.Healthz))
+	require.Equal(t, http.StatusOK, probe(queue.Readyz))
+
+	close(release)
+	<-started
+	wg.Wait()
+	require.Equal(t, http.StatusOK, probe(queue.Healthz))
+
+	// The queue is not ready without workers
+	require.NoError(t, queue.SetWorkers(0))
+	require.Equal(t, http.StatusServiceUnavailable, probe(queue.Readyz))
+	require.NoError(t, queue.SetWorkers(2))
+	require.Equal(t, http.StatusOK, probe(queue.Readyz))
+
+	// The queue is neither live nor ready once it has been shutdown
+	require.NoError(t, queue.Shutdown())
+	require.Equal(t, http.StatusServiceUnavailable, probe(queue.Healthz))
+	require.Equal(t, http.StatusServiceUnavailable, probe(queue.Readyz))
+}
+
+func TestPublishVars(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "task"}
+
+	queue, err := New(&Config{Workers: 2, QueueSize: 100, NoSignals: true}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		_, err = queue.Delay(task.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+	require.Eventually(t, func() bool { return queue.BusyWorkers() == 0 }, time.Second, 10*time.Millisecond)
+
+	queue.PublishVars()
+	rec := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	vars := make(map[string]json.RawMessage)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vars))
+	require.Contains(t, vars, "radish")
+
+	expected := `{"queue_size":0,"queue_capacity":100,"workers":2,"busy_workers":0,"paused":false,"draining":false,"tasks":{"task":{"pending":0,"queued":3,"succeeded":3,"failed":0,"canceled":0}}}`
+	require.JSONEq(t, expected, string(vars["radish"]))
+}
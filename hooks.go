@@ -0,0 +1,88 @@
+package radish
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies the lifecycle event passed to the hooks registered with OnEvent.
+type EventType uint8
+
+// Lifecycle events of futures and workers.
+const (
+	EventEnqueue EventType = iota + 1 // a future was added to the queue, including when it is retried or becomes due
+	EventDequeue                      // a worker dequeued a future
+	EventStart                        // a worker started handling a future after acquiring its slots and resources
+	EventSuccess                      // a future completed successfully
+	EventFailure                      // a future failed permanently, was canceled, or was dropped
+	EventScale                        // the number of workers changed
+)
+
+var eventTypeNames = map[EventType]string{
+	EventEnqueue: "enqueue",
+	EventDequeue: "dequeue",
+	EventStart:   "start",
+	EventSuccess: "success",
+	EventFailure: "failure",
+	EventScale:   "scale",
+}
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Event describes a lifecycle event of a future or of the workers of the queue.
+type Event struct {
+	Type     EventType // the type of the event
+	Time     time.Time // when the event occurred
+	Future   *Future   // the future of the event, nil for scale events; must not be modified
+	Err      error     // the error that caused the future to fail for failure events
+	Workers  int       // the number of workers after a scale event
+	Previous int       // the number of workers before a scale event
+}
+
+// OnEvent registers a hook that is called with the lifecycle events of every future as it
+// is enqueued, dequeued, started, and completed, and whenever the number of workers
+// changes, so that applications can implement their own auditing, metrics, or persistence.
+// Hooks are called synchronously in the order they were registered from the go routine
+// that caused the event, e.g. the worker handling the future, so they must be thread safe
+// and should return quickly; hand events off to another go routine for slow work.
+func (r *Radish) OnEvent(hook func(Event)) {
+	r.hooks.Lock()
+	r.hooks.fns = append(r.hooks.fns, hook)
+	r.hooks.Unlock()
+}
+
+// eventHooks are the hooks registered with OnEvent.
+type eventHooks struct {
+	sync.RWMutex
+	fns []func(Event)
+}
+
+// emit the event to the registered hooks. The hooks are called without holding the lock
+// so that hooks may register other hooks.
+func (h *eventHooks) emit(event Event) {
+	h.RLock()
+	fns := h.fns
+	h.RUnlock()
+
+	if len(fns) == 0 {
+		return
+	}
+
+	event.Time = time.Now()
+	for _, fn := range fns {
+		fn(event)
+	}
+}
+
+// emitScale emits a scale event if the number of workers changed.
+func (h *eventHooks) emitScale(previous, workers int) {
+	if previous != workers {
+		h.emit(Event{Type: EventScale, Workers: workers, Previous: previous})
+	}
+}
@@ -0,0 +1,75 @@
+package radish_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHooks(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	good := &testTask{wg: wg, name: "good"}
+	bad := &testTask{wg: wg, name: "bad", onHandle: func(id uuid.UUID, params []byte) error { return errors.New("whoops!") }}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn"}, good, bad)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	var mu sync.Mutex
+	events := make(map[string][]EventType)
+	failures := make(map[string]error)
+	scales := make([][2]int, 0)
+	untimed := 0
+
+	queue.OnEvent(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if e.Time.IsZero() {
+			untimed++
+		}
+
+		if e.Type == EventScale {
+			scales = append(scales, [2]int{e.Previous, e.Workers})
+			return
+		}
+
+		events[e.Future.ID.String()] = append(events[e.Future.ID.String()], e.Type)
+		if e.Type == EventFailure {
+			failures[e.Future.ID.String()] = e.Err
+		}
+	})
+
+	wg.Add(2)
+	gid, err := queue.Delay(good.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	bid, err := queue.Delay(bad.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	// Completion events are emitted after the callbacks of the task are called
+	completed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events[gid.String()]) == 4 && len(events[bid.String()]) == 4
+	}
+	require.Eventually(t, completed, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, queue.AddWorkers(2))
+	require.NoError(t, queue.SetWorkers(2))
+	require.NoError(t, queue.SetWorkers(2))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []EventType{EventEnqueue, EventDequeue, EventStart, EventSuccess}, events[gid.String()])
+	require.Equal(t, []EventType{EventEnqueue, EventDequeue, EventStart, EventFailure}, events[bid.String()])
+	require.EqualError(t, failures[bid.String()], "whoops!")
+	require.Equal(t, [][2]int{{1, 3}, {3, 2}}, scales)
+	require.Zero(t, untimed)
+	require.Equal(t, "enqueue", EventEnqueue.String())
+	require.Equal(t, "scale", EventScale.String())
+}
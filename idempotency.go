@@ -0,0 +1,101 @@
+package radish
+
+import (
+	"context"
+
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// WithIdempotencyKey returns a copy of the parent context with the specified idempotency
+// key. When a task is delayed using DelayContext, the id of the existing future is
+// returned instead of queueing a duplicate if a future of the same task was delayed with
+// the same key and is still scheduled, queued, or in flight, or if it completed recently
+// enough that its record has not yet been evicted after the ResultTTL. Producers that
+// retry requests can use the key to ensure that a task is only queued once.
+func WithIdempotencyKey(parent context.Context, key string) context.Context {
+	return context.WithValue(parent, idempotencyKey, key)
+}
+
+// IdempotencyKeyFrom returns the idempotency key on the context, or an empty string if
+// the context does not have an idempotency key.
+func IdempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey).(string)
+	return key
+}
+
+// claim the idempotency key of the future before it is queued or scheduled, returning
+// the id of the existing future with the same key if there is one. Otherwise the key is
+// claimed by the future until its record is evicted; the returned release function must
+// be called with the id returned to the caller so that the key is released if the future
+// could not be queued.
+func (r *Radish) claim(future *Future) (existing uuid.UUID, release func(id uuid.UUID)) {
+	if future.IdempotencyKey == "" {
+		return nil, func(uuid.UUID) {}
+	}
+
+	if existing = r.results.claim(future); existing != nil {
+		r.logf(out.LevelDebug, future.Task, "%s future %s has the same idempotency key, not queueing a duplicate", future.Task, existing)
+		return existing, nil
+	}
+
+	return nil, func(id uuid.UUID) {
+		if !uuid.Equal(id, future.ID) {
+			r.results.unclaim(future)
+		}
+	}
+}
+
+// claim the idempotency key of the future, returning the id of the future that has
+// already claimed the key, if any.
+func (s *records) claim(future *Future) uuid.UUID {
+	s.Lock()
+	defer s.Unlock()
+
+	key := claimKey(future.Task, future.IdempotencyKey)
+	if id, ok := s.keys[key]; ok {
+		return uuid.Parse(id)
+	}
+	s.keys[key] = future.ID.String()
+	return nil
+}
+
+// unclaim releases the idempotency key of a future that was not queued unless it has
+// been claimed by another future.
+func (s *records) unclaim(future *Future) {
+	s.Lock()
+	defer s.Unlock()
+
+	key := claimKey(future.Task, future.IdempotencyKey)
+	if id, ok := s.keys[key]; ok && id == future.ID.String() {
+		delete(s.keys, key)
+	}
+}
+
+// claimed returns the id of the future that has claimed the idempotency key of the task.
+func (s *records) claimed(task, key string) uuid.UUID {
+	s.RLock()
+	defer s.RUnlock()
+
+	if id, ok := s.keys[claimKey(task, key)]; ok {
+		return uuid.Parse(id)
+	}
+	return nil
+}
+
+// evict the record of the future with the specified id, releasing its idempotency key.
+// Must hold the lock.
+func (s *records) evict(id string) {
+	if rec, ok := s.entries[id]; ok && rec.key != "" {
+		key := claimKey(rec.task, rec.key)
+		if s.keys[key] == id {
+			delete(s.keys, key)
+		}
+	}
+	delete(s.entries, id)
+}
+
+// claimKey scopes idempotency keys by task so that different tasks can use the same keys.
+func claimKey(task, key string) string {
+	return task + "\x00" + key
+}
@@ -0,0 +1,99 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	blocking := &testTask{wg: wg, name: "blocking", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+	task := &testTask{wg: wg, name: "task"}
+
+	conf := &Config{Workers: 1, NoSignals: true, LogLevel: "warn", TaskQuotas: map[string]int{"task": 2}, ResultTTL: 50 * time.Millisecond, SweepInterval: 10 * time.Millisecond}
+	queue, err := New(conf, blocking, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Block the worker so that the futures of the task remain in the queue
+	wg.Add(1)
+	_, err = queue.Delay(blocking.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	ctx := WithIdempotencyKey(context.Background(), "order-42")
+	require.Equal(t, "order-42", IdempotencyKeyFrom(ctx))
+
+	wg.Add(1)
+	id, err := queue.DelayContext(ctx, task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+
+	// Delaying the task again with the same key returns the existing future
+	dup, err := queue.DelayContext(ctx, task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	require.True(t, uuid.Equal(id, dup))
+
+	rep, err := queue.Queue(context.Background(), &api.QueueRequest{Task: task.Name(), IdempotencyKey: "order-42"})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	require.True(t, uuid.Equal(id, uuid.UUID(rep.Uuid)))
+
+	rep, err = queue.Queue(context.Background(), &api.QueueRequest{Task: task.Name(), IdempotencyKey: "order-42", Delay: int64(time.Minute)})
+	require.NoError(t, err)
+	require.True(t, uuid.Equal(id, uuid.UUID(rep.Uuid)))
+
+	outcome, err := queue.DryRun(ctx, task.Name(), nil)
+	require.NoError(t, err)
+	require.Equal(t, "would return the existing task future "+id.String()+" with the same idempotency key", outcome)
+
+	// Keys are scoped by task
+	wg.Add(1)
+	other, err := queue.DelayContext(ctx, blocking.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	require.False(t, uuid.Equal(id, other))
+
+	// The key is not claimed by a future that could not be queued
+	wg.Add(1)
+	_, err = queue.Delay(task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+
+	fullctx := WithIdempotencyKey(context.Background(), "order-43")
+	_, err = queue.DelayContext(fullctx, task.Name(), nil, nil, nil)
+	require.EqualError(t, err, "[11] task \"task\" has reached its quota of 2 queued futures")
+
+	// The key of a completed future is claimed until its record is evicted
+	close(release)
+	wg.Wait()
+
+	dup, err = queue.DelayContext(ctx, task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	require.True(t, uuid.Equal(id, dup))
+
+	wg.Add(2)
+	require.Eventually(t, func() bool {
+		rep, err := queue.GetFuture(context.Background(), &api.GetFutureRequest{Uuid: id})
+		return err == nil && !rep.Success
+	}, time.Second, 10*time.Millisecond)
+
+	next, err := queue.DelayContext(ctx, task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	require.False(t, uuid.Equal(id, next))
+
+	_, err = queue.DelayContext(fullctx, task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+}
@@ -0,0 +1,118 @@
+package radish
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// IDGenerator creates the IDs of new futures. Future IDs are always 128 bits so that
+// they can be used wherever a UUID is expected, but the generator determines their
+// format; sortable IDs (e.g. UUIDv7 or ULID) simplify storing futures downstream since
+// they are ordered by the time they were delayed. Generators must be thread safe.
+type IDGenerator interface {
+	NewID() uuid.UUID
+}
+
+// IDGeneratorFunc allows ordinary functions to be used as ID generators.
+type IDGeneratorFunc func() uuid.UUID
+
+// NewID calls f().
+func (f IDGeneratorFunc) NewID() uuid.UUID {
+	return f()
+}
+
+// RandomIDs generates random (version 4) UUIDs, the default ID format.
+var RandomIDs IDGenerator = IDGeneratorFunc(uuid.NewRandom)
+
+// UUIDv7 generates version 7 UUIDs, which begin with a 48 bit unix timestamp in
+// milliseconds followed by random bits, so they sort in the order they were created.
+var UUIDv7 IDGenerator = IDGeneratorFunc(func() uuid.UUID {
+	id := timestampID(time.Now())
+	id[6] = (id[6] & 0x0f) | 0x70 // version 7
+	id[8] = (id[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return id
+})
+
+// ULIDs generates IDs with the ULID layout: a 48 bit unix timestamp in milliseconds
+// followed by 80 random bits. The IDs are rendered as UUIDs rather than in Crockford's
+// base32 but can be converted to their canonical ULID string representation.
+var ULIDs IDGenerator = IDGeneratorFunc(func() uuid.UUID {
+	return timestampID(time.Now())
+})
+
+// timestampID returns 16 bytes beginning with the big endian unix timestamp in
+// milliseconds followed by random bits.
+func timestampID(ts time.Time) uuid.UUID {
+	id := make(uuid.UUID, 16)
+	if _, err := rand.Read(id[6:]); err != nil {
+		panic(err)
+	}
+
+	ms := uint64(ts.UnixNano() / int64(time.Millisecond))
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+	return id
+}
+
+// Snowflake ID layout, using the Twitter epoch.
+const (
+	snowflakeEpoch    = int64(1288834974657)
+	snowflakeNodeBits = 10
+	snowflakeSeqBits  = 12
+	snowflakeMaxNode  = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSeq   = 1<<snowflakeSeqBits - 1
+)
+
+// Snowflake generates 64 bit snowflake IDs: a 41 bit millisecond timestamp, a 10 bit
+// node ID, and a 12 bit sequence number. Snowflake IDs are unique across nodes as long
+// as each node is assigned a different node ID between 0 and 1023. The snowflake is
+// stored big endian in the first 8 bytes of the future ID and the remaining bytes are 0.
+type Snowflake struct {
+	sync.Mutex
+	node int64
+	last int64
+	seq  int64
+}
+
+// NewSnowflake creates a snowflake ID generator for the specified node.
+func NewSnowflake(node int64) (*Snowflake, error) {
+	if node < 0 || node > snowflakeMaxNode {
+		return nil, Errorf(CodeInvalidConfig, "snowflake node id must be between 0 and %d", snowflakeMaxNode)
+	}
+	return &Snowflake{node: node}, nil
+}
+
+// NewID returns the next snowflake ID, waiting for the next millisecond if more than
+// 4096 IDs have been generated in the current millisecond.
+func (s *Snowflake) NewID() uuid.UUID {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if now <= s.last {
+		// Use the last timestamp if the clock has moved backwards
+		now = s.last
+		if s.seq = (s.seq + 1) & snowflakeMaxSeq; s.seq == 0 {
+			for now <= s.last {
+				time.Sleep(100 * time.Microsecond)
+				now = time.Now().UnixNano() / int64(time.Millisecond)
+			}
+		}
+	} else {
+		s.seq = 0
+	}
+	s.last = now
+
+	id := make(uuid.UUID, 16)
+	flake := (now-snowflakeEpoch)<<(snowflakeNodeBits+snowflakeSeqBits) | s.node<<snowflakeSeqBits | s.seq
+	binary.BigEndian.PutUint64(id[:8], uint64(flake))
+	return id
+}
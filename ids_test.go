@@ -0,0 +1,62 @@
+package radish_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDGenerators(t *testing.T) {
+	id := RandomIDs.NewID()
+	require.Len(t, id, 16)
+	version, _ := id.Version()
+	require.Equal(t, uuid.Version(4), version)
+
+	// Time ordered ids sort in the order they were generated
+	for _, ids := range []IDGenerator{UUIDv7, ULIDs} {
+		prev := ids.NewID()
+		require.Len(t, prev, 16)
+		time.Sleep(2 * time.Millisecond)
+		next := ids.NewID()
+		require.Equal(t, -1, bytes.Compare(prev, next))
+	}
+	version, _ = UUIDv7.NewID().Version()
+	require.Equal(t, uuid.Version(7), version)
+
+	_, err := NewSnowflake(1024)
+	require.EqualError(t, err, "[1] snowflake node id must be between 0 and 1023")
+
+	flakes, err := NewSnowflake(42)
+	require.NoError(t, err)
+
+	var prev uint64
+	seen := make(map[uint64]struct{})
+	for i := 0; i < 10000; i++ {
+		id := flakes.NewID()
+		flake := binary.BigEndian.Uint64(id[:8])
+		require.True(t, flake > prev)
+		require.Equal(t, uint64(42), (flake>>12)&0x3ff)
+		seen[flake] = struct{}{}
+		prev = flake
+	}
+	require.Len(t, seen, 10000)
+
+	// The queue uses the configured generator for new futures
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	queue, err := New(&Config{Workers: 1, NoSignals: true, IDs: IDGeneratorFunc(func() uuid.UUID {
+		return uuid.Parse("7c8a3b0e-3a4f-4d9e-8e6a-1f2b3c4d5e6f")
+	})}, &testTask{name: "ids", wg: wg})
+	require.NoError(t, err)
+
+	id, err = queue.Delay("ids", nil, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "7c8a3b0e-3a4f-4d9e-8e6a-1f2b3c4d5e6f", id.String())
+	wg.Wait()
+}
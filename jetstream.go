@@ -0,0 +1,122 @@
+package radish
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// JetStream publishes futures to a NATS JetStream stream and fetches them from a durable
+// pull consumer of the stream. Radish does not depend on a NATS client; implement the
+// interface by wrapping the JetStream context and pull subscription of the nats.go client.
+type JetStream interface {
+	// Publish the data to the subject of the stream, returning once it is stored.
+	Publish(ctx context.Context, data []byte) error
+
+	// Fetch blocks until the consumer delivers the next message or the context is done,
+	// in which case the context error is returned.
+	Fetch(ctx context.Context) (JetStreamMessage, error)
+
+	// Pending returns the number of messages that have not been delivered to the consumer.
+	Pending() (int, error)
+}
+
+// JetStreamMessage is a message delivered by a JetStream consumer that must be
+// acknowledged before the AckWait of the consumer or it is redelivered.
+type JetStreamMessage interface {
+	Data() []byte         // the payload of the message
+	NumDelivered() uint64 // the number of times the message has been delivered
+	Ack() error           // acknowledge the message so that it is not redelivered
+	Term() error          // acknowledge the message as unprocessable so that it is not redelivered
+}
+
+// NewJetStreamBroker returns a broker that publishes futures to a JetStream stream and
+// dequeues them from a durable pull consumer that is shared by every radish process, giving
+// durable, multi-node distribution of futures. A future is acknowledged once it has been
+// handled, so futures whose process stops before handling them are redelivered to another
+// process by JetStream; the AckWait of the consumer should be longer than the longest time
+// a future may wait for a worker and be handled. Futures are handled in the order they are
+// published regardless of their priority.
+func NewJetStreamBroker(js JetStream) Broker {
+	return &jetStreamBroker{js: js, pending: make(map[string]JetStreamMessage)}
+}
+
+type jetStreamBroker struct {
+	sync.Mutex
+	js      JetStream
+	pending map[string]JetStreamMessage // messages that have been dequeued but not acknowledged
+}
+
+func (b *jetStreamBroker) Enqueue(ctx context.Context, future *Future) (err error) {
+	var data []byte
+	if data, err = json.Marshal(&storedFuture{Future: future, Client: future.client, Stored: time.Now()}); err != nil {
+		return Errorf(CodeInvalidParams, "could not serialize future %s: %s", future.ID, err)
+	}
+
+	if err = b.js.Publish(ctx, data); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return Errorf(CodeBadGateway, "could not publish future %s: %s", future.ID, err)
+	}
+	return nil
+}
+
+// Dequeue fetches the next message from the consumer, counting the deliveries of the
+// message before this one as redeliveries of the future.
+func (b *jetStreamBroker) Dequeue(ctx context.Context) (_ *Future, err error) {
+	var msg JetStreamMessage
+	if msg, err = b.js.Fetch(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, Errorf(CodeBadGateway, "could not fetch future: %s", err)
+	}
+
+	stored := &storedFuture{}
+	if err = json.Unmarshal(msg.Data(), stored); err != nil || stored.Future == nil {
+		// Terminate the message so that it is not redelivered
+		msg.Term()
+		return nil, Errorf(CodeInvalidParams, "could not parse future from jetstream message: %v", err)
+	}
+
+	future := stored.Future
+	future.client = stored.Client
+	if n := msg.NumDelivered(); n > 1 {
+		future.Redelivered = int(n - 1)
+	}
+
+	b.Lock()
+	b.pending[future.ID.String()] = msg
+	b.Unlock()
+	return future, nil
+}
+
+func (b *jetStreamBroker) Ack(id uuid.UUID) (err error) {
+	b.Lock()
+	msg, ok := b.pending[id.String()]
+	delete(b.pending, id.String())
+	b.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if err = msg.Ack(); err != nil {
+		return Errorf(CodeBadGateway, "could not acknowledge future %s: %s", id, err)
+	}
+	return nil
+}
+
+// Len returns the number of messages that have not been delivered to the consumer, or 0
+// if the number cannot be determined.
+func (b *jetStreamBroker) Len() int {
+	n, err := b.js.Pending()
+	if err != nil {
+		return 0
+	}
+	return n
+}
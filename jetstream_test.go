@@ -0,0 +1,157 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJetStreamBroker(t *testing.T) {
+	stream := newFakeJetStream()
+
+	// Futures published by one queue are handled by the workers of every queue
+	wg := new(sync.WaitGroup)
+	var handled [2]int32
+	queues := make([]*Radish, 2)
+	for i := range queues {
+		i := i
+		task := &testTask{wg: wg, name: "shared", onHandle: func(id uuid.UUID, params []byte) error {
+			atomic.AddInt32(&handled[i], 1)
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		}}
+
+		var err error
+		queues[i], err = New(&Config{Workers: 2, NoSignals: true, LogLevel: "warn", Broker: NewJetStreamBroker(stream)}, task)
+		require.NoError(t, err)
+	}
+
+	wg.Add(20)
+	for i := 0; i < 20; i++ {
+		_, err := queues[0].Delay("shared", nil, nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(20), atomic.LoadInt32(&handled[0])+atomic.LoadInt32(&handled[1]))
+	require.NotZero(t, atomic.LoadInt32(&handled[1]), "the second queue did not handle any futures")
+	for _, queue := range queues {
+		require.NoError(t, queue.Shutdown())
+	}
+	require.Equal(t, 20, stream.numAcked())
+
+	// Unacknowledged messages are redelivered with the redelivery count of the future
+	broker := NewJetStreamBroker(stream)
+	require.NoError(t, broker.Enqueue(context.Background(), &Future{ID: uuid.NewRandom(), Task: "shared"}))
+	require.Equal(t, 1, broker.Len())
+
+	future, err := broker.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, future.Redelivered)
+	stream.redeliver()
+
+	future, err = broker.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, future.Redelivered)
+	require.NoError(t, broker.Ack(future.ID))
+	require.Equal(t, 21, stream.numAcked())
+
+	// Messages that cannot be parsed are terminated
+	stream.Publish(context.Background(), []byte("garbage"))
+	_, err = broker.Dequeue(context.Background())
+	require.EqualError(t, err, "[9] could not parse future from jetstream message: invalid character 'g' looking for beginning of value")
+	require.Equal(t, 1, stream.numTerminated())
+}
+
+// fakeJetStream is a stream with a single pull consumer that redelivers unacknowledged
+// messages on demand rather than after an AckWait.
+type fakeJetStream struct {
+	sync.Mutex
+	messages   chan *fakeMessage
+	unacked    map[*fakeMessage]struct{}
+	acked      int
+	terminated int
+}
+
+func newFakeJetStream() *fakeJetStream {
+	return &fakeJetStream{messages: make(chan *fakeMessage, 100), unacked: make(map[*fakeMessage]struct{})}
+}
+
+func (s *fakeJetStream) Publish(ctx context.Context, data []byte) error {
+	s.messages <- &fakeMessage{stream: s, data: data}
+	return nil
+}
+
+func (s *fakeJetStream) Fetch(ctx context.Context) (JetStreamMessage, error) {
+	select {
+	case msg := <-s.messages:
+		s.Lock()
+		msg.delivered++
+		s.unacked[msg] = struct{}{}
+		s.Unlock()
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *fakeJetStream) Pending() (int, error) {
+	return len(s.messages), nil
+}
+
+func (s *fakeJetStream) redeliver() {
+	s.Lock()
+	defer s.Unlock()
+	for msg := range s.unacked {
+		delete(s.unacked, msg)
+		s.messages <- msg
+	}
+}
+
+func (s *fakeJetStream) numAcked() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.acked
+}
+
+func (s *fakeJetStream) numTerminated() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.terminated
+}
+
+type fakeMessage struct {
+	stream    *fakeJetStream
+	data      []byte
+	delivered uint64
+}
+
+func (m *fakeMessage) Data() []byte {
+	return m.data
+}
+
+func (m *fakeMessage) NumDelivered() uint64 {
+	return m.delivered
+}
+
+func (m *fakeMessage) Ack() error {
+	m.stream.Lock()
+	defer m.stream.Unlock()
+	delete(m.stream.unacked, m)
+	m.stream.acked++
+	return nil
+}
+
+func (m *fakeMessage) Term() error {
+	m.stream.Lock()
+	defer m.stream.Unlock()
+	delete(m.stream.unacked, m)
+	m.stream.terminated++
+	return nil
+}
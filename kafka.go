@@ -0,0 +1,123 @@
+package radish
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kansaslabs/x/out"
+)
+
+// Metadata keys of the futures delayed from Kafka records.
+const (
+	KafkaTopicKey     = "kafka-topic"
+	KafkaPartitionKey = "kafka-partition"
+	KafkaOffsetKey    = "kafka-offset"
+)
+
+// KafkaRecord is a record consumed from a Kafka topic.
+type KafkaRecord struct {
+	Topic     string            // the topic the record was consumed from
+	Partition int32             // the partition of the topic
+	Offset    int64             // the offset of the record in the partition
+	Key       []byte            // the key of the record, if any
+	Value     []byte            // the payload of the record, passed to the task as its params
+	Headers   map[string]string // the headers of the record, copied to the metadata of the future
+}
+
+// KafkaConsumer reads records from the topics of a Kafka consumer group. Radish does not
+// depend on a Kafka client; implement the interface by wrapping the consumer of your
+// client library, e.g. the FetchMessage and CommitMessages methods of a kafka-go Reader.
+type KafkaConsumer interface {
+	// FetchRecord blocks until the next record is available or the context is done, in
+	// which case the context error is returned. The record should not be committed.
+	FetchRecord(ctx context.Context) (*KafkaRecord, error)
+
+	// CommitRecord commits the offset of a record once a future has been delayed for it.
+	CommitRecord(ctx context.Context, record *KafkaRecord) error
+}
+
+// KafkaSource maps the topics of a Kafka consumer to the tasks that handle their records.
+type KafkaSource struct {
+	Consumer KafkaConsumer     // the consumer to read records from
+	Topics   map[string]string // the name of the task that handles the records of each topic
+}
+
+// ConsumeKafka delays a future for every record read from the consumer of the source so
+// that radish can act as the worker layer behind an existing Kafka pipeline, blocking
+// until the context is done or the queue is shutdown. The value of each record is passed
+// to the task of its topic as the params of the future and its headers, topic, partition,
+// and offset are copied to the metadata of the future; the topic, partition, and offset
+// are also used as the idempotency key of the future so that records that are consumed
+// again are not queued twice while the key is claimed. A record is only committed once
+// its future has been queued, so records are queued at least once. Records of topics
+// without a task are committed and skipped. If a record cannot be fetched, delayed, or
+// committed the error is returned and the record is consumed again when consuming is
+// restarted.
+func (r *Radish) ConsumeKafka(ctx context.Context, source *KafkaSource) (err error) {
+	if source.Consumer == nil || len(source.Topics) == 0 {
+		return Errorf(CodeInvalidConfig, "kafka source requires a consumer and at least one topic")
+	}
+
+	for topic, task := range source.Topics {
+		if _, err = r.Handler(task); err != nil {
+			return Errorf(CodeTaskNotRegistered, "could not consume kafka topic %q: %s", topic, err)
+		}
+	}
+
+	// Stop consuming when the queue is shutdown
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		var record *KafkaRecord
+		if record, err = source.Consumer.FetchRecord(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return Errorf(CodeBadGateway, "could not fetch kafka record: %s", err)
+		}
+
+		if err = r.delayRecord(ctx, source, record); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if err = source.Consumer.CommitRecord(ctx, record); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return Errorf(CodeBadGateway, "could not commit kafka record %d of %s[%d]: %s", record.Offset, record.Topic, record.Partition, err)
+		}
+	}
+}
+
+// delayRecord delays a future of the task of the topic of the record.
+func (r *Radish) delayRecord(ctx context.Context, source *KafkaSource, record *KafkaRecord) (err error) {
+	task, ok := source.Topics[record.Topic]
+	if !ok {
+		r.logf(out.LevelWarn, "", "skipping kafka record %d of %s[%d]: no task handles the topic", record.Offset, record.Topic, record.Partition)
+		return nil
+	}
+
+	for key, value := range record.Headers {
+		ctx = WithMetadata(ctx, key, value)
+	}
+	ctx = WithMetadata(ctx, KafkaTopicKey, record.Topic)
+	ctx = WithMetadata(ctx, KafkaPartitionKey, fmt.Sprint(record.Partition))
+	ctx = WithMetadata(ctx, KafkaOffsetKey, fmt.Sprint(record.Offset))
+	ctx = WithIdempotencyKey(ctx, fmt.Sprintf("%s/%d/%d", record.Topic, record.Partition, record.Offset))
+
+	if _, err = r.DelayContext(ctx, task, record.Value, nil, nil); err != nil {
+		return Errorf(CodeOf(err), "could not delay kafka record %d of %s[%d]: %w", record.Offset, record.Topic, record.Partition, err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumeKafka(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	var mu sync.Mutex
+	payloads := make([]string, 0)
+	task := &testTask{wg: wg, name: "ingest", onHandle: func(id uuid.UUID, params []byte) error {
+		mu.Lock()
+		payloads = append(payloads, string(params))
+		mu.Unlock()
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "silent"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	metadata := make(map[string]map[string]string)
+	queue.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, future *Future) error {
+			mu.Lock()
+			metadata[string(future.Params)] = future.Metadata
+			mu.Unlock()
+			return next(ctx, future)
+		}
+	})
+
+	// Sources must map topics to registered tasks
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	consumer := &fakeConsumer{records: make(chan *KafkaRecord, 8)}
+	require.EqualError(t, queue.ConsumeKafka(ctx, &KafkaSource{Consumer: consumer}), "[1] kafka source requires a consumer and at least one topic")
+	require.EqualError(t, queue.ConsumeKafka(ctx, &KafkaSource{Consumer: consumer, Topics: map[string]string{"orders": "unknown"}}), "[3] could not consume kafka topic \"orders\": [3] unknown task \"unknown\"")
+
+	// Records are delayed as futures of the task of their topic and committed
+	source := &KafkaSource{Consumer: consumer, Topics: map[string]string{"orders": "ingest"}}
+	consumer.records <- &KafkaRecord{Topic: "orders", Partition: 1, Offset: 7, Value: []byte("first"), Headers: map[string]string{"trace": "abc"}}
+	consumer.records <- &KafkaRecord{Topic: "clicks", Partition: 0, Offset: 3, Value: []byte("skipped")}
+	consumer.records <- &KafkaRecord{Topic: "orders", Partition: 1, Offset: 8, Value: []byte("second")}
+
+	// A record that is consumed again is not queued twice
+	consumer.records <- &KafkaRecord{Topic: "orders", Partition: 1, Offset: 8, Value: []byte("second")}
+
+	done := make(chan error, 1)
+	wg.Add(2)
+	go func() { done <- queue.ConsumeKafka(ctx, source) }()
+	wg.Wait()
+
+	require.Eventually(t, func() bool { return consumer.numCommitted() == 4 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"first", "second"}, payloads)
+	require.Equal(t, map[string]string{"trace": "abc", KafkaTopicKey: "orders", KafkaPartitionKey: "1", KafkaOffsetKey: "7"}, metadata["first"])
+
+	// Consuming stops without error when the context is canceled
+	cancel()
+	require.NoError(t, <-done)
+
+	// Errors of the consumer are returned without committing the record
+	consumer.fail = errors.New("broker unavailable")
+	err = queue.ConsumeKafka(context.Background(), source)
+	require.EqualError(t, err, "[6] could not fetch kafka record: broker unavailable")
+	require.Equal(t, 4, consumer.numCommitted())
+}
+
+type fakeConsumer struct {
+	sync.Mutex
+	records   chan *KafkaRecord
+	committed []*KafkaRecord
+	fail      error
+}
+
+func (c *fakeConsumer) FetchRecord(ctx context.Context) (*KafkaRecord, error) {
+	if c.fail != nil {
+		return nil, c.fail
+	}
+
+	select {
+	case record := <-c.records:
+		return record, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *fakeConsumer) CommitRecord(ctx context.Context, record *KafkaRecord) error {
+	c.Lock()
+	defer c.Unlock()
+	c.committed = append(c.committed, record)
+	return nil
+}
+
+func (c *fakeConsumer) numCommitted() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.committed)
+}
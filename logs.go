@@ -0,0 +1,284 @@
+package radish
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+)
+
+// The number of recent log entries kept in memory for the Logs RPC tail option.
+const (
+	logHistorySize   = 1000
+	logSubscriberBuf = 256
+)
+
+// logHub keeps a history of recent log entries and broadcasts new entries to the
+// subscribers of the Logs RPC. Entries are dropped for subscribers that cannot keep up
+// so that logging never blocks the workers.
+type logHub struct {
+	sync.RWMutex
+	history []*api.LogEntry // ring buffer of entries at or above the configured log level
+	next    int             // the index of the next entry to write in the history
+	full    bool            // if the history ring buffer has wrapped around
+	subs    map[chan *api.LogEntry]uint8
+}
+
+func newLogHub() *logHub {
+	return &logHub{
+		history: make([]*api.LogEntry, logHistorySize),
+		subs:    make(map[chan *api.LogEntry]uint8),
+	}
+}
+
+// subscribe to log entries at or above the specified level.
+func (h *logHub) subscribe(level uint8) chan *api.LogEntry {
+	c := make(chan *api.LogEntry, logSubscriberBuf)
+	h.Lock()
+	h.subs[c] = level
+	h.Unlock()
+	return c
+}
+
+func (h *logHub) unsubscribe(c chan *api.LogEntry) {
+	h.Lock()
+	delete(h.subs, c)
+	h.Unlock()
+}
+
+// wants returns true if the entry should be recorded at all, e.g. if it is at or above
+// the configured log level or a subscriber has requested a more verbose level.
+func (h *logHub) wants(level, threshold uint8) bool {
+	if level >= threshold {
+		return true
+	}
+
+	h.RLock()
+	defer h.RUnlock()
+	for _, sub := range h.subs {
+		if level >= sub {
+			return true
+		}
+	}
+	return false
+}
+
+// publish the entry to the history if it is at or above the configured log level and to
+// all subscribers that are interested in the level of the entry.
+func (h *logHub) publish(entry *api.LogEntry, level, threshold uint8) {
+	h.Lock()
+	defer h.Unlock()
+
+	if level >= threshold {
+		h.history[h.next] = entry
+		if h.next = (h.next + 1) % len(h.history); h.next == 0 {
+			h.full = true
+		}
+	}
+
+	for c, sub := range h.subs {
+		if level >= sub {
+			select {
+			case c <- entry:
+			default:
+			}
+		}
+	}
+}
+
+// tail returns up to n of the most recent entries in the history that match the filter,
+// oldest first.
+func (h *logHub) tail(n int, matches func(*api.LogEntry) bool) []*api.LogEntry {
+	h.RLock()
+	defer h.RUnlock()
+
+	size := h.next
+	if h.full {
+		size = len(h.history)
+	}
+
+	entries := make([]*api.LogEntry, 0, n)
+	for i := 1; i <= size && len(entries) < n; i++ {
+		entry := h.history[(h.next-i+len(h.history))%len(h.history)]
+		if matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	// Reverse the entries so that they are in the order they were logged
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries
+}
+
+// logf writes the message to the out logger at the specified level and records it so
+// that it can be streamed to clients with the Logs RPC. If the message relates to a
+// specific task, the task name should be specified so that clients can filter by task.
+func (r *Radish) logf(level uint8, task string, msg string, a ...interface{}) {
+	write(level, msg, a...)
+
+	threshold := r.logThreshold()
+	if !r.logs.wants(level, threshold) {
+		return
+	}
+
+	entry := &api.LogEntry{
+		Timestamp: time.Now().UnixNano(),
+		Level:     levelName(level),
+		Task:      task,
+		Message:   strings.TrimSuffix(fmt.Sprintf(msg, a...), "\n"),
+	}
+	r.logs.publish(entry, level, threshold)
+}
+
+// The out logger is global and does not synchronize changes to its level, so the level
+// is only changed while holding the write lock and messages are written with the read
+// lock held.
+var (
+	outMu   sync.RWMutex
+	outInit sync.Once
+)
+
+// write the message to the out logger at the specified level.
+func write(level uint8, msg string, a ...interface{}) {
+	outMu.RLock()
+	defer outMu.RUnlock()
+
+	switch level {
+	case out.LevelTrace:
+		out.Trace(msg, a...)
+	case out.LevelDebug:
+		out.Debug(msg, a...)
+	case out.LevelInfo:
+		out.Info(msg, a...)
+	case out.LevelCaution:
+		out.Caution(msg, a...)
+	case out.LevelStatus:
+		out.Status(msg, a...)
+	default:
+		out.Warn(msg, a...)
+	}
+}
+
+// Logf logs the message at the specified out log level from the handler of a future,
+// e.g. Logf(ctx, out.LevelInfo, "sent %d emails", n), with the context passed to
+// HandleContext or HandleResult. The message is logged by the queue with the task and
+// id of the future and, if FutureLogLines is configured, the most recent lines are kept
+// on the record of the future so that a single failed future can be debugged with the
+// GetFuture RPC without searching the logs of the server. Messages logged with a context
+// that was not passed to a handler are written to the out logger.
+func Logf(ctx context.Context, level uint8, msg string, a ...interface{}) {
+	logger, ok := ctx.Value(futureLogKey).(*futureLogger)
+	if !ok {
+		write(level, msg, a...)
+		return
+	}
+	logger.logf(level, msg, a...)
+}
+
+// futureLogger is added to the context passed to the handler of a future for Logf.
+type futureLogger struct {
+	parent *Radish
+	future *Future
+}
+
+// withFutureLogger returns a copy of the parent context that logs to the future.
+func (r *Radish) withFutureLogger(parent context.Context, future *Future) context.Context {
+	return context.WithValue(parent, futureLogKey, &futureLogger{parent: r, future: future})
+}
+
+func (l *futureLogger) logf(level uint8, msg string, a ...interface{}) {
+	msg = strings.TrimSuffix(fmt.Sprintf(msg, a...), "\n")
+	l.parent.logf(level, l.future.Task, "%s future %s: %s", l.future.Task, l.future.ID, msg)
+
+	if max := l.parent.config.FutureLogLines; max > 0 {
+		entry := &api.LogEntry{
+			Timestamp: time.Now().UnixNano(),
+			Level:     levelName(level),
+			Task:      l.future.Task,
+			Message:   msg,
+		}
+		l.parent.results.log(l.future.ID, entry, max)
+	}
+}
+
+// levelName returns the string representation of an out log level.
+func levelName(level uint8) string {
+	for name, lvl := range logLevels {
+		if lvl == level {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// Logs implements the RadishServer interface, streaming log entries that match the
+// request's level and task filters. If tail is specified, the most recent log entries
+// are sent first; if follow is specified, new entries are streamed until the request is
+// canceled or the queue is shutdown.
+func (r *Radish) Logs(in *api.LogsRequest, stream api.Radish_LogsServer) (err error) {
+	level := out.LevelInfo
+	if in.Level != "" {
+		var ok bool
+		if level, ok = logLevels[strings.ToLower(in.Level)]; !ok {
+			return Errorf(CodeInvalidConfig, "%q is an invalid log level", in.Level)
+		}
+	}
+
+	tasks := make(map[string]struct{}, len(in.Tasks))
+	for _, task := range in.Tasks {
+		tasks[task] = struct{}{}
+	}
+
+	matches := func(entry *api.LogEntry) bool {
+		if logLevels[entry.Level] < level {
+			return false
+		}
+		if len(tasks) > 0 {
+			if _, ok := tasks[entry.Task]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Subscribe before sending the tail so that no entries are missed
+	var entries chan *api.LogEntry
+	if in.Follow {
+		entries = r.logs.subscribe(level)
+		defer r.logs.unsubscribe(entries)
+	}
+
+	if in.Tail > 0 {
+		for _, entry := range r.logs.tail(int(in.Tail), matches) {
+			if err = stream.Send(entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !in.Follow {
+		return nil
+	}
+
+	for {
+		select {
+		case entry := <-entries:
+			if !matches(entry) {
+				continue
+			}
+			if err = stream.Send(entry); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		case <-r.shutdown:
+			return nil
+		}
+	}
+}
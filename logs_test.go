@@ -0,0 +1,119 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestLogs(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+
+	good := &testTask{wg: wg, name: "good"}
+	bad := &testTask{wg: wg, name: "bad", onHandle: func(id uuid.UUID, params []byte) error { return errors.New("whoops!") }}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "info", CautionThreshold: 1}, good, bad)
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	api.RegisterRadishServer(srv, queue)
+	go srv.Serve(sock)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(sock.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := api.NewRadishClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Recent log entries can be filtered by task
+	stream, err := client.Logs(ctx, &api.LogsRequest{Tail: 10, Tasks: []string{"bad"}})
+	require.NoError(t, err)
+
+	entry, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "info", entry.Level)
+	require.Equal(t, "bad", entry.Task)
+	require.Equal(t, "registered task bad", entry.Message)
+
+	_, err = stream.Recv()
+	require.Error(t, err)
+
+	// Follow the logs at the caution level for the bad task
+	stream, err = client.Logs(ctx, &api.LogsRequest{Level: "caution", Tasks: []string{"bad"}, Follow: true})
+	require.NoError(t, err)
+
+	// Ensure the subscription has been made before the task fails
+	time.Sleep(50 * time.Millisecond)
+	_, err = queue.Delay(good.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Delay(bad.Name(), nil, nil, nil)
+	require.NoError(t, err)
+
+	entry, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "caution", entry.Level)
+	require.Equal(t, "bad", entry.Task)
+	require.Equal(t, "whoops!", entry.Message)
+	wg.Wait()
+
+	// Invalid log levels are rejected
+	stream, err = client.Logs(ctx, &api.LogsRequest{Level: "loud"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Error(t, err)
+}
+
+func TestFutureLogs(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &loggingTask{testTask{wg: wg, name: "chatty"}}
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn", FutureLogLines: 2}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	wg.Add(1)
+	id, err := queue.Delay("chatty", []byte("3"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Wait(context.Background(), id)
+	require.EqualError(t, err, "gave up")
+	wg.Wait()
+
+	// Only the most recent lines logged by the handler are kept on the record
+	rep, err := queue.GetFuture(context.Background(), &api.GetFutureRequest{Uuid: id})
+	require.NoError(t, err)
+	require.Len(t, rep.Future.Logs, 2)
+	require.Equal(t, "step 2", rep.Future.Logs[0].Message)
+	require.Equal(t, "info", rep.Future.Logs[0].Level)
+	require.Equal(t, "giving up after 3 steps", rep.Future.Logs[1].Message)
+	require.Equal(t, "warn", rep.Future.Logs[1].Level)
+	require.Equal(t, "chatty", rep.Future.Logs[1].Task)
+}
+
+type loggingTask struct {
+	testTask
+}
+
+func (t *loggingTask) HandleContext(ctx context.Context, id uuid.UUID, params []byte) error {
+	t.Handle(id, params)
+	for i := 0; i < 3; i++ {
+		Logf(ctx, out.LevelInfo, "step %d", i)
+	}
+	Logf(ctx, out.LevelWarn, "giving up after %s steps", params)
+	return errors.New("gave up")
+}
@@ -0,0 +1,96 @@
+package radish
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	anypb "github.com/golang/protobuf/ptypes/any"
+	"github.com/pborman/uuid"
+)
+
+// MessageHandler handles futures whose params are a protocol buffer message packed into
+// a google.protobuf.Any. Rather than being registered by name, message handlers are
+// registered by message type; the worker unmarshals the params into a new message of
+// the registered type before calling Handle, so handlers receive a typed message instead
+// of raw bytes. As with tasks, the methods of a message handler must be thread safe.
+type MessageHandler interface {
+	Handle(id uuid.UUID, msg proto.Message) error   // handle the unmarshaled message
+	Success(id uuid.UUID, params []byte)            // callback for when the message has successfully been handled
+	Failure(id uuid.UUID, err error, params []byte) // callback for when the message could not be handled
+}
+
+// RegisterMessage registers a handler for futures whose params are an Any containing a
+// message of the same type as msg. The task name of the handler is the fully qualified
+// name of the message type (e.g. "google.protobuf.StringValue"), which may be used with
+// Delay, though it is simpler to use DelayMessage or to queue an Any via the API with
+// an empty task name so that the future is routed by the type of its params.
+func (r *Radish) RegisterMessage(msg proto.Message, handler MessageHandler) error {
+	return r.Register(&messageTask{name: proto.MessageName(msg), prototype: msg, handler: handler})
+}
+
+// DelayMessage packs the message into an Any and delays it for the message handler that
+// was registered for the message type.
+func (r *Radish) DelayMessage(ctx context.Context, msg proto.Message, success, failure []byte) (id uuid.UUID, err error) {
+	var params []byte
+	if params, err = marshalAny(msg); err != nil {
+		return nil, err
+	}
+	return r.DelayContext(ctx, proto.MessageName(msg), params, success, failure)
+}
+
+// messageTask adapts a MessageHandler to the Task interface.
+type messageTask struct {
+	name      string
+	prototype proto.Message
+	handler   MessageHandler
+}
+
+func (t *messageTask) Name() string {
+	return t.name
+}
+
+func (t *messageTask) Handle(id uuid.UUID, params []byte) (err error) {
+	packed := &anypb.Any{}
+	if err = proto.Unmarshal(params, packed); err != nil {
+		return Errorf(CodeInvalidParams, "could not unmarshal any: %s", err)
+	}
+
+	msg := proto.Clone(t.prototype)
+	msg.Reset()
+	if err = ptypes.UnmarshalAny(packed, msg); err != nil {
+		return Errorf(CodeInvalidParams, "could not unmarshal %s: %s", t.name, err)
+	}
+	return t.handler.Handle(id, msg)
+}
+
+func (t *messageTask) Success(id uuid.UUID, params []byte) {
+	t.handler.Success(id, params)
+}
+
+func (t *messageTask) Failure(id uuid.UUID, err error, params []byte) {
+	t.handler.Failure(id, err, params)
+}
+
+// marshalAny packs the message into an Any and returns the serialized Any.
+func marshalAny(msg proto.Message) (params []byte, err error) {
+	var packed *anypb.Any
+	if packed, err = ptypes.MarshalAny(msg); err != nil {
+		return nil, Errorf(CodeInvalidParams, "could not marshal any: %s", err)
+	}
+
+	if params, err = proto.Marshal(packed); err != nil {
+		return nil, Errorf(CodeInvalidParams, "could not marshal any: %s", err)
+	}
+	return params, nil
+}
+
+// anyMessageName returns the name of the message type packed into the serialized Any,
+// which is used to route futures that are queued without a task name.
+func anyMessageName(params []byte) (name string, err error) {
+	packed := &anypb.Any{}
+	if err = proto.Unmarshal(params, packed); err != nil {
+		return "", err
+	}
+	return ptypes.AnyMessageName(packed)
+}
@@ -0,0 +1,76 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageDispatch(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+
+	handler := &testMessageHandler{wg: wg, msgs: make(chan proto.Message, 2)}
+	queue, err := New(&Config{Workers: 1})
+	require.NoError(t, err)
+	require.NoError(t, queue.RegisterMessage(&wrappers.StringValue{}, handler))
+
+	// Cannot register a second handler for the same message type
+	require.EqualError(t, queue.RegisterMessage(&wrappers.StringValue{}, handler), `[2] task named "google.protobuf.StringValue" has already been registered`)
+
+	// Delay the message directly
+	_, err = queue.DelayMessage(context.Background(), &wrappers.StringValue{Value: "hello"}, nil, nil)
+	require.NoError(t, err)
+
+	// Delay an any without a task name so it is routed by type
+	packed, err := ptypes.MarshalAny(&wrappers.StringValue{Value: "world"})
+	require.NoError(t, err)
+	params, err := proto.Marshal(packed)
+	require.NoError(t, err)
+	_, err = queue.Delay("", params, nil, nil)
+	require.NoError(t, err)
+
+	wg.Wait()
+	close(handler.msgs)
+
+	values := make([]string, 0, 2)
+	for msg := range handler.msgs {
+		sv, ok := msg.(*wrappers.StringValue)
+		require.True(t, ok)
+		values = append(values, sv.Value)
+	}
+	require.ElementsMatch(t, []string{"hello", "world"}, values)
+
+	// An unregistered message type cannot be delayed
+	_, err = queue.DelayMessage(context.Background(), &wrappers.Int64Value{Value: 42}, nil, nil)
+	require.EqualError(t, err, `[3] could not delay [3] unknown task "google.protobuf.Int64Value"`)
+
+	// Params that are not an any cannot be routed without a task name
+	_, err = queue.Delay("", []byte("foo"), nil, nil)
+	require.Error(t, err)
+}
+
+type testMessageHandler struct {
+	wg   *sync.WaitGroup
+	msgs chan proto.Message
+}
+
+func (h *testMessageHandler) Handle(id uuid.UUID, msg proto.Message) error {
+	h.msgs <- msg
+	return nil
+}
+
+func (h *testMessageHandler) Success(id uuid.UUID, params []byte) {
+	h.wg.Done()
+}
+
+func (h *testMessageHandler) Failure(id uuid.UUID, err error, params []byte) {
+	h.wg.Done()
+}
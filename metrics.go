@@ -1,23 +1,76 @@
 package radish
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/kansaslabs/x/out"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Metric interfaces implemented by the collectors used on the hot path.
+type (
+	gauge interface {
+		Set(float64)
+	}
+
+	counter interface {
+		Add(float64)
+	}
+
+	counterVec interface {
+		WithLabelValues(lvs ...string) prometheus.Counter
+	}
+
+	gaugeVec interface {
+		WithLabelValues(lvs ...string) prometheus.Gauge
+	}
+
+	histogramVec interface {
+		WithLabelValues(lvs ...string) prometheus.Observer
+	}
+)
+
+// The prometheus collectors are created once when the package is initialized, but do not
+// record anything until they are registered so that the hot path does not spend time
+// observing metrics that nothing can scrape, e.g. when SuppressMetrics is set.
 var (
-	pmWorkers     prometheus.Gauge // number of available workers
-	pmQueueSize   prometheus.Gauge // number of tasks in the queue awaiting handling
-	pmPercentFull prometheus.Gauge // the percent of the queue that is full * 100
+	pmWorkers     gauge // number of available workers
+	pmWorkersBusy gauge // number of workers handling a future
+	pmWorkersIdle gauge // number of workers waiting for a future
+	pmQueueSize   gauge // number of tasks in the queue awaiting handling
+	pmPercentFull gauge // the percent of the queue that is full * 100
+	pmPaused      gauge // 1 if the workers have been paused, otherwise 0
 	// pmPercentSuccess *prometheus.GaugeVec     // the percent of tasks successfully completed, labeled by task
-	pmTasksSucceeded *prometheus.CounterVec   // the count of successfully completed tasks, labeled by task type
-	pmTasksFailed    *prometheus.CounterVec   // the count of failed tasks, labeled by task type
-	pmTaskLatency    *prometheus.HistogramVec // the time it is taking for tasks to complete, labeled by task type, success, and failure
+	pmTasksSucceeded counterVec   // the count of successfully completed tasks, labeled by task type
+	pmTasksFailed    counterVec   // the count of failed tasks, labeled by task type
+	pmTasksCanceled  counterVec   // the count of canceled tasks, labeled by task type
+	pmTasksRetried   counterVec   // the count of failed attempts that were retried, labeled by task type
+	pmTaskLatency    histogramVec // the time it is taking for tasks to complete, labeled by task type, success, and failure
+	pmTasksInFlight  gaugeVec     // the number of futures being handled by workers, labeled by task type
+	pmQueueWait      histogramVec // the time futures wait in the queue before a worker starts handling them, labeled by task type
+	pmTasksForwarded counterVec   // the count of futures forwarded to idle peers of the cluster, labeled by task type
+	pmTasksDropped   counterVec   // the count of queued futures dropped to make room in the full queue, labeled by task type
+	pmRecordsEvicted counter      // the count of completed future records evicted after their ttl expired
+	pmRequests       counterVec   // the count of gRPC requests, labeled by method and status code
+	pmRequestErrors  counterVec   // the count of gRPC requests that failed or replied with an error, labeled by method
+	pmRequestLatency histogramVec // the time it takes to handle gRPC requests, labeled by method
+)
+
+var (
+	pmCollectors []prometheus.Collector // the prometheus collectors of the metrics
+	pmRegister   sync.Once
+	pmRegistered error
+	pmEnabled    int32 // set to 1 once the collectors are registered
 )
 
 const (
@@ -25,19 +78,37 @@ const (
 )
 
 func initMetrics() {
-	pmWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+	workers := prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: pmNamespace,
 		Name:      "workers",
 		Help:      "The number of available workers",
 	})
 
-	pmQueueSize = prometheus.NewGauge(prometheus.GaugeOpts{
+	workersBusy := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: pmNamespace,
+		Name:      "workers_busy",
+		Help:      "the number of workers handling a future",
+	})
+
+	workersIdle := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: pmNamespace,
+		Name:      "workers_idle",
+		Help:      "the number of workers waiting for a future to be queued",
+	})
+
+	paused := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: pmNamespace,
+		Name:      "paused",
+		Help:      "1 if the workers have been paused and are not dequeuing futures, otherwise 0",
+	})
+
+	queueSize := prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: pmNamespace,
 		Name:      "queue_size",
 		Help:      "number of tasks in the queue awaiting handling",
 	})
 
-	pmPercentFull = prometheus.NewGauge(prometheus.GaugeOpts{
+	percentFull := prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: pmNamespace,
 		Name:      "percent_full",
 		Help:      "the percent of the queue that is already full",
@@ -50,55 +121,243 @@ func initMetrics() {
 	// 	Help:      "the percent of tasks successfully completed, labeled by task",
 	// }, []string{"task"})
 
-	pmTasksSucceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+	tasksSucceeded := prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: pmNamespace,
 		Name:      "tasks_succeeded",
 		Help:      "the count of tasks successfully completed, labeled by task type",
 	}, []string{"task"})
 
-	pmTasksFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+	tasksFailed := prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: pmNamespace,
 		Name:      "tasks_failed",
 		Help:      "the count of failed tasks, labeled by task type",
 	}, []string{"task"})
 
-	pmTaskLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	tasksCanceled := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: pmNamespace,
+		Name:      "tasks_canceled",
+		Help:      "the count of canceled tasks, labeled by task type",
+	}, []string{"task"})
+
+	tasksRetried := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: pmNamespace,
+		Name:      "tasks_retried",
+		Help:      "the count of failed attempts that were retried, labeled by task type",
+	}, []string{"task"})
+
+	tasksInFlight := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: pmNamespace,
+		Name:      "tasks_in_flight",
+		Help:      "the number of futures being handled by workers, labeled by task type",
+	}, []string{"task"})
+
+	taskLatency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: pmNamespace,
 		Name:      "task_latency",
 		Help:      "time to task completion, labeled by task type, success, and failure",
 	}, []string{"task", "result"})
+
+	queueWait := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: pmNamespace,
+		Name:      "queue_wait",
+		Help:      "time futures wait in the queue before a worker starts handling them in seconds, labeled by task type",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"task"})
+
+	tasksForwarded := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: pmNamespace,
+		Name:      "tasks_forwarded",
+		Help:      "the count of futures forwarded to idle peers of the cluster, labeled by task type",
+	}, []string{"task"})
+
+	tasksDropped := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: pmNamespace,
+		Name:      "tasks_dropped",
+		Help:      "the count of queued futures dropped to make room in the full queue, labeled by task type",
+	}, []string{"task"})
+
+	recordsEvicted := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: pmNamespace,
+		Name:      "records_evicted",
+		Help:      "the count of completed future records evicted after their ttl expired",
+	})
+
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: pmNamespace,
+		Name:      "grpc_requests",
+		Help:      "the count of gRPC requests, labeled by method and status code",
+	}, []string{"method", "code"})
+
+	requestErrors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: pmNamespace,
+		Name:      "grpc_request_errors",
+		Help:      "the count of gRPC requests that failed or replied with an error, labeled by method",
+	}, []string{"method"})
+
+	requestLatency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: pmNamespace,
+		Name:      "grpc_request_latency",
+		Help:      "time to handle gRPC requests in seconds, labeled by method",
+	}, []string{"method"})
+
+	pmWorkers, pmWorkersBusy, pmWorkersIdle = metricGauge{workers}, metricGauge{workersBusy}, metricGauge{workersIdle}
+	pmQueueSize, pmPercentFull, pmPaused = metricGauge{queueSize}, metricGauge{percentFull}, metricGauge{paused}
+	pmTasksInFlight = metricGaugeVec{tasksInFlight}
+	pmTasksSucceeded, pmTasksFailed = metricCounterVec{tasksSucceeded}, metricCounterVec{tasksFailed}
+	pmTasksCanceled, pmTasksRetried = metricCounterVec{tasksCanceled}, metricCounterVec{tasksRetried}
+	pmTasksForwarded, pmTasksDropped = metricCounterVec{tasksForwarded}, metricCounterVec{tasksDropped}
+	pmTaskLatency, pmQueueWait = metricHistogramVec{taskLatency}, metricHistogramVec{queueWait}
+	pmRecordsEvicted = metricCounter{recordsEvicted}
+	pmRequests, pmRequestErrors = metricCounterVec{requests}, metricCounterVec{requestErrors}
+	pmRequestLatency = metricHistogramVec{requestLatency}
+
+	pmCollectors = []prometheus.Collector{
+		workers, workersBusy, workersIdle, paused, queueSize, percentFull, tasksInFlight, tasksSucceeded, tasksFailed, tasksCanceled, tasksRetried, tasksForwarded, tasksDropped, taskLatency, queueWait, recordsEvicted,
+		requests, requestErrors, requestLatency,
+	}
+}
+
+func init() {
+	initMetrics()
 }
 
-func serveMetrics(metricsAddr string) {
-	out.Status("serving prometheus metrics at http://%s/metrics", metricsAddr)
-	http.Handle("/metrics", promhttp.Handler())
-	if err := http.ListenAndServe(metricsAddr, nil); err != nil {
-		out.Warne(err)
+// metricsEnabled returns true once the collectors have been registered.
+func metricsEnabled() bool {
+	return atomic.LoadInt32(&pmEnabled) == 1
+}
+
+// Wrappers of the prometheus collectors that only record observations once the metrics
+// are enabled; the vectors return no-op metrics until then so that no series are created.
+type (
+	metricGauge        struct{ prometheus.Gauge }
+	metricCounter      struct{ prometheus.Counter }
+	metricCounterVec   struct{ *prometheus.CounterVec }
+	metricGaugeVec     struct{ *prometheus.GaugeVec }
+	metricHistogramVec struct{ *prometheus.HistogramVec }
+)
+
+func (m metricGauge) Set(v float64) {
+	if metricsEnabled() {
+		m.Gauge.Set(v)
 	}
 }
 
-func registerMetrics() error {
-	if err := prometheus.Register(pmWorkers); err != nil {
-		return fmt.Errorf("did not register %s: %s", pmWorkers, err)
+func (m metricCounter) Add(v float64) {
+	if metricsEnabled() {
+		m.Counter.Add(v)
 	}
-	if err := prometheus.Register(pmQueueSize); err != nil {
-		return fmt.Errorf("did not register %s: %s", pmQueueSize, err)
+}
+
+func (m metricCounterVec) WithLabelValues(lvs ...string) prometheus.Counter {
+	if !metricsEnabled() {
+		return noopMetric{}
 	}
-	if err := prometheus.Register(pmPercentFull); err != nil {
-		return fmt.Errorf("did not register %s: %s", pmPercentFull, err)
+	return m.CounterVec.WithLabelValues(lvs...)
+}
+
+func (m metricGaugeVec) WithLabelValues(lvs ...string) prometheus.Gauge {
+	if !metricsEnabled() {
+		return noopGauge{}
+	}
+	return m.GaugeVec.WithLabelValues(lvs...)
+}
+
+func (m metricHistogramVec) WithLabelValues(lvs ...string) prometheus.Observer {
+	if !metricsEnabled() {
+		return noopHistogram{}
 	}
-	// if err := prometheus.Register(pmPercentSuccess); err != nil {
-	// 	return fmt.Errorf("did not register %v: %s", pmPercentSuccess, err)
-	// }
-	if err := prometheus.Register(pmTasksSucceeded); err != nil {
-		return fmt.Errorf("did not register %v: %s", pmTasksSucceeded, err)
+	return m.HistogramVec.WithLabelValues(lvs...)
+}
+
+// unaryMetrics records the count, errors, and latency of unary gRPC requests so that the
+// health of the API can be distinguished from the health of task handling. Requests that
+// reply with success false count as errors even though the RPC itself succeeded.
+func unaryMetrics(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	rep, err := handler(ctx, req)
+
+	failed := err != nil
+	if reply, ok := rep.(interface{ GetSuccess() bool }); ok && err == nil && !reply.GetSuccess() {
+		failed = true
 	}
-	if err := prometheus.Register(pmTasksFailed); err != nil {
-		return fmt.Errorf("did not register %v: %s", pmTasksFailed, err)
+	observeRequest(info.FullMethod, err, failed, start)
+	return rep, err
+}
+
+// streamMetrics records the count, errors, and duration of streaming gRPC requests.
+func streamMetrics(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	observeRequest(info.FullMethod, err, err != nil, start)
+	return err
+}
+
+// observeRequest records the metrics of a gRPC request labeled by the short method name.
+func observeRequest(fullMethod string, err error, failed bool, start time.Time) {
+	method := path.Base(fullMethod)
+	pmRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	if failed {
+		pmRequestErrors.WithLabelValues(method).Inc()
 	}
-	if err := prometheus.Register(pmTaskLatency); err != nil {
-		return fmt.Errorf("did not register %v: %s", pmTaskLatency, err)
+	pmRequestLatency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// noopMetric implements the prometheus counter without recording anything. Only the
+// methods used on the hot path are implemented; the embedded counter is nil so calling
+// any other method panics.
+type noopMetric struct {
+	prometheus.Counter
+}
+
+func (noopMetric) Add(float64) {}
+func (noopMetric) Inc()        {}
+
+// noopGauge implements the prometheus gauge without recording anything. Only the methods
+// used on the hot path are implemented; the embedded gauge is nil.
+type noopGauge struct {
+	prometheus.Gauge
+}
+
+func (noopGauge) Set(float64) {}
+func (noopGauge) Inc()        {}
+func (noopGauge) Dec()        {}
+func (noopGauge) Add(float64) {}
+func (noopGauge) Sub(float64) {}
+
+// noopHistogram implements the prometheus observer without recording anything.
+type noopHistogram struct{}
+
+func (noopHistogram) Observe(float64) {}
+
+// serveMetrics serves the prometheus metrics and the /healthz and /readyz probes on the
+// metrics address. Other requests are handled by the default serve mux, e.g. so that
+// handlers the application registered such as pprof and the expvars published at
+// /debug/vars continue to be served.
+func (r *Radish) serveMetrics() {
+	write(out.LevelStatus, "serving prometheus metrics at http://%s/metrics", r.config.MetricsAddr)
+	r.PublishVars()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", r.Healthz)
+	mux.HandleFunc("/readyz", r.Readyz)
+	mux.Handle("/", http.DefaultServeMux)
+
+	if err := http.ListenAndServe(r.config.MetricsAddr, mux); err != nil {
+		write(out.LevelWarn, "%s", err)
 	}
+}
 
-	return nil
+// registerMetrics registers the collectors with the default prometheus registry once and
+// enables recording the metrics if they were registered.
+func registerMetrics() error {
+	pmRegister.Do(func() {
+		for _, collector := range pmCollectors {
+			if err := prometheus.Register(collector); err != nil {
+				pmRegistered = fmt.Errorf("did not register %v: %s", collector, err)
+				return
+			}
+		}
+		atomic.StoreInt32(&pmEnabled, 1)
+	})
+	return pmRegistered
 }
@@ -0,0 +1,268 @@
+package radish_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestMetrics(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "metered"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	running := &testTask{wg: new(sync.WaitGroup), name: "running", onHandle: func(id uuid.UUID, params []byte) error {
+		close(started)
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 4, NoSignals: true, MetricsAddr: "127.0.0.1:0", LogLevel: "silent"}, task, running)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// A future that is running while the metrics are registered
+	running.wg.Add(1)
+	_, err = queue.Delay("running", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	// Futures handled before the metrics are registered are not recorded
+	wg.Add(10)
+	for i := 0; i < 10; i++ {
+		_, err = queue.Delay("metered", nil, nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+	require.Zero(t, succeeded(t, "metered"))
+
+	// Register the metrics while the workers are handling futures
+	wg.Add(20)
+	for i := 0; i < 10; i++ {
+		_, err = queue.Delay("metered", nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	srv := grpc.NewServer()
+	defer srv.Stop()
+	require.NoError(t, queue.RegisterWith(srv))
+
+	for i := 0; i < 10; i++ {
+		_, err = queue.Delay("metered", nil, nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	count := succeeded(t, "metered")
+	require.True(t, count >= 10 && count <= 20, "expected the futures handled after registering to be recorded, got %v", count)
+
+	// Futures that started before the metrics were registered are not counted when they finish
+	close(release)
+	running.wg.Wait()
+	require.Eventually(t, func() bool {
+		return gather(t, "radish_workers_busy", nil).GetGauge().GetValue() == 0
+	}, time.Second, 5*time.Millisecond)
+	require.Zero(t, gather(t, "radish_tasks_in_flight", map[string]string{"task": "running"}).GetGauge().GetValue())
+}
+
+func TestRequestMetrics(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	queue, err := New(&Config{Workers: 1, NoSignals: true, MetricsAddr: "127.0.0.1:0", LogLevel: "silent"}, &testTask{wg: wg, name: "task"})
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	errc := make(chan error, 1)
+	go func() { errc <- queue.Serve(sock) }()
+	defer func() {
+		queue.Shutdown()
+		require.NoError(t, <-errc)
+	}()
+
+	cc, err := grpc.Dial(sock.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+	client := api.NewRadishClient(cc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	requests := func(method, code string) float64 {
+		return gather(t, "radish_grpc_requests", map[string]string{"method": method, "code": code}).GetCounter().GetValue()
+	}
+	errors := func(method string) float64 {
+		return gather(t, "radish_grpc_request_errors", map[string]string{"method": method}).GetCounter().GetValue()
+	}
+	latency := func(method string) uint64 {
+		return gather(t, "radish_grpc_request_latency", map[string]string{"method": method}).GetHistogram().GetSampleCount()
+	}
+
+	// Metrics are global, so only the changes made by this test are checked
+	statusOK, statusErrors, statusLatency := requests("Status", "OK"), errors("Status"), latency("Status")
+	queueOK, queueErrors := requests("Queue", "OK"), errors("Queue")
+	watchOK, watchLatency := requests("Watch", "OK"), latency("Watch")
+
+	// Successful unary requests are counted by method and code, and their latency observed
+	for i := 0; i < 3; i++ {
+		_, err = client.Status(ctx, &api.StatusRequest{}, grpc.WaitForReady(true))
+		require.NoError(t, err)
+	}
+	require.Equal(t, statusOK+3, requests("Status", "OK"))
+	require.Equal(t, statusErrors, errors("Status"))
+	require.Equal(t, statusLatency+3, latency("Status"))
+
+	// Replies with success false are errors even though the RPC succeeded
+	rep, err := client.Queue(ctx, &api.QueueRequest{Task: "unknown"})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, queueOK+1, requests("Queue", "OK"))
+	require.Equal(t, queueErrors+1, errors("Queue"))
+
+	// Streams are recorded once they return, e.g. when the client stops watching
+	sctx, scancel := context.WithCancel(ctx)
+	stream, err := client.Watch(sctx, &api.WatchRequest{})
+	require.NoError(t, err)
+
+	// Queue futures until the stream is watching and an event is received
+	events := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		events <- err
+	}()
+
+	for watching := false; !watching; {
+		wg.Add(1)
+		_, err = queue.Delay("task", nil, nil, nil)
+		require.NoError(t, err)
+		wg.Wait()
+
+		select {
+		case err = <-events:
+			require.NoError(t, err)
+			watching = true
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	scancel()
+
+	require.Eventually(t, func() bool {
+		return requests("Watch", "OK") == watchOK+1 && latency("Watch") == watchLatency+1
+	}, time.Second, 10*time.Millisecond)
+}
+
+// succeeded returns the count of succeeded futures of the task in the default registry.
+func succeeded(t *testing.T, task string) float64 {
+	return gather(t, "radish_tasks_succeeded", map[string]string{"task": task}).GetCounter().GetValue()
+}
+
+// gather returns the metric with the name and labels from the default registry, or nil
+// if it has not been recorded.
+func gather(t *testing.T, name string, labels map[string]string) *dto.Metric {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value != label.GetValue() {
+					continue metrics
+				}
+			}
+			return metric
+		}
+	}
+	return nil
+}
+
+func TestQueueWaitMetrics(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "waiting", onHandle: func(id uuid.UUID, params []byte) error {
+		if string(params) == "block" {
+			started <- struct{}{}
+			<-release
+		}
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, MetricsAddr: "127.0.0.1:0", LogLevel: "silent"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	srv := grpc.NewServer()
+	defer srv.Stop()
+	require.NoError(t, queue.RegisterWith(srv))
+
+	// The second future waits in the queue while the only worker is blocked
+	wg.Add(2)
+	_, err = queue.Delay("waiting", []byte("block"), nil, nil)
+	require.NoError(t, err)
+	<-started
+	_, err = queue.Delay("waiting", nil, nil, nil)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	wait := gather(t, "radish_queue_wait", map[string]string{"task": "waiting"}).GetHistogram()
+	require.Equal(t, uint64(2), wait.GetSampleCount())
+	require.GreaterOrEqual(t, wait.GetSampleSum(), 0.1)
+	require.Less(t, wait.GetSampleSum(), 10.0)
+}
+
+func TestInFlightMetrics(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "flying", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 2, NoSignals: true, MetricsAddr: "127.0.0.1:0", LogLevel: "silent"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	srv := grpc.NewServer()
+	defer srv.Stop()
+	require.NoError(t, queue.RegisterWith(srv))
+
+	inFlight := func() float64 {
+		return gather(t, "radish_tasks_in_flight", map[string]string{"task": "flying"}).GetGauge().GetValue()
+	}
+
+	// Futures are in flight while their handlers are running
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		_, err = queue.Delay("flying", nil, nil, nil)
+		require.NoError(t, err)
+	}
+	<-started
+	<-started
+	require.Equal(t, 2.0, inFlight())
+
+	// And are no longer in flight once their handlers have returned
+	close(release)
+	wg.Wait()
+	require.Eventually(t, func() bool { return inFlight() == 0 }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,35 @@
+package radish
+
+import (
+	"context"
+)
+
+// HandlerFunc handles a dequeued future with the context passed to the task, which is
+// canceled if the future is canceled while it is being handled.
+type HandlerFunc func(ctx context.Context, future *Future) error
+
+// Middleware wraps the handling of futures, e.g. to add logging, metrics, authorization,
+// or tracing to every task without modifying each Task implementation. Middleware should
+// call next to handle the future and may return an error without calling next to fail
+// the future. The middleware is called from multiple go routines and must be thread safe.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Use adds middleware that wraps the handling of every future. Middleware is applied in
+// the order it is added, so the first middleware is the outermost and is called first.
+// Middleware added while futures are being handled applies to futures dequeued later.
+func (r *Radish) Use(mw ...Middleware) {
+	r.Lock()
+	defer r.Unlock()
+	r.middleware = append(r.middleware, mw...)
+}
+
+// chain wraps the handler with the middleware so that the first middleware is called first.
+func (r *Radish) chain(handler HandlerFunc) HandlerFunc {
+	r.RLock()
+	defer r.RUnlock()
+
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,61 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	queue, err := New(&Config{Workers: 1, NoSignals: true})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, future *Future) error {
+				mu.Lock()
+				calls = append(calls, name+":"+future.Task)
+				mu.Unlock()
+				return next(ctx, future)
+			}
+		}
+	}
+
+	// Reject futures that were not delayed with an authorized user
+	auth := func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, future *Future) error {
+			if future.Metadata["user"] != "admin" {
+				return Errorf(CodeInvalidParams, "%s future is not authorized", future.Task)
+			}
+			return next(ctx, future)
+		}
+	}
+	queue.Use(record("outer"), record("inner"))
+	queue.Use(auth)
+
+	var errs []error
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "wrapped", onFailure: func(id uuid.UUID, err error, params []byte) {
+		errs = append(errs, err)
+	}}
+	require.NoError(t, queue.Register(task))
+
+	wg.Add(2)
+	_, err = queue.DelayContext(WithMetadata(context.Background(), "user", "admin"), "wrapped", nil, nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Delay("wrapped", nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	require.Equal(t, []string{"outer:wrapped", "inner:wrapped", "outer:wrapped", "inner:wrapped"}, calls)
+	require.Equal(t, int32(1), task.handled)
+	require.Equal(t, int32(1), task.successes)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "[9] wrapped future is not authorized")
+}
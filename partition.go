@@ -0,0 +1,153 @@
+package radish
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kansaslabs/x/out"
+)
+
+// WithPartitionKey returns a copy of the parent context with the specified partition key.
+// When a task is delayed using DelayContext, futures with the same partition key are
+// handled serially, one at a time, in the order they were queued, even if the queue has
+// many workers; futures with different keys are still handled concurrently. A partition
+// key orders the futures of one entity, e.g. the events of a user, across tasks. A failed
+// future that is retried holds up the futures queued after it with the same key until it
+// is completed, and futures with a partition key are never forwarded to peers.
+func WithPartitionKey(parent context.Context, key string) context.Context {
+	return context.WithValue(parent, partitionKey, key)
+}
+
+// PartitionKeyFrom returns the partition key on the context, or an empty string if the
+// context does not have a partition key.
+func PartitionKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(partitionKey).(string)
+	return key
+}
+
+// partitions tracks the futures of each partition key in the order they were queued so
+// that only the oldest future of a key is handled at a time. Workers that dequeue a
+// future whose turn has not come park it; the parked future is requeued once the futures
+// queued before it are done.
+type partitions struct {
+	sync.Mutex
+	keys map[string]*partition
+}
+
+// partition holds the ids of the queued futures of a key in the order they were queued.
+type partition struct {
+	pending []string           // the ids of the futures of the key, oldest first
+	parked  map[string]*Future // the futures that were dequeued before their turn, by id
+}
+
+func newPartitions() *partitions {
+	return &partitions{keys: make(map[string]*partition)}
+}
+
+// add the future to the end of its partition when it enters the queue; futures that are
+// already pending, e.g. because they are being retried, keep their place.
+func (p *partitions) add(future *Future) {
+	if future.PartitionKey == "" {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	part, ok := p.keys[future.PartitionKey]
+	if !ok {
+		part = &partition{parked: make(map[string]*Future)}
+		p.keys[future.PartitionKey] = part
+	}
+
+	id := future.ID.String()
+	for _, pending := range part.pending {
+		if pending == id {
+			return
+		}
+	}
+	part.pending = append(part.pending, id)
+}
+
+// acquire returns true if the dequeued future can be handled, otherwise the future is
+// parked until the futures queued before it with the same key are done. Futures that are
+// not pending in their partition are always handled.
+func (p *partitions) acquire(future *Future) bool {
+	if future.PartitionKey == "" {
+		return true
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	part, ok := p.keys[future.PartitionKey]
+	if !ok {
+		return true
+	}
+
+	id := future.ID.String()
+	for i, pending := range part.pending {
+		if pending == id {
+			if i == 0 {
+				return true
+			}
+			part.parked[id] = future
+			return false
+		}
+	}
+	return true
+}
+
+// done removes the future from its partition once it will not be handled again,
+// returning the next future of the partition if it was parked waiting for its turn.
+func (p *partitions) done(future *Future) *Future {
+	if future.PartitionKey == "" {
+		return nil
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	part, ok := p.keys[future.PartitionKey]
+	if !ok {
+		return nil
+	}
+
+	id := future.ID.String()
+	delete(part.parked, id)
+	if len(part.pending) == 0 || part.pending[0] != id {
+		for i, pending := range part.pending {
+			if pending == id {
+				part.pending = append(part.pending[:i:i], part.pending[i+1:]...)
+				break
+			}
+		}
+		return nil
+	}
+
+	part.pending = part.pending[1:]
+	if len(part.pending) == 0 {
+		delete(p.keys, future.PartitionKey)
+		return nil
+	}
+
+	next, ok := part.parked[part.pending[0]]
+	if ok {
+		delete(part.parked, part.pending[0])
+	}
+	return next
+}
+
+// releasePartition removes the future from its partition, requeueing the next future
+// of the partition in the background if it was parked waiting for its turn.
+func (r *Radish) releasePartition(future *Future) {
+	next := r.partitions.done(future)
+	if next == nil {
+		return
+	}
+
+	go func() {
+		if err := r.requeue(context.Background(), next); err != nil {
+			r.inflight.drop(next.ID)
+			r.results.remove(next.ID)
+			r.logf(out.LevelWarn, next.Task, "could not queue %s future %s after its partition was released: %s", next.Task, next.ID, err)
+		}
+	}()
+}
@@ -0,0 +1,77 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionKey(t *testing.T) {
+	// Futures of each user are handled in order by many workers, and the first future of
+	// each user fails once so that it is retried before the rest of its partition
+	var (
+		mu       sync.Mutex
+		handled  = make(map[string][]string)
+		running  = make(map[string]bool)
+		failed   = make(map[string]bool)
+		overlaps int
+	)
+
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "event", onHandle: func(id uuid.UUID, params []byte) error {
+		user := strings.Split(string(params), ":")[0]
+		mu.Lock()
+		if running[user] {
+			overlaps++
+		}
+		running[user] = true
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		running[user] = false
+		if strings.HasSuffix(string(params), ":0") && !failed[user] {
+			failed[user] = true
+			return errors.New("try again")
+		}
+		handled[user] = append(handled[user], string(params))
+		return nil
+	}}
+
+	conf := &Config{Workers: 8, NoSignals: true, SuppressMetrics: true, LogLevel: "warn", Retry: RetryPolicy{MaxAttempts: 2, BaseDelay: 5 * time.Millisecond}}
+	queue, err := New(conf, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	users := []string{"alice", "bob", "carol"}
+	wg.Add(len(users) * 20)
+	for i := 0; i < 20; i++ {
+		for _, user := range users {
+			ctx := WithPartitionKey(context.Background(), user)
+			_, err = queue.DelayContext(ctx, "event", []byte(fmt.Sprintf("%s:%d", user, i)), nil, nil)
+			require.NoError(t, err)
+		}
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Zero(t, overlaps, "futures with the same partition key were handled concurrently")
+	for _, user := range users {
+		require.Len(t, handled[user], 20)
+		for i, params := range handled[user] {
+			require.Equal(t, fmt.Sprintf("%s:%d", user, i), params)
+		}
+	}
+	require.Equal(t, "", PartitionKeyFrom(context.Background()))
+}
@@ -0,0 +1,137 @@
+package radish
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kansaslabs/x/out"
+)
+
+// errWorkerPaused is returned by a worker's dequeue when the queue is paused so that the
+// worker waits until the queue is resumed instead of logging a dequeue failure.
+var errWorkerPaused = errors.New("queue has been paused")
+
+// Pause the workers so that they stop dequeuing futures, e.g. during a maintenance window
+// of a downstream dependency. Tasks can still be delayed while the queue is paused and
+// remain in the queue until it is resumed; futures that are in flight when the queue is
+// paused are completed. Note that a paused queue cannot be drained. Pausing a queue that
+// is already paused has no effect.
+func (r *Radish) Pause() error {
+	r.Lock()
+	defer r.Unlock()
+
+	select {
+	case <-r.shutdown:
+		return Errorf(CodeShutdown, "queue has been shutdown")
+	default:
+	}
+
+	if r.resumed != nil {
+		return nil
+	}
+
+	r.resumed = make(chan struct{})
+	close(r.paused)
+	pmPaused.Set(1)
+	r.logf(out.LevelStatus, "", "paused %d workers with %d tasks in the queue", len(r.workers), r.tasks.Len())
+	return nil
+}
+
+// Resume the workers of a paused queue so that they continue handling the futures in
+// the queue. Resuming a queue that is not paused has no effect.
+func (r *Radish) Resume() error {
+	r.Lock()
+	defer r.Unlock()
+
+	select {
+	case <-r.shutdown:
+		return Errorf(CodeShutdown, "queue has been shutdown")
+	default:
+	}
+
+	if r.resumed == nil {
+		return nil
+	}
+
+	close(r.resumed)
+	r.resumed = nil
+	r.paused = make(chan struct{})
+	pmPaused.Set(0)
+	r.logf(out.LevelStatus, "", "resumed %d workers with %d tasks in the queue", len(r.workers), r.tasks.Len())
+	return nil
+}
+
+// Paused returns true if the workers have been paused.
+func (r *Radish) Paused() bool {
+	r.RLock()
+	defer r.RUnlock()
+	return r.resumed != nil
+}
+
+// pauseState returns a channel that is closed when the queue is paused and, if the queue
+// is already paused, a channel that is closed when it is resumed.
+func (r *Radish) pauseState() (paused, resumed chan struct{}) {
+	r.RLock()
+	defer r.RUnlock()
+	return r.paused, r.resumed
+}
+
+// dequeue the next future, interrupting the dequeue if the queue is paused. If the queue
+// is paused, dequeue blocks until the queue is resumed or the worker is stopped and then
+// returns errWorkerPaused.
+func (w *worker) dequeue() (*Future, error) {
+	paused, resumed := w.parent.pauseState()
+	if resumed != nil {
+		select {
+		case <-resumed:
+		case <-w.ctx.Done():
+		}
+		return nil, errWorkerPaused
+	}
+
+	ctx, cancel := context.WithCancel(w.ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-paused:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	task, err := w.parent.tasks.Dequeue(ctx)
+	if err != nil && w.ctx.Err() == nil && ctx.Err() != nil {
+		return nil, errWorkerPaused
+	}
+
+	// A future may be dequeued after the queue is paused but before the dequeue is
+	// interrupted; hold it until the queue is resumed. If the worker is stopped while it
+	// holds the future, the future is put back on the queue for another worker.
+	if err == nil {
+		select {
+		case <-paused:
+			if _, resumed = w.parent.pauseState(); resumed != nil {
+				select {
+				case <-resumed:
+				case <-w.ctx.Done():
+					w.parent.unhold(task)
+					return nil, errWorkerPaused
+				}
+			}
+		default:
+		}
+	}
+	return task, err
+}
+
+// unhold puts a future that was held by a worker while the queue was paused back on the
+// queue once the worker is stopped. The future is only acknowledged if it was queued so
+// that brokers with delivery guarantees redeliver it otherwise.
+func (r *Radish) unhold(future *Future) {
+	if err := r.enqueue(context.Background(), future); err != nil {
+		r.logf(out.LevelWarn, future.Task, "could not requeue %s future %s held while paused: %s", future.Task, future.ID, err)
+		return
+	}
+	r.ack(future)
+}
@@ -0,0 +1,113 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPause(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	blocking := &testTask{wg: wg, name: "blocking", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+	task := &testTask{wg: wg, name: "paused"}
+
+	queue, err := New(&Config{Workers: 2, NoSignals: true, LogLevel: "warn"}, blocking, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+	require.False(t, queue.Paused())
+
+	// Futures that are in flight when the queue is paused are completed
+	wg.Add(1)
+	_, err = queue.Delay(blocking.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	require.NoError(t, queue.Pause())
+	require.NoError(t, queue.Pause())
+	require.True(t, queue.Paused())
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), blocking.successes)
+
+	// Futures can be delayed while the queue is paused but are not handled
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		_, err = queue.Delay(task.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(0), atomic.LoadInt32(&task.handled))
+	require.Equal(t, 2, queue.NumWorkers())
+
+	// The futures in the queue are handled once the queue is resumed
+	require.NoError(t, queue.Resume())
+	require.NoError(t, queue.Resume())
+	require.False(t, queue.Paused())
+	wg.Wait()
+	require.Equal(t, int32(3), task.successes)
+
+	// The queue cannot be paused after it has been shutdown
+	require.NoError(t, queue.Shutdown())
+	require.EqualError(t, queue.Pause(), "[10] queue has been shutdown")
+}
+
+func TestPauseStopHeld(t *testing.T) {
+	var handled int32
+	task := &testTask{name: "held", onHandle: func(id uuid.UUID, params []byte) error {
+		atomic.AddInt32(&handled, 1)
+		return nil
+	}}
+
+	broker := &lateBroker{Broker: NewMemoryBroker(10), late: make(chan *Future, 1), waiting: make(chan struct{}, 1)}
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "silent", Broker: broker}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// The future is dequeued as the queue is paused, so the worker holds it
+	<-broker.waiting
+	broker.late <- &Future{ID: uuid.NewRandom(), Task: "held"}
+	require.NoError(t, queue.Pause())
+
+	// A worker that is stopped while it holds a future puts it back on the queue
+	require.NoError(t, queue.SetWorkers(0))
+	require.Zero(t, atomic.LoadInt32(&handled))
+	require.Equal(t, 1, broker.Len())
+}
+
+// lateBroker only returns from Dequeue once the dequeue is interrupted, returning a late
+// future if there is one as though it arrived just as the dequeue was interrupted.
+type lateBroker struct {
+	Broker
+	late    chan *Future
+	waiting chan struct{}
+}
+
+func (b *lateBroker) Dequeue(ctx context.Context) (*Future, error) {
+	select {
+	case b.waiting <- struct{}{}:
+	default:
+	}
+
+	<-ctx.Done()
+	select {
+	case future := <-b.late:
+		return future, nil
+	default:
+		return b.Broker.Dequeue(ctx)
+	}
+}
@@ -0,0 +1,216 @@
+package radish
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/pborman/uuid"
+)
+
+// errQueueShutdown is returned when the queue is shutdown before the future could be
+// added to the broker.
+var errQueueShutdown = errors.New("queue has been shutdown")
+
+// Queue orders that determine which of the futures with the same priority is dequeued
+// first by the memory broker.
+const (
+	OrderFIFO = "fifo" // the future that was queued first is handled first
+	OrderLIFO = "lifo" // the future that was queued last is handled first
+)
+
+// NewMemoryBroker returns the default Broker, a bounded in-memory priority queue of the
+// specified size. Futures with a higher priority are dequeued first and futures with
+// the same priority are dequeued in the order they were enqueued.
+func NewMemoryBroker(size int) Broker {
+	return newTaskQueue(size, false)
+}
+
+// NewLIFOMemoryBroker returns a bounded in-memory priority queue of the specified size
+// that dequeues the futures with the same priority newest first, e.g. for cache refreshes
+// where the freshest futures matter most. Futures with a higher priority are still
+// dequeued first.
+func NewLIFOMemoryBroker(size int) Broker {
+	return newTaskQueue(size, true)
+}
+
+// taskQueue is a bounded priority queue of futures that workers operate on. Like a
+// buffered channel, Enqueue blocks while the queue is full and Dequeue blocks while the
+// queue is empty; the slots and items semaphores allow both to be canceled by a context.
+type taskQueue struct {
+	sync.Mutex
+	futures futureHeap
+	fair    *fairQueues   // interleaves the futures of each task instead of the heap, if not nil
+	seq     uint64        // the order futures were pushed, to break priority ties
+	slots   chan struct{} // holds a token for every future in the queue, blocks when full
+	items   chan struct{} // holds a token for every future that can be popped
+}
+
+func newTaskQueue(size int, lifo bool) *taskQueue {
+	return &taskQueue{
+		futures: futureHeap{lifo: lifo, items: make([]*queued, 0, size)},
+		slots:   make(chan struct{}, size),
+		items:   make(chan struct{}, size),
+	}
+}
+
+// newFairTaskQueue returns a task queue that interleaves the futures of each task by the
+// weights of the tasks (see fairQueues).
+func newFairTaskQueue(size int, weights map[string]int, lifo bool) *taskQueue {
+	q := newTaskQueue(size, lifo)
+	q.fair = newFairQueues(weights, lifo)
+	return q
+}
+
+// Enqueue the future, blocking until there is room in the queue or the context is done.
+// The future is added if there is room even if the context is done.
+func (q *taskQueue) Enqueue(ctx context.Context, future *Future) error {
+	select {
+	case q.slots <- struct{}{}:
+	default:
+		select {
+		case q.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	q.Lock()
+	if q.fair != nil {
+		q.fair.push(&queued{future: future, seq: q.seq})
+	} else {
+		heap.Push(&q.futures, &queued{future: future, seq: q.seq})
+	}
+	q.seq++
+	q.Unlock()
+
+	q.items <- struct{}{}
+	return nil
+}
+
+// Dequeue the highest priority future, blocking until a future is available or the
+// context is done. Available futures are returned even if the context is done.
+func (q *taskQueue) Dequeue(ctx context.Context) (*Future, error) {
+	select {
+	case <-q.items:
+		return q.take(), nil
+	default:
+	}
+
+	select {
+	case <-q.items:
+		return q.take(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// dequeueOldest removes the future that was enqueued first, regardless of its priority,
+// blocking until a future is available or the context is done like Dequeue. It is used
+// to drop the oldest future from a full LIFO queue.
+func (q *taskQueue) dequeueOldest(ctx context.Context) (*Future, error) {
+	select {
+	case <-q.items:
+		return q.takeOldest(), nil
+	default:
+	}
+
+	select {
+	case <-q.items:
+		return q.takeOldest(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Ack is a no-op since futures are removed from the queue when they are dequeued.
+func (q *taskQueue) Ack(id uuid.UUID) error {
+	return nil
+}
+
+// Len returns the number of futures in the queue.
+func (q *taskQueue) Len() int {
+	q.Lock()
+	defer q.Unlock()
+	if q.fair != nil {
+		return q.fair.len()
+	}
+	return len(q.futures.items)
+}
+
+// take the highest priority future off of the heap, or the next future of the fair
+// queues, once an item token is held.
+func (q *taskQueue) take() *Future {
+	var item *queued
+	q.Lock()
+	if q.fair != nil {
+		item = q.fair.pop()
+	} else {
+		item = heap.Pop(&q.futures).(*queued)
+	}
+	q.Unlock()
+
+	<-q.slots
+	return item.future
+}
+
+// takeOldest takes the future with the lowest sequence off of the heap once an item token
+// is held.
+func (q *taskQueue) takeOldest() *Future {
+	var item *queued
+	q.Lock()
+	if q.fair != nil {
+		item = q.fair.popOldest()
+	} else {
+		oldest := 0
+		for i, item := range q.futures.items {
+			if item.seq < q.futures.items[oldest].seq {
+				oldest = i
+			}
+		}
+		item = heap.Remove(&q.futures, oldest).(*queued)
+	}
+	q.Unlock()
+
+	<-q.slots
+	return item.future
+}
+
+// queued is a future in the task queue.
+type queued struct {
+	future *Future
+	seq    uint64
+}
+
+// futureHeap implements heap.Interface, ordering futures by priority then by sequence,
+// oldest first unless the heap is LIFO.
+type futureHeap struct {
+	lifo  bool
+	items []*queued
+}
+
+func (h futureHeap) Len() int { return len(h.items) }
+
+func (h futureHeap) Less(i, j int) bool {
+	if h.items[i].future.Priority != h.items[j].future.Priority {
+		return h.items[i].future.Priority > h.items[j].future.Priority
+	}
+	if h.lifo {
+		return h.items[i].seq > h.items[j].seq
+	}
+	return h.items[i].seq < h.items[j].seq
+}
+
+func (h futureHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *futureHeap) Push(x interface{}) { h.items = append(h.items, x.(*queued)) }
+
+func (h *futureHeap) Pop() interface{} {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	h.items = old[:n-1]
+	return item
+}
@@ -0,0 +1,113 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriority(t *testing.T) {
+	// Block the only worker so that futures accumulate in the queue
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blocker := &testTask{wg: new(sync.WaitGroup), name: "blocker", onHandle: func(id uuid.UUID, params []byte) error {
+		close(started)
+		<-release
+		return nil
+	}}
+	blocker.wg.Add(1)
+
+	var mu sync.Mutex
+	var order []string
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "ordered", onHandle: func(id uuid.UUID, params []byte) error {
+		mu.Lock()
+		order = append(order, string(params))
+		mu.Unlock()
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true}, blocker, task)
+	require.NoError(t, err)
+
+	_, err = queue.Delay("blocker", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	wg.Add(5)
+	ctx := context.Background()
+	_, err = queue.DelayContext(ctx, "ordered", []byte("normal1"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.DelayContext(WithPriority(ctx, -1), "ordered", []byte("low"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.DelayContext(WithPriority(ctx, 10), "ordered", []byte("urgent"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.DelayContext(ctx, "ordered", []byte("normal2"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Queue(ctx, &api.QueueRequest{Task: "ordered", Params: []byte("high"), Priority: 5})
+	require.NoError(t, err)
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, []string{"urgent", "high", "normal1", "normal2", "low"}, order)
+}
+
+func TestQueueOrder(t *testing.T) {
+	// Block the only worker so that futures accumulate in the queue
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blocker := &testTask{wg: new(sync.WaitGroup), name: "blocker", onHandle: func(id uuid.UUID, params []byte) error {
+		close(started)
+		<-release
+		return nil
+	}}
+	blocker.wg.Add(1)
+
+	var mu sync.Mutex
+	var order []string
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "ordered", onHandle: func(id uuid.UUID, params []byte) error {
+		mu.Lock()
+		order = append(order, string(params))
+		mu.Unlock()
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, QueueSize: 4, QueueOrder: "LIFO", FullPolicy: FullDropOldest, NoSignals: true, LogLevel: "silent"}, blocker, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	_, err = queue.Delay("blocker", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	// The newest futures are handled first, after futures with a higher priority, and
+	// the oldest future is dropped when the queue is full
+	wg.Add(5)
+	ctx := context.Background()
+	for _, params := range []string{"first", "second", "third"} {
+		_, err = queue.DelayContext(ctx, "ordered", []byte(params), nil, nil)
+		require.NoError(t, err)
+	}
+	_, err = queue.DelayContext(WithPriority(ctx, 10), "ordered", []byte("urgent"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.DelayContext(ctx, "ordered", []byte("fourth"), nil, nil)
+	require.NoError(t, err)
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, []string{"urgent", "fourth", "third", "second"}, order)
+	require.Equal(t, int32(1), atomic.LoadInt32(&task.failures))
+
+	// Only the memory broker supports the lifo order
+	_, err = New(&Config{QueueOrder: "random"})
+	require.EqualError(t, err, `[1] "random" is an invalid queue order, use fifo or lifo`)
+	_, err = New(&Config{QueueOrder: OrderLIFO, Broker: NewMemoryBroker(10)})
+	require.EqualError(t, err, "[1] the lifo queue order requires the memory broker")
+}
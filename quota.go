@@ -0,0 +1,162 @@
+package radish
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientMetadataKey is the gRPC metadata key that clients can use to identify themselves
+// for per-client quotas. If not specified, the client is identified by its IP address.
+const ClientMetadataKey = "radish-client"
+
+// quota counts the number of pending futures by key (e.g. client or task name) and
+// enforces a maximum number of pending futures per key.
+type quota struct {
+	sync.Mutex
+	pending map[string]int
+	latest  map[string]uuid.UUID
+}
+
+func newQuota() *quota {
+	return &quota{pending: make(map[string]int), latest: make(map[string]uuid.UUID)}
+}
+
+// acquire a slot for the future with the specified id, returning false and the id of
+// the most recently acquired future for the key if the key already has limit pending
+// futures. A limit <= 0 means the key is not limited.
+func (q *quota) acquire(key string, id uuid.UUID, limit int) (uuid.UUID, bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if limit > 0 && q.pending[key] >= limit {
+		return q.latest[key], false
+	}
+	q.pending[key]++
+	q.latest[key] = id
+	return id, true
+}
+
+// release a slot for the key once a future is no longer pending.
+func (q *quota) release(key string) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.pending[key] <= 1 {
+		delete(q.pending, key)
+		delete(q.latest, key)
+		return
+	}
+	q.pending[key]--
+}
+
+// count returns the number of pending futures for the key.
+func (q *quota) count(key string) int {
+	q.Lock()
+	defer q.Unlock()
+	return q.pending[key]
+}
+
+// clientQuota returns the maximum number of pending futures for the client.
+func (c *Config) clientQuota(client string) int {
+	if limit, ok := c.ClientQuotas[client]; ok {
+		return limit
+	}
+	return c.ClientQuota
+}
+
+// acquireClientQuota for the future if it was queued by an API client.
+func (r *Radish) acquireClientQuota(future *Future) error {
+	if future.client == "" {
+		return nil
+	}
+
+	limit := r.clientQuota(future.client)
+	if _, ok := r.clients.acquire(future.client, future.ID, limit); !ok {
+		return Errorf(CodeQuotaExceeded, "client %q has reached its quota of %d pending futures", future.client, limit)
+	}
+	return nil
+}
+
+// releaseClientQuota once the future is no longer pending.
+func (r *Radish) releaseClientQuota(future *Future) {
+	if future.client != "" {
+		r.clients.release(future.client)
+	}
+}
+
+// taskQuota returns the maximum number of queued futures for the task.
+func (c *Config) taskQuota(task string) int {
+	if limit, ok := c.TaskQuotas[task]; ok {
+		return limit
+	}
+	return c.TaskQuota
+}
+
+// acquireTaskQuota for the future before it is queued. If the task has reached its quota
+// and CoalesceTasks is set, the id of the most recently queued future of the task is
+// returned so that the caller can coalesce the new future into it rather than queueing.
+func (r *Radish) acquireTaskQuota(future *Future) (coalesced uuid.UUID, err error) {
+	limit := r.taskQuota(future.Task)
+	latest, ok := r.queued.acquire(future.Task, future.ID, limit)
+	if ok {
+		return nil, nil
+	}
+
+	if r.config.CoalesceTasks && latest != nil {
+		r.logf(out.LevelDebug, future.Task, "%s task has reached its quota of %d queued futures, coalescing into %s", future.Task, limit, latest)
+		return latest, nil
+	}
+	return nil, Errorf(CodeQuotaExceeded, "task %q has reached its quota of %d queued futures", future.Task, limit)
+}
+
+// releaseTaskQuota once the future is no longer queued.
+func (r *Radish) releaseTaskQuota(future *Future) {
+	r.queued.release(future.Task)
+}
+
+// releaseQuotas held by the future once it has been dequeued or could not be queued.
+func (r *Radish) releaseQuotas(future *Future) {
+	r.releaseTaskQuota(future)
+	r.releaseClientQuota(future)
+}
+
+// clientIdentity returns the identity of the API client that was authenticated by its
+// API key, or from the gRPC metadata if specified, otherwise the IP address of the peer
+// that made the request.
+func clientIdentity(ctx context.Context) string {
+	if client, ok := ctx.Value(authKey).(string); ok && client != "" {
+		return client
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(ClientMetadataKey); len(vals) > 0 && vals[0] != "" {
+			return vals[0]
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return "unknown"
+}
+
+// withClient sets the API client identity on the context so that it is recorded on the
+// future when it is delayed and counted against the client's quota.
+func withClient(parent context.Context, client string) context.Context {
+	return context.WithValue(parent, clientKey, client)
+}
+
+// clientFrom returns the API client identity on the context, if any.
+func clientFrom(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey).(string)
+	return client
+}
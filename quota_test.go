@@ -0,0 +1,158 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestClientQuotas(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(5)
+
+	// Block the worker so that queued futures remain pending
+	started := make(chan struct{}, 5)
+	release := make(chan struct{})
+	task := &testTask{wg: wg, name: "quota", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	conf := &Config{Workers: 1, NoSignals: true, ClientQuota: 2, ClientQuotas: map[string]int{"vip": 0}}
+	queue, err := New(conf, task)
+	require.NoError(t, err)
+
+	teamA := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ClientMetadataKey, "teamA"))
+	teamB := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ClientMetadataKey, "teamB"))
+	vip := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ClientMetadataKey, "vip"))
+	req := &api.QueueRequest{Task: task.Name()}
+
+	// The first future is dequeued by the worker and no longer counts as pending
+	rep, err := queue.Queue(teamA, req)
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	<-started
+
+	// teamA can have two pending futures
+	for i := 0; i < 2; i++ {
+		rep, err = queue.Queue(teamA, req)
+		require.NoError(t, err)
+		require.True(t, rep.Success)
+	}
+
+	rep, err = queue.Queue(teamA, req)
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, `[11] client "teamA" has reached its quota of 2 pending futures`, rep.Error.Error())
+
+	// Other clients are not affected by teamA's backlog
+	rep, err = queue.Queue(teamB, req)
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+
+	// Overrides can remove the quota for a client
+	rep, err = queue.Queue(vip, req)
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestTaskQuotas(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(5)
+
+	// Block the worker so that queued futures remain in the queue
+	started := make(chan struct{}, 5)
+	release := make(chan struct{})
+	onHandle := func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+
+	runaway := &testTask{wg: wg, name: "runaway", onHandle: onHandle}
+	other := &testTask{wg: wg, name: "other", onHandle: onHandle}
+	refresh := &testTask{wg: wg, name: "refresh", onHandle: onHandle}
+
+	conf := &Config{Workers: 1, NoSignals: true, TaskQuota: 2, TaskQuotas: map[string]int{"refresh": 1}}
+	queue, err := New(conf, runaway, other, refresh)
+	require.NoError(t, err)
+
+	// The first future is dequeued by the worker and no longer counts against the quota
+	_, err = queue.Delay(runaway.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	for i := 0; i < 2; i++ {
+		_, err = queue.Delay(runaway.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	_, err = queue.Delay(runaway.Name(), nil, nil, nil)
+	require.EqualError(t, err, `[11] task "runaway" has reached its quota of 2 queued futures`)
+	require.True(t, errors.Is(err, ErrQuotaExceeded))
+
+	// The Queue RPC replies with the same error
+	rep, err := queue.Queue(context.Background(), &api.QueueRequest{Task: runaway.Name()})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.True(t, errors.Is(rep.Error, ErrQuotaExceeded))
+
+	// Other tasks keep flowing
+	_, err = queue.Delay(other.Name(), nil, nil, nil)
+	require.NoError(t, err)
+
+	// Overrides can set a different quota for a task
+	_, err = queue.Delay(refresh.Name(), nil, nil, nil)
+	require.NoError(t, err)
+
+	_, err = queue.Delay(refresh.Name(), nil, nil, nil)
+	require.EqualError(t, err, `[11] task "refresh" has reached its quota of 1 queued futures`)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestCoalesceTasks(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	task := &testTask{wg: wg, name: "refresh", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, TaskQuota: 1, CoalesceTasks: true}, task)
+	require.NoError(t, err)
+
+	_, err = queue.Delay(task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	// Futures beyond the quota are coalesced into the queued future
+	queued, err := queue.Delay(task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		id, err := queue.Delay(task.Name(), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, queued, id)
+	}
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(2), task.handled)
+}
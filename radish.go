@@ -43,6 +43,82 @@ callbacks take parameters that are specific to those methods and must be provide
 the task being queued. The Failure method will additionally be passed the error that
 caused the task to fail.
 
+Tasks that fan out work, e.g. splitting a file into chunks that are processed
+separately, can enqueue child futures from their Handle method using Spawn with their
+own future id as the parent. If the task also implements ChildAwaiter, its Success or
+Failure callback is deferred until all of its children have completed so that the job
+is tracked as one logical unit; if any child fails, the parent fails as well.
+
+Jobs made up of several tasks with dependencies between them can be run as a Workflow,
+a directed acyclic graph of steps. RunWorkflow queues the steps without prerequisites and
+every other step is queued once all of the steps it depends on have succeeded; if a step
+fails, the steps that depend on it are canceled. The state of the workflow and its steps
+can be queried by its id with the GetWorkflow RPC until the ResultTTL after it completes.
+To fan out work and fan the results back in, DelayGroup queues a Group of futures that
+are handled in parallel; once every member has completed, the OnComplete function of the
+group is called with the aggregated result of its members and, if a Chord task is
+specified, a future of the chord is queued with the serialized result as its params.
+
+Tasks can be scheduled to run later using DelayAt or DelayAfter (or the eta and delay
+fields of a queue request). The id of the future is returned immediately and the future
+is added to the queue once it is due; scheduled futures can be canceled before they are
+due but are dropped if the queue is shutdown first (unless storage is durable).
+
+The lifecycle state of every future (scheduled, queued, running, succeeded, failed, or
+canceled) is tracked in memory so that callers can find out what happened to a task they
+delayed with GetFuture (or the GetFuture RPC). Completed futures are kept for the
+ResultTTL and at most MaxRecords records are kept, evicting the oldest completed first.
+Handlers can log with Logf and the context passed to them; if FutureLogLines is set, the
+most recent lines are kept on the record of the future and returned by GetFuture.
+
+Tasks that produce a result can implement ResultTask; workers call HandleResult instead
+of Handle and the result is stored with the record of the future. Callers can block for
+the outcome of a delayed task with Wait (or the Result RPC), which returns the result if
+the task succeeded or the error that caused it to fail:
+
+	id, err := queue.Delay("resize", []byte("cat.png"), nil, nil)
+	thumbnail, err := queue.Wait(ctx, id)
+
+Tasks that implement BatchTask handle many futures with one call to HandleBatch. Workers
+coalesce up to BatchSize futures of the task that are dequeued within the BatchWait into
+a batch, and each future succeeds, fails, or is retried with its own error.
+
+External systems can observe the queue in real time with the Watch RPC, which streams
+an event every time a future is queued or scheduled, started, or completes, with its
+task, id, attempts, and timing. Events can be filtered by task and by state.
+
+Futures are handled in the order they were queued unless they are given a priority with
+WithPriority on the context passed to DelayContext (or with the priority field of a queue
+request). Workers handle futures with a higher priority first, and futures with the same
+priority in the order they were queued.
+
+To ensure that a task is only queued once, e.g. when producers retry requests, specify an
+idempotency key with WithIdempotencyKey (or the idempotency_key field of a queue request).
+If a future of the same task was delayed with the same key and has not completed, or its
+record has not been evicted after the ResultTTL, the id of the existing future is returned
+instead of queueing a duplicate.
+
+Futures with the same partition key, specified with WithPartitionKey (or the partition_key
+field of a queue request), are handled serially in the order they were queued, which
+orders the futures of one entity, e.g. the events of a user, across many workers.
+Futures with different keys are still handled concurrently.
+
+Failed futures can be retried automatically by specifying a RetryPolicy as the Retry
+option in the config. A failed future is re-enqueued after an exponential backoff until
+it has been attempted MaxAttempts times; the Failure callback is only called after the
+final attempt. Tasks can override the policy in the config by implementing RetryTask.
+
+Futures that fail permanently, because they failed on their final attempt, their handler
+panicked, or their task was not registered when they were dequeued, are moved to a
+bounded dead letter queue rather than being dropped. Dead letters can be inspected with
+DeadLetters, enqueued again with Redrive, or removed with Purge.
+
+Futures that are queued or in flight can be canceled with Cancel. Canceled futures that
+are still in the queue are not handled; to abort futures that are already being handled,
+tasks implement ContextTask, whose HandleContext method is passed a context that is
+canceled when the future is canceled. The Failure callback of a canceled future is passed
+an ErrCanceled error and the future is recorded as canceled rather than failed.
+
 Radish Quick Start
 
 Once we have defined our custom task handlers, we can register them and begin delaying
@@ -60,6 +136,53 @@ id of the future of the task (which you can use for book keeping in success or f
 In this example, the tasks are submited with an email and an address, but no parameters
 for success or failure handling.
 
+Delay blocks if the task queue is full. To give up after a deadline or when a request is
+canceled, use DelayContext instead. Metadata attached to the context with WithMetadata
+(e.g. a trace id) is copied onto the future:
+
+	ctx = radish.WithMetadata(ctx, "trace", traceID)
+	id, err := queue.DelayContext(ctx, "sendEmail", []byte("jdoe@example.com"), nil, nil)
+
+The metadata of a future is passed to its handler on the context and can be read with
+MetadataFrom rather than decoding out-of-band data from the params; tasks that implement
+CallbackContextTask receive it on the context of their callbacks as well. Clients of the
+gRPC API send the metadata with the QueueRequest.
+
+A W3C traceparent and tracestate in the gRPC metadata of a Queue request (or the headers
+of a gateway request) are copied onto the metadata of the future so that handlers can
+link their work to the trace of the request with TraceParentFrom. Producers that delay
+tasks in process attach a traceparent to the context with WithTraceParent.
+
+The MaxParamSize config option limits the size of the params of a future; Delay, the
+Queue RPC, and the gateway reject larger params with an ErrInvalidParams error. If the
+limit is larger than 4MB, the gRPC message limit and the gateway body limit are raised to
+match.
+
+To never block, use TryDelay, which returns an ErrQueueFull error if the queue is full.
+The FullPolicy config option changes the backpressure behavior of Delay and the gRPC API:
+"block" (the default) waits for room, "block-timeout" waits for up to the FullTimeout,
+"reject" returns an ErrQueueFull error, and "drop-oldest" drops the oldest queued future
+to make room, moving it to the dead letters.
+The reply to a Queue request includes the length of the queue and an estimate of how long
+the future will wait for a worker based on the throughput of the last minute, so that
+producers can back off before the queue is full.
+
+The QueueOrder config option determines which of the futures with the same priority the
+memory broker hands to the workers first: the oldest with "fifo" (the default) or the
+newest with "lifo", e.g. for cache refreshes (see NewLIFOMemoryBroker). With the
+FairScheduling config option, the memory broker interleaves the futures of each task by
+weighted round robin so that a burst of futures of one task does not monopolize the
+workers; each task with queued futures gets a share of the dequeues proportional to its
+weight in TaskWeights.
+
+Errors returned by radish have one of the Code* codes. Match them with errors.Is and the
+sentinel error of the code, e.g. ErrQueueFull, even if they have been wrapped, or get the
+code with CodeOf:
+
+	if errors.Is(err, radish.ErrQueueFull) {
+		// back off and try again later
+	}
+
 Configuring Radish
 
 More detailed configuration and registration is possible with radish. In the quick start
@@ -70,22 +193,134 @@ using the Config object:
 	config := &radish.Config{Workers: 4, QueueSize: 10000}
 	queue, err := radish.New(config)
 
+Services that are configured by their environment can use ConfigFromEnv, which builds a
+validated config from the RADISH_WORKERS, RADISH_QUEUE_SIZE, RADISH_ADDR,
+RADISH_METRICS_ADDR, RADISH_LOG_LEVEL, and related environment variables. Configs can
+also be kept in a YAML file and loaded with LoadConfig, which uses the snake_case names
+of the options as keys with nested retry, autoscale, and callback_tls sections, and
+validates the file the same way as a programmatic config.
+
+By default futures are assigned random (version 4) UUIDs. The IDs config option can
+select a different IDGenerator for sortable ids, e.g. UUIDv7, ULIDs, or NewSnowflake.
+
 The config is validated when it is created and any invalid configurations will return an
 error when the queue is created. We can also manually register tasks with the queue (and
 register tasks at runtime) as follows:
 
 	err := queue.Register(new(SendEmail))
 
-This allows the queue to be dynamic and handle different tasks at different times. It
-is also possible to scale the number of workers at runtime:
+The handler of a registered task can be swapped atomically with Replace, e.g. when a
+plugin is reloaded; futures in flight complete with the old handler.
+
+This allows the queue to be dynamic and handle different tasks at different times. Task
+logic authored in other languages can be compiled to WebAssembly and registered with
+RegisterWASM if a WASMRuntime is specified in the config; modules receive the params on
+stdin, their stdout is the result of the task, and their exit status determines whether
+the task succeeded or failed. Similarly,
+legacy scripts can be driven by the queue using the built-in ExecTask handler:
+
+	err := queue.Register(&radish.ExecTask{TaskName: "resize", Command: "resize.sh"})
+
+The Typed handler decodes the params of each future with the codec of the task (JSON by
+default) into a value of its type parameter before calling a strongly typed handler, and
+DelayTyped encodes typed params with the same codec when delaying a future:
+
+	err := queue.Register(&radish.Typed[Email]{TaskName: "sendEmail", Handler: sendEmail})
+	id, err := radish.DelayTyped(ctx, queue, "sendEmail", Email{To: "jdoe@example.com"}, nil, nil)
+
+The Codec of a task, registered with RegisterCodec or specified by a CodecTask handler,
+encodes the params of DelayValue and decodes them in the handler with DecodeParams, so
+that the serialization conventions of a task live in one place. JSONCodec is the
+default; ProtoCodec and GobCodec are also provided.
+
+Simple transform or notify tasks can also be written as sandboxed Lua scripts that are
+defined in the Scripts config option or registered and updated at runtime with
+RegisterScript or the SetScript RPC without recompiling the server. Running scripts are
+interrupted when their future is canceled or their worker is forced to stop.
+
+Tasks that share a dependency which can only handle a limited number of concurrent
+requests can implement the ResourceConsumer interface; workers acquire a slot from each
+named resource the task consumes, with capacities defined by the Resources config
+option, before handling the task. Similarly, the TaskConcurrency config option limits the
+number of futures of a task that are handled at once across all workers, e.g. for a task
+that calls a rate-limited API; futures beyond the limit wait until a slot frees.
+
+By default futures are only kept in memory and any futures remaining in the queue are
+lost when the process stops. Setting the Storage config option to "bolt" persists every
+future to a local BoltDB file at the StoragePath before it is queued and removes it once
+it has completed; queued, scheduled, and retrying futures are restored when the queue is
+restarted. Payloads are encrypted on disk if an EncryptionKey or Cipher is configured.
+Tasks should be passed to New so they are registered before futures are restored.
+Setting Storage to "wal" instead appends a record of every future to a write-ahead log
+that is synced before the future is queued and replayed on restart; the log is compacted
+every CompactInterval and on shutdown to drop the records of completed futures.
+Futures are only removed from storage once they have been handled, so futures that were
+being handled when the process crashed are redelivered on restart with the Redelivered
+count of the future incremented (at-least-once delivery).
+
+Queued futures are held by a Broker, an in-memory priority queue of QueueSize futures by
+default (see NewMemoryBroker). Alternate backends such as a disk, Redis, or SQS queue can
+be used by implementing the Broker interface and specifying it in the Broker config
+option; workers Ack each future once it has been handled. NewRedisBroker returns a broker
+that holds futures in a Redis list so that multiple radish processes can share a queue;
+futures dequeued by a process that stops before handling them are redelivered when it is
+restarted with the same consumer name. Use a rediss:// url or NewRedisBrokerTLS to connect
+to the Redis server with TLS. NewJetStreamBroker returns a broker that publishes
+futures to a NATS JetStream stream and dequeues them from a durable pull consumer shared
+by every process, which redelivers futures that are not acknowledged within its AckWait.
+NewSQSBroker returns a broker that long polls an Amazon SQS queue, hiding futures for the
+visibility timeout of their task while they are handled and moving futures received more
+than MaxReceives times to a dead letter queue.
+
+ConsumeKafka delays a future for every record read from a KafkaConsumer, which wraps the
+consumer of a Kafka client library, using the task mapped to the topic of the record and
+the value of the record as its params; records are committed once they are queued.
+
+Cross-cutting concerns such as logging, metrics, authorization, or tracing can be added
+to every task with middleware, which wraps the HandlerFunc that handles each future. The
+first middleware added with Use is the outermost and is called first:
+
+	queue.Use(func(next radish.HandlerFunc) radish.HandlerFunc {
+		return func(ctx context.Context, future *radish.Future) error {
+			log.Printf("handling %s future %s", future.Task, future.ID)
+			return next(ctx, future)
+		}
+	})
+
+Hooks registered with OnEvent are called with the lifecycle events of every future as it
+is enqueued, dequeued, started, and succeeds or fails, and whenever the number of workers
+changes, so that applications can implement their own auditing, metrics, or persistence.
+If an AuditLog or Auditor is configured, who queued each future and its final outcome are
+recorded as an append-only audit trail.
+
+It is also possible to scale the number of workers at runtime:
 
 	queue.AddWorkers(8)
 	queue.RemoveWorkers(2)
 	queue.SetWorkers(4)
 	queue.NumWorkers()
 
+Removed workers finish the future they are handling before they stop, so RemoveWorkers
+can block for as long as the longest running task. RemoveWorkersContext and
+SetWorkersContext only wait until the context is done, returning the number of removed
+workers that are still draining, and optionally cancel the futures of those workers.
+
+Instead of scaling the workers manually during bursts, specify an AutoscalePolicy as the
+Autoscale config option to add or remove workers between MinWorkers and MaxWorkers when
+the queue is more than ScaleUp or less than ScaleDown percent full, waiting at least the
+Cooldown between changes. SetAutoscale (or the Autoscale RPC) turns autoscaling on or off
+and changes its bounds at runtime, e.g. to pin the number of workers during an incident.
+
 The queue can also be scaled and tasks delayed using the Radish service.
 
+Many futures can be delayed at once with DelayMany, which returns the id of each future
+and any error that prevented it from being queued in the same order as the specs:
+
+	ids, errs := queue.DelayMany(ctx, []radish.FutureSpec{{Task: "sendEmail", Params: a}, {Task: "sendEmail", Params: b}})
+
+Remote producers batch futures with the QueueBatch RPC or stream them with the Enqueue
+RPC, which acks each queue request in the order it was received over a single stream.
+
 Radish Service
 
 Radish implements a gRPC API so that remote clients can connect and get the queue
@@ -101,17 +336,94 @@ can also manually call:
 	queue.Shutdown()
 
 To gracefully shutdown the queue, completing any tasks that are in flight and not
-accepting new tasks if they run the listener in its own go routine. Applications that
-need to specify their own services using gRPC or http servers can manually run the
-service as follows:
+accepting new tasks if they run the listener in its own go routine. By default, Listen
+shuts down the queue on SIGINT or SIGTERM and calls the OnReload function in the config
+on SIGHUP. The DrainSignals, ShutdownSignals, and ReloadSignals config options specify
+which signals drain the queue before shutting down, shutdown immediately after tasks in
+flight complete, or reload. Applications that handle signals themselves should set
+NoSignals in the config. If a ShutdownGrace is configured and a signal-triggered drain or
+shutdown takes longer, or a second signal is received while shutting down, the futures in
+flight are canceled and Listen returns without waiting for the handlers to stop.
+
+The number of workers, log level, caution threshold, and quotas can be changed without
+restarting the queue using ReloadConfig or the Reload RPC. If a LoadConfig function is
+specified in the config, Listen reloads the config it returns on a reload signal. The queue can also be drained remotely with the DrainQueue RPC,
+which optionally shuts the queue down once it is empty.
+
+Applications can drain the queue without shutting it down by calling Drain, which stops
+accepting new tasks and blocks until the futures in the queue and in flight, including
+futures waiting to be retried, have been handled or the context is done:
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	err := queue.Drain(ctx)
+
+To stop handling tasks temporarily, e.g. during a maintenance window of a downstream
+dependency, call Pause. The workers finish the futures in flight and stop dequeuing, but
+tasks can still be delayed and remain in the queue until Resume is called.
+
+For zero-downtime deploys, enable ReusePort in the config and specify HandoffSignals.
+The new radish process binds to the same address as the old process, then the old
+process is signaled; it stops serving requests and hands off its pending futures to the
+new process before shutting down once its in flight tasks are complete. Applications
+that open their own listener can pass it to Serve, which otherwise behaves like Listen.
+Applications that need to specify their own services using gRPC or http servers can
+register radish on their server with RegisterWith, which also starts the metrics server,
+and must call Shutdown when they stop the server:
 
 	sock, err := net.Listen("tcp", "0.0.0.0:80")
-	srv := grpc.NewSever()
-	api.RegisterRadishServer(srv, queue)
+	srv := grpc.NewServer(queue.ServerOptions()...)
+	queue.RegisterWith(srv)
 	// Register additional gRPC services here
 
 	srv.Serve(sock)
 
+Servers whose addresses are listed in each other's Peers form a cluster: a server with
+more queued futures than idle workers forwards the excess to peers with idle workers
+every PeerInterval, and a Status request with cluster set returns the status of every
+peer of the server. Wait, Cancel, and GetFuture still accept the original ids of
+forwarded futures and pass the request on to the peer.
+
+Workers can also run in other processes on other machines without an external broker:
+a remote worker calls Work on a radish client with its task handlers, which streams the
+futures of those tasks from the server and streams back their results.
+
+When Listen is run under systemd with Type=notify, radish notifies systemd when it is
+ready to receive requests and when it is stopping. If WatchdogSec is set, radish will
+also send watchdog pings as long as its workers are making progress on the queue so
+that systemd can restart a wedged service.
+
+Clients that cannot use gRPC can queue tasks, scale the workers, and check the status of
+the queue with the JSON/HTTP gateway, which Listen serves on the GatewayAddr if it is
+configured, or which applications can mount on their own HTTP server with Gateway:
+
+	$ curl -X POST -d '{"task": "mytask", "params": {"my": "data"}}' http://localhost:8080/v1/queue
+
+To require API clients to authenticate, specify a shared secret as the AuthToken config
+option and/or per-client APIKeys that map each key to the client it is assigned to.
+Clients present the token or key as a bearer token in the "authorization" gRPC metadata
+and unauthenticated requests are rejected. Applications that serve radish on their own
+gRPC server should create it with the options returned by ServerOptions.
+
+In multi-tenant deployments, the ClientQuota config option limits the number of pending
+futures each API client can have in the queue so that one client's backlog cannot consume
+the entire queue. Clients identify themselves with the "radish-client" gRPC metadata key
+or are otherwise identified by their IP address. Similarly, the TaskQuota config option
+limits the number of futures of each task that can wait in the queue at once so that a
+single runaway producer cannot starve other tasks. Futures beyond the quota are rejected,
+or if CoalesceTasks is set, are coalesced into the most recently queued future of the
+task, whose id is returned instead.
+
+Remote producers can be notified when the futures they queue complete by implementing
+the RadishCallback gRPC service and specifying its address in the callback field of the
+queue request (or with WithCallback when using DelayContext). Once the future has been
+handled, radish dials the callback service and delivers the outcome of the future.
+
+Producers can verify their integration against a production queue without queueing
+anything using DryRun (or the dry_run field of a queue request), which checks that the
+task is registered, validates the params, and checks the quota headroom, then describes
+what would have happened to the future.
+
 The radish CLI command can then be used to access the service and submit tasks.
 
 Metrics
@@ -120,11 +432,44 @@ Radish also serves a metrics endpoint that can be polled by Prometheus. Radish k
 track of the following metrics associated with the task queue:
 
 	- radish.workers: A gauge that tracks the number of workers over time as users issue scale requests.
+	- radish.workers_busy: A gauge that tracks the number of workers that are currently handling a future.
+	- radish.workers_idle: A gauge that tracks the number of workers that are waiting for futures to be queued.
+	- radish.paused: A gauge that is 1 while the workers have been paused and 0 otherwise.
+	- radish.tasks_in_flight: A gauge that tracks the number of futures currently being handled, labeled by task name.
 	- radish.queue_size: A gauge that tracks the number of the tasks in the queue currently awaiting handling.
 	- radish.percent_full: A gauge that tracks the relative fullness of the task queue based on the configured queue size.
 	- radish.tasks_succeeded: A counter that tracks the number of tasks that have been handled and succeeded, labeled by task name.
 	- radish.tasks_failed: A counter that tracks the number of tasks that have been handled and failed, labeled by task name.
+	- radish.tasks_canceled: A counter that tracks the number of tasks that were canceled, labeled by task name.
+	- radish.tasks_retried: A counter that tracks the number of failed attempts that were retried, labeled by task name.
 	- radish.task_latency: A histogram that tracks the amount of time it takes to handle the task in milliseconds; labeled by task name and result.
+	- radish.queue_wait: A histogram that tracks the time futures wait in the queue before a worker starts handling them in seconds; labeled by task name.
+	- radish.records_evicted: A counter that tracks the number of completed future records evicted after their TTL expired or because there were too many records.
+	- radish.grpc_requests: A counter that tracks the number of gRPC API requests, labeled by method and status code.
+	- radish.grpc_request_errors: A counter that tracks the number of gRPC API requests that failed or replied with an error, labeled by method.
+	- radish.grpc_request_latency: A histogram that tracks the time it takes to handle gRPC API requests in seconds, labeled by method.
+
+Radish also keeps a short in-memory history of throughput snapshots (the number of
+workers, the queue depth, and the futures of each task that succeeded or failed since the
+previous snapshot), recorded every StatsInterval, which is returned by the StatsHistory
+RPC so that trends can be shown even where Prometheus is not scraping the endpoint. The
+ListTasks RPC returns each registered task with the number of its futures that are
+pending and the totals queued, succeeded, failed, and canceled since the queue started.
+The Status RPC returns a summary of the queue and, optionally filtered by task, the
+sections that are requested: the tasks and their counts (the default), the workers, the
+futures in flight, and the scheduler. The workers section includes the id of each worker,
+the number of futures it has handled, its busy time, and the future it is handling.
+
+The metrics server also serves /healthz and /readyz endpoints for Kubernetes liveness and
+readiness probes. The queue is ready only when it has workers and is accepting tasks, and
+is live until it is shutdown or, if a LivenessTimeout is configured, until its workers
+stop dequeuing futures that are waiting in the queue. The queue size, worker counts, and
+per-task counters are also published as the radish expvar at /debug/vars so that they
+can be read without Prometheus (see PublishVars).
+
+Metrics are only recorded once they are registered by Listen; if SuppressMetrics is set
+(or Listen is not used) the metrics are no-ops so that handling tasks does not incur the
+overhead of observing metrics that nothing scrapes.
 
 Coming soon: If you have your own Prometheus endpoint, you will be able to register
 Radish metrics manually without serving them in Radish.
@@ -152,31 +497,72 @@ server. For example to set the number of workers you can use the scale command:
 
 	$ radish -a localhost:5356 -U scale -w 12
 
-To get the status of the server and the currently registered tasks you can use the
-status command:
+The ping command checks that the server is reachable and implements a compatible
+protocol version of the API before work is queued:
+
+	$ radish -a localhost:5356 -U ping
+
+To get the status of the server, including its version and uptime, the workers and
+futures in flight, the depth and capacity of the queue, and the registered tasks with
+the number of futures of each task that succeeded and failed, use the status command:
 
 	$ radish -a localhost:5356 -U status
 
+Responses are printed as JSON by default; use the --output flag to print them as yaml or
+as a human readable table instead:
+
+	$ radish -a localhost:5356 -U -o table status
+
 Finally, once you know the names of the tasks that the radish server is handling, you
 can queue tasks as follows:
 
 	$ radish -a localhost:5356 -U queue -t mytask -p '{"my": "data"}'
 
+Many tasks can be queued from a file of newline-delimited JSON task specs, e.g. for
+backfills or load tests, printing the ids of the queued futures and any failures:
+
+	$ radish -a localhost:5356 -U queue --file tasks.jsonl
+
+Tasks that were queued by mistake or are stuck can be canceled by id:
+
+	$ radish -a localhost:5356 -U cancel 5c3c1fb8-3e3b-4ad9-8c5a-45bd7a73e6d4
+
+To watch worker activity on the server, view recent log entries and follow new entries
+as they are logged, optionally filtering by level and task:
+
+	$ radish -a localhost:5356 -U logs -f -l debug -t mytask
+
+For a live view of the queue depth, workers, and recently completed tasks that refreshes
+in place, use the watch command:
+
+	$ radish -a localhost:5356 -U watch
+
 The CLI interface is meant to help you get quickly started with Radish task queues
 without having to write your own interfaces or servers.
 */
 package radish
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/kansaslabs/x/out"
 	"github.com/pborman/uuid"
+	"github.com/xeipuuv/gojsonschema"
+	"google.golang.org/grpc"
 )
 
 // PackageVersion of the current Radish implementation
 const PackageVersion = "1.0"
 
+// ProtocolVersion of the radish gRPC API, which is incremented whenever the API changes
+// in a way that is not compatible with older clients.
+const ProtocolVersion = 1
+
 // New creates a Radish object with the specified config and registers the specified
 // task handlers. If the handler cannot be registered or the config is invalid an error
 // is returned.
@@ -192,11 +578,38 @@ func New(config *Config, tasks ...Task) (r *Radish, err error) {
 
 	// Create the radish instance
 	r = &Radish{
-		config:   config,
-		tasks:    make(chan *Future, config.QueueSize),
-		workers:  make([]*worker, 0, config.Workers),
-		handlers: make(map[string]Task),
+		config:      config,
+		tasks:       config.Broker,
+		workers:     make([]*worker, 0, config.Workers),
+		handlers:    make(map[string]Task),
+		batchers:    make(map[string]*batcher),
+		codecs:      make(map[string]Codec),
+		schemas:     make(map[string]*gojsonschema.Schema),
+		shutdown:    make(chan struct{}),
+		paused:      make(chan struct{}),
+		stopped:     make(chan struct{}),
+		clients:     newQuota(),
+		queued:      newQuota(),
+		events:      newEventHub(),
+		logs:        newLogHub(),
+		resources:   newSemaphores(config.Resources),
+		concurrency: newSemaphores(config.TaskConcurrency),
+		callbacks:   newCallbacks(),
+		peers:       newPeers(),
+		forwards:    newForwards(),
+		remotes:     newRemoteWorkers(),
+		stats:       newStats(config.StatsHistory),
+		inflight:    newInflight(),
+		partitions:  newPartitions(),
+		deadLetters: newDeadLetters(config.DeadLetterSize),
+		scheduled:   newSchedule(),
+		created:     time.Now(),
 	}
+	r.results = newRecords(config.ResultTTL, config.MaxRecords, r.events)
+	r.families = newFamilies(r)
+	r.workflows = newWorkflows(r, config.ResultTTL)
+	r.groups = newGroups(r)
+	r.forced, r.force = context.WithCancel(context.Background())
 
 	// Register the tasks on the radish server
 	for _, task := range tasks {
@@ -205,24 +618,123 @@ func New(config *Config, tasks ...Task) (r *Radish, err error) {
 		}
 	}
 
+	// Register any script tasks defined in the config
+	for task, source := range config.Scripts {
+		if err = r.RegisterScript(task, source); err != nil {
+			return nil, err
+		}
+	}
+
+	// Open the audit log that records who queued each future and its outcome
+	if r.auditor, err = openAuditor(config); err != nil {
+		return nil, err
+	}
+
+	// Open the storage that futures are persisted to until they complete
+	if r.store, err = r.openStore(config); err != nil {
+		r.closeAuditLog()
+		return nil, err
+	}
+
 	// Create the workers and start them
 	if err = r.AddWorkers(config.Workers); err != nil {
+		r.abort()
 		return nil, err
 	}
 
+	// Queue any futures that were persisted before the queue was last stopped
+	if err = r.restore(); err != nil {
+		r.abort()
+		return nil, err
+	}
+
+	// Evict expired records of completed futures in the background
+	go r.sweeper()
+
+	// Record periodic throughput snapshots for the stats history
+	go r.collector()
+
+	// Queue scheduled futures when they are due
+	go r.scheduleLoop()
+
+	// Scale the workers based on the queue depth if autoscaling is enabled
+	if config.Autoscale.enabled() {
+		r.autoscaling, r.autoscalerUp = true, true
+		go r.autoscaler(config.Autoscale.Interval)
+	}
+
+	// Forward futures to idle peers if the queue is part of a cluster
+	if len(config.Peers) > 0 {
+		go r.balancer()
+	}
+
 	return r, nil
 }
 
+// abort a queue that could not be created, stopping the workers that were started and
+// closing the storage and the audit log so that New does not leak them.
+func (r *Radish) abort() {
+	close(r.shutdown)
+	r.SetWorkers(0)
+	r.store.close()
+	r.closeAuditLog()
+}
+
 // Radish is a stateless task queue. It listens to requests via the gRPC api to enqueue
 // tasks (or they can be enqueued directly in code) and manages workers to handle each
 // task in the order they are received. Before running the server, tasks must be
 // registered so that the Radish queue knows how to handle them.
 type Radish struct {
-	sync.RWMutex                 // server concurrency control for both workers and registration
-	config       *Config         // the radish configuration
-	tasks        chan *Future    // the task queue that workers are operating on
-	workers      []*worker       // the workers that are currently operating on the queue
-	handlers     map[string]Task // all currently registered tasks the server can handle
+	dequeued     int64                           // unix nanoseconds of the last dequeue, must be first for atomic alignment
+	busy         int64                           // the number of workers handling a future, accessed atomically
+	batched      int64                           // the number of futures waiting in the batchers of batch tasks, accessed atomically
+	sync.RWMutex                                 // server concurrency control for both workers and registration
+	config       *Config                         // the radish configuration
+	tasks        Broker                          // the broker that holds the futures that workers are operating on
+	workers      []*worker                       // the workers that are currently operating on the queue
+	handlers     map[string]Task                 // all currently registered tasks the server can handle
+	middleware   []Middleware                    // wraps the handling of every future, outermost first
+	schemas      map[string]*gojsonschema.Schema // json schemas to validate params against, by task name
+	srv          *grpc.Server                    // the gRPC server started by Listen, stopped on shutdown
+	gateway      *http.Server                    // the JSON/HTTP gateway started by Listen, closed on shutdown
+	draining     bool                            // if the queue is draining, no new tasks are accepted
+	paused       chan struct{}                   // closed when the workers are paused, replaced when resumed
+	resumed      chan struct{}                   // closed when the workers are resumed, nil if not paused
+	shutdown     chan struct{}                   // closed when the queue begins to shutdown
+	stopped      chan struct{}                   // closed when the queue has finished shutting down
+	forced       context.Context                 // done when the queue is force stopped after the shutdown grace period
+	force        context.CancelFunc              // force stops the queue, canceling the forced context
+	created      time.Time                       // when the queue was created, reported as its uptime
+	clients      *quota                          // the number of pending futures queued by each API client
+	queued       *quota                          // the number of futures of each task waiting in the queue
+	results      *records                        // records of the state of futures, evicted after the result TTL once completed
+	events       *eventHub                       // subscribers of the Watch RPC to the lifecycle events of futures
+	hooks        eventHooks                      // hooks registered with OnEvent
+	metricsOnce  sync.Once                       // starts the metrics server the first time the queue is served
+	reloadMu     sync.RWMutex                    // guards the config options that can be changed by ReloadConfig
+	workerSeq    uint64                          // the id of the last worker added, guarded by the queue lock
+	autoscaling  bool                            // if the autoscaler is scaling the workers, guarded by the reload lock
+	autoscalerUp bool                            // if the autoscaler has been started, guarded by the reload lock
+	logs         *logHub                         // recent log entries and subscribers of the Logs RPC
+	resources    map[string]semaphore            // semaphores limiting concurrent use of named shared resources
+	concurrency  map[string]semaphore            // semaphores limiting the futures of a task handled at once
+	batchMu      sync.Mutex                      // guards the batchers, which are created when a future of a batch task is first dequeued
+	batchers     map[string]*batcher             // coalesces the futures of batch tasks, keyed by task name
+	codecs       map[string]Codec                // the codecs registered for the params of tasks, keyed by task name
+	callbacks    *callbacks                      // connections to the callback services of remote producers
+	peers        *peers                          // connections to the other radish servers of the cluster
+	forwards     *forwards                       // the futures forwarded to peers of the cluster
+	remotes      *remoteWorkers                  // remote worker processes connected by the Work stream
+	auditor      Auditor                         // records who queued each future and its outcome, nil if not audited
+	families     *families                       // children of spawned futures that have not completed, by parent
+	workflows    *workflows                      // the state of workflows that are running or completed recently
+	groups       *groups                         // groups whose members have not all completed, by group id
+	stats        *stats                          // throughput counts and the history of periodic snapshots
+	inflight     *inflight                       // futures that are queued or being handled so they can be canceled
+	partitions   *partitions                     // the queued futures of each partition key in the order they were queued
+	deadLetters  *deadLetters                    // futures that failed permanently
+	scheduled    *schedule                       // futures delayed until a later time that are not yet due
+	store        store                           // persists futures until they complete if storage is durable
 }
 
 // Register a task handler with the Radish task queue.
@@ -232,35 +744,169 @@ func (r *Radish) Register(task Task) (err error) {
 
 	// Check to see if a task with this name has already been registered
 	if _, ok := r.handlers[task.Name()]; ok {
-		return Errorf(ErrTaskAlreadyRegistered, "task named %q has already been registered", task.Name())
+		return Errorf(CodeTaskAlreadyRegistered, "task named %q has already been registered", task.Name())
+	}
+
+	// Ensure the resources consumed by the task have been defined
+	if err = r.checkResources(task); err != nil {
+		return err
+	}
+
+	if err = checkRetryPolicy(task); err != nil {
+		return err
 	}
 
 	r.handlers[task.Name()] = task
-	out.Info("registered task %s", task.Name())
+	r.logf(out.LevelInfo, task.Name(), "registered task %s", task.Name())
 	return nil
 }
 
-// Delay creates a new future and adds it to the task queue if the handler has been registered.
+// Replace atomically swaps the handler of a registered task with a new implementation of
+// the task with the same name, e.g. when a plugin is reloaded or the credentials used by
+// a handler are rotated. Futures that are being handled complete with the old handler,
+// while futures that are dequeued after Replace returns are handled by the new handler.
+// The new handler is checked the same way as by Register; if it is invalid the old
+// handler remains registered.
+func (r *Radish) Replace(task Task) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.handlers[task.Name()]; !ok {
+		return Errorf(CodeTaskNotRegistered, "cannot replace unknown task %q", task.Name())
+	}
+
+	if err = r.checkResources(task); err != nil {
+		return err
+	}
+
+	if err = checkRetryPolicy(task); err != nil {
+		return err
+	}
+
+	r.handlers[task.Name()] = task
+	r.logf(out.LevelInfo, task.Name(), "replaced handler of task %s", task.Name())
+	return nil
+}
+
+// Delay creates a new future and adds it to the task queue if the handler has been
+// registered. Delay blocks if the queue is full, use DelayContext to specify a deadline.
 func (r *Radish) Delay(task string, params, success, failure []byte) (id uuid.UUID, err error) {
+	return r.DelayContext(context.Background(), task, params, success, failure)
+}
+
+// DelayContext creates a new future and adds it to the task queue if the handler has
+// been registered. If the queue is full, DelayContext blocks until there is room in the
+// queue or until the context is canceled or its deadline expires, unless the FullPolicy
+// config option rejects futures or drops the oldest queued future instead. Any metadata
+// attached to the context with WithMetadata is copied onto the future. If the task name
+// is empty and the params are a serialized protobuf Any, the future is routed to the
+// handler that was registered for the message type with RegisterMessage.
+func (r *Radish) DelayContext(ctx context.Context, task string, params, success, failure []byte) (id uuid.UUID, err error) {
+	return r.delay(ctx, task, params, success, failure, r.config.FullPolicy)
+}
+
+// newFuture creates a future with a new ID, copying the future options on the context.
+func (r *Radish) newFuture(ctx context.Context, task string, params, success, failure []byte) *Future {
+	return &Future{
+		ID:             r.config.IDs.NewID(),
+		Task:           task,
+		Params:         params,
+		Success:        success,
+		Failure:        failure,
+		Metadata:       MetadataFrom(ctx),
+		Callback:       callbackFrom(ctx),
+		Parent:         parentFrom(ctx),
+		Workflow:       workflowFrom(ctx).id,
+		Step:           workflowFrom(ctx).step,
+		Group:          groupFrom(ctx).id,
+		Member:         groupFrom(ctx).member,
+		Priority:       PriorityFrom(ctx),
+		IdempotencyKey: IdempotencyKeyFrom(ctx),
+		PartitionKey:   PartitionKeyFrom(ctx),
+		client:         clientFrom(ctx),
+	}
+}
+
+// check that a future of the task with the params can be delayed, returning the name of
+// the task, which is resolved from the params if not specified.
+func (r *Radish) check(ctx context.Context, task string, params []byte) (_ string, err error) {
+	if task == "" {
+		if task, err = anyMessageName(params); err != nil {
+			return "", Errorf(CodeTaskNotRegistered, "could not delay: no task name specified and params are not an any: %s", err)
+		}
+	}
+
 	if _, err = r.Handler(task); err != nil {
-		return nil, Errorf(ErrTaskNotRegistered, "could not delay %s", err)
+		return "", Errorf(CodeTaskNotRegistered, "could not delay %s", err)
+	}
+
+	// Do not accept new tasks if the queue is draining or shutdown
+	if err = r.accepting(); err != nil {
+		return "", err
+	}
+
+	// Reject params that are larger than the maximum param size
+	if max := r.config.MaxParamSize; max > 0 && len(params) > max {
+		return "", Errorf(CodeInvalidParams, "could not delay %s: params are %d bytes, the maximum is %d bytes", task, len(params), max)
+	}
+
+	// Validate the params if the task has a schema associated with it
+	if err = r.validate(task, params); err != nil {
+		return "", err
+	}
+
+	// Do not enqueue the future if the context is already done
+	if err = ctx.Err(); err != nil {
+		return "", Errorf(CodeCanceled, "could not delay %s: %s", task, err)
+	}
+	return task, nil
+}
+
+// DryRun checks if a future of the task with the params would be queued by DelayContext
+// without queueing it, so that producers can safely verify their integration against a
+// production queue. DryRun checks that the handler is registered, validates the params,
+// and checks the quota headroom of the task and of the API client on the context. If the
+// future would be queued, a description of what would happen to it is returned.
+func (r *Radish) DryRun(ctx context.Context, task string, params []byte) (outcome string, err error) {
+	if task, err = r.check(ctx, task, params); err != nil {
+		return "", err
 	}
 
-	// TODO: replace uuid.NewRandom with  uuid.NewUUID?
-	future := &Future{
-		ID:      uuid.NewRandom(),
-		Task:    task,
-		Params:  params,
-		Success: success,
-		Failure: failure,
+	// Check if a future was already delayed with the same idempotency key
+	if key := IdempotencyKeyFrom(ctx); key != "" {
+		if existing := r.results.claimed(task, key); existing != nil {
+			return fmt.Sprintf("would return the existing %s future %s with the same idempotency key", task, existing), nil
+		}
 	}
 
-	r.tasks <- future
+	// Check the quota headroom of the task, which may coalesce the future
+	if limit := r.taskQuota(task); limit > 0 && r.queued.count(task) >= limit {
+		if !r.config.CoalesceTasks {
+			return "", Errorf(CodeQuotaExceeded, "task %q has reached its quota of %d queued futures", task, limit)
+		}
+		return fmt.Sprintf("would coalesce into the latest queued %s future", task), nil
+	}
 
-	// Update the queue size and percent full
-	pmQueueSize.Set(float64(len(r.tasks)))
-	pmPercentFull.Set(float64(len(r.tasks)) / float64(r.config.QueueSize) * 100)
-	return future.ID, nil
+	// Check the quota headroom of the API client
+	if client := clientFrom(ctx); client != "" {
+		if limit := r.clientQuota(client); limit > 0 && r.clients.count(client) >= limit {
+			return "", Errorf(CodeQuotaExceeded, "client %q has reached its quota of %d pending futures", client, limit)
+		}
+	}
+
+	if n := r.tasks.Len(); n >= r.config.QueueSize {
+		switch r.config.FullPolicy {
+		case FullReject:
+			return "", Errorf(CodeQueueFull, "could not delay %s: queue is full", task)
+		case FullDropOldest:
+			return fmt.Sprintf("would drop the oldest queued future from the full queue (%d futures)", n), nil
+		case FullBlockTimeout:
+			return fmt.Sprintf("would wait up to %s for room in the full queue (%d futures)", r.config.FullTimeout, n), nil
+		default:
+			return fmt.Sprintf("would wait for room in the full queue (%d futures)", n), nil
+		}
+	}
+	return fmt.Sprintf("would queue %s future behind %d futures", task, r.tasks.Len()), nil
 }
 
 // SetWorkers to the specified number of workers. Does nothing if n == number of workers
@@ -268,29 +914,45 @@ func (r *Radish) Delay(task string, params, success, failure []byte) (id uuid.UU
 // n > number of workers.
 func (r *Radish) SetWorkers(n int) (err error) {
 	if n < 0 {
-		return Errorf(ErrInvalidWorkers, "cannot set number of workers <0")
+		return Errorf(CodeInvalidWorkers, "cannot set number of workers <0")
 	}
 
 	r.Lock()
-	defer r.Unlock()
+	previous := len(r.workers)
+	stopped, err := r.setWorkers(n)
+	current := len(r.workers)
+	r.Unlock()
+
+	r.hooks.emitScale(previous, current)
+	waitWorkers(stopped)
+	return err
+}
 
+// set workers, not thread-safe, returns any stopped workers that must be waited on
+// after the lock is released.
+func (r *Radish) setWorkers(n int) (stopped []*worker, err error) {
 	nworkers := len(r.workers)
 	if n > nworkers {
-		return r.addWorkers(n - nworkers)
+		return nil, r.addWorkers(n - nworkers)
 	}
 
 	if n < nworkers {
 		return r.removeWorkers(nworkers - n)
 	}
 
-	return nil
+	return nil, nil
 }
 
 // AddWorkers to process tasks. Note that this is thread-safe but does start go routines.
 func (r *Radish) AddWorkers(n int) (err error) {
 	r.Lock()
-	defer r.Unlock()
-	return r.addWorkers(n)
+	previous := len(r.workers)
+	err = r.addWorkers(n)
+	current := len(r.workers)
+	r.Unlock()
+
+	r.hooks.emitScale(previous, current)
+	return err
 }
 
 // add workers, not thread-safe
@@ -298,51 +960,136 @@ func (r *Radish) addWorkers(n int) (err error) {
 	if n == 0 {
 		return nil
 	} else if n < 0 {
-		return Errorf(ErrInvalidWorkers, "cannot add negative workers, use RemoveWorkers")
+		return Errorf(CodeInvalidWorkers, "cannot add negative workers, use RemoveWorkers")
 	}
 
 	for i := 0; i < n; i++ {
-		w := &worker{parent: r, stop: make(chan bool)}
+		r.workerSeq++
+		w := newWorker(r, r.workerSeq)
 		r.workers = append(r.workers, w)
 		go w.run()
 	}
 
-	// Update the workers gauge
-	pmWorkers.Set(float64(len(r.workers)))
+	// Update the worker gauges
+	r.observeWorkers(len(r.workers))
 
-	out.Status("added %d workers -- %d workers running", n, len(r.workers))
+	r.logf(out.LevelStatus, "", "added %d workers -- %d workers running", n, len(r.workers))
 	return nil
 }
 
 // RemoveWorkers by stopping them gracefully after they've completed the given task.
 func (r *Radish) RemoveWorkers(n int) (err error) {
 	r.Lock()
-	defer r.Unlock()
-	return r.removeWorkers(n)
+	previous := len(r.workers)
+	stopped, err := r.removeWorkers(n)
+	current := len(r.workers)
+	r.Unlock()
+
+	r.hooks.emitScale(previous, current)
+	waitWorkers(stopped)
+	return err
 }
 
-// remove workers, not thread-safe
-func (r *Radish) removeWorkers(n int) (err error) {
+// remove workers, not thread-safe, returns the stopped workers so that the caller can
+// wait for them to complete their tasks after releasing the lock; waiting while holding
+// the lock would deadlock workers that need the lock to look up the handler of a task.
+func (r *Radish) removeWorkers(n int) (stopped []*worker, err error) {
 	if n > len(r.workers) {
-		return Errorf(ErrInvalidWorkers, "cannot remove %d workers, only %d currently running", n, len(r.workers))
+		return nil, Errorf(CodeInvalidWorkers, "cannot remove %d workers, only %d currently running", n, len(r.workers))
 	} else if n == 0 {
-		return nil
+		return nil, nil
 	} else if n < 0 {
-		return Errorf(ErrInvalidWorkers, "cannot remove negative workers, use AddWorkers")
+		return nil, Errorf(CodeInvalidWorkers, "cannot remove negative workers, use AddWorkers")
 	}
 
+	stopped = make([]*worker, 0, n)
 	for i := 0; i < n; i++ {
 		w := len(r.workers) - 1
-		r.workers[w].stop <- true // wait for worker to stop, this should block
-		r.workers[w] = nil        // delete the worker
-		r.workers = r.workers[:w] // truncate the workers list
+		r.workers[w].stop()                     // stop the worker once it has completed its task
+		stopped = append(stopped, r.workers[w]) // wait for the worker to stop after unlocking
+		r.workers[w] = nil                      // delete the worker
+		r.workers = r.workers[:w]               // truncate the workers list
 	}
 
-	// Update the workers gauge
-	pmWorkers.Set(float64(len(r.workers)))
+	// Update the worker gauges
+	r.observeWorkers(len(r.workers))
 
-	out.Status("removed %d workers -- %d workers running", n, len(r.workers))
-	return nil
+	r.logf(out.LevelStatus, "", "removed %d workers -- %d workers running", n, len(r.workers))
+	return stopped, nil
+}
+
+// RemoveWorkersContext stops n workers as RemoveWorkers does but only waits for them to
+// finish the futures they are handling until the context is done, returning the number
+// of stopped workers that are still draining. The workers are removed from the queue as
+// soon as they are signaled, so draining workers do not dequeue any more futures. If
+// force is true, the futures of the workers that are still draining are canceled once
+// the context is done so that handlers that respect cancellation return promptly.
+func (r *Radish) RemoveWorkersContext(ctx context.Context, n int, force bool) (draining int, err error) {
+	r.Lock()
+	previous := len(r.workers)
+	stopped, err := r.removeWorkers(n)
+	current := len(r.workers)
+	r.Unlock()
+
+	r.hooks.emitScale(previous, current)
+	return r.waitWorkersContext(ctx, stopped, force), err
+}
+
+// SetWorkersContext sets the number of workers as SetWorkers does, waiting for removed
+// workers as RemoveWorkersContext does, and returns the number of removed workers that
+// are still draining.
+func (r *Radish) SetWorkersContext(ctx context.Context, n int, force bool) (draining int, err error) {
+	if n < 0 {
+		return 0, Errorf(CodeInvalidWorkers, "cannot set number of workers <0")
+	}
+
+	r.Lock()
+	previous := len(r.workers)
+	stopped, err := r.setWorkers(n)
+	current := len(r.workers)
+	r.Unlock()
+
+	r.hooks.emitScale(previous, current)
+	return r.waitWorkersContext(ctx, stopped, force), err
+}
+
+// waitWorkers blocks until the stopped workers have completed their tasks.
+func waitWorkers(stopped []*worker) {
+	for _, w := range stopped {
+		<-w.done
+	}
+}
+
+// waitWorkersContext waits for the stopped workers to complete their tasks until the
+// context is done, canceling the futures of the workers that are still draining if force
+// is true, and returns the number of workers that are still draining.
+func (r *Radish) waitWorkersContext(ctx context.Context, stopped []*worker, force bool) (draining int) {
+	for i, w := range stopped {
+		select {
+		case <-w.done:
+			continue
+		case <-ctx.Done():
+		}
+
+		for _, w := range stopped[i:] {
+			select {
+			case <-w.done:
+				continue
+			default:
+			}
+
+			draining++
+			if force {
+				w.cancel()
+			}
+		}
+
+		if force {
+			r.logf(out.LevelWarn, "", "canceled the futures of %d removed workers that were still draining", draining)
+		}
+		return draining
+	}
+	return 0
 }
 
 // NumWorkers returns the number of currently running workers
@@ -350,12 +1097,32 @@ func (r *Radish) NumWorkers() int {
 	r.RLock()
 	defer r.RUnlock()
 
-	// Refresh the workers gauge
-	pmWorkers.Set(float64(len(r.workers)))
+	// Refresh the worker gauges
+	r.observeWorkers(len(r.workers))
 
 	return len(r.workers)
 }
 
+// BusyWorkers returns the number of workers that are currently handling a future; the
+// remaining workers are idle, waiting for futures to be queued.
+func (r *Radish) BusyWorkers() int {
+	return int(atomic.LoadInt64(&r.busy))
+}
+
+// accepting returns an error if the queue is not accepting new tasks.
+func (r *Radish) accepting() error {
+	select {
+	case <-r.shutdown:
+		return Errorf(CodeShutdown, "queue has been shutdown, not accepting new tasks")
+	default:
+	}
+
+	if r.isDraining() {
+		return Errorf(CodeShutdown, "queue is draining, not accepting new tasks")
+	}
+	return nil
+}
+
 // Handler is a thread-safe mechanism to fetch a task handler or check if it exists.
 func (r *Radish) Handler(task string) (handler Task, err error) {
 	r.RLock()
@@ -363,12 +1130,8 @@ func (r *Radish) Handler(task string) (handler Task, err error) {
 
 	var ok bool
 	if handler, ok = r.handlers[task]; !ok {
-		return nil, Errorf(ErrTaskNotRegistered, "unknown task %q", task)
+		return nil, Errorf(CodeTaskNotRegistered, "unknown task %q", task)
 	}
 
 	return handler, nil
 }
-
-func init() {
-	initMetrics()
-}
@@ -1,11 +1,17 @@
 package radish_test
 
 import (
+	"context"
 	"errors"
+	"os"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
 	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -90,3 +96,330 @@ func TestRadishScaling(t *testing.T) {
 	require.EqualError(t, radish.RemoveWorkers(87), "[5] cannot remove 87 workers, only 4 currently running")
 	require.Equal(t, 4, radish.NumWorkers())
 }
+
+func TestRemoveWorkersContext(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &drainTask{testTask: testTask{wg: wg, name: "drain"}, started: make(chan struct{}, 2), release: make(chan struct{})}
+	queue, err := New(&Config{Workers: 2, NoSignals: true, LogLevel: "warn"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		_, err = queue.Delay("drain", nil, nil, nil)
+		require.NoError(t, err)
+		<-task.started
+	}
+
+	// The workers are removed but are still draining when the timeout expires
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	draining, err := queue.RemoveWorkersContext(ctx, 1, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, draining)
+	require.Equal(t, 1, queue.NumWorkers())
+
+	// Forcing the workers to stop cancels the futures they are handling
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	draining, err = queue.SetWorkersContext(ctx, 0, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, draining)
+
+	require.Eventually(t, func() bool { return queue.BusyWorkers() == 1 }, time.Second, 5*time.Millisecond)
+	close(task.release)
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&task.successes))
+	require.Equal(t, int32(1), atomic.LoadInt32(&task.failures))
+}
+
+// drainTask blocks until it is released or canceled.
+type drainTask struct {
+	testTask
+	started chan struct{}
+	release chan struct{}
+}
+
+func (t *drainTask) HandleContext(ctx context.Context, id uuid.UUID, params []byte) error {
+	t.started <- struct{}{}
+	select {
+	case <-t.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestBusyWorkers(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	task := &testTask{wg: wg, name: "block", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 3, NoSignals: true}, task)
+	require.NoError(t, err)
+	require.Equal(t, 0, queue.BusyWorkers())
+
+	for i := 0; i < 2; i++ {
+		_, err = queue.Delay(task.Name(), nil, nil, nil)
+		require.NoError(t, err)
+		<-started
+	}
+	require.Equal(t, 2, queue.BusyWorkers())
+
+	close(release)
+	wg.Wait()
+	require.NoError(t, queue.Shutdown())
+	require.Equal(t, 0, queue.BusyWorkers())
+}
+
+func TestDelayContext(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+
+	// Block the handler until the test releases it so that the queue fills up
+	release := make(chan struct{})
+	blocking := &testTask{wg: wg, name: "blocking", onHandle: func(id uuid.UUID, params []byte) error {
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, QueueSize: 1}, blocking)
+	require.NoError(t, err)
+
+	// The first task is handled by the worker, the second fills the queue
+	ctx := WithMetadata(context.Background(), "trace", "abc123")
+	for i := 0; i < 2; i++ {
+		_, err := queue.DelayContext(ctx, blocking.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	// The queue is full so the delay should block until the deadline
+	tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = queue.DelayContext(tctx, blocking.Name(), nil, nil, nil)
+	require.EqualError(t, err, "[7] could not delay blocking: context deadline exceeded")
+
+	// A canceled context should not be enqueued
+	cctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = queue.DelayContext(cctx, blocking.Name(), nil, nil, nil)
+	require.EqualError(t, err, "[7] could not delay blocking: context canceled")
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(2), blocking.handled)
+
+	// Metadata is copied from the context
+	md := MetadataFrom(ctx)
+	require.Equal(t, map[string]string{"trace": "abc123"}, md)
+	require.Nil(t, MetadataFrom(context.Background()))
+}
+
+func TestMetadata(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &metadataTask{testTask: testTask{wg: wg, name: "tenant"}, seen: make(chan map[string]string, 2)}
+	queue, err := New(&Config{Workers: 1, NoSignals: true}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Metadata of the queue request is passed to the handler and its callbacks
+	wg.Add(1)
+	rep, err := queue.Queue(context.Background(), &api.QueueRequest{Task: "tenant", Metadata: map[string]string{"tenant": "acme"}})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	wg.Wait()
+
+	require.Equal(t, map[string]string{"tenant": "acme"}, <-task.seen)
+	require.Equal(t, map[string]string{"tenant": "acme"}, <-task.seen)
+}
+
+type metadataTask struct {
+	testTask
+	seen chan map[string]string
+}
+
+func (t *metadataTask) HandleContext(ctx context.Context, id uuid.UUID, params []byte) error {
+	t.seen <- MetadataFrom(ctx)
+	return nil
+}
+
+func (t *metadataTask) SuccessContext(ctx context.Context, id uuid.UUID, params []byte) {
+	t.seen <- MetadataFrom(ctx)
+	t.Success(id, params)
+}
+
+func (t *metadataTask) FailureContext(ctx context.Context, id uuid.UUID, err error, params []byte) {
+	t.Failure(id, err, params)
+}
+
+func TestReplace(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	old := &testTask{wg: wg, name: "plugin", onHandle: func(id uuid.UUID, params []byte) error {
+		close(started)
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn"}, old)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Only registered tasks can be replaced and the new handler must be valid
+	err = queue.Replace(&testTask{wg: wg, name: "unknown"})
+	require.EqualError(t, err, "[3] cannot replace unknown task \"unknown\"")
+
+	err = queue.Replace(&resourceTask{testTask: testTask{wg: wg, name: "plugin"}, resources: []string{"db"}})
+	require.EqualError(t, err, "[1] task \"plugin\" consumes undefined resource \"db\"")
+
+	handler, err := queue.Handler("plugin")
+	require.NoError(t, err)
+	require.True(t, handler == old)
+
+	// A future in flight completes with the old handler
+	wg.Add(1)
+	_, err = queue.Delay("plugin", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	replacement := &testTask{wg: wg, name: "plugin"}
+	require.NoError(t, queue.Replace(replacement))
+	close(release)
+
+	// Futures dequeued after the replacement are handled by the new handler
+	wg.Add(1)
+	_, err = queue.Delay("plugin", nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	require.Equal(t, int32(1), old.successes)
+	require.Equal(t, int32(1), replacement.successes)
+}
+
+func TestShutdown(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+
+	task := &testTask{wg: wg, name: "shutdown"}
+	queue, err := New(&Config{Workers: 2, NoSignals: true}, task)
+	require.NoError(t, err)
+
+	_, err = queue.Delay(task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	require.NoError(t, queue.Shutdown())
+	require.Equal(t, 0, queue.NumWorkers())
+
+	// Tasks cannot be delayed after shutdown
+	_, err = queue.Delay(task.Name(), nil, nil, nil)
+	require.EqualError(t, err, "[10] queue has been shutdown, not accepting new tasks")
+
+	// The queue cannot be shutdown twice
+	require.EqualError(t, queue.Shutdown(), "[10] queue has already been shutdown")
+}
+
+func TestConfigSignals(t *testing.T) {
+	conf := &Config{}
+	require.NoError(t, conf.Validate())
+	require.Len(t, conf.ShutdownSignals, 2)
+	require.Len(t, conf.ReloadSignals, 1)
+	require.Len(t, conf.DrainSignals, 0)
+
+	conf = &Config{DrainSignals: []os.Signal{syscall.SIGTERM}}
+	require.NoError(t, conf.Validate())
+	require.Len(t, conf.ShutdownSignals, 0)
+	require.Len(t, conf.ReloadSignals, 0)
+
+	conf = &Config{NoSignals: true}
+	require.NoError(t, conf.Validate())
+	require.Len(t, conf.ShutdownSignals, 0)
+
+	conf = &Config{DrainSignals: []os.Signal{syscall.SIGTERM}, ShutdownSignals: []os.Signal{syscall.SIGTERM}}
+	require.EqualError(t, conf.Validate(), "[1] signal terminated cannot trigger more than one action")
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	setenv := func(key, val string) {
+		require.NoError(t, os.Setenv(key, val))
+	}
+	defer func() {
+		for _, key := range []string{EnvWorkers, EnvQueueSize, EnvAddr, EnvMetricsAddr, EnvLogLevel, EnvCautionThreshold, EnvSuppressMetrics} {
+			os.Unsetenv(key)
+		}
+	}()
+
+	// Defaults are populated when the environment is empty
+	conf, err := ConfigFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, 5000, conf.QueueSize)
+	require.Equal(t, ":5356", conf.Addr)
+	require.Equal(t, "info", conf.LogLevel)
+
+	setenv(EnvWorkers, "12")
+	setenv(EnvQueueSize, "100")
+	setenv(EnvAddr, "127.0.0.1:5000")
+	setenv(EnvMetricsAddr, "127.0.0.1:9000")
+	setenv(EnvLogLevel, "WARN")
+	setenv(EnvCautionThreshold, "50")
+	setenv(EnvSuppressMetrics, "true")
+
+	conf, err = ConfigFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, 12, conf.Workers)
+	require.Equal(t, 100, conf.QueueSize)
+	require.Equal(t, "127.0.0.1:5000", conf.Addr)
+	require.Equal(t, "127.0.0.1:9000", conf.MetricsAddr)
+	require.Equal(t, "warn", conf.LogLevel)
+	require.Equal(t, uint(50), conf.CautionThreshold)
+	require.True(t, conf.SuppressMetrics)
+
+	// Invalid values are rejected
+	setenv(EnvWorkers, "many")
+	_, err = ConfigFromEnv()
+	require.EqualError(t, err, `[1] could not parse $RADISH_WORKERS: "many" is not an integer`)
+	setenv(EnvWorkers, "4")
+
+	setenv(EnvSuppressMetrics, "sure")
+	_, err = ConfigFromEnv()
+	require.EqualError(t, err, `[1] could not parse $RADISH_SUPPRESS_METRICS: "sure" is not a boolean`)
+	setenv(EnvSuppressMetrics, "false")
+
+	setenv(EnvLogLevel, "loud")
+	_, err = ConfigFromEnv()
+	require.EqualError(t, err, `[1] "loud" is an invalid log level, use trace, debug, info, caution, status, warn, or silent`)
+	setenv(EnvLogLevel, "warn")
+}
+
+func TestMaxParamSize(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "upload"}
+	queue, err := New(&Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent", MaxParamSize: 8}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Params up to the maximum size are queued
+	wg.Add(1)
+	_, err = queue.Delay("upload", []byte("12345678"), nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	// Larger params are rejected by Delay and the Queue RPC
+	_, err = queue.Delay("upload", []byte("123456789"), nil, nil)
+	require.EqualError(t, err, "[9] could not delay upload: params are 9 bytes, the maximum is 8 bytes")
+
+	rep, err := queue.Queue(context.Background(), &api.QueueRequest{Task: "upload", Params: make([]byte, 1024)})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, CodeInvalidParams, rep.Error.Code)
+
+	_, err = New(&Config{MaxParamSize: -1})
+	require.EqualError(t, err, "[1] max param size cannot be negative")
+}
@@ -0,0 +1,308 @@
+package radish
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// Default retention of completed future records.
+const (
+	defaultResultTTL     = 1 * time.Hour
+	defaultSweepInterval = 1 * time.Minute
+	defaultMaxRecords    = 100000
+)
+
+// record of the lifecycle state of a future from when it is queued until it has been
+// handled. Records are kept in memory so that callers can find out what happened to a
+// future; once the future has completed its record is evicted by the sweeper after its
+// TTL has expired, or earlier if there are too many records, so that long running
+// servers do not grow without bound.
+type record struct {
+	task      string          // the name of the task that handles the future
+	key       string          // the idempotency key of the future, if any
+	state     api.FutureState // the current lifecycle state of the future
+	attempts  int             // the number of times the future has been handled
+	redeliver int             // the number of times the future was redelivered after the queue stopped
+	err       error           // the error returned by the handler if the task failed
+	result    []byte          // the result returned by the handler if the task succeeded
+	logs      []*api.LogEntry // the most recent lines logged by the handler with Logf, if captured
+	done      chan struct{}   // closed when the future completes or the record is removed
+	queued    time.Time       // when the future was queued or scheduled
+	started   time.Time       // when a worker last started handling the future
+	completed time.Time       // when the task was handled
+	expires   time.Time       // when the record is evicted by the sweeper
+}
+
+// completion is an entry in the order that records were completed, used to evict the
+// oldest completed records first when there are too many records.
+type completion struct {
+	id        string
+	completed time.Time
+}
+
+// records is a thread-safe store of future records keyed by future ID.
+type records struct {
+	sync.RWMutex
+	ttl     time.Duration
+	max     int
+	entries map[string]*record
+	keys    map[string]string // the ids of the futures that claimed each idempotency key
+	order   []completion
+	events  *eventHub // notified of every lifecycle state transition of a future
+}
+
+func newRecords(ttl time.Duration, max int, events *eventHub) *records {
+	return &records{ttl: ttl, max: max, entries: make(map[string]*record), keys: make(map[string]string), events: events}
+}
+
+// track a future that has been queued or scheduled.
+func (s *records) track(future *Future, state api.FutureState) {
+	s.Lock()
+	defer s.Unlock()
+
+	rec := s.entry(future)
+	if rec.finished() {
+		// The future is being handled again, e.g. if it was re-driven
+		rec.done, rec.err, rec.result, rec.logs = make(chan struct{}), nil, nil, nil
+		rec.completed, rec.expires = time.Time{}, time.Time{}
+	}
+	rec.state, rec.attempts, rec.queued = state, future.Attempts, time.Now()
+	rec.redeliver = future.Redelivered
+	s.events.publish(rec.event(future.ID))
+}
+
+// start marks the future as running once a worker begins handling it, returning the
+// amount of time the future waited in the queue since it was last queued.
+func (s *records) start(future *Future) (wait time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	rec := s.entry(future)
+	rec.state, rec.started = api.FutureState_RUNNING, time.Now()
+	s.events.publish(rec.event(future.ID))
+
+	if !rec.queued.IsZero() {
+		wait = rec.started.Sub(rec.queued)
+	}
+	return wait
+}
+
+// complete records the outcome of the future, which expires after the TTL. If there are
+// more records than the maximum, the oldest completed records are evicted, returning
+// the number of evicted records.
+func (s *records) complete(future *Future, err error) (n int) {
+	now := time.Now()
+	state := api.FutureState_SUCCEEDED
+	switch {
+	case future.canceled:
+		state = api.FutureState_CANCELED
+	case err != nil:
+		state = api.FutureState_FAILED
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	rec := s.entry(future)
+	rec.state, rec.attempts, rec.err, rec.result = state, future.Attempts, err, nil
+	if state == api.FutureState_SUCCEEDED {
+		rec.result = future.result
+	}
+	rec.completed, rec.expires = now, now.Add(s.ttl)
+	if !rec.finished() {
+		close(rec.done)
+	}
+	s.events.publish(rec.event(future.ID))
+
+	key := future.ID.String()
+	s.order = append(s.order, completion{id: key, completed: now})
+
+	for s.max > 0 && len(s.entries) > s.max && len(s.order) > 0 {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+		if rec, ok := s.entries[oldest.id]; ok && rec.completed.Equal(oldest.completed) {
+			s.evict(oldest.id)
+			n++
+		}
+	}
+	return n
+}
+
+// remove the record of a future that was not queued, waking any waiters.
+func (s *records) remove(id uuid.UUID) {
+	s.Lock()
+	defer s.Unlock()
+
+	key := id.String()
+	if rec, ok := s.entries[key]; ok {
+		if !rec.finished() {
+			close(rec.done)
+		}
+		s.evict(key)
+	}
+}
+
+// entry returns the record of the future, creating it if necessary. Must hold the lock.
+func (s *records) entry(future *Future) *record {
+	key := future.ID.String()
+	rec, ok := s.entries[key]
+	if !ok {
+		rec = &record{task: future.Task, key: future.IdempotencyKey, done: make(chan struct{})}
+		s.entries[key] = rec
+
+		// Futures restored from storage or handed off have not claimed their key
+		if rec.key != "" {
+			if _, ok := s.keys[claimKey(rec.task, rec.key)]; !ok {
+				s.keys[claimKey(rec.task, rec.key)] = key
+			}
+		}
+	}
+	return rec
+}
+
+// get returns a copy of the record of the future.
+func (s *records) get(id uuid.UUID) (record, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	rec, ok := s.entries[id.String()]
+	if !ok {
+		return record{}, false
+	}
+	return *rec, true
+}
+
+// log appends the entry to the logs of the future, keeping at most the max most recent
+// entries.
+func (s *records) log(id uuid.UUID, entry *api.LogEntry, max int) {
+	s.Lock()
+	defer s.Unlock()
+
+	rec, ok := s.entries[id.String()]
+	if !ok {
+		return
+	}
+
+	if len(rec.logs) >= max {
+		rec.logs = rec.logs[len(rec.logs)-max+1:]
+	}
+	rec.logs = append(rec.logs, entry)
+}
+
+// finished returns true if the done channel of the record has been closed.
+func (r *record) finished() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// sweep evicts all records that have expired, returning the number of evicted records.
+func (s *records) sweep(now time.Time) (n int) {
+	s.Lock()
+	defer s.Unlock()
+
+	for id, rec := range s.entries {
+		if !rec.expires.IsZero() && now.After(rec.expires) {
+			s.evict(id)
+			n++
+		}
+	}
+
+	// Compact the completion order, removing records that no longer exist
+	order := make([]completion, 0, len(s.order))
+	for _, c := range s.order {
+		if rec, ok := s.entries[c.id]; ok && rec.completed.Equal(c.completed) {
+			order = append(order, c)
+		}
+	}
+	s.order = order
+	return n
+}
+
+// sweeper periodically evicts expired records and workflows until the queue is shutdown.
+func (r *Radish) sweeper() {
+	ticker := time.NewTicker(r.config.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.shutdown:
+			return
+		case now := <-ticker.C:
+			if n := r.results.sweep(now); n > 0 {
+				pmRecordsEvicted.Add(float64(n))
+				r.logf(out.LevelDebug, "", "evicted %d expired future records", n)
+			}
+			r.forwards.sweep(now)
+			if n := r.workflows.sweep(now); n > 0 {
+				r.logf(out.LevelDebug, "", "evicted %d expired workflows", n)
+			}
+		}
+	}
+}
+
+// GetFuture returns the lifecycle state of the future with the specified id, e.g. so
+// that callers can find out what happened to a task they delayed. Futures are only
+// known until their record is evicted after the ResultTTL once they have completed.
+// The state of futures that were forwarded to a peer of the cluster is requested from
+// the peer.
+func (r *Radish) GetFuture(ctx context.Context, in *api.GetFutureRequest) (rep *api.GetFutureReply, err error) {
+	if client, peerID, ok := r.forwarded(uuid.UUID(in.Uuid)); ok {
+		if rep, err = client.GetFuture(r.peerContext(ctx), &api.GetFutureRequest{Uuid: peerID}); err != nil {
+			err = Errorf(CodeBadGateway, "could not get forwarded future %s: %s", uuid.UUID(in.Uuid), err)
+			return &api.GetFutureReply{Success: false, Error: err.(*api.Error)}, nil
+		}
+
+		if rep.Future != nil {
+			rep.Future.Uuid = in.Uuid
+		}
+		return rep, nil
+	}
+
+	rec, ok := r.results.get(uuid.UUID(in.Uuid))
+	if !ok {
+		err = Errorf(CodeNotFound, "future %s not found", uuid.UUID(in.Uuid))
+		return &api.GetFutureReply{Success: false, Error: err.(*api.Error)}, nil
+	}
+
+	return &api.GetFutureReply{Success: true, Future: rec.info(in.Uuid)}, nil
+}
+
+// info returns the protocol buffer representation of the record of the future.
+func (r *record) info(id uuid.UUID) *api.FutureInfo {
+	info := &api.FutureInfo{
+		Uuid:        id,
+		Task:        r.task,
+		State:       r.state,
+		Attempts:    int32(r.attempts),
+		Queued:      unixNano(r.queued),
+		Started:     unixNano(r.started),
+		Finished:    unixNano(r.completed),
+		Redelivered: int32(r.redeliver),
+		Logs:        r.logs,
+	}
+
+	if r.err != nil {
+		var ok bool
+		if info.Error, ok = apiError(r.err); !ok {
+			info.Error = &api.Error{Code: CodeUnknown, Message: r.err.Error()}
+		}
+	}
+	return info
+}
+
+// unixNano returns the timestamp in unix nanoseconds or 0 if the timestamp is zero.
+func unixNano(ts time.Time) int64 {
+	if ts.IsZero() {
+		return 0
+	}
+	return ts.UnixNano()
+}
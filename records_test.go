@@ -0,0 +1,111 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFuture(t *testing.T) {
+	// Block the worker so that the state of the futures can be observed
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "tracked", onHandle: func(id uuid.UUID, params []byte) error {
+		if string(params) == "block" {
+			close(started)
+			<-release
+		}
+		if string(params) == "fail" {
+			return errors.New("whoops!")
+		}
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, MaxRecords: 2}, task)
+	require.NoError(t, err)
+
+	state := func(id uuid.UUID) api.FutureState {
+		rep, err := queue.GetFuture(context.Background(), &api.GetFutureRequest{Uuid: id})
+		require.NoError(t, err)
+		require.True(t, rep.Success)
+		return rep.Future.State
+	}
+
+	wg.Add(2)
+	running, err := queue.Delay("tracked", []byte("block"), nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	failed, err := queue.Delay("tracked", []byte("fail"), nil, nil)
+	require.NoError(t, err)
+	scheduled, err := queue.DelayAfter("tracked", time.Hour, nil, nil, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, api.FutureState_RUNNING, state(running))
+	require.Equal(t, api.FutureState_QUEUED, state(failed))
+	require.Equal(t, api.FutureState_SCHEDULED, state(scheduled))
+
+	close(release)
+	wg.Wait()
+	time.Sleep(10 * time.Millisecond)
+
+	// The oldest completed record was evicted to keep at most 2 records
+	rep, err := queue.GetFuture(context.Background(), &api.GetFutureRequest{Uuid: running})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, "[13] future "+running.String()+" not found", rep.Error.Error())
+
+	rep, err = queue.GetFuture(context.Background(), &api.GetFutureRequest{Uuid: failed})
+	require.NoError(t, err)
+	require.Equal(t, api.FutureState_FAILED, rep.Future.State)
+	require.Equal(t, int32(1), rep.Future.Attempts)
+	require.Equal(t, "whoops!", rep.Future.Error.Message)
+	require.True(t, rep.Future.Finished >= rep.Future.Started)
+}
+
+func TestResultTTL(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "expiring"}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "silent", ResultTTL: 200 * time.Millisecond, SweepInterval: 10 * time.Millisecond}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	found := func(id uuid.UUID) bool {
+		rep, err := queue.GetFuture(context.Background(), &api.GetFutureRequest{Uuid: id})
+		require.NoError(t, err)
+		if !rep.Success {
+			require.Equal(t, CodeNotFound, rep.Error.Code)
+		}
+		return rep.Success
+	}
+
+	wg.Add(1)
+	completed, err := queue.Delay("expiring", nil, nil, nil)
+	require.NoError(t, err)
+	scheduled, err := queue.DelayAfter("expiring", time.Hour, nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	// The record of the completed future is kept until its TTL expires
+	require.Eventually(t, func() bool {
+		rep, err := queue.GetFuture(context.Background(), &api.GetFutureRequest{Uuid: completed})
+		require.NoError(t, err)
+		return rep.Success && rep.Future.State == api.FutureState_SUCCEEDED
+	}, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.True(t, found(completed))
+
+	// Then it is evicted by the sweeper, but futures that have not completed are never swept
+	require.Eventually(t, func() bool { return !found(completed) }, 2*time.Second, 10*time.Millisecond)
+	require.True(t, found(scheduled))
+}
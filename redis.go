@@ -0,0 +1,419 @@
+package radish
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// How long a dequeue waits for a future before checking if its context is done, and how
+// long to wait to connect to the Redis server.
+const (
+	redisPollTimeout = "1"
+	redisDialTimeout = 5 * time.Second
+)
+
+// The maximum length of bulk strings, the maximum number of elements of arrays, and the
+// maximum nesting of arrays in replies from the Redis server, so that a malformed reply
+// or a server that is not Redis cannot exhaust the memory of the process. The bulk length
+// is the default proto-max-bulk-len of Redis. Replies are read as they arrive, allocating
+// at most the read size up front.
+const (
+	redisMaxBulkLen  = 512 * 1024 * 1024
+	redisMaxArrayLen = 1024 * 1024
+	redisMaxDepth    = 8
+	redisReadSize    = 64 * 1024
+)
+
+// RedisBroker is a Broker that holds queued futures in a Redis list so that multiple
+// radish processes can share a queue. Futures are pushed onto the list when they are
+// enqueued and atomically moved onto a processing list of the consumer when they are
+// dequeued, where they remain until they are acknowledged. When a broker is created any
+// futures remaining on the processing list of its consumer, which were dequeued but never
+// acknowledged before the consumer stopped, are moved back onto the queue so that they
+// are redelivered. Futures are handled in the order that they were enqueued regardless of
+// their priority and the queue is not bounded by the QueueSize.
+type RedisBroker struct {
+	sync.Mutex
+	addr       string            // the host:port of the Redis server
+	tls        *tls.Config       // the TLS config of connections to the server, nil to connect without TLS
+	password   string            // the password to authenticate with, if any
+	db         int               // the Redis database to select
+	queue      string            // the key of the list of queued futures
+	processing string            // the key of the list of futures dequeued by the consumer
+	idle       []*redisConn      // connections that are not in use
+	pending    map[string][]byte // the serialized futures that have not been acknowledged
+}
+
+// NewRedisBroker connects to the Redis server at the url, either a host:port address or
+// a redis://[:password@]host:port[/db] url, and returns a broker that holds futures in
+// the list with the queue key. The consumer uniquely identifies the process and must be
+// the same when the process is restarted so that its unacknowledged futures are
+// redelivered. Servers that require TLS are connected to with a rediss:// url, verifying
+// the certificate of the server with the system roots.
+func NewRedisBroker(url, queue, consumer string) (_ *RedisBroker, err error) {
+	return NewRedisBrokerTLS(url, queue, consumer, nil)
+}
+
+// NewRedisBrokerTLS returns a Redis broker exactly as NewRedisBroker does, connecting to
+// the server with TLS using the config, e.g. to verify the certificate of the server with
+// a private CA or to present a client certificate. If the config is nil, only rediss://
+// urls are connected to with TLS.
+func NewRedisBrokerTLS(url, queue, consumer string, conf *tls.Config) (_ *RedisBroker, err error) {
+	if queue == "" || consumer == "" {
+		return nil, Errorf(CodeInvalidConfig, "redis broker requires a queue and a consumer")
+	}
+
+	var secure bool
+	b := &RedisBroker{queue: queue, processing: queue + ":processing:" + consumer, pending: make(map[string][]byte)}
+	if b.addr, b.password, b.db, secure, err = parseRedisURL(url); err != nil {
+		return nil, err
+	}
+
+	if conf != nil || secure {
+		if conf != nil {
+			b.tls = conf.Clone()
+		} else {
+			b.tls = &tls.Config{}
+		}
+
+		if b.tls.ServerName == "" {
+			if b.tls.ServerName, _, err = net.SplitHostPort(b.addr); err != nil {
+				return nil, Errorf(CodeInvalidConfig, "could not parse redis address %q", b.addr)
+			}
+		}
+	}
+
+	// Redeliver the futures that were dequeued but not acknowledged by the consumer
+	for {
+		var reply interface{}
+		if reply, err = b.do(context.Background(), "RPOPLPUSH", b.processing, b.queue); err != nil {
+			return nil, err
+		}
+		if reply == nil {
+			break
+		}
+	}
+
+	return b, nil
+}
+
+// parseRedisURL returns the address, password, and database of the Redis server and if
+// the server must be connected to with TLS.
+func parseRedisURL(addr string) (host, password string, db int, secure bool, err error) {
+	if !strings.Contains(addr, "://") {
+		return addr, "", 0, false, nil
+	}
+
+	var u *url.URL
+	if u, err = url.Parse(addr); err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") {
+		return "", "", 0, false, Errorf(CodeInvalidConfig, "could not parse redis url %q", addr)
+	}
+
+	if u.User != nil {
+		password, _ = u.User.Password()
+	}
+
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		if db, err = strconv.Atoi(path); err != nil {
+			return "", "", 0, false, Errorf(CodeInvalidConfig, "could not parse redis database %q", path)
+		}
+	}
+	return u.Host, password, db, u.Scheme == "rediss", nil
+}
+
+// Enqueue pushes the future onto the queue; since the queue is not bounded it never blocks
+// longer than the round trip to the server.
+func (b *RedisBroker) Enqueue(ctx context.Context, future *Future) (err error) {
+	var data []byte
+	if data, err = json.Marshal(&storedFuture{Future: future, Client: future.client, Stored: time.Now()}); err != nil {
+		return Errorf(CodeInvalidParams, "could not serialize future %s: %s", future.ID, err)
+	}
+
+	if _, err = b.do(ctx, "LPUSH", b.queue, string(data)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Dequeue moves the next future onto the processing list of the consumer, polling the
+// server until a future is available or the context is done.
+func (b *RedisBroker) Dequeue(ctx context.Context) (_ *Future, err error) {
+	for {
+		var reply interface{}
+		if reply, err = b.do(context.Background(), "BRPOPLPUSH", b.queue, b.processing, redisPollTimeout); err != nil {
+			return nil, err
+		}
+
+		if data, ok := reply.([]byte); ok {
+			stored := &storedFuture{}
+			if err = json.Unmarshal(data, stored); err != nil || stored.Future == nil {
+				// Remove the future so that it is not redelivered when the consumer restarts
+				b.do(context.Background(), "LREM", b.processing, "1", string(data))
+				return nil, Errorf(CodeInvalidParams, "could not parse future from redis: %v", err)
+			}
+
+			future := stored.Future
+			future.client = stored.Client
+
+			b.Lock()
+			b.pending[future.ID.String()] = data
+			b.Unlock()
+			return future, nil
+		}
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Ack removes the future from the processing list of the consumer.
+func (b *RedisBroker) Ack(id uuid.UUID) (err error) {
+	b.Lock()
+	data, ok := b.pending[id.String()]
+	delete(b.pending, id.String())
+	b.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if _, err = b.do(context.Background(), "LREM", b.processing, "1", string(data)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Len returns the number of futures in the queue that is shared by every consumer, or 0
+// if the server cannot be reached.
+func (b *RedisBroker) Len() int {
+	reply, err := b.do(context.Background(), "LLEN", b.queue)
+	if err != nil {
+		return 0
+	}
+
+	n, _ := reply.(int64)
+	return int(n)
+}
+
+// Close the connections to the Redis server, which is called when the queue is shutdown.
+// Futures that were dequeued but not acknowledged are redelivered when the consumer is
+// restarted.
+func (b *RedisBroker) Close() error {
+	b.Lock()
+	defer b.Unlock()
+	for _, conn := range b.idle {
+		conn.Close()
+	}
+	b.idle = nil
+	return nil
+}
+
+// do executes the command on an idle connection, connecting to the server if there are
+// no idle connections, and returns the connection to the pool unless it failed.
+func (b *RedisBroker) do(ctx context.Context, args ...string) (reply interface{}, err error) {
+	var conn *redisConn
+	if conn, err = b.conn(); err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Time{})
+	}
+
+	if reply, err = conn.do(args...); err != nil {
+		var rerr redisError
+		if !errors.As(err, &rerr) {
+			conn.Close()
+			return nil, Errorf(CodeBadGateway, "could not execute redis %s command: %s", args[0], err)
+		}
+		b.release(conn)
+		return nil, Errorf(CodeBadGateway, "redis %s command failed: %s", args[0], err)
+	}
+
+	b.release(conn)
+	return reply, nil
+}
+
+// conn returns an idle connection or connects to the server.
+func (b *RedisBroker) conn() (conn *redisConn, err error) {
+	b.Lock()
+	if n := len(b.idle); n > 0 {
+		conn = b.idle[n-1]
+		b.idle = b.idle[:n-1]
+		b.Unlock()
+		return conn, nil
+	}
+	b.Unlock()
+
+	if conn, err = dialRedis(b.addr, b.tls, b.password, b.db); err != nil {
+		return nil, Errorf(CodeBadGateway, "could not connect to redis at %s: %s", b.addr, err)
+	}
+	return conn, nil
+}
+
+// release returns the connection to the pool.
+func (b *RedisBroker) release(conn *redisConn) {
+	b.Lock()
+	b.idle = append(b.idle, conn)
+	b.Unlock()
+}
+
+// redisConn is a connection that executes commands using the Redis serialization protocol.
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// redisError is an error reply from the Redis server.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// dialRedis connects to the Redis server, with TLS if the config is not nil, then
+// authenticates and selects the database.
+func dialRedis(addr string, conf *tls.Config, password string, db int) (conn *redisConn, err error) {
+	var c net.Conn
+	dialer := &net.Dialer{Timeout: redisDialTimeout}
+	if conf != nil {
+		c, err = tls.DialWithDialer(dialer, "tcp", addr, conf)
+	} else {
+		c, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn = &redisConn{Conn: c, reader: bufio.NewReader(c)}
+
+	if password != "" {
+		if _, err = conn.do("AUTH", password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if db != 0 {
+		if _, err = conn.do("SELECT", strconv.Itoa(db)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// do sends the command as an array of bulk strings and reads the reply.
+func (c *redisConn) do(args ...string) (interface{}, error) {
+	cmd := make([]byte, 0, 64)
+	cmd = append(cmd, fmt.Sprintf("*%d\r\n", len(args))...)
+	for _, arg := range args {
+		cmd = append(cmd, fmt.Sprintf("$%d\r\n", len(arg))...)
+		cmd = append(cmd, arg...)
+		cmd = append(cmd, "\r\n"...)
+	}
+
+	if _, err := c.Write(cmd); err != nil {
+		return nil, err
+	}
+	return c.read(0)
+}
+
+// read a reply, returning a string, an int64, a []byte, a []interface{}, or nil. The
+// depth is the number of arrays the reply is nested in.
+func (c *redisConn) read(depth int) (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, line := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return line, nil
+	case '-':
+		return nil, redisError(line)
+	case ':':
+		return strconv.ParseInt(line, 10, 64)
+	case '$':
+		var n int
+		if n, err = redisLength(line, "bulk string", redisMaxBulkLen); err != nil || n < 0 {
+			return nil, err
+		}
+
+		// Read the bulk string as it arrives rather than allocating its length up front
+		size := n + 2
+		if size > redisReadSize {
+			size = redisReadSize
+		}
+
+		data := bytes.NewBuffer(make([]byte, 0, size))
+		if _, err = io.CopyN(data, c.reader, int64(n+2)); err != nil {
+			return nil, err
+		}
+
+		if !bytes.HasSuffix(data.Bytes(), []byte("\r\n")) {
+			return nil, fmt.Errorf("malformed bulk string reply")
+		}
+		return data.Bytes()[:n], nil
+	case '*':
+		var n int
+		if n, err = redisLength(line, "array", redisMaxArrayLen); err != nil || n < 0 {
+			return nil, err
+		}
+
+		if depth >= redisMaxDepth {
+			return nil, fmt.Errorf("array reply is nested more than %d deep", redisMaxDepth)
+		}
+
+		size := n
+		if size > redisReadSize {
+			size = redisReadSize
+		}
+
+		items := make([]interface{}, 0, size)
+		for i := 0; i < n; i++ {
+			var item interface{}
+			if item, err = c.read(depth + 1); err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", kind)
+	}
+}
+
+// redisLength parses the length of a bulk string or array reply, which is -1 for a nil
+// reply and at most the maximum.
+func redisLength(line, kind string, max int) (n int, err error) {
+	if n, err = strconv.Atoi(line); err != nil || n < -1 {
+		return 0, fmt.Errorf("malformed %s length %q", kind, line)
+	}
+
+	if n > max {
+		return 0, fmt.Errorf("%s of length %d is longer than the maximum of %d", kind, n, max)
+	}
+	return n, nil
+}
@@ -0,0 +1,370 @@
+package radish_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisBrokerTLS(t *testing.T) {
+	server, roots := newFakeRediss(t, "secret")
+	defer server.Close()
+	url := fmt.Sprintf("rediss://:secret@%s/1", server.Addr())
+
+	// The certificate of the server is verified with the system roots by default
+	_, err := NewRedisBroker(url, "jobs", "a")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "could not connect to redis")
+	require.Contains(t, err.Error(), "certificate")
+
+	// Connecting without TLS fails since the server only speaks TLS
+	_, err = NewRedisBroker(fmt.Sprintf("redis://:secret@%s/1", server.Addr()), "jobs", "a")
+	require.Error(t, err)
+
+	// Futures are enqueued and dequeued over TLS with the certificate of the server
+	broker, err := NewRedisBrokerTLS(url, "jobs", "a", &tls.Config{RootCAs: roots})
+	require.NoError(t, err)
+	defer broker.Close()
+
+	id := uuid.NewRandom()
+	require.NoError(t, broker.Enqueue(context.Background(), &Future{ID: id, Task: "secure", Params: []byte("hello")}))
+	require.Equal(t, 1, server.len("jobs"))
+	require.Equal(t, 1, broker.Len())
+
+	future, err := broker.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, id, future.ID)
+	require.Equal(t, "hello", string(future.Params))
+	require.NoError(t, broker.Ack(id))
+	require.Zero(t, server.len("jobs:processing:a"))
+
+	// A TLS config also connects to host:port addresses with TLS
+	open, roots := newFakeRediss(t, "")
+	defer open.Close()
+	broker, err = NewRedisBrokerTLS(open.Addr().String(), "jobs", "b", &tls.Config{RootCAs: roots})
+	require.NoError(t, err)
+	require.NoError(t, broker.Close())
+
+	_, err = NewRedisBroker("rediss://"+server.Addr().String()+"/db", "jobs", "a")
+	require.EqualError(t, err, `[1] could not parse redis database "db"`)
+}
+
+func TestRedisReplyLimits(t *testing.T) {
+	// A server that replies to every command with the same reply
+	serve := func(reply string) string {
+		sock, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { sock.Close() })
+
+		go func() {
+			for {
+				conn, err := sock.Accept()
+				if err != nil {
+					return
+				}
+
+				go func() {
+					defer conn.Close()
+					reader := bufio.NewReader(conn)
+					for {
+						if _, err := readCommand(reader); err != nil {
+							return
+						}
+						io.WriteString(conn, reply)
+					}
+				}()
+			}
+		}()
+		return sock.Addr().String()
+	}
+
+	for reply, msg := range map[string]string{
+		"$1073741824\r\n":           "bulk string of length 1073741824 is longer than the maximum of 536870912",
+		"$-2\r\n":                   `malformed bulk string length "-2"`,
+		"$3\r\nabcde\r\n":           "malformed bulk string reply",
+		"*2000000\r\n":              "array of length 2000000 is longer than the maximum of 1048576",
+		"*x\r\n":                    `malformed array length "x"`,
+		strings.Repeat("*1\r\n", 9): "array reply is nested more than 8 deep",
+	} {
+		_, err := NewRedisBroker(serve(reply), "jobs", "a")
+		require.Error(t, err, reply)
+		require.Contains(t, err.Error(), "could not execute redis RPOPLPUSH command: "+msg, reply)
+	}
+
+	// Nil replies are still read
+	_, err := NewRedisBroker(serve("$-1\r\n"), "jobs", "a")
+	require.NoError(t, err)
+}
+
+func TestRedisBroker(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	defer server.Close()
+	url := fmt.Sprintf("redis://:secret@%s/1", server.Addr())
+
+	// Brokers cannot be created with invalid options
+	_, err := NewRedisBroker(url, "jobs", "")
+	require.EqualError(t, err, "[1] redis broker requires a queue and a consumer")
+
+	_, err = NewRedisBroker("http://localhost:6379", "jobs", "a")
+	require.EqualError(t, err, "[1] could not parse redis url \"http://localhost:6379\"")
+
+	_, err = NewRedisBroker(fmt.Sprintf("redis://:wrong@%s", server.Addr()), "jobs", "a")
+	require.EqualError(t, err, fmt.Sprintf("[6] could not connect to redis at %s: ERR invalid password", server.Addr()))
+
+	// Multiple queues share the futures in the redis list
+	wg := new(sync.WaitGroup)
+	var handled [2]int32
+	queues := make([]*Radish, 2)
+	for i, consumer := range []string{"a", "b"} {
+		i := i
+		broker, err := NewRedisBroker(url, "jobs", consumer)
+		require.NoError(t, err)
+
+		task := &testTask{wg: wg, name: "shared", onHandle: func(id uuid.UUID, params []byte) error {
+			atomic.AddInt32(&handled[i], 1)
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}}
+
+		queues[i], err = New(&Config{Workers: 2, NoSignals: true, LogLevel: "warn", Broker: broker}, task)
+		require.NoError(t, err)
+	}
+
+	wg.Add(20)
+	for i := 0; i < 20; i++ {
+		_, err = queues[0].Delay("shared", []byte(strconv.Itoa(i)), nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(20), atomic.LoadInt32(&handled[0])+atomic.LoadInt32(&handled[1]))
+	require.NotZero(t, atomic.LoadInt32(&handled[1]), "the second queue did not handle any futures")
+	for _, queue := range queues {
+		require.NoError(t, queue.Shutdown())
+	}
+	require.Zero(t, server.len("jobs"))
+	require.Zero(t, server.len("jobs:processing:a"))
+	require.Zero(t, server.len("jobs:processing:b"))
+
+	// Futures that were dequeued but not acknowledged are redelivered to the consumer
+	broker, err := NewRedisBroker(url, "jobs", "c")
+	require.NoError(t, err)
+	require.NoError(t, broker.Enqueue(context.Background(), &Future{ID: uuid.NewRandom(), Task: "shared", Params: []byte("lost")}))
+	future, err := broker.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "lost", string(future.Params))
+	require.NoError(t, broker.Close())
+	require.Equal(t, 1, server.len("jobs:processing:c"))
+
+	broker, err = NewRedisBroker(url, "jobs", "c")
+	require.NoError(t, err)
+	require.Equal(t, 1, broker.Len())
+
+	wg.Add(1)
+	var params []byte
+	task := &testTask{wg: wg, name: "shared", onHandle: func(id uuid.UUID, p []byte) error {
+		params = p
+		return nil
+	}}
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn", Broker: broker}, task)
+	require.NoError(t, err)
+	wg.Wait()
+	require.NoError(t, queue.Shutdown())
+	require.Equal(t, "lost", string(params))
+	require.Zero(t, server.len("jobs:processing:c"))
+}
+
+// fakeRedis implements the subset of Redis list commands used by the broker.
+type fakeRedis struct {
+	sync.Mutex
+	net.Listener
+	password string
+	lists    map[string][]string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return serveFakeRedis(sock, password)
+}
+
+// newFakeRediss serves a fake Redis server with TLS, returning the server and a pool
+// with its self-signed certificate.
+func newFakeRediss(t *testing.T, password string) (*fakeRedis, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+
+	conf := &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	sock, err := tls.Listen("tcp", "127.0.0.1:0", conf)
+	require.NoError(t, err)
+	return serveFakeRedis(sock, password), roots
+}
+
+// serveFakeRedis serves a fake Redis server on the listener.
+func serveFakeRedis(sock net.Listener, password string) *fakeRedis {
+	server := &fakeRedis{Listener: sock, password: password, lists: make(map[string][]string)}
+	go func() {
+		for {
+			conn, err := sock.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeRedis) len(key string) int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.lists[key])
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(args[0])
+		switch {
+		case cmd == "AUTH":
+			if args[1] != s.password {
+				io.WriteString(conn, "-ERR invalid password\r\n")
+				continue
+			}
+			authenticated = true
+			io.WriteString(conn, "+OK\r\n")
+		case !authenticated:
+			io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+		case cmd == "SELECT":
+			io.WriteString(conn, "+OK\r\n")
+		default:
+			io.WriteString(conn, s.exec(cmd, args[1:]))
+		}
+	}
+}
+
+func (s *fakeRedis) exec(cmd string, args []string) string {
+	switch cmd {
+	case "LPUSH":
+		s.Lock()
+		defer s.Unlock()
+		s.lists[args[0]] = append([]string{args[1]}, s.lists[args[0]]...)
+		return fmt.Sprintf(":%d\r\n", len(s.lists[args[0]]))
+	case "LLEN":
+		return fmt.Sprintf(":%d\r\n", s.len(args[0]))
+	case "LREM":
+		s.Lock()
+		defer s.Unlock()
+		list := s.lists[args[0]]
+		for i, item := range list {
+			if item == args[2] {
+				s.lists[args[0]] = append(list[:i:i], list[i+1:]...)
+				return ":1\r\n"
+			}
+		}
+		return ":0\r\n"
+	case "RPOPLPUSH", "BRPOPLPUSH":
+		deadline := time.Now()
+		if cmd == "BRPOPLPUSH" {
+			timeout, _ := strconv.Atoi(args[2])
+			deadline = deadline.Add(time.Duration(timeout) * time.Second)
+		}
+
+		for {
+			s.Lock()
+			if list := s.lists[args[0]]; len(list) > 0 {
+				item := list[len(list)-1]
+				s.lists[args[0]] = list[:len(list)-1]
+				s.lists[args[1]] = append([]string{item}, s.lists[args[1]]...)
+				s.Unlock()
+				return fmt.Sprintf("$%d\r\n%s\r\n", len(item), item)
+			}
+			s.Unlock()
+
+			if time.Now().After(deadline) {
+				return "$-1\r\n"
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", cmd)
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		if line, err = reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+
+		data := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
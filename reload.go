@@ -0,0 +1,180 @@
+package radish
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+)
+
+// ConfigLoader loads a config, e.g. from a file, so that it can be reloaded.
+type ConfigLoader func() (*Config, error)
+
+// ReloadConfig applies the reloadable settings of the config to the running queue without
+// restarting it or losing the futures in the queue: the number of Workers, the LogLevel
+// and CautionThreshold, and the TaskQuota, TaskQuotas, ClientQuota, and ClientQuotas.
+// The reloadable settings are validated first, so zero valued settings are reset to
+// their defaults; all other options in the config are ignored. The number of Workers is
+// not changed while autoscaling is enabled, since the autoscaler manages the workers.
+// When a reload signal is received, Listen reloads the config returned by the LoadConfig
+// function in the config, if specified.
+func (r *Radish) ReloadConfig(conf *Config) error {
+	return r.reload(conf, true)
+}
+
+// reload applies the reloadable settings of the config, scaling the workers if specified.
+func (r *Radish) reload(conf *Config, scale bool) (err error) {
+	select {
+	case <-r.shutdown:
+		return Errorf(CodeShutdown, "cannot reload the config of a queue that has been shutdown")
+	default:
+	}
+
+	// Only the reloadable settings are validated so that the other options of the config,
+	// e.g. the broker, are not created for a reload and then thrown away
+	if conf.Workers <= 0 {
+		conf.Workers = runtime.NumCPU()
+	}
+
+	if err = conf.validateLogging(); err != nil {
+		return err
+	}
+
+	r.reloadMu.Lock()
+	r.config.LogLevel, r.config.CautionThreshold = conf.LogLevel, conf.CautionThreshold
+	r.config.TaskQuota, r.config.TaskQuotas = conf.TaskQuota, conf.TaskQuotas
+	r.config.ClientQuota, r.config.ClientQuotas = conf.ClientQuota, conf.ClientQuotas
+	autoscaling := r.autoscaling
+	r.reloadMu.Unlock()
+	conf.setLogLevel()
+
+	if scale && autoscaling {
+		r.logf(out.LevelInfo, "", "autoscaling is enabled, not changing the number of workers to %d", conf.Workers)
+	} else if scale {
+		if err = r.SetWorkers(conf.Workers); err != nil {
+			return err
+		}
+	}
+
+	r.logf(out.LevelStatus, "", "reloaded config: %d workers, log level %s, task quota %d, client quota %d", r.NumWorkers(), conf.LogLevel, conf.TaskQuota, conf.ClientQuota)
+	return nil
+}
+
+// settings returns a config with the current reloadable settings of the queue.
+func (r *Radish) settings() *Config {
+	// The workers are counted without holding the reload lock since logging while
+	// holding the queue lock acquires the reload lock.
+	workers := r.NumWorkers()
+
+	r.reloadMu.RLock()
+	defer r.reloadMu.RUnlock()
+	return &Config{
+		Workers:          workers,
+		LogLevel:         r.config.LogLevel,
+		CautionThreshold: r.config.CautionThreshold,
+		TaskQuota:        r.config.TaskQuota,
+		TaskQuotas:       r.config.TaskQuotas,
+		ClientQuota:      r.config.ClientQuota,
+		ClientQuotas:     r.config.ClientQuotas,
+	}
+}
+
+// loadConfig loads the config to reload with the LoadConfig function in the config.
+func (r *Radish) loadConfig() (*Config, error) {
+	if r.config.LoadConfig == nil {
+		return nil, Errorf(CodeInvalidConfig, "no LoadConfig function is configured")
+	}
+
+	conf, err := r.config.LoadConfig()
+	if err != nil {
+		return nil, Errorf(CodeInvalidConfig, "could not load config: %s", err)
+	}
+	return conf, nil
+}
+
+// logThreshold returns the level that log entries are published to the Logs RPC at.
+func (r *Radish) logThreshold() uint8 {
+	r.reloadMu.RLock()
+	defer r.reloadMu.RUnlock()
+	return logLevels[r.config.LogLevel]
+}
+
+// taskQuota returns the current quota of the task.
+func (r *Radish) taskQuota(task string) int {
+	r.reloadMu.RLock()
+	defer r.reloadMu.RUnlock()
+	return r.config.taskQuota(task)
+}
+
+// clientQuota returns the current quota of the API client.
+func (r *Radish) clientQuota(client string) int {
+	r.reloadMu.RLock()
+	defer r.reloadMu.RUnlock()
+	return r.config.clientQuota(client)
+}
+
+// Reload implements the RadishServer interface, changing the reloadable settings of the
+// queue without restarting it. Settings that are zero valued in the request are left
+// unchanged unless the request loads the config with the LoadConfig function, in which
+// case the settings in the request are applied on top of the loaded config.
+func (r *Radish) Reload(ctx context.Context, in *api.ReloadRequest) (rep *api.ReloadReply, err error) {
+	rep = &api.ReloadReply{Success: true}
+	if err = r.reloadRequest(in); err != nil {
+		rep.Success = false
+
+		var ok bool
+		if rep.Error, ok = apiError(err); !ok {
+			return nil, fmt.Errorf("could not cast error to API error: %s", err)
+		}
+	}
+
+	current := r.settings()
+	rep.Workers = int32(current.Workers)
+	rep.LogLevel = current.LogLevel
+	rep.CautionThreshold = uint32(current.CautionThreshold)
+	rep.TaskQuota = int32(current.TaskQuota)
+	rep.ClientQuota = int32(current.ClientQuota)
+	return rep, nil
+}
+
+// reloadRequest applies the settings in the reload request.
+func (r *Radish) reloadRequest(in *api.ReloadRequest) (err error) {
+	if in.Workers < 0 {
+		return Errorf(CodeInvalidWorkers, "cannot set number of workers <0")
+	}
+
+	conf := r.settings()
+	if in.Load {
+		if conf, err = r.loadConfig(); err != nil {
+			return err
+		}
+	}
+
+	if in.Workers > 0 {
+		conf.Workers = int(in.Workers)
+	}
+	if in.LogLevel != "" {
+		conf.LogLevel = in.LogLevel
+	}
+	if in.CautionThreshold > 0 {
+		conf.CautionThreshold = uint(in.CautionThreshold)
+	}
+	if in.TaskQuota != 0 {
+		conf.TaskQuota = quotaLimit(in.TaskQuota)
+	}
+	if in.ClientQuota != 0 {
+		conf.ClientQuota = quotaLimit(in.ClientQuota)
+	}
+
+	return r.reload(conf, in.Load || in.Workers > 0)
+}
+
+// quotaLimit converts a quota in a reload request to a config quota, where -1 is unlimited.
+func quotaLimit(quota int32) int {
+	if quota < 0 {
+		return 0
+	}
+	return int(quota)
+}
@@ -0,0 +1,98 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload(t *testing.T) {
+	loaded := &Config{Workers: 5, LogLevel: "warn", TaskQuota: 20, TaskQuotas: map[string]int{"task": 1}}
+	var loadErr error
+	load := func() (*Config, error) {
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		conf := *loaded
+		return &conf, nil
+	}
+
+	queue, err := New(&Config{Workers: 2, NoSignals: true, LogLevel: "warn", LoadConfig: load}, &testTask{name: "task"})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Reloading the config applies the reloadable settings
+	require.NoError(t, queue.ReloadConfig(&Config{Workers: 3, LogLevel: "warn", TaskQuota: 1}))
+	require.Equal(t, 3, queue.NumWorkers())
+
+	_, err = queue.Delay("task", nil, nil, nil)
+	require.NoError(t, err)
+	_, err = queue.DryRun(context.Background(), "task", nil)
+	require.EqualError(t, err, "[11] task \"task\" has reached its quota of 1 queued futures")
+
+	require.EqualError(t, queue.ReloadConfig(&Config{LogLevel: "loud"}), "[1] \"loud\" is an invalid log level, use trace, debug, info, caution, status, warn, or silent")
+
+	// Zero valued settings in a reload request are left unchanged
+	rep, err := queue.Reload(context.Background(), &api.ReloadRequest{ClientQuota: 4, TaskQuota: -1})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	require.Equal(t, int32(3), rep.Workers)
+	require.Equal(t, "warn", rep.LogLevel)
+	require.Zero(t, rep.TaskQuota)
+	require.Equal(t, int32(4), rep.ClientQuota)
+
+	// Requests can load the config, applying the settings in the request on top of it
+	rep, err = queue.Reload(context.Background(), &api.ReloadRequest{Load: true, ClientQuota: 8})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	require.Equal(t, int32(5), rep.Workers)
+	require.Equal(t, int32(20), rep.TaskQuota)
+	require.Equal(t, int32(8), rep.ClientQuota)
+	require.Equal(t, 5, queue.NumWorkers())
+
+	loadErr = errors.New("no such file")
+	rep, err = queue.Reload(context.Background(), &api.ReloadRequest{Load: true})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, "could not load config: no such file", rep.Error.Message)
+
+	rep, err = queue.Reload(context.Background(), &api.ReloadRequest{Workers: -1})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, CodeInvalidWorkers, rep.Error.Code)
+
+	// The config cannot be reloaded once the queue has been shutdown
+	require.NoError(t, queue.Shutdown())
+	require.EqualError(t, queue.ReloadConfig(&Config{}), "[10] cannot reload the config of a queue that has been shutdown")
+}
+
+func TestReloadSettings(t *testing.T) {
+	policy := AutoscalePolicy{MinWorkers: 2, MaxWorkers: 4, Interval: time.Hour}
+	queue, err := New(&Config{Workers: 2, NoSignals: true, SuppressMetrics: true, LogLevel: "silent", Autoscale: policy}, &testTask{name: "task"})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Only the reloadable settings are validated, so the other options are not created
+	conf := &Config{Workers: 8, LogLevel: "SILENT", QueueOrder: "random", TaskQuota: 3}
+	require.NoError(t, queue.ReloadConfig(conf))
+	require.Nil(t, conf.Broker)
+	require.Equal(t, "silent", conf.LogLevel)
+
+	// The autoscaler manages the workers, so the reload does not change them
+	require.Equal(t, 2, queue.NumWorkers())
+	rep, err := queue.Reload(context.Background(), &api.ReloadRequest{Workers: 6})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	require.Equal(t, int32(2), rep.Workers)
+	require.Equal(t, int32(3), rep.TaskQuota)
+
+	// Once autoscaling is disabled the workers are reloaded again
+	require.NoError(t, queue.SetAutoscale(false, 0, 0))
+	require.NoError(t, queue.ReloadConfig(&Config{Workers: 3, LogLevel: "silent"}))
+	require.Equal(t, 3, queue.NumWorkers())
+}
@@ -0,0 +1,357 @@
+package radish
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// Work is a bidirectional stream that remote worker processes open to pull futures from
+// the queue. The worker first says hello with the tasks it has handlers for and the
+// number of futures it handles at once; tasks that are not registered with the queue are
+// registered as remote tasks and the queue adds that many workers to dispatch futures to
+// the remote worker until the stream is closed. Futures of remote tasks are assigned to
+// the connected remote worker with the fewest futures in flight and complete once the
+// worker streams back the result. Futures that are being handled by a worker when its
+// stream is closed fail with an ErrBadGateway error, so they are retried by the retry
+// policy of the task, if any.
+func (r *Radish) Work(stream api.Radish_WorkServer) (err error) {
+	var msg *api.WorkerMessage
+	if msg, err = stream.Recv(); err != nil {
+		return err
+	}
+
+	hello := msg.GetHello()
+	if hello == nil || len(hello.Tasks) == 0 {
+		return Errorf(CodeInvalidWorkers, "remote workers must say hello with the tasks they handle")
+	}
+
+	concurrency := int(hello.Concurrency)
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	select {
+	case <-r.shutdown:
+		return Errorf(CodeShutdown, "queue has been shutdown, not accepting remote workers")
+	default:
+	}
+
+	if err = r.registerRemote(hello.Tasks); err != nil {
+		return err
+	}
+
+	if err = r.AddWorkers(concurrency); err != nil {
+		return err
+	}
+
+	w := &remoteWorker{name: hello.Name, tasks: hello.Tasks, stream: stream, pending: make(map[string]chan *api.WorkResult), done: make(chan struct{})}
+	r.remotes.add(w)
+	r.logf(out.LevelStatus, "", "remote worker %s connected to handle %v with %d workers", w.name, w.tasks, concurrency)
+
+	defer func() {
+		// Fail the futures assigned to the worker before waiting for the workers to stop
+		r.remotes.remove(w)
+		select {
+		case <-r.shutdown:
+		default:
+			if err := r.RemoveWorkers(concurrency); err != nil {
+				r.logf(out.LevelWarn, "", "could not remove the workers of remote worker %s: %s", w.name, err)
+			}
+		}
+		r.logf(out.LevelStatus, "", "remote worker %s disconnected", w.name)
+	}()
+
+	// Receive results until the stream is closed
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				errc <- err
+				return
+			}
+
+			if result := msg.GetResult(); result != nil {
+				w.deliver(result)
+			}
+		}
+	}()
+
+	select {
+	case <-errc:
+		return nil
+	case <-stream.Context().Done():
+		return nil
+	case <-r.shutdown:
+		return nil
+	}
+}
+
+// registerRemote registers a remote task for each of the tasks that are not registered
+// with the queue, returning an error if a task is handled by a local handler.
+func (r *Radish) registerRemote(tasks []string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, task := range tasks {
+		handler, ok := r.handlers[task]
+		if !ok {
+			r.handlers[task] = &remoteTask{name: task, remotes: r.remotes}
+			r.logf(out.LevelInfo, task, "registered remote task %s", task)
+			continue
+		}
+
+		if _, ok := handler.(*remoteTask); !ok {
+			return Errorf(CodeTaskAlreadyRegistered, "task named %q is handled by a local handler", task)
+		}
+	}
+	return nil
+}
+
+// remoteTask is registered for the tasks that are handled by remote workers, assigning
+// the futures of the task to a connected remote worker.
+type remoteTask struct {
+	name    string
+	remotes *remoteWorkers
+}
+
+func (t *remoteTask) Name() string {
+	return t.name
+}
+
+// Handle the future on a remote worker; workers call handle directly so that the
+// metadata and attempt of the future are sent to the remote worker.
+func (t *remoteTask) Handle(id uuid.UUID, params []byte) error {
+	_, err := t.handle(context.Background(), &Future{ID: id, Task: t.name, Params: params})
+	return err
+}
+
+func (t *remoteTask) Success(id uuid.UUID, params []byte) {
+	t.remotes.callback(&api.WorkAssignment{Kind: api.WorkAssignment_SUCCESS, Uuid: id, Task: t.name, Params: params})
+}
+
+func (t *remoteTask) Failure(id uuid.UUID, err error, params []byte) {
+	e, ok := apiError(err)
+	if !ok {
+		e = &api.Error{Code: CodeUnknown, Message: err.Error()}
+	}
+	t.remotes.callback(&api.WorkAssignment{Kind: api.WorkAssignment_FAILURE, Uuid: id, Task: t.name, Params: params, Error: e})
+}
+
+// handle assigns the future to the least busy remote worker of the task and waits for its
+// result, signaling the worker to cancel the future if the context is canceled.
+func (t *remoteTask) handle(ctx context.Context, future *Future) (_ []byte, err error) {
+	w, results := t.remotes.assign(future)
+	if w == nil {
+		return nil, Errorf(CodeNoWorkers, "no remote workers are connected to handle task %q", t.name)
+	}
+	defer w.finish(future.ID)
+
+	assignment := &api.WorkAssignment{
+		Kind:     api.WorkAssignment_HANDLE,
+		Uuid:     future.ID,
+		Task:     future.Task,
+		Params:   future.Params,
+		Metadata: future.Metadata,
+		Attempt:  int32(future.Attempts),
+	}
+	if err = w.send(assignment); err != nil {
+		return nil, Errorf(CodeBadGateway, "could not assign %s future %s to remote worker %s: %s", future.Task, future.ID, w.name, err)
+	}
+
+	canceled := ctx.Done()
+	for {
+		select {
+		case result := <-results:
+			if !result.Success {
+				if result.Error == nil {
+					return nil, Errorf(CodeUnknown, "remote worker %s did not specify an error", w.name)
+				}
+				return nil, result.Error
+			}
+			return result.Result, nil
+		case <-canceled:
+			// Wait for the worker to report the outcome of the canceled future
+			canceled = nil
+			w.send(&api.WorkAssignment{Kind: api.WorkAssignment_CANCEL, Uuid: future.ID, Task: future.Task})
+		case <-w.done:
+			return nil, Errorf(CodeBadGateway, "remote worker %s disconnected while handling %s future %s", w.name, future.Task, future.ID)
+		}
+	}
+}
+
+// remoteWorkers are the remote workers connected to the queue and the worker each future
+// was last assigned to, so that the callbacks of the future are called by that worker.
+type remoteWorkers struct {
+	sync.Mutex
+	workers  []*remoteWorker
+	assigned map[string]*remoteWorker
+}
+
+func newRemoteWorkers() *remoteWorkers {
+	return &remoteWorkers{assigned: make(map[string]*remoteWorker)}
+}
+
+func (r *remoteWorkers) add(w *remoteWorker) {
+	r.Lock()
+	defer r.Unlock()
+	r.workers = append(r.workers, w)
+}
+
+// remove the worker once its stream is closed, failing the futures assigned to it.
+func (r *remoteWorkers) remove(w *remoteWorker) {
+	r.Lock()
+	defer r.Unlock()
+	for i, worker := range r.workers {
+		if worker == w {
+			r.workers = append(r.workers[:i:i], r.workers[i+1:]...)
+			break
+		}
+	}
+
+	for id, worker := range r.assigned {
+		if worker == w {
+			delete(r.assigned, id)
+		}
+	}
+	w.close()
+}
+
+// assign the future to the connected worker of its task with the fewest futures in
+// flight, returning the worker and the channel its result is delivered on, or nil if no
+// worker handles the task.
+func (r *remoteWorkers) assign(future *Future) (assigned *remoteWorker, results chan *api.WorkResult) {
+	r.Lock()
+	defer r.Unlock()
+	for _, w := range r.workers {
+		if !w.handles(future.Task) {
+			continue
+		}
+
+		if assigned == nil || w.inflight() < assigned.inflight() {
+			assigned = w
+		}
+	}
+
+	if assigned != nil {
+		results = assigned.start(future.ID)
+		r.assigned[future.ID.String()] = assigned
+	}
+	return assigned, results
+}
+
+// status returns the connected remote workers that handle any of the included tasks.
+func (r *remoteWorkers) status(include func(task string) bool) []*api.RemoteWorkerStatus {
+	r.Lock()
+	defer r.Unlock()
+
+	statuses := make([]*api.RemoteWorkerStatus, 0, len(r.workers))
+	for _, w := range r.workers {
+		for _, task := range w.tasks {
+			if include(task) {
+				statuses = append(statuses, &api.RemoteWorkerStatus{Name: w.name, Tasks: w.tasks, InFlight: int32(w.inflight())})
+				break
+			}
+		}
+	}
+	return statuses
+}
+
+// callback sends the success or failure callback of the future to the worker it was last
+// assigned to, or to any worker of the task if the future was not handled, e.g. if it was
+// canceled while queued.
+func (r *remoteWorkers) callback(assignment *api.WorkAssignment) {
+	id := uuid.UUID(assignment.Uuid).String()
+	r.Lock()
+	w, ok := r.assigned[id]
+	delete(r.assigned, id)
+	if !ok {
+		for _, worker := range r.workers {
+			if worker.handles(assignment.Task) {
+				w = worker
+				break
+			}
+		}
+	}
+	r.Unlock()
+
+	if w != nil {
+		w.send(assignment)
+	}
+}
+
+// remoteWorker is a worker process connected by the Work stream.
+type remoteWorker struct {
+	sync.Mutex
+	name    string
+	tasks   []string
+	stream  api.Radish_WorkServer
+	sendMu  sync.Mutex                      // streams do not support concurrent sends
+	pending map[string]chan *api.WorkResult // the futures assigned to the worker, by id
+	done    chan struct{}                   // closed when the stream of the worker is closed
+}
+
+func (w *remoteWorker) handles(task string) bool {
+	for _, t := range w.tasks {
+		if t == task {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *remoteWorker) inflight() int {
+	w.Lock()
+	defer w.Unlock()
+	return len(w.pending)
+}
+
+func (w *remoteWorker) start(id uuid.UUID) chan *api.WorkResult {
+	w.Lock()
+	defer w.Unlock()
+	results := make(chan *api.WorkResult, 1)
+	w.pending[id.String()] = results
+	return results
+}
+
+func (w *remoteWorker) finish(id uuid.UUID) {
+	w.Lock()
+	defer w.Unlock()
+	delete(w.pending, id.String())
+}
+
+// deliver the result to the future it was streamed back for, ignoring the results of
+// futures that are no longer assigned to the worker.
+func (w *remoteWorker) deliver(result *api.WorkResult) {
+	w.Lock()
+	defer w.Unlock()
+	if results, ok := w.pending[uuid.UUID(result.Uuid).String()]; ok {
+		select {
+		case results <- result:
+		default:
+		}
+	}
+}
+
+// send the assignment to the worker unless its stream has been closed.
+func (w *remoteWorker) send(assignment *api.WorkAssignment) error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+	select {
+	case <-w.done:
+		return Errorf(CodeBadGateway, "remote worker %s has disconnected", w.name)
+	default:
+		return w.stream.Send(assignment)
+	}
+}
+
+// close the worker so that nothing is sent on its stream after the stream is closed.
+func (w *remoteWorker) close() {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+	close(w.done)
+}
@@ -0,0 +1,84 @@
+package radish
+
+import (
+	"sort"
+)
+
+// ResourceConsumer may be implemented by tasks that consume named shared resources such
+// as a database connection pool or an SMTP relay. Before a worker handles a future, it
+// acquires a slot from each of the resources the task consumes, waiting if the resource
+// is at capacity, so that concurrent tasks do not overload a shared dependency even when
+// plenty of workers are free. Resources and their capacities are defined by the
+// Resources config option; tasks that consume undefined resources cannot be registered.
+type ResourceConsumer interface {
+	Resources() []string // the names of the resources consumed by the task
+}
+
+// semaphore limits the number of tasks concurrently consuming a resource.
+type semaphore chan struct{}
+
+func newSemaphores(capacities map[string]int) map[string]semaphore {
+	sems := make(map[string]semaphore, len(capacities))
+	for name, capacity := range capacities {
+		sems[name] = make(semaphore, capacity)
+	}
+	return sems
+}
+
+// acquireSlot blocks until fewer than the concurrency limit of the task, if any, are being
+// handled. The returned function releases the slot.
+func (r *Radish) acquireSlot(task string) (release func()) {
+	sem, ok := r.concurrency[task]
+	if !ok {
+		return func() {}
+	}
+
+	sem <- struct{}{}
+	return func() { <-sem }
+}
+
+// checkResources returns an error if the task consumes resources that are not defined.
+func (r *Radish) checkResources(task Task) error {
+	consumer, ok := task.(ResourceConsumer)
+	if !ok {
+		return nil
+	}
+
+	for _, name := range consumer.Resources() {
+		if _, ok := r.resources[name]; !ok {
+			return Errorf(CodeInvalidConfig, "task %q consumes undefined resource %q", task.Name(), name)
+		}
+	}
+	return nil
+}
+
+// acquireResources blocks until a slot is available in each of the resources consumed by
+// the task. Resources are always acquired in sorted order so that tasks that consume
+// overlapping resources cannot deadlock. The returned function releases the resources.
+func (r *Radish) acquireResources(task Task) (release func()) {
+	consumer, ok := task.(ResourceConsumer)
+	if !ok {
+		return func() {}
+	}
+
+	names := append([]string(nil), consumer.Resources()...)
+	sort.Strings(names)
+
+	acquired := make([]semaphore, 0, len(names))
+	for i, name := range names {
+		if i > 0 && name == names[i-1] {
+			continue
+		}
+
+		if sem, ok := r.resources[name]; ok {
+			sem <- struct{}{}
+			acquired = append(acquired, sem)
+		}
+	}
+
+	return func() {
+		for _, sem := range acquired {
+			<-sem
+		}
+	}
+}
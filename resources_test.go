@@ -0,0 +1,102 @@
+package radish_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResources(t *testing.T) {
+	_, err := New(&Config{NoSignals: true, Resources: map[string]int{"db": 0}})
+	require.EqualError(t, err, `[1] resource "db" must have a capacity of at least 1`)
+
+	queue, err := New(&Config{Workers: 4, NoSignals: true, Resources: map[string]int{"db": 2}})
+	require.NoError(t, err)
+	require.EqualError(t, queue.Register(&resourceTask{testTask: testTask{name: "smtp"}, resources: []string{"smtp"}}), `[1] task "smtp" consumes undefined resource "smtp"`)
+
+	// Track the maximum number of tasks concurrently consuming the db
+	var running, peak int32
+	wg := new(sync.WaitGroup)
+	wg.Add(8)
+
+	task := &resourceTask{resources: []string{"db", "db"}, testTask: testTask{wg: wg, name: "query", onHandle: func(id uuid.UUID, params []byte) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}}}
+	require.NoError(t, queue.Register(task))
+
+	for i := 0; i < 8; i++ {
+		_, err = queue.Delay(task.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	wg.Wait()
+	require.Equal(t, int32(2), atomic.LoadInt32(&peak))
+}
+
+func TestTaskConcurrency(t *testing.T) {
+	_, err := New(&Config{NoSignals: true, TaskConcurrency: map[string]int{"geocode": -1}})
+	require.EqualError(t, err, `[1] task "geocode" must have a concurrency of at least 1`)
+
+	// Track the maximum number of futures of each task handled at once
+	var running, peak, others int32
+	wg := new(sync.WaitGroup)
+	wg.Add(12)
+
+	limited := &testTask{wg: wg, name: "geocode", onHandle: func(id uuid.UUID, params []byte) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}}
+	unlimited := &testTask{wg: wg, name: "other", onHandle: func(id uuid.UUID, params []byte) error {
+		atomic.AddInt32(&others, 1)
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 6, NoSignals: true, TaskConcurrency: map[string]int{"geocode": 2}}, limited, unlimited)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	for i := 0; i < 8; i++ {
+		_, err = queue.Delay(limited.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < 4; i++ {
+		_, err = queue.Delay(unlimited.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	wg.Wait()
+	require.Equal(t, int32(2), atomic.LoadInt32(&peak))
+	require.Equal(t, int32(4), atomic.LoadInt32(&others))
+}
+
+type resourceTask struct {
+	testTask
+	resources []string
+}
+
+func (t *resourceTask) Resources() []string {
+	return t.resources
+}
@@ -0,0 +1,101 @@
+package radish
+
+import (
+	"context"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+)
+
+// ResultTask may be implemented by tasks that return a result payload when they are
+// handled. If a task implements ResultTask, workers call HandleResult instead of Handle
+// (or HandleContext) and the result is stored with the record of the future so that
+// callers can block for or fetch it with Wait or the Result RPC. The context is canceled
+// when Cancel is called with the ID of the future, as with ContextTask.
+type ResultTask interface {
+	HandleResult(ctx context.Context, id uuid.UUID, params []byte) ([]byte, error)
+}
+
+// Wait blocks until the future with the specified id has completed or the context is
+// done, returning the result of the handler if the future succeeded or the error that
+// caused the future to fail or be canceled. Results are only available until the record
+// of the future is evicted, after which an ErrNotFound error is returned. Futures that
+// were forwarded to a peer of the cluster are waited on by requesting their result from
+// the peer.
+func (r *Radish) Wait(ctx context.Context, id uuid.UUID) (result []byte, err error) {
+	if client, peerID, ok := r.forwarded(id); ok {
+		var rep *api.ResultReply
+		if rep, err = client.Result(r.peerContext(ctx), &api.ResultRequest{Uuid: peerID, Wait: true}); err != nil {
+			return nil, Errorf(CodeBadGateway, "could not wait for forwarded future %s: %s", id, err)
+		}
+
+		switch {
+		case !rep.Success:
+			return nil, rep.Error
+		case rep.Future.Error != nil:
+			return nil, rep.Future.Error
+		default:
+			return rep.Result, nil
+		}
+	}
+
+	rec, err := r.wait(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return rec.result, rec.err
+}
+
+// wait blocks until the future has completed or the context is done, returning its record.
+func (r *Radish) wait(ctx context.Context, id uuid.UUID) (rec record, err error) {
+	for {
+		var ok bool
+		if rec, ok = r.results.get(id); !ok {
+			return record{}, Errorf(CodeNotFound, "future %s not found", id)
+		}
+
+		if rec.finished() {
+			return rec, nil
+		}
+
+		// The done channel is also closed if the record is removed, so check it again
+		select {
+		case <-rec.done:
+		case <-ctx.Done():
+			return record{}, Errorf(CodeCanceled, "stopped waiting for future %s: %s", id, ctx.Err())
+		}
+	}
+}
+
+// Result returns the result of the future with the specified id. If wait is specified,
+// the request blocks until the future has completed or the request deadline is exceeded,
+// otherwise the current state of the future is returned with the result if it succeeded.
+func (r *Radish) Result(ctx context.Context, in *api.ResultRequest) (rep *api.ResultReply, err error) {
+	id := uuid.UUID(in.Uuid)
+	if client, peerID, ok := r.forwarded(id); ok {
+		if rep, err = client.Result(r.peerContext(ctx), &api.ResultRequest{Uuid: peerID, Wait: in.Wait}); err != nil {
+			err = Errorf(CodeBadGateway, "could not get the result of forwarded future %s: %s", id, err)
+			return &api.ResultReply{Success: false, Error: err.(*api.Error)}, nil
+		}
+
+		if rep.Future != nil {
+			rep.Future.Uuid = in.Uuid
+		}
+		return rep, nil
+	}
+
+	var rec record
+	if in.Wait {
+		rec, err = r.wait(ctx, id)
+	} else {
+		var ok bool
+		if rec, ok = r.results.get(id); !ok {
+			err = Errorf(CodeNotFound, "future %s not found", id)
+		}
+	}
+
+	if err != nil {
+		return &api.ResultReply{Success: false, Error: err.(*api.Error)}, nil
+	}
+	return &api.ResultReply{Success: true, Future: rec.info(in.Uuid), Result: rec.result}, nil
+}
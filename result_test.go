@@ -0,0 +1,75 @@
+package radish_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWait(t *testing.T) {
+	queue, err := New(&Config{Workers: 2, NoSignals: true})
+	require.NoError(t, err)
+
+	wg := new(sync.WaitGroup)
+	task := &resultTask{testTask: testTask{wg: wg, name: "upper"}}
+	require.NoError(t, queue.Register(task))
+
+	wg.Add(2)
+	good, err := queue.Delay("upper", []byte("hello"), nil, nil)
+	require.NoError(t, err)
+	bad, err := queue.Delay("upper", nil, nil, nil)
+	require.NoError(t, err)
+
+	result, err := queue.Wait(context.Background(), good)
+	require.NoError(t, err)
+	require.Equal(t, []byte("HELLO"), result)
+
+	result, err = queue.Wait(context.Background(), bad)
+	require.EqualError(t, err, "no params to upper")
+	require.Nil(t, result)
+	wg.Wait()
+
+	// Fetch the result with the RPC without waiting
+	rep, err := queue.Result(context.Background(), &api.ResultRequest{Uuid: good})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+	require.Equal(t, api.FutureState_SUCCEEDED, rep.Future.State)
+	require.Equal(t, []byte("HELLO"), rep.Result)
+
+	// Waiting on a future that does not complete before the deadline
+	scheduled, err := queue.DelayAfter("upper", time.Hour, []byte("later"), nil, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = queue.Wait(ctx, scheduled)
+	require.EqualError(t, err, "[7] stopped waiting for future "+scheduled.String()+": context deadline exceeded")
+
+	// Unknown futures cannot be waited on
+	unknown := uuid.NewRandom()
+	_, err = queue.Wait(context.Background(), unknown)
+	require.EqualError(t, err, "[13] future "+unknown.String()+" not found")
+}
+
+type resultTask struct {
+	testTask
+}
+
+func (t *resultTask) HandleResult(ctx context.Context, id uuid.UUID, params []byte) ([]byte, error) {
+	if err := t.Handle(id, params); err != nil {
+		return nil, err
+	}
+
+	if len(params) == 0 {
+		return nil, errors.New("no params to upper")
+	}
+	return bytes.ToUpper(params), nil
+}
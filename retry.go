@@ -0,0 +1,168 @@
+package radish
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+)
+
+// Default retry backoff.
+const (
+	defaultRetryDelay      = 1 * time.Second
+	defaultRetryMultiplier = 2.0
+)
+
+// RetryPolicy determines if and when failed futures are retried. A failed future is
+// re-enqueued after an exponential backoff until it has been attempted MaxAttempts
+// times; the Failure callback of the task is only called after the final attempt. The
+// zero value does not retry failed futures.
+type RetryPolicy struct {
+	MaxAttempts int           // the maximum number of times a future is handled, including the first attempt (default 1, no retries)
+	BaseDelay   time.Duration // the delay before the first retry (default 1 second)
+	Multiplier  float64       // the factor the delay is multiplied by after each retry (default 2)
+	MaxDelay    time.Duration // the maximum delay between retries (default no maximum)
+}
+
+// RetryTask may be implemented by tasks that override the Retry policy in the config,
+// e.g. so that network-bound tasks are retried many times while tasks that are not safe
+// to repeat are never retried. If RetryPolicy returns nil the config policy is used. The
+// policy is validated when the task is registered and zero valued options are defaulted
+// as in the config, so a policy with MaxAttempts of 0 or 1 disables retries for the task.
+type RetryTask interface {
+	RetryPolicy() *RetryPolicy
+}
+
+// validate the retry policy and populate any defaults for zero valued options.
+func (p *RetryPolicy) validate() error {
+	if p.MaxAttempts < 0 {
+		return Errorf(CodeInvalidConfig, "retry policy max attempts cannot be negative")
+	}
+
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultRetryDelay
+	}
+
+	if p.Multiplier == 0 {
+		p.Multiplier = defaultRetryMultiplier
+	}
+
+	if p.Multiplier < 1 {
+		return Errorf(CodeInvalidConfig, "retry policy multiplier must be at least 1")
+	}
+	return nil
+}
+
+// backoff returns the delay before the next attempt of a future that has been attempted
+// the specified number of times.
+func (p *RetryPolicy) backoff(attempts int) time.Duration {
+	delay := time.Duration(float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(attempts-1)))
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// checkRetryPolicy ensures the retry policy of the task is valid before it is registered.
+func checkRetryPolicy(task Task) error {
+	policy := retryPolicyOf(task)
+	if policy == nil {
+		return nil
+	}
+
+	if err := policy.validate(); err != nil {
+		return Errorf(CodeInvalidConfig, "task %q has an invalid retry policy: %s", task.Name(), err)
+	}
+	return nil
+}
+
+// retryPolicy returns the retry policy of the task, or the config policy if the task
+// does not override it.
+func (r *Radish) retryPolicy(task Task) *RetryPolicy {
+	if policy := retryPolicyOf(task); policy != nil {
+		// The policy is valid since it was checked when the task was registered
+		policy.validate()
+		return policy
+	}
+	return &r.config.Retry
+}
+
+// retryPolicyOf returns a copy of the retry policy of the task, if it overrides the
+// config policy, so that defaults can be populated without modifying the task.
+func retryPolicyOf(task Task) *RetryPolicy {
+	if retrier, ok := task.(RetryTask); ok {
+		if policy := retrier.RetryPolicy(); policy != nil {
+			override := *policy
+			return &override
+		}
+	}
+	return nil
+}
+
+// retry the failed future if it has attempts remaining, returning false if the future
+// should be completed instead. The future is re-enqueued after the backoff in the
+// background; if the queue is shutdown before then, the future fails with the error.
+func (r *Radish) retry(task *Future, handler Task, err error, start time.Time) bool {
+	policy := r.retryPolicy(handler)
+	if task.Attempts >= policy.MaxAttempts {
+		return false
+	}
+
+	delay := policy.backoff(task.Attempts)
+	r.logf(out.LevelInfo, task.Task, "%s future %s failed on attempt %d, retrying in %s: %s", task.Task, task.ID, task.Attempts, delay, err)
+	pmTasksRetried.WithLabelValues(task.Task).Inc()
+
+	// The future is tracked as queued while it waits so that it can be canceled
+	r.inflight.enqueue(task.ID)
+
+	go func() {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			if r.requeue(context.Background(), task) == nil {
+				return
+			}
+		case <-r.shutdown:
+			if r.store.durable() {
+				// The future is retried from storage when the queue is restarted
+				r.inflight.drop(task.ID)
+				return
+			}
+		}
+
+		r.inflight.drop(task.ID)
+		r.complete(task, handler, err, start)
+	}()
+	return true
+}
+
+// requeue a future that was already accepted into the queue, e.g. to retry it. The
+// future is counted against the quotas again until it is dequeued but is never rejected.
+// The future must be tracked as queued by the inflight tracker before it is requeued; if
+// the future cannot be requeued the caller must complete or remove its record.
+func (r *Radish) requeue(ctx context.Context, task *Future) error {
+	r.partitions.add(task)
+	r.queued.acquire(task.Task, task.ID, 0)
+	if task.client != "" {
+		r.clients.acquire(task.client, task.ID, 0)
+	}
+
+	if err := r.store.put(task, time.Time{}); err != nil {
+		r.logf(out.LevelWarn, task.Task, "%s", err)
+	}
+
+	r.results.track(task, api.FutureState_QUEUED)
+	if err := r.enqueue(ctx, task); err != nil {
+		r.releaseQuotas(task)
+		r.releasePartition(task)
+		if err == errQueueShutdown {
+			return Errorf(CodeShutdown, "could not requeue %s future %s: queue has been shutdown", task.Task, task.ID)
+		}
+		return Errorf(CodeCanceled, "could not requeue %s future %s: %s", task.Task, task.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package radish_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetries(t *testing.T) {
+	_, err := New(&Config{NoSignals: true, Retry: RetryPolicy{MaxAttempts: -1}})
+	require.EqualError(t, err, "[1] retry policy max attempts cannot be negative")
+
+	_, err = New(&Config{NoSignals: true, Retry: RetryPolicy{MaxAttempts: 3, Multiplier: 0.5}})
+	require.EqualError(t, err, "[1] retry policy multiplier must be at least 1")
+
+	queue, err := New(&Config{Workers: 2, NoSignals: true, Retry: RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond}})
+	require.NoError(t, err)
+
+	// The flaky task succeeds on its third attempt
+	var flakes int32
+	wg := new(sync.WaitGroup)
+	flaky := &testTask{wg: wg, name: "flaky", onHandle: func(id uuid.UUID, params []byte) error {
+		if atomic.AddInt32(&flakes, 1) < 3 {
+			return errors.New("try again")
+		}
+		return nil
+	}}
+
+	// The broken task fails on every attempt
+	var failure error
+	broken := &testTask{wg: wg, name: "broken",
+		onHandle:  func(id uuid.UUID, params []byte) error { return errors.New("whoops!") },
+		onFailure: func(id uuid.UUID, err error, params []byte) { failure = err },
+	}
+	require.NoError(t, queue.Register(flaky))
+	require.NoError(t, queue.Register(broken))
+
+	wg.Add(2)
+	start := time.Now()
+	_, err = queue.Delay("flaky", nil, nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Delay("broken", nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	// Failure is only called after the final attempt, backing off between attempts
+	require.True(t, time.Since(start) >= 30*time.Millisecond)
+	require.Equal(t, int32(3), flaky.handled)
+	require.Equal(t, int32(1), flaky.successes)
+	require.Zero(t, flaky.failures)
+	require.Equal(t, int32(3), broken.handled)
+	require.Equal(t, int32(1), broken.failures)
+	require.EqualError(t, failure, "whoops!")
+}
+
+// retryTask overrides the retry policy of the queue.
+type retryTask struct {
+	*testTask
+	policy *RetryPolicy
+}
+
+func (t *retryTask) RetryPolicy() *RetryPolicy {
+	return t.policy
+}
+
+func TestRetryPolicyOverrides(t *testing.T) {
+	queue, err := New(&Config{Workers: 2, NoSignals: true, Retry: RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond}})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	err = queue.Register(&retryTask{testTask: &testTask{name: "invalid"}, policy: &RetryPolicy{MaxAttempts: -1}})
+	require.EqualError(t, err, "[1] task \"invalid\" has an invalid retry policy: [1] retry policy max attempts cannot be negative")
+
+	wg := new(sync.WaitGroup)
+	fail := func(id uuid.UUID, params []byte) error { return errors.New("whoops!") }
+
+	// Tasks can retry more or less than the config policy, or use it by returning nil
+	network := &retryTask{testTask: &testTask{wg: wg, name: "network", onHandle: fail}, policy: &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}}
+	report := &retryTask{testTask: &testTask{wg: wg, name: "report", onHandle: fail}, policy: &RetryPolicy{}}
+	defaults := &retryTask{testTask: &testTask{wg: wg, name: "defaults", onHandle: fail}}
+	require.NoError(t, queue.Register(network))
+	require.NoError(t, queue.Register(report))
+	require.NoError(t, queue.Register(defaults))
+
+	wg.Add(3)
+	for _, task := range []string{"network", "report", "defaults"} {
+		_, err = queue.Delay(task, nil, nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(5), atomic.LoadInt32(&network.handled))
+	require.Equal(t, int32(1), atomic.LoadInt32(&report.handled))
+	require.Equal(t, int32(3), atomic.LoadInt32(&defaults.handled))
+	require.Zero(t, report.policy.BaseDelay, "the policy of the task should not be modified")
+}
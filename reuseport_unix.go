@@ -0,0 +1,23 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package radish
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// reusePort sets SO_REUSEPORT on the socket so that multiple processes can bind to the
+// same address, which allows a new radish process to take over the listen address while
+// the old process hands off its pending futures.
+func reusePort(network, address string, conn syscall.RawConn) (err error) {
+	ctrlErr := conn.Control(func(fd uintptr) {
+		err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	})
+	if ctrlErr != nil {
+		return ctrlErr
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+package radish
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// DelayAt creates a new future that is added to the task queue at the specified time,
+// returning its id immediately. If the time has already passed, the future is queued
+// right away as with Delay.
+func (r *Radish) DelayAt(task string, at time.Time, params, success, failure []byte) (id uuid.UUID, err error) {
+	return r.delayAt(context.Background(), task, at, params, success, failure)
+}
+
+// DelayAfter creates a new future that is added to the task queue once the specified
+// duration has elapsed, returning its id immediately.
+func (r *Radish) DelayAfter(task string, d time.Duration, params, success, failure []byte) (id uuid.UUID, err error) {
+	return r.delayAt(context.Background(), task, time.Now().Add(d), params, success, failure)
+}
+
+// delayAt schedules a future with the options on the context to be queued at the
+// specified time. The task and params are checked when the future is scheduled, but the
+// quotas are not applied since the future is not queued until it is due. Scheduled
+// futures can be canceled before they are due and are dropped if the queue is shutdown,
+// unless storage is durable in which case they are restored when the queue restarts.
+func (r *Radish) delayAt(ctx context.Context, task string, at time.Time, params, success, failure []byte) (id uuid.UUID, err error) {
+	if !at.After(time.Now()) {
+		return r.DelayContext(ctx, task, params, success, failure)
+	}
+
+	if task, err = r.check(ctx, task, params); err != nil {
+		return nil, err
+	}
+
+	future := r.newFuture(ctx, task, params, success, failure)
+
+	// Return the existing future if a future was delayed with the same idempotency key
+	existing, release := r.claim(future)
+	if existing != nil {
+		return existing, nil
+	}
+	defer func() { release(id) }()
+
+	if err = r.store.put(future, at); err != nil {
+		return nil, err
+	}
+
+	r.inflight.enqueue(future.ID)
+	r.results.track(future, api.FutureState_SCHEDULED)
+	r.scheduled.add(future, at)
+	r.stats.queue(future.Task)
+	r.audit(AuditQueued, future, nil)
+	r.logf(out.LevelDebug, task, "scheduled %s future %s for %s", task, future.ID, at.Format(time.RFC3339))
+	return future.ID, nil
+}
+
+// scheduleLoop queues scheduled futures when they are due until the queue is shutdown.
+func (r *Radish) scheduleLoop() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		// Reset the timer for the next scheduled future
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+
+		if next, ok := r.scheduled.next(); ok {
+			timer.Reset(time.Until(next))
+		} else {
+			timer.Reset(time.Hour)
+		}
+
+		select {
+		case <-r.shutdown:
+			if n := r.scheduled.len(); n > 0 && r.store.durable() {
+				r.logf(out.LevelInfo, "", "%d scheduled futures that were not yet due will be restored on restart", n)
+			} else if n > 0 {
+				r.logf(out.LevelWarn, "", "dropped %d scheduled futures that were not yet due", n)
+			}
+			return
+		case <-r.scheduled.wake:
+		case now := <-timer.C:
+			for _, future := range r.scheduled.due(now) {
+				if err := r.requeue(context.Background(), future); err != nil {
+					r.inflight.drop(future.ID)
+					r.results.remove(future.ID)
+					r.logf(out.LevelWarn, future.Task, "could not queue scheduled %s future %s: %s", future.Task, future.ID, err)
+				}
+			}
+		}
+	}
+}
+
+// schedule holds futures that are not yet due ordered by the time they are due.
+type schedule struct {
+	sync.Mutex
+	futures scheduleHeap
+	wake    chan struct{} // notifies the schedule loop when the next due time changes
+}
+
+func newSchedule() *schedule {
+	return &schedule{futures: make(scheduleHeap, 0), wake: make(chan struct{}, 1)}
+}
+
+// add a future to the schedule, waking the schedule loop if it is due next.
+func (s *schedule) add(future *Future, at time.Time) {
+	s.Lock()
+	heap.Push(&s.futures, &scheduled{future: future, at: at})
+	next := s.futures[0].future == future
+	s.Unlock()
+
+	if next {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// next returns the time the next future is due, or false if no futures are scheduled.
+func (s *schedule) next() (time.Time, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.futures) == 0 {
+		return time.Time{}, false
+	}
+	return s.futures[0].at, true
+}
+
+// due removes and returns the futures that are due at the specified time.
+func (s *schedule) due(now time.Time) (futures []*Future) {
+	s.Lock()
+	defer s.Unlock()
+
+	for len(s.futures) > 0 && !s.futures[0].at.After(now) {
+		futures = append(futures, heap.Pop(&s.futures).(*scheduled).future)
+	}
+	return futures
+}
+
+// len returns the number of scheduled futures.
+func (s *schedule) len() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.futures)
+}
+
+// status returns the number of scheduled futures of the tasks that are included and when
+// the next of them is due.
+func (s *schedule) status(include func(task string) bool) (n int, next time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, item := range s.futures {
+		if !include(item.future.Task) {
+			continue
+		}
+
+		n++
+		if next.IsZero() || item.at.Before(next) {
+			next = item.at
+		}
+	}
+	return n, next
+}
+
+// scheduled is a future that will be queued at the specified time.
+type scheduled struct {
+	future *Future
+	at     time.Time
+}
+
+// scheduleHeap implements heap.Interface, ordering futures by the time they are due.
+type scheduleHeap []*scheduled
+
+func (h scheduleHeap) Len() int           { return len(h) }
+func (h scheduleHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h scheduleHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *scheduleHeap) Push(x interface{}) { *h = append(*h, x.(*scheduled)) }
+
+func (h *scheduleHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
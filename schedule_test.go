@@ -0,0 +1,57 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelayAt(t *testing.T) {
+	queue, err := New(&Config{Workers: 2, NoSignals: true})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	handled := make(map[string]time.Time)
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "later", onHandle: func(id uuid.UUID, params []byte) error {
+		mu.Lock()
+		handled[string(params)] = time.Now()
+		mu.Unlock()
+		return nil
+	}}
+	require.NoError(t, queue.Register(task))
+
+	_, err = queue.DelayAfter("unknown", time.Second, nil, nil, nil)
+	require.EqualError(t, err, `[3] could not delay [3] unknown task "unknown"`)
+
+	wg.Add(4)
+	start := time.Now()
+	_, err = queue.DelayAfter("later", 60*time.Millisecond, []byte("after"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.DelayAt("later", start.Add(30*time.Millisecond), []byte("at"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.DelayAt("later", start.Add(-time.Second), []byte("now"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Queue(context.Background(), &api.QueueRequest{Task: "later", Params: []byte("api"), Delay: int64(45 * time.Millisecond)})
+	require.NoError(t, err)
+
+	// Scheduled futures can be canceled before they are due
+	canceled, err := queue.DelayAfter("later", 20*time.Millisecond, []byte("canceled"), nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, queue.Cancel(canceled))
+	wg.Add(1)
+
+	wg.Wait()
+	require.Equal(t, int32(1), task.failures)
+	require.True(t, handled["now"].Before(handled["at"]))
+	require.True(t, handled["at"].Sub(start) >= 30*time.Millisecond)
+	require.True(t, handled["api"].Sub(start) >= 45*time.Millisecond)
+	require.True(t, handled["after"].Sub(start) >= 60*time.Millisecond)
+	require.NotContains(t, handled, "canceled")
+}
@@ -0,0 +1,62 @@
+package radish
+
+import (
+	"strings"
+
+	"github.com/kansaslabs/x/out"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// SetSchema associates a JSON Schema with a registered task. Once a schema is set, the
+// params of any future delayed for the task (including futures queued via the API) are
+// validated against the schema and rejected with a descriptive error if they do not
+// conform, catching producer bugs before they reach the handler. Setting a nil schema
+// removes any schema associated with the task.
+func (r *Radish) SetSchema(task string, schema []byte) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.handlers[task]; !ok {
+		return Errorf(CodeTaskNotRegistered, "cannot set schema for unknown task %q", task)
+	}
+
+	if schema == nil {
+		delete(r.schemas, task)
+		r.logf(out.LevelInfo, task, "removed schema for task %s", task)
+		return nil
+	}
+
+	var compiled *gojsonschema.Schema
+	if compiled, err = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema)); err != nil {
+		return Errorf(CodeInvalidSchema, "could not compile schema for task %s: %s", task, err)
+	}
+
+	r.schemas[task] = compiled
+	r.logf(out.LevelInfo, task, "set schema for task %s", task)
+	return nil
+}
+
+// validate the params against the schema associated with the task, if any.
+func (r *Radish) validate(task string, params []byte) (err error) {
+	r.RLock()
+	schema, ok := r.schemas[task]
+	r.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	var result *gojsonschema.Result
+	if result, err = schema.Validate(gojsonschema.NewBytesLoader(params)); err != nil {
+		return Errorf(CodeInvalidParams, "invalid params for task %s: %s", task, err)
+	}
+
+	if !result.Valid() {
+		errs := make([]string, 0, len(result.Errors()))
+		for _, desc := range result.Errors() {
+			errs = append(errs, desc.String())
+		}
+		return Errorf(CodeInvalidParams, "invalid params for task %s: %s", task, strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package radish_test
+
+import (
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaValidation(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+
+	email := &testTask{wg: wg, name: "sendEmail"}
+	queue, err := New(&Config{Workers: 1}, email)
+	require.NoError(t, err)
+
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"to": {"type": "string"},
+			"retries": {"type": "integer", "minimum": 0}
+		},
+		"required": ["to"]
+	}`)
+
+	// Cannot set a schema for an unregistered task or an invalid schema
+	require.EqualError(t, queue.SetSchema("unknown", schema), `[3] cannot set schema for unknown task "unknown"`)
+	require.Error(t, queue.SetSchema(email.Name(), []byte(`{"type": 42}`)))
+	require.NoError(t, queue.SetSchema(email.Name(), schema))
+
+	// Invalid params are rejected before being enqueued
+	_, err = queue.Delay(email.Name(), []byte(`{"retries": -1}`), nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "[9] invalid params for task sendEmail: ")
+	require.Contains(t, err.Error(), "to is required")
+	require.Contains(t, err.Error(), "retries: Must be greater than or equal to 0")
+
+	_, err = queue.Delay(email.Name(), []byte("not json"), nil, nil)
+	require.Error(t, err)
+
+	// Valid params are enqueued and handled
+	_, err = queue.Delay(email.Name(), []byte(`{"to": "jdoe@example.com"}`), nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+	require.Equal(t, int32(1), email.handled)
+
+	// Removing the schema disables validation
+	wg.Add(1)
+	require.NoError(t, queue.SetSchema(email.Name(), nil))
+	_, err = queue.Delay(email.Name(), []byte("not json"), nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+}
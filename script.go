@@ -0,0 +1,174 @@
+package radish
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// Lua libraries that are available to scripts; the os, io, package, and debug libraries
+// are not loaded so that scripts cannot access the host.
+var scriptLibs = []struct {
+	name string
+	open lua.LGFunction
+}{
+	{lua.BaseLibName, lua.OpenBase},
+	{lua.TabLibName, lua.OpenTable},
+	{lua.StringLibName, lua.OpenString},
+	{lua.MathLibName, lua.OpenMath},
+}
+
+// Functions in the base library that are removed so scripts cannot load code from disk.
+var scriptUnsafe = []string{"dofile", "loadfile", "load", "loadstring"}
+
+// How long a script may run its top level statements when it is checked before it is
+// registered, so that scripts that never finish loading are rejected.
+const scriptLoadTimeout = time.Second
+
+// RegisterScript registers or replaces the Lua script that handles the named task so
+// that simple transform or notify tasks can be defined in the config or updated at
+// runtime via the API without recompiling the server. The script must define a global handle
+// function that is called with the future ID and params as strings; raising an error
+// with error() fails the task, otherwise any value returned is the result of the task.
+//
+//	function handle(id, params)
+//	    return string.upper(params)
+//	end
+//
+// Each future is handled in a new Lua state with only the base, table, string, and math
+// libraries loaded. Futures that are in flight when the script is replaced are handled
+// by the previous version of the script. Scripts are stopped when their future is
+// canceled or their worker is forced to stop, so a script that never returns does not
+// hold a worker indefinitely.
+func (r *Radish) RegisterScript(task, source string) (err error) {
+	var proto *lua.FunctionProto
+	if proto, err = compileScript(task, source); err != nil {
+		return err
+	}
+
+	// If the task is already registered it must be a script task to be updated
+	if handler, err := r.Handler(task); err == nil {
+		script, ok := handler.(*scriptTask)
+		if !ok {
+			return Errorf(CodeTaskAlreadyRegistered, "task %q is not a script task", task)
+		}
+
+		script.Lock()
+		script.proto = proto
+		script.Unlock()
+		r.logf(out.LevelInfo, task, "updated script for task %s", task)
+		return nil
+	}
+
+	return r.Register(&scriptTask{name: task, proto: proto, parent: r})
+}
+
+// compileScript parses and compiles the source, then checks that it defines a handle
+// function so that invalid scripts are rejected before they replace a working script.
+func compileScript(task, source string) (proto *lua.FunctionProto, err error) {
+	chunk, err := parse.Parse(strings.NewReader(source), task)
+	if err != nil {
+		return nil, Errorf(CodeInvalidConfig, "could not parse script for %s: %s", task, strings.TrimSpace(err.Error()))
+	}
+
+	if proto, err = lua.Compile(chunk, task); err != nil {
+		return nil, Errorf(CodeInvalidConfig, "could not compile script for %s: %s", task, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scriptLoadTimeout)
+	defer cancel()
+
+	L, err := loadScript(ctx, proto)
+	if err != nil {
+		return nil, Errorf(CodeInvalidConfig, "could not load script for %s: %s", task, err)
+	}
+	defer L.Close()
+
+	if L.GetGlobal("handle").Type() != lua.LTFunction {
+		return nil, Errorf(CodeInvalidConfig, "script for %s does not define a handle function", task)
+	}
+	return proto, nil
+}
+
+// loadScript creates a new sandboxed Lua state and runs the compiled script in it. The
+// state is stopped with an error once the context is done.
+func loadScript(ctx context.Context, proto *lua.FunctionProto) (L *lua.LState, err error) {
+	L = lua.NewState(lua.Options{SkipOpenLibs: true})
+	L.SetContext(ctx)
+	for _, lib := range scriptLibs {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+
+	for _, name := range scriptUnsafe {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	L.Push(L.NewFunctionFromProto(proto))
+	if err = L.PCall(0, lua.MultRet, nil); err != nil {
+		L.Close()
+		return nil, err
+	}
+	return L, nil
+}
+
+// scriptTask adapts a compiled Lua script to the Task interface.
+type scriptTask struct {
+	sync.RWMutex
+	name   string
+	proto  *lua.FunctionProto
+	parent *Radish
+}
+
+func (t *scriptTask) Name() string {
+	return t.name
+}
+
+func (t *scriptTask) Handle(id uuid.UUID, params []byte) error {
+	return t.HandleContext(context.Background(), id, params)
+}
+
+// HandleContext runs the script with the context so that the script is stopped once the
+// future is canceled or its worker is forced to stop.
+func (t *scriptTask) HandleContext(ctx context.Context, id uuid.UUID, params []byte) (err error) {
+	t.RLock()
+	proto := t.proto
+	t.RUnlock()
+
+	var L *lua.LState
+	if L, err = loadScript(ctx, proto); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return Errorf(CodeUnknown, "could not load script for %s: %s", t.name, err)
+	}
+	defer L.Close()
+
+	handle := lua.P{Fn: L.GetGlobal("handle"), NRet: 1, Protect: true}
+	if err = L.CallByParam(handle, lua.LString(id.String()), lua.LString(params)); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return Errorf(CodeUnknown, "%s script failed: %s", t.name, err)
+	}
+
+	if result := L.Get(-1); result != lua.LNil {
+		t.parent.logf(out.LevelDebug, t.name, "%s task %s returned %s", t.name, id, result)
+	}
+	return nil
+}
+
+func (t *scriptTask) Success(id uuid.UUID, params []byte) {
+	t.parent.logf(out.LevelDebug, t.name, "script task %s succeeded", id)
+}
+
+func (t *scriptTask) Failure(id uuid.UUID, err error, params []byte) {
+	t.parent.logf(out.LevelInfo, t.name, "script task %s failed: %s", id, err)
+}
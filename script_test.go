@@ -0,0 +1,94 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScripts(t *testing.T) {
+	scripts := map[string]string{
+		"shout": `function handle(id, params) return string.upper(params) end`,
+	}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, Scripts: scripts}, &testTask{name: "native"})
+	require.NoError(t, err)
+
+	// Invalid scripts are rejected
+	err = queue.RegisterScript("bad", "function handle(")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "[1] could not parse script for bad")
+	require.EqualError(t, queue.RegisterScript("bad", "x = 1"), "[1] script for bad does not define a handle function")
+	require.EqualError(t, queue.RegisterScript("native", "function handle(id, params) end"), `[2] task "native" is not a script task`)
+
+	// Scripts can be registered and updated via the API
+	ctx := context.Background()
+	rep, err := queue.SetScript(ctx, &api.ScriptRequest{Task: "fail", Source: `function handle(id, params) error("whoops") end`})
+	require.NoError(t, err)
+	require.True(t, rep.Success)
+
+	rep, err = queue.SetScript(ctx, &api.ScriptRequest{Task: "shout", Source: "return"})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, "[1] script for shout does not define a handle function", rep.Error.Error())
+
+	status, err := queue.Status(ctx, &api.StatusRequest{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"native", "shout", "fail"}, status.Tasks)
+
+	shout, err := queue.Handler("shout")
+	require.NoError(t, err)
+	require.NoError(t, shout.Handle(nil, []byte("radish")))
+
+	fail, err := queue.Handler("fail")
+	require.NoError(t, err)
+	require.Error(t, fail.Handle(nil, nil))
+
+	// Updated scripts take effect for new futures
+	require.NoError(t, queue.RegisterScript("fail", `function handle(id, params) return "ok" end`))
+	require.NoError(t, fail.Handle(nil, nil))
+
+	// Scripts cannot access the host
+	require.NoError(t, queue.RegisterScript("escape", `function handle(id, params) os.exit(1) end`))
+	escape, err := queue.Handler("escape")
+	require.NoError(t, err)
+	require.Error(t, escape.Handle(nil, nil))
+}
+
+func TestScriptCancel(t *testing.T) {
+	scripts := map[string]string{
+		"loop": `function handle(id, params) while true do end end`,
+	}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "silent", Scripts: scripts})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	started := make(chan struct{}, 1)
+	queue.OnEvent(func(e Event) {
+		if e.Type == EventStart {
+			started <- struct{}{}
+		}
+	})
+
+	// A script that never returns is stopped when its future is canceled
+	id, err := queue.Delay("loop", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+	require.NoError(t, queue.Cancel(id))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = queue.Wait(ctx, id)
+	require.True(t, errors.Is(err, ErrCanceled), "expected the script to be canceled, got %v", err)
+
+	// Scripts that never finish loading are rejected
+	err = queue.RegisterScript("stuck", "while true do end\nfunction handle(id, params) end")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "[1] could not load script for stuck")
+}
@@ -3,65 +3,306 @@ package radish
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	"github.com/kansaslabs/radish/api"
 	"github.com/kansaslabs/x/out"
 	"google.golang.org/grpc"
 )
 
-// Listen on the configured address and port for API requests and run prometheus metrics server.
+// Listen on the configured address and port for API requests and run prometheus metrics
+// server. Listen blocks until the queue is shutdown, either by calling Shutdown or when
+// one of the signals specified in the config is received.
 func (r *Radish) Listen() (err error) {
-	if !r.config.SuppressMetrics {
-		if err = registerMetrics(); err != nil {
-			return fmt.Errorf("could not register prometheus metrics: %s", err)
-		}
-		go serveMetrics(r.config.MetricsAddr)
-	}
-
 	// Open TCP socket to listen on from the configuration
 	var sock net.Listener
-	if sock, err = net.Listen("tcp", r.config.Addr); err != nil {
-		return Errorf(ErrBadGateway, "could not listen on %s: %s", r.config.Addr, err)
+	lc := net.ListenConfig{}
+	if r.config.ReusePort {
+		lc.Control = reusePort
 	}
+
+	if sock, err = lc.Listen(context.Background(), "tcp", r.config.Addr); err != nil {
+		return Errorf(CodeBadGateway, "could not listen on %s: %s", r.config.Addr, err)
+	}
+	return r.Serve(sock)
+}
+
+// Serve API requests on the listener with the same behavior as Listen, e.g. when the
+// application opens the listener itself or uses systemd socket activation. The metrics
+// server and JSON/HTTP gateway are served if configured and signals are handled unless
+// NoSignals is set. Serve blocks until the queue is shutdown and closes the listener.
+func (r *Radish) Serve(sock net.Listener) (err error) {
 	defer sock.Close()
-	out.Status("listening for requests on %s", r.config.Addr)
+	if err = r.startMetrics(); err != nil {
+		return err
+	}
+	r.logf(out.LevelStatus, "", "listening for requests on %s", sock.Addr())
 
-	// Initialize and run the gRPC server
-	srv := grpc.NewServer()
+	// Notify systemd that the server is ready and that it is stopping on return
+	stopping := r.notifyReady()
+	defer stopping()
+
+	// Initialize the gRPC server so that it can be stopped on shutdown
+	srv := grpc.NewServer(r.ServerOptions()...)
 	api.RegisterRadishServer(srv, r)
-	return srv.Serve(sock)
+
+	// Serve the JSON/HTTP gateway if configured so it can be stopped on shutdown
+	var gateway *http.Server
+	if r.config.GatewayAddr != "" {
+		gateway = &http.Server{Addr: r.config.GatewayAddr, Handler: r.Gateway()}
+		go r.serveGateway(gateway)
+	}
+
+	r.Lock()
+	r.srv, r.gateway = srv, gateway
+	r.Unlock()
+
+	// Handle OS signals unless the application is handling them itself
+	if !r.config.NoSignals {
+		go r.handleSignals()
+	}
+
+	if err = srv.Serve(sock); err != nil {
+		return err
+	}
+
+	// Serve only returns without an error when stopped by Shutdown, wait for in flight
+	// tasks to complete before returning unless the queue is force stopped.
+	select {
+	case <-r.stopped:
+		return nil
+	case <-r.forced.Done():
+		return Errorf(CodeCanceled, "queue was force stopped with %d tasks in flight", r.BusyWorkers())
+	}
+}
+
+// RegisterWith registers the radish API on a gRPC server owned by the application, e.g.
+// alongside other services, and starts the metrics server if it is not suppressed. The
+// server should be created with the ServerOptions so that requests are authenticated and
+// their metrics are recorded. Unlike Serve, the application owns the lifecycle of the
+// server and its signals: it should call Shutdown when it stops the server so that the
+// tasks in flight complete, and streaming requests such as Watch end once it does.
+func (r *Radish) RegisterWith(srv *grpc.Server) (err error) {
+	if err = r.startMetrics(); err != nil {
+		return err
+	}
+
+	api.RegisterRadishServer(srv, r)
+	return nil
+}
+
+// startMetrics registers the prometheus metrics and serves the metrics server unless
+// metrics are suppressed. The metrics server is only started once for the queue.
+func (r *Radish) startMetrics() (err error) {
+	if r.config.SuppressMetrics {
+		return nil
+	}
+
+	r.metricsOnce.Do(func() {
+		if err = registerMetrics(); err != nil {
+			err = fmt.Errorf("could not register prometheus metrics: %s", err)
+			return
+		}
+
+		// Gauges set before the metrics were registered were not recorded
+		pmWorkers.Set(float64(r.NumWorkers()))
+		pmQueueSize.Set(float64(r.tasks.Len()))
+		pmPercentFull.Set(float64(r.tasks.Len()) / float64(r.config.QueueSize) * 100)
+		go r.serveMetrics()
+	})
+	return err
+}
+
+// The default maximum size of messages received by gRPC servers and the room left for the
+// rest of a queue request when the limit is raised for the maximum param size.
+const (
+	defaultMaxRecvMsgSize = 4 * 1024 * 1024
+	maxRequestOverhead    = 64 * 1024
+)
+
+// ServerOptions returns the gRPC server options that Listen uses to serve the radish API:
+// interceptors that record request metrics and authenticate API clients if an AuthToken
+// or APIKeys are configured. If the MaxParamSize is larger than the default 4MB message
+// limit of gRPC servers, the limit is raised so that params up to the maximum size can be
+// queued. Applications that register radish on their own gRPC server should create the
+// server with these options.
+func (r *Radish) ServerOptions() []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryMetrics, r.unaryAuth),
+		grpc.ChainStreamInterceptor(streamMetrics, r.streamAuth),
+	}
+
+	if size := r.maxRequestSize(); size > defaultMaxRecvMsgSize {
+		opts = append(opts, grpc.MaxRecvMsgSize(int(size)))
+	}
+	return opts
+}
+
+// maxRequestSize returns the maximum size of a gRPC message or gateway request body: the
+// default 4MB message limit, or the MaxParamSize with room for the rest of the request if
+// the maximum param size is larger.
+func (r *Radish) maxRequestSize() int64 {
+	if size := int64(r.config.MaxParamSize) + maxRequestOverhead; r.config.MaxParamSize > 0 && size > defaultMaxRecvMsgSize {
+		return size
+	}
+	return defaultMaxRecvMsgSize
 }
 
 // Shutdown the queue gracefully, stopping the server, completing any tasks in flight
-// and stopping workers. Tasks cannot be delayed after shutdown is called.
+// and stopping workers. Tasks cannot be delayed after shutdown is called and any tasks
+// remaining in the queue are not handled; if storage is durable they are restored when
+// the queue is restarted.
 func (r *Radish) Shutdown() (err error) {
-	return Errorf(ErrUnknown, "shutdown is not implemented yet")
+	r.Lock()
+	select {
+	case <-r.shutdown:
+		r.Unlock()
+		return Errorf(CodeShutdown, "queue has already been shutdown")
+	default:
+		close(r.shutdown)
+	}
+	srv, gateway := r.srv, r.gateway
+	r.Unlock()
+
+	r.logf(out.LevelStatus, "", "shutting down the radish queue")
+	if gateway != nil {
+		gateway.Close()
+	}
+	if srv != nil {
+		srv.GracefulStop()
+	}
+
+	// Stop the workers, waiting for any tasks in flight to complete
+	if err = r.SetWorkers(0); err != nil {
+		return err
+	}
+
+	// Close the storage, any futures remaining in the queue are restored on restart
+	if err = r.store.close(); err != nil {
+		return Errorf(CodeStorage, "could not close storage: %s", err)
+	}
+
+	if err = r.closeAuditLog(); err != nil {
+		return Errorf(CodeStorage, "could not close audit log: %s", err)
+	}
+
+	close(r.stopped)
+	r.logf(out.LevelStatus, "", "radish queue shutdown with %d tasks remaining in the queue", r.tasks.Len())
+
+	// Close the connections of brokers with external backends
+	if closer, ok := r.tasks.(io.Closer); ok {
+		if err = closer.Close(); err != nil {
+			return Errorf(CodeBadGateway, "could not close broker: %s", err)
+		}
+	}
+	return nil
 }
 
-// Queue an asynchronous task from a gRPC request.
+// Queue an asynchronous task from a gRPC request. The future is counted against the
+// quota of the client that made the request until it is dequeued by a worker. A W3C
+// traceparent in the gRPC metadata of the request is copied onto the future so that its
+// handler can link its work to the trace of the request with TraceParentFrom. The reply
+// includes the length of the queue and an estimate of how long the future will wait for
+// a worker based on the throughput of the last minute so that clients can back off. If
+// the queue is full, the request waits for room with the context of the request, so it
+// fails with an ErrCanceled error and the future is not queued if the client cancels the
+// request or its deadline expires first.
 func (r *Radish) Queue(ctx context.Context, in *api.QueueRequest) (rep *api.QueueReply, err error) {
 	rep = &api.QueueReply{Success: true}
-	if rep.Uuid, err = r.Delay(in.Task, in.Params, in.Success, in.Failure); err != nil {
+	ctx = withClient(ctx, clientIdentity(ctx))
+	if in.Callback != "" {
+		ctx = WithCallback(ctx, in.Callback)
+	}
+	if in.Priority != 0 {
+		ctx = WithPriority(ctx, in.Priority)
+	}
+	if in.PartitionKey != "" {
+		ctx = WithPartitionKey(ctx, in.PartitionKey)
+	}
+	if in.IdempotencyKey != "" {
+		ctx = WithIdempotencyKey(ctx, in.IdempotencyKey)
+	}
+	for key, value := range in.Metadata {
+		ctx = WithMetadata(ctx, key, value)
+	}
+	ctx = withTraceContext(ctx)
+
+	if in.DryRun {
+		if rep.Outcome, err = r.DryRun(ctx, in.Task, in.Params); err != nil {
+			rep.Success = false
+
+			var ok bool
+			if rep.Error, ok = apiError(err); !ok {
+				return nil, fmt.Errorf("could not cast error to API error: %s", err)
+			}
+		}
+		return rep, nil
+	}
+
+	var at time.Time
+	switch {
+	case in.Eta > 0:
+		at = time.Unix(0, in.Eta)
+	case in.Delay > 0:
+		at = time.Now().Add(time.Duration(in.Delay))
+	}
+
+	if rep.Uuid, err = r.delayAt(ctx, in.Task, at, in.Params, in.Success, in.Failure); err != nil {
 		rep.Success = false
 
 		var ok bool
-		if rep.Error, ok = err.(*api.Error); !ok {
+		if rep.Error, ok = apiError(err); !ok {
 			return nil, fmt.Errorf("could not cast error to API error: %s", err)
 		}
+		return rep, nil
 	}
 
+	var wait time.Duration
+	rep.QueueLength, wait = r.estimateWait(at)
+	rep.EstimatedWait = int64(wait)
 	return rep, nil
 }
 
-// Scale the number of workers on the server.
+// workerInfo returns the state of each worker, only including the workers that are
+// handling a future of an included task if the tasks are filtered.
+func (r *Radish) workerInfo(include func(task string) bool) []*api.WorkerInfo {
+	r.RLock()
+	defer r.RUnlock()
+
+	workers := make([]*api.WorkerInfo, 0, len(r.workers))
+	for _, w := range r.workers {
+		if info, ok := w.info(include); ok {
+			workers = append(workers, info)
+		}
+	}
+	return workers
+}
+
+// Scale the number of workers on the server. Removed workers finish the futures they are
+// handling before they stop; the reply is sent once they have stopped, or when the
+// timeout of the request expires with the number of removed workers still draining, in
+// which case their futures are canceled if the request forces them to stop.
 func (r *Radish) Scale(ctx context.Context, in *api.ScaleRequest) (rep *api.ScaleReply, err error) {
 	rep = &api.ScaleReply{Success: true}
-	if err = r.SetWorkers(int(in.Workers)); err != nil {
+	if in.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(in.Timeout))
+		defer cancel()
+	}
+
+	var draining int
+	draining, err = r.SetWorkersContext(ctx, int(in.Workers), in.Force)
+	rep.Draining = int32(draining)
+	if err != nil {
 		rep.Success = false
 
 		var ok bool
-		if rep.Error, ok = err.(*api.Error); !ok {
+		if rep.Error, ok = apiError(err); !ok {
 			return nil, fmt.Errorf("could not cast error to API error: %s", err)
 		}
 		return rep, nil
@@ -71,17 +312,149 @@ func (r *Radish) Scale(ctx context.Context, in *api.ScaleRequest) (rep *api.Scal
 	return rep, nil
 }
 
-// Status returns information about the state of the radish task queue.
+// SetScript registers or updates a script task from a gRPC request.
+func (r *Radish) SetScript(ctx context.Context, in *api.ScriptRequest) (rep *api.ScriptReply, err error) {
+	rep = &api.ScriptReply{Success: true}
+	if err = r.RegisterScript(in.Task, in.Source); err != nil {
+		rep.Success = false
+
+		var ok bool
+		if rep.Error, ok = apiError(err); !ok {
+			return nil, fmt.Errorf("could not cast error to API error: %s", err)
+		}
+	}
+	return rep, nil
+}
+
+// DrainQueue stops the queue from accepting new tasks so that the futures already in the
+// queue are handled, e.g. before a deploy, replying with the number of futures that
+// remain in the queue; clients can poll Status until the queue is empty. If shutdown is
+// requested, the queue is shutdown in the background once it has been drained.
+func (r *Radish) DrainQueue(ctx context.Context, in *api.DrainRequest) (rep *api.DrainReply, err error) {
+	select {
+	case <-r.shutdown:
+		err = Errorf(CodeShutdown, "queue has been shutdown")
+		return &api.DrainReply{Success: false, Error: err.(*api.Error)}, nil
+	default:
+	}
+
+	r.startDraining()
+	if in.Shutdown {
+		// Shutdown must not be called by the handler since it waits for requests to finish
+		go func() {
+			r.drain()
+			r.onShutdownSignal()
+		}()
+	}
+	return &api.DrainReply{Success: true, Queue: uint64(r.tasks.Len())}, nil
+}
+
+// ListTasks returns the registered tasks ordered by name with the number of futures of
+// each task that are pending and that have been queued, succeeded, failed, or canceled
+// since the queue started.
+func (r *Radish) ListTasks(ctx context.Context, in *api.ListTasksRequest) (rep *api.ListTasksReply, err error) {
+	r.RLock()
+	rep = &api.ListTasksReply{Tasks: make([]*api.TaskInfo, 0, len(r.handlers))}
+	for name := range r.handlers {
+		info := r.stats.info(name)
+		info.Pending = uint64(r.queued.count(name))
+		rep.Tasks = append(rep.Tasks, info)
+	}
+	r.RUnlock()
+
+	sort.Slice(rep.Tasks, func(i, j int) bool { return rep.Tasks[i].Name < rep.Tasks[j].Name })
+	return rep, nil
+}
+
+// Ping returns the version of the server and the radish API it implements along with the
+// current time on the server, so that clients can check connectivity and compatibility
+// before queueing tasks.
+func (r *Radish) Ping(ctx context.Context, in *api.PingRequest) (rep *api.PingReply, err error) {
+	return &api.PingReply{
+		Version:   PackageVersion,
+		Protocol:  ProtocolVersion,
+		Timestamp: time.Now().UnixNano(),
+	}, nil
+}
+
+// Status returns information about the state of the radish task queue. The summary of
+// the queue is always returned, and the registered tasks with their counts unless other
+// sections are requested, so that the common case stays cheap while dashboards can ask
+// for the state of the workers, the futures in flight, and the scheduler, optionally
+// only for specific tasks.
 func (r *Radish) Status(ctx context.Context, in *api.StatusRequest) (rep *api.StatusReply, err error) {
 	rep = &api.StatusReply{
-		Workers: int32(r.NumWorkers()),
-		Queue:   uint64(len(r.tasks)),
-		Tasks:   make([]string, 0, len(r.handlers)),
+		Success:  true,
+		Workers:  int32(r.NumWorkers()),
+		Queue:    uint64(r.tasks.Len()),
+		Draining: r.isDraining(),
+		Paused:   r.Paused(),
+		Capacity: uint64(r.config.QueueSize),
+		InFlight: uint64(r.inflight.numRunning() + int(atomic.LoadInt64(&r.batched))),
+		Uptime:   int64(time.Since(r.created)),
+		Version:  PackageVersion,
 	}
 
-	for name := range r.handlers {
-		rep.Tasks = append(rep.Tasks, name)
+	include := func(task string) bool { return true }
+	if len(in.GetTasks()) > 0 {
+		filter := make(map[string]bool, len(in.Tasks))
+		for _, task := range in.Tasks {
+			filter[task] = true
+		}
+		include = func(task string) bool { return filter[task] }
+	}
+
+	sections := in.GetSections()
+	if len(sections) == 0 {
+		sections = []api.StatusRequest_Section{api.StatusRequest_TASKS}
 	}
 
+	for _, section := range sections {
+		switch section {
+		case api.StatusRequest_TASKS:
+			var tasks *api.ListTasksReply
+			if tasks, err = r.ListTasks(ctx, nil); err != nil {
+				return nil, err
+			}
+
+			rep.Tasks, rep.Counts = make([]string, 0, len(tasks.Tasks)), make([]*api.TaskInfo, 0, len(tasks.Tasks))
+			for _, info := range tasks.Tasks {
+				if include(info.Name) {
+					rep.Tasks = append(rep.Tasks, info.Name)
+					rep.Counts = append(rep.Counts, info)
+				}
+			}
+		case api.StatusRequest_WORKERS:
+			busy := int32(r.BusyWorkers())
+			rep.WorkerStatus = &api.WorkersStatus{Busy: busy, Idle: rep.Workers - busy, Remote: r.remotes.status(include), Workers: r.workerInfo(include)}
+			if rep.WorkerStatus.Idle < 0 {
+				rep.WorkerStatus.Idle = 0
+			}
+		case api.StatusRequest_IN_FLIGHT:
+			rep.Running = make([]*api.FutureInfo, 0)
+			for _, id := range r.inflight.runningIDs() {
+				if rec, ok := r.results.get(id); ok && include(rec.task) {
+					rep.Running = append(rep.Running, rec.info(id))
+				}
+			}
+			sort.Slice(rep.Running, func(i, j int) bool { return rep.Running[i].Started < rep.Running[j].Started })
+		case api.StatusRequest_SCHEDULER:
+			scheduled, next := r.scheduled.status(include)
+			rep.Scheduler = &api.SchedulerStatus{Scheduled: uint64(scheduled), NextDue: unixNano(next)}
+		default:
+			rep.Success = false
+
+			var ok bool
+			err = Errorf(CodeInvalidParams, "unknown status section %s", section)
+			if rep.Error, ok = apiError(err); !ok {
+				return nil, fmt.Errorf("could not cast error to API error: %s", err)
+			}
+			return rep, nil
+		}
+	}
+
+	if in.GetCluster() {
+		rep.Peers = r.clusterStatus(ctx, in)
+	}
 	return rep, nil
 }
@@ -0,0 +1,281 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestServe(t *testing.T) {
+	queue, err := New(&Config{Workers: 2, NoSignals: true, SuppressMetrics: true}, &testTask{name: "task"})
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	errc := make(chan error, 1)
+	go func() { errc <- queue.Serve(sock) }()
+
+	cc, err := grpc.Dial(sock.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rep, err := api.NewRadishClient(cc).Status(ctx, &api.StatusRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), rep.Workers)
+
+	// Serve returns once the queue has been shutdown
+	require.NoError(t, queue.Shutdown())
+	select {
+	case err = <-errc:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("serve did not return after shutdown")
+	}
+}
+
+func TestRegisterWith(t *testing.T) {
+	queue, err := New(&Config{Workers: 3, NoSignals: true, SuppressMetrics: true}, &testTask{name: "task"})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	srv := grpc.NewServer(queue.ServerOptions()...)
+	require.NoError(t, queue.RegisterWith(srv))
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(sock)
+	defer srv.Stop()
+
+	cc, err := grpc.Dial(sock.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rep, err := api.NewRadishClient(cc).Status(ctx, &api.StatusRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	require.Equal(t, int32(3), rep.Workers)
+}
+
+func TestStatus(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	task := &testTask{wg: wg, name: "status", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, QueueSize: 10, NoSignals: true, LogLevel: "warn"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// One future is handled by the worker while the other waits in the queue
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		_, err = queue.Delay(task.Name(), nil, nil, nil)
+		require.NoError(t, err)
+	}
+	<-started
+
+	ctx := context.Background()
+	rep, err := queue.Status(ctx, &api.StatusRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), rep.Workers)
+	require.Equal(t, uint64(1), rep.Queue)
+	require.Equal(t, uint64(10), rep.Capacity)
+	require.Equal(t, uint64(1), rep.InFlight)
+	require.Equal(t, PackageVersion, rep.Version)
+	require.NotZero(t, rep.Uptime)
+	require.False(t, rep.Paused)
+	require.False(t, rep.Draining)
+	require.Equal(t, []string{"status"}, rep.Tasks)
+	require.Len(t, rep.Counts, 1)
+	require.Equal(t, uint64(1), rep.Counts[0].Pending)
+
+	close(release)
+	wg.Wait()
+	require.NoError(t, queue.Pause())
+
+	require.Eventually(t, func() bool {
+		rep, err = queue.Status(ctx, &api.StatusRequest{})
+		require.NoError(t, err)
+		return rep.Counts[0].Succeeded == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Zero(t, rep.InFlight)
+	require.Zero(t, rep.Counts[0].Pending)
+	require.True(t, rep.Paused)
+}
+
+func TestStatusSections(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	slow := &testTask{wg: wg, name: "slow", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+	later := &testTask{wg: wg, name: "later"}
+
+	queue, err := New(&Config{Workers: 2, NoSignals: true, LogLevel: "warn"}, slow, later)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	wg.Add(1)
+	id, err := queue.Delay("slow", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	at := time.Now().Add(time.Hour)
+	_, err = queue.DelayAt("later", at, nil, nil, nil)
+	require.NoError(t, err)
+
+	// Only the requested sections are returned
+	ctx := context.Background()
+	all := []api.StatusRequest_Section{api.StatusRequest_WORKERS, api.StatusRequest_IN_FLIGHT, api.StatusRequest_SCHEDULER}
+	rep, err := queue.Status(ctx, &api.StatusRequest{Sections: all})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), rep.Workers)
+	require.Empty(t, rep.Tasks)
+	require.Empty(t, rep.Counts)
+	require.Equal(t, int32(1), rep.WorkerStatus.Busy)
+	require.Equal(t, int32(1), rep.WorkerStatus.Idle)
+	require.Empty(t, rep.WorkerStatus.Remote)
+	require.Len(t, rep.Running, 1)
+	require.Equal(t, []byte(id), rep.Running[0].Uuid)
+	require.Equal(t, api.FutureState_RUNNING, rep.Running[0].State)
+	require.Equal(t, uint64(1), rep.Scheduler.Scheduled)
+	require.Equal(t, at.UnixNano(), rep.Scheduler.NextDue)
+
+	// The sections are filtered by task
+	rep, err = queue.Status(ctx, &api.StatusRequest{Sections: append(all, api.StatusRequest_TASKS), Tasks: []string{"later"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"later"}, rep.Tasks)
+	require.Len(t, rep.Counts, 1)
+	require.Empty(t, rep.Running)
+	require.Equal(t, uint64(1), rep.Scheduler.Scheduled)
+
+	rep, err = queue.Status(ctx, &api.StatusRequest{Sections: []api.StatusRequest_Section{api.StatusRequest_SCHEDULER}, Tasks: []string{"slow"}})
+	require.NoError(t, err)
+	require.Zero(t, rep.Scheduler.Scheduled)
+	require.Zero(t, rep.Scheduler.NextDue)
+	require.Nil(t, rep.WorkerStatus)
+
+	// Unknown sections are reported as an error in the reply
+	rep, err = queue.Status(ctx, &api.StatusRequest{Sections: []api.StatusRequest_Section{42}})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, CodeInvalidParams, rep.Error.Code)
+	require.True(t, errors.Is(rep.Error, ErrInvalidParams))
+
+	close(release)
+	wg.Wait()
+}
+
+func TestWorkerInfo(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	quick := &testTask{wg: wg, name: "quick"}
+	slow := &testTask{wg: wg, name: "slow", onHandle: func(id uuid.UUID, params []byte) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 2, NoSignals: true, LogLevel: "warn"}, quick, slow)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	wg.Add(4)
+	for i := 0; i < 3; i++ {
+		_, err = queue.Delay("quick", nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	id, err := queue.Delay("slow", nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	// Each worker reports the futures it handled and the future it is handling
+	ctx := context.Background()
+	workers := &api.StatusRequest{Sections: []api.StatusRequest_Section{api.StatusRequest_WORKERS}}
+	require.Eventually(t, func() bool {
+		rep, err := queue.Status(ctx, workers)
+		require.NoError(t, err)
+		require.Len(t, rep.WorkerStatus.Workers, 2)
+		return rep.WorkerStatus.Workers[0].Handled+rep.WorkerStatus.Workers[1].Handled == 3
+	}, time.Second, 5*time.Millisecond)
+
+	rep, err := queue.Status(ctx, workers)
+	require.NoError(t, err)
+	require.NotEqual(t, rep.WorkerStatus.Workers[0].Id, rep.WorkerStatus.Workers[1].Id)
+
+	// Only the worker handling a future of the task is returned when filtered
+	workers.Tasks = []string{"slow"}
+	rep, err = queue.Status(ctx, workers)
+	require.NoError(t, err)
+	require.Len(t, rep.WorkerStatus.Workers, 1)
+	info := rep.WorkerStatus.Workers[0]
+	require.Equal(t, "slow", info.Task)
+	require.Equal(t, []byte(id), info.Future)
+	require.NotZero(t, info.Started)
+	require.NotZero(t, info.Busy)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestQueueCanceled(t *testing.T) {
+	// Block the only worker and fill the queue
+	started := make(chan struct{})
+	release := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "full", onHandle: func(id uuid.UUID, params []byte) error {
+		if string(params) == "block" {
+			close(started)
+			<-release
+		}
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, QueueSize: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	wg.Add(2)
+	_, err = queue.Delay("full", []byte("block"), nil, nil)
+	require.NoError(t, err)
+	<-started
+	_, err = queue.Delay("full", nil, nil, nil)
+	require.NoError(t, err)
+
+	// The request gives up waiting for room once its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	rep, err := queue.Queue(ctx, &api.QueueRequest{Task: "full"})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, "[7] could not delay full: context deadline exceeded", rep.Error.Error())
+
+	// The request that gave up was not queued
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(2), atomic.LoadInt32(&task.handled))
+	require.Equal(t, int32(0), atomic.LoadInt32(&task.failures))
+}
@@ -0,0 +1,218 @@
+package radish
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kansaslabs/x/out"
+)
+
+// Default signals handled by Listen if no signals are specified in the config.
+var (
+	defaultShutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	defaultReloadSignals   = []os.Signal{syscall.SIGHUP}
+)
+
+// How frequently to check if the queue has been drained.
+const drainInterval = 100 * time.Millisecond
+
+// handleSignals listens for the OS signals specified in the config and drains, shuts
+// down, or reloads the queue when they are received. Receiving a drain signal while the
+// queue is already draining causes the queue to shutdown immediately, and receiving a
+// drain or shutdown signal while the queue is shutting down force stops the queue, as
+// does a drain or shutdown that takes longer than the ShutdownGrace. Returns when the
+// queue has stopped.
+func (r *Radish) handleSignals() {
+	actions := make(map[os.Signal]func())
+	for _, sig := range r.config.DrainSignals {
+		actions[sig] = r.onDrainSignal
+	}
+	for _, sig := range r.config.ShutdownSignals {
+		actions[sig] = func() { go r.onShutdownSignal() }
+	}
+	for _, sig := range r.config.ReloadSignals {
+		actions[sig] = r.onReloadSignal
+	}
+	for _, sig := range r.config.HandoffSignals {
+		actions[sig] = func() { go r.onHandoffSignal() }
+	}
+
+	if len(actions) == 0 {
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	for sig := range actions {
+		signal.Notify(sigs, sig)
+	}
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case sig := <-sigs:
+			r.logf(out.LevelStatus, "", "received %s signal", sig)
+			actions[sig]()
+		case <-r.stopped:
+			return
+		case <-r.forced.Done():
+			return
+		}
+	}
+}
+
+func (r *Radish) onDrainSignal() {
+	if r.isDraining() {
+		r.logf(out.LevelWarn, "", "received drain signal while draining, shutting down immediately")
+		go r.onShutdownSignal()
+		return
+	}
+
+	go func() {
+		stop := r.forceAfterGrace()
+		defer stop()
+
+		r.Drain(r.forced)
+		r.shutdownOnSignal()
+	}()
+}
+
+func (r *Radish) onShutdownSignal() {
+	select {
+	case <-r.shutdown:
+		r.forceStop("received a signal while shutting down")
+		return
+	default:
+	}
+
+	stop := r.forceAfterGrace()
+	defer stop()
+	r.shutdownOnSignal()
+}
+
+func (r *Radish) shutdownOnSignal() {
+	if err := r.Shutdown(); err != nil {
+		r.logf(out.LevelWarn, "", "%s", err)
+	}
+}
+
+// forceAfterGrace force stops the queue if it has not stopped within the ShutdownGrace,
+// returning a function that stops the timer.
+func (r *Radish) forceAfterGrace() (stop func()) {
+	if r.config.ShutdownGrace <= 0 {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(r.config.ShutdownGrace, func() {
+		r.forceStop(fmt.Sprintf("queue did not stop within the %s shutdown grace period", r.config.ShutdownGrace))
+	})
+	return func() { timer.Stop() }
+}
+
+// forceStop cancels the futures in flight so that the queue can shutdown and causes
+// Listen to return without waiting for handlers that do not respect cancellation.
+func (r *Radish) forceStop(reason string) {
+	if r.forced.Err() != nil {
+		return
+	}
+
+	r.force()
+	n := r.inflight.cancelRunning()
+	r.logf(out.LevelWarn, "", "%s, force stopping with %d futures in flight", reason, n)
+}
+
+func (r *Radish) onReloadSignal() {
+	if r.config.LoadConfig == nil && r.config.OnReload == nil {
+		r.logf(out.LevelInfo, "", "no reload handler configured, ignoring reload signal")
+		return
+	}
+
+	if r.config.LoadConfig != nil {
+		conf, err := r.loadConfig()
+		if err == nil {
+			err = r.ReloadConfig(conf)
+		}
+
+		if err != nil {
+			r.logf(out.LevelWarn, "", "could not reload config: %s", err)
+		}
+	}
+
+	if r.config.OnReload != nil {
+		if err := r.config.OnReload(); err != nil {
+			r.logf(out.LevelWarn, "", "could not reload: %s", err)
+		}
+	}
+}
+
+// Drain stops accepting new tasks, so that Delay returns an ErrShutdown error, and
+// blocks until the futures already in the queue and in flight have been handled or the
+// context is done. Futures that are waiting to be retried, parked behind an earlier
+// future with the same partition key, or coalesced into a batch are in flight, so Drain
+// waits for them, but not for scheduled futures that are not yet due. Unlike Shutdown,
+// the workers keep running once the queue has been drained, e.g. to handle scheduled
+// futures as they become due, but the queue does not accept new tasks again. Returns an
+// ErrCanceled error if the context is done before the queue has been drained and an
+// ErrShutdown error if the queue has been shutdown. Note that a paused queue is not
+// drained until it is resumed.
+func (r *Radish) Drain(ctx context.Context) error {
+	select {
+	case <-r.shutdown:
+		return Errorf(CodeShutdown, "queue has been shutdown")
+	default:
+	}
+
+	r.startDraining()
+	ticker := time.NewTicker(drainInterval)
+	defer ticker.Stop()
+
+	for r.remaining() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return Errorf(CodeCanceled, "queue was not drained with %d tasks remaining: %s", r.remaining(), ctx.Err())
+		case <-r.shutdown:
+			return Errorf(CodeShutdown, "queue was shutdown with %d tasks remaining", r.remaining())
+		}
+	}
+	return nil
+}
+
+// remaining returns the number of futures that must be handled before the queue has been
+// drained: the futures tracked as queued or in flight other than scheduled futures that
+// are not yet due. Busy workers are counted as well so that a future whose handler has
+// returned is not missed while its callbacks run or before it is tracked for a retry.
+func (r *Radish) remaining() int {
+	n := r.inflight.numTracked() - r.scheduled.len()
+	if busy := r.BusyWorkers(); busy > n {
+		n = busy
+	}
+	return n
+}
+
+// drain stops accepting new tasks and blocks until the queue has been drained or the
+// queue has been shutdown.
+func (r *Radish) drain() {
+	r.Drain(context.Background())
+}
+
+// startDraining stops accepting new tasks without waiting for the queue to be empty.
+func (r *Radish) startDraining() {
+	r.Lock()
+	draining := r.draining
+	r.draining = true
+	r.Unlock()
+
+	if !draining {
+		r.logf(out.LevelStatus, "", "draining %d tasks from the queue", r.tasks.Len())
+	}
+}
+
+func (r *Radish) isDraining() bool {
+	r.RLock()
+	defer r.RUnlock()
+	return r.draining
+}
@@ -0,0 +1,64 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package radish_test
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownGrace(t *testing.T) {
+	// Handle the signal in the test so that the process is never terminated by it
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR2)
+	defer signal.Stop(sigs)
+
+	wg := new(sync.WaitGroup)
+	started, release := make(chan struct{}), make(chan struct{})
+	task := &testTask{wg: wg, name: "stubborn", onHandle: func(id uuid.UUID, params []byte) error {
+		close(started)
+		<-release
+		return nil
+	}}
+
+	conf := &Config{Addr: "127.0.0.1:0", Workers: 1, SuppressMetrics: true, LogLevel: "warn", ShutdownSignals: []os.Signal{syscall.SIGUSR2}, ShutdownGrace: 50 * time.Millisecond}
+	queue, err := New(conf, task)
+	require.NoError(t, err)
+
+	errc := make(chan error, 1)
+	go func() { errc <- queue.Listen() }()
+
+	wg.Add(1)
+	_, err = queue.Delay(task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	<-started
+
+	// Give Listen time to start handling signals
+	time.Sleep(100 * time.Millisecond)
+
+	// Listen returns once the grace period expires even though the handler is still running
+	start := time.Now()
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+
+	select {
+	case err = <-errc:
+		require.EqualError(t, err, "[7] queue was force stopped with 1 tasks in flight")
+		require.True(t, time.Since(start) >= 50*time.Millisecond)
+	case <-time.After(time.Second):
+		t.Fatal("queue was not force stopped after the grace period")
+	}
+
+	// The shutdown completes in the background once the handler returns
+	close(release)
+	wg.Wait()
+	require.Eventually(t, func() bool { return queue.NumWorkers() == 0 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,140 @@
+package radish
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// ChildAwaiter may be implemented by tasks that spawn child futures to defer their own
+// completion until all of their children have completed, so that fan-out jobs (e.g.
+// split a file then process the chunks) are tracked as one logical unit. If AwaitChildren
+// returns true, the Success or Failure callback of the task is not called when Handle
+// returns but once its last child completes; if Handle succeeded but any child failed,
+// the task fails with an ErrChildFailed error.
+type ChildAwaiter interface {
+	AwaitChildren() bool
+}
+
+// Spawn delays a child future linked to the parent future, which is usually the future
+// currently being handled by the caller's Handle method. Spawn should be called before
+// the parent's Handle method returns so that the parent can wait for its children.
+func (r *Radish) Spawn(ctx context.Context, parent uuid.UUID, task string, params, success, failure []byte) (id uuid.UUID, err error) {
+	r.families.spawn(parent)
+	if id, err = r.DelayContext(context.WithValue(ctx, parentKey, parent), task, params, success, failure); err != nil {
+		r.families.spawnFailed(parent)
+		return nil, err
+	}
+	return id, nil
+}
+
+// parentFrom returns the parent future id on the context, if any.
+func parentFrom(ctx context.Context) uuid.UUID {
+	parent, _ := ctx.Value(parentKey).(uuid.UUID)
+	return parent
+}
+
+// family tracks the children of a parent future that have not yet completed.
+type family struct {
+	children int       // the number of children that have not completed
+	err      error     // the error of the first child that failed
+	future   *Future   // the parent future, set once it is waiting for its children
+	handler  Task      // the handler of the parent future
+	perr     error     // the error returned by the parent's handler
+	start    time.Time // when the parent started being handled
+}
+
+// families tracks parent futures and their children by parent id.
+type families struct {
+	sync.Mutex
+	parent  *Radish
+	parents map[string]*family
+}
+
+func newFamilies(r *Radish) *families {
+	return &families{parent: r, parents: make(map[string]*family)}
+}
+
+func (f *families) spawn(parent uuid.UUID) {
+	f.Lock()
+	defer f.Unlock()
+
+	fam, ok := f.parents[parent.String()]
+	if !ok {
+		fam = &family{}
+		f.parents[parent.String()] = fam
+	}
+	fam.children++
+}
+
+// spawnFailed is called when a child could not be delayed.
+func (f *families) spawnFailed(parent uuid.UUID) {
+	f.Lock()
+	defer f.Unlock()
+
+	if fam, ok := f.parents[parent.String()]; ok {
+		fam.children--
+	}
+}
+
+// handled is called when the handler of a future returns. If the future is waiting for
+// children that have not completed, it is deferred and true is returned. Otherwise, the
+// error of the future is returned, which is a child error if a child failed.
+func (f *families) handled(future *Future, handler Task, err error, start time.Time) (bool, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	key := future.ID.String()
+	fam, ok := f.parents[key]
+	if !ok {
+		return false, err
+	}
+
+	if awaiter, ok := handler.(ChildAwaiter); !ok || !awaiter.AwaitChildren() {
+		delete(f.parents, key)
+		return false, err
+	}
+
+	if fam.children > 0 {
+		fam.future, fam.handler, fam.perr, fam.start = future, handler, err, start
+		return true, nil
+	}
+
+	delete(f.parents, key)
+	if err == nil {
+		err = fam.err
+	}
+	return false, err
+}
+
+// childCompleted is called when a child future completes, completing its parent if the
+// parent is waiting for its children and this was its last child.
+func (f *families) childCompleted(child *Future, err error) {
+	f.Lock()
+	key := child.Parent.String()
+	fam, ok := f.parents[key]
+	if !ok {
+		f.Unlock()
+		return
+	}
+
+	fam.children--
+	if err != nil && fam.err == nil {
+		fam.err = Errorf(CodeChildFailed, "child %s future %s failed: %s", child.Task, child.ID, err)
+	}
+
+	if fam.children > 0 || fam.future == nil {
+		f.Unlock()
+		return
+	}
+
+	delete(f.parents, key)
+	f.Unlock()
+
+	if fam.perr == nil {
+		fam.perr = fam.err
+	}
+	f.parent.complete(fam.future, fam.handler, fam.perr, fam.start)
+}
@@ -0,0 +1,78 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpawn(t *testing.T) {
+	queue, err := New(&Config{Workers: 4, NoSignals: true})
+	require.NoError(t, err)
+
+	// The parent splits its params into chunks that are processed by children
+	var chunks int32
+	wg := new(sync.WaitGroup)
+	chunk := &testTask{wg: wg, name: "chunk", onHandle: func(id uuid.UUID, params []byte) error {
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&chunks, 1)
+		if string(params) == "x" {
+			return errors.New("bad chunk")
+		}
+		return nil
+	}}
+
+	var processed int32
+	var failure error
+	split := &awaitTask{testTask: testTask{wg: wg, name: "split",
+		onHandle: func(id uuid.UUID, params []byte) error {
+			for _, c := range params {
+				if _, err := queue.Spawn(context.Background(), id, "chunk", []byte{c}, nil, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		onSuccess: func(id uuid.UUID, params []byte) { processed = atomic.LoadInt32(&chunks) },
+		onFailure: func(id uuid.UUID, err error, params []byte) { processed, failure = atomic.LoadInt32(&chunks), err },
+	}}
+	require.NoError(t, queue.Register(chunk))
+	require.NoError(t, queue.Register(split))
+
+	// The parent does not complete until all of its children have completed
+	wg.Add(4)
+	_, err = queue.Delay("split", []byte("abc"), nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+	require.Equal(t, int32(1), split.successes)
+	require.Equal(t, int32(3), processed)
+
+	// If any child fails the parent fails
+	wg.Add(4)
+	_, err = queue.Delay("split", []byte("axc"), nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+	require.Equal(t, int32(1), split.failures)
+	require.Equal(t, int32(6), processed)
+	require.Contains(t, failure.Error(), "[12] child chunk future")
+	require.Contains(t, failure.Error(), "bad chunk")
+
+	// Children cannot be spawned for unknown tasks
+	_, err = queue.Spawn(context.Background(), uuid.NewRandom(), "unknown", nil, nil, nil)
+	require.Error(t, err)
+}
+
+type awaitTask struct {
+	testTask
+}
+
+func (t *awaitTask) AwaitChildren() bool {
+	return true
+}
@@ -0,0 +1,194 @@
+package radish
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// Default options of the SQS broker.
+const (
+	defaultSQSWaitTime          = 20 * time.Second
+	defaultSQSVisibilityTimeout = 30 * time.Second
+)
+
+// SQS sends, receives, and deletes the messages of Amazon SQS queues. Radish does not
+// depend on the AWS SDK; implement the interface by wrapping the SQS client of the SDK.
+type SQS interface {
+	// SendMessage sends a message with the body to the queue.
+	SendMessage(ctx context.Context, queueURL, body string) error
+
+	// ReceiveMessage long polls the queue for up to the wait time, returning nil if no
+	// message is received. The message is hidden from other consumers for the visibility
+	// timeout; the receive count of the message must include the ApproximateReceiveCount.
+	ReceiveMessage(ctx context.Context, queueURL string, wait, visibility time.Duration) (*SQSMessage, error)
+
+	// ChangeMessageVisibility hides a received message for the timeout from now.
+	ChangeMessageVisibility(ctx context.Context, queueURL, receiptHandle string, timeout time.Duration) error
+
+	// DeleteMessage deletes a received message from the queue.
+	DeleteMessage(ctx context.Context, queueURL, receiptHandle string) error
+
+	// ApproximateNumberOfMessages returns the number of messages available in the queue.
+	ApproximateNumberOfMessages(ctx context.Context, queueURL string) (int, error)
+}
+
+// SQSMessage is a message received from an SQS queue.
+type SQSMessage struct {
+	Body          string // the body of the message
+	ReceiptHandle string // the handle used to change the visibility of and delete the message
+	ReceiveCount  int    // the number of times the message has been received
+}
+
+// SQSOptions configure the queue of an SQS broker and how its messages are received.
+type SQSOptions struct {
+	QueueURL           string                   // the url of the queue that holds futures
+	DeadLetterQueueURL string                   // the queue to move futures to once they have been received MaxReceives times
+	MaxReceives        int                      // the number of receives before a future is dead lettered (default 0, never)
+	WaitTime           time.Duration            // how long to long poll for messages (default and maximum 20 seconds)
+	VisibilityTimeout  time.Duration            // how long a received future is hidden before it is redelivered (default 30 seconds)
+	TaskTimeouts       map[string]time.Duration // the visibility timeout of the futures of tasks that take longer to handle
+}
+
+// NewSQSBroker returns a broker that holds futures in an Amazon SQS queue so that radish
+// workers can run against managed queues. Futures are received by long polling the queue
+// and are deleted once they have been handled; while they are handled they are hidden
+// from other consumers for the visibility timeout of their task, after which SQS
+// redelivers them, so the timeout should be longer than the longest time the future may
+// take to be handled. The receive count of the message sets the Redelivered count of the
+// future and, if MaxReceives is set, futures that have been received more than MaxReceives
+// times are moved to the dead letter queue rather than being handled again. Futures are
+// handled in the order they are received from SQS regardless of their priority.
+func NewSQSBroker(client SQS, opts SQSOptions) (Broker, error) {
+	if opts.QueueURL == "" {
+		return nil, Errorf(CodeInvalidConfig, "sqs broker requires a queue url")
+	}
+
+	if opts.MaxReceives > 0 && opts.DeadLetterQueueURL == "" {
+		return nil, Errorf(CodeInvalidConfig, "sqs broker requires a dead letter queue url to dead letter futures after %d receives", opts.MaxReceives)
+	}
+
+	if opts.WaitTime <= 0 || opts.WaitTime > defaultSQSWaitTime {
+		opts.WaitTime = defaultSQSWaitTime
+	}
+
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = defaultSQSVisibilityTimeout
+	}
+
+	return &sqsBroker{client: client, opts: opts, pending: make(map[string]string)}, nil
+}
+
+type sqsBroker struct {
+	sync.Mutex
+	client  SQS
+	opts    SQSOptions
+	pending map[string]string // the receipt handles of futures that have been dequeued but not acknowledged
+}
+
+func (b *sqsBroker) Enqueue(ctx context.Context, future *Future) (err error) {
+	var data []byte
+	if data, err = json.Marshal(&storedFuture{Future: future, Client: future.client, Stored: time.Now()}); err != nil {
+		return Errorf(CodeInvalidParams, "could not serialize future %s: %s", future.ID, err)
+	}
+
+	if err = b.client.SendMessage(ctx, b.opts.QueueURL, string(data)); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return Errorf(CodeBadGateway, "could not send future %s to sqs: %s", future.ID, err)
+	}
+	return nil
+}
+
+// Dequeue long polls the queue until a future is received or the context is done.
+func (b *sqsBroker) Dequeue(ctx context.Context) (future *Future, err error) {
+	for {
+		var msg *SQSMessage
+		if msg, err = b.client.ReceiveMessage(ctx, b.opts.QueueURL, b.opts.WaitTime, b.opts.VisibilityTimeout); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, Errorf(CodeBadGateway, "could not receive future from sqs: %s", err)
+		}
+
+		if msg != nil {
+			if future, err = b.receive(ctx, msg); future != nil || err != nil {
+				return future, err
+			}
+		}
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// receive parses the future from the message and extends its visibility to the timeout
+// of its task, returning nil if the future has been moved to the dead letter queue.
+func (b *sqsBroker) receive(ctx context.Context, msg *SQSMessage) (_ *Future, err error) {
+	stored := &storedFuture{}
+	if err = json.Unmarshal([]byte(msg.Body), stored); err != nil || stored.Future == nil {
+		// Delete the message so that it is not redelivered
+		b.client.DeleteMessage(ctx, b.opts.QueueURL, msg.ReceiptHandle)
+		return nil, Errorf(CodeInvalidParams, "could not parse future from sqs message: %v", err)
+	}
+
+	future := stored.Future
+	future.client = stored.Client
+	if msg.ReceiveCount > 1 {
+		future.Redelivered = msg.ReceiveCount - 1
+	}
+
+	if b.opts.MaxReceives > 0 && msg.ReceiveCount > b.opts.MaxReceives {
+		if err = b.client.SendMessage(ctx, b.opts.DeadLetterQueueURL, msg.Body); err != nil {
+			return nil, Errorf(CodeBadGateway, "could not dead letter future %s: %s", future.ID, err)
+		}
+
+		if err = b.client.DeleteMessage(ctx, b.opts.QueueURL, msg.ReceiptHandle); err != nil {
+			return nil, Errorf(CodeBadGateway, "could not delete dead lettered future %s: %s", future.ID, err)
+		}
+		return nil, nil
+	}
+
+	if timeout, ok := b.opts.TaskTimeouts[future.Task]; ok && timeout != b.opts.VisibilityTimeout {
+		if err = b.client.ChangeMessageVisibility(ctx, b.opts.QueueURL, msg.ReceiptHandle, timeout); err != nil {
+			return nil, Errorf(CodeBadGateway, "could not change the visibility of future %s: %s", future.ID, err)
+		}
+	}
+
+	b.Lock()
+	b.pending[future.ID.String()] = msg.ReceiptHandle
+	b.Unlock()
+	return future, nil
+}
+
+// Ack deletes the message of the future from the queue.
+func (b *sqsBroker) Ack(id uuid.UUID) (err error) {
+	b.Lock()
+	handle, ok := b.pending[id.String()]
+	delete(b.pending, id.String())
+	b.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if err = b.client.DeleteMessage(context.Background(), b.opts.QueueURL, handle); err != nil {
+		return Errorf(CodeBadGateway, "could not delete future %s from sqs: %s", id, err)
+	}
+	return nil
+}
+
+// Len returns the approximate number of messages available in the queue, or 0 if the
+// number cannot be determined.
+func (b *sqsBroker) Len() int {
+	n, err := b.client.ApproximateNumberOfMessages(context.Background(), b.opts.QueueURL)
+	if err != nil {
+		return 0
+	}
+	return n
+}
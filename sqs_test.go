@@ -0,0 +1,181 @@
+package radish_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQSBroker(t *testing.T) {
+	sqs := newFakeSQS()
+
+	// Brokers cannot be created with invalid options
+	_, err := NewSQSBroker(sqs, SQSOptions{})
+	require.EqualError(t, err, "[1] sqs broker requires a queue url")
+
+	_, err = NewSQSBroker(sqs, SQSOptions{QueueURL: "jobs", MaxReceives: 2})
+	require.EqualError(t, err, "[1] sqs broker requires a dead letter queue url to dead letter futures after 2 receives")
+
+	// Futures are deleted from the queue once they have been handled
+	broker, err := NewSQSBroker(sqs, SQSOptions{QueueURL: "jobs", WaitTime: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "task"}
+	queue, err := New(&Config{Workers: 2, NoSignals: true, LogLevel: "warn", Broker: broker}, task)
+	require.NoError(t, err)
+
+	wg.Add(5)
+	for i := 0; i < 5; i++ {
+		_, err = queue.Delay("task", nil, nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+	require.NoError(t, queue.Shutdown())
+	require.Equal(t, int32(5), task.successes)
+	require.Zero(t, sqs.len("jobs"))
+
+	// The visibility of futures is extended to the timeout of their task
+	broker, err = NewSQSBroker(sqs, SQSOptions{
+		QueueURL:           "jobs",
+		DeadLetterQueueURL: "jobs-dlq",
+		MaxReceives:        2,
+		VisibilityTimeout:  10 * time.Millisecond,
+		TaskTimeouts:       map[string]time.Duration{"slow": time.Hour},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, broker.Enqueue(ctx, &Future{ID: uuid.NewRandom(), Task: "slow"}))
+	require.Equal(t, 1, broker.Len())
+	future, err := broker.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "slow", future.Task)
+	require.Equal(t, time.Hour, sqs.visibility(future.ID))
+	require.NoError(t, broker.Ack(future.ID))
+
+	// Futures that are not deleted before their visibility timeout are redelivered
+	require.NoError(t, broker.Enqueue(ctx, &Future{ID: uuid.NewRandom(), Task: "task"}))
+	future, err = broker.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, future.Redelivered)
+
+	future, err = broker.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, future.Redelivered)
+
+	// Futures received more than the maximum number of times are dead lettered
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = broker.Dequeue(ctx)
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Zero(t, sqs.len("jobs"))
+	require.Equal(t, 1, sqs.len("jobs-dlq"))
+}
+
+// fakeSQS holds the messages of queues in memory, hiding received messages until their
+// visibility timeout expires.
+type fakeSQS struct {
+	sync.Mutex
+	queues map[string][]*fakeSQSMessage
+}
+
+type fakeSQSMessage struct {
+	body       string
+	handle     string
+	receives   int
+	hidden     time.Time
+	visibility time.Duration
+}
+
+func newFakeSQS() *fakeSQS {
+	return &fakeSQS{queues: make(map[string][]*fakeSQSMessage)}
+}
+
+func (s *fakeSQS) SendMessage(ctx context.Context, queueURL, body string) error {
+	s.Lock()
+	defer s.Unlock()
+	s.queues[queueURL] = append(s.queues[queueURL], &fakeSQSMessage{body: body})
+	return nil
+}
+
+func (s *fakeSQS) ReceiveMessage(ctx context.Context, queueURL string, wait, visibility time.Duration) (*SQSMessage, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		s.Lock()
+		for _, msg := range s.queues[queueURL] {
+			if time.Now().After(msg.hidden) {
+				msg.receives++
+				msg.handle = uuid.New()
+				msg.hidden, msg.visibility = time.Now().Add(visibility), visibility
+				s.Unlock()
+				return &SQSMessage{Body: msg.body, ReceiptHandle: msg.handle, ReceiveCount: msg.receives}, nil
+			}
+		}
+		s.Unlock()
+
+		if time.Now().After(deadline) {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (s *fakeSQS) ChangeMessageVisibility(ctx context.Context, queueURL, receiptHandle string, timeout time.Duration) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, msg := range s.queues[queueURL] {
+		if msg.handle == receiptHandle {
+			msg.hidden, msg.visibility = time.Now().Add(timeout), timeout
+		}
+	}
+	return nil
+}
+
+func (s *fakeSQS) DeleteMessage(ctx context.Context, queueURL, receiptHandle string) error {
+	s.Lock()
+	defer s.Unlock()
+	msgs := s.queues[queueURL]
+	for i, msg := range msgs {
+		if msg.handle == receiptHandle {
+			s.queues[queueURL] = append(msgs[:i:i], msgs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *fakeSQS) ApproximateNumberOfMessages(ctx context.Context, queueURL string) (int, error) {
+	return s.len(queueURL), nil
+}
+
+func (s *fakeSQS) len(queueURL string) int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.queues[queueURL])
+}
+
+// visibility returns the last visibility timeout of the message of the future.
+func (s *fakeSQS) visibility(id uuid.UUID) time.Duration {
+	s.Lock()
+	defer s.Unlock()
+	for _, msgs := range s.queues {
+		for _, msg := range msgs {
+			if strings.Contains(msg.body, id.String()) {
+				return msg.visibility
+			}
+		}
+	}
+	return 0
+}
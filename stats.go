@@ -0,0 +1,229 @@
+package radish
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+)
+
+// Default resolution and length of the stats history.
+const (
+	defaultStatsInterval = 10 * time.Second
+	defaultStatsHistory  = 360
+	throughputWindow     = time.Minute // the recent throughput used to estimate wait times
+)
+
+// stats counts the futures handled by each task since the last snapshot and keeps a
+// ring buffer of periodic snapshots so that trends in throughput can be shown by
+// clients even when Prometheus is not scraping the metrics endpoint. The totals for
+// each task since the queue started are also kept for the ListTasks RPC.
+type stats struct {
+	sync.RWMutex
+	counts  map[string]*api.TaskStats // futures handled since the last snapshot by task
+	totals  map[string]*api.TaskInfo  // futures queued and handled since the queue started by task
+	history []*api.StatsSnapshot      // ring buffer of snapshots
+	next    int                       // the index in the ring the next snapshot is written to
+	full    bool                      // if the ring has wrapped around
+	last    time.Time                 // when the last snapshot was recorded
+}
+
+func newStats(size int) *stats {
+	return &stats{counts: make(map[string]*api.TaskStats), totals: make(map[string]*api.TaskInfo), history: make([]*api.StatsSnapshot, size), last: time.Now()}
+}
+
+// total returns the totals of the named task, creating them if necessary. Must hold the
+// stats lock.
+func (s *stats) total(task string) *api.TaskInfo {
+	total, ok := s.totals[task]
+	if !ok {
+		total = &api.TaskInfo{Name: task}
+		s.totals[task] = total
+	}
+	return total
+}
+
+// queue records that a future of the named task was queued or scheduled.
+func (s *stats) queue(task string) {
+	s.Lock()
+	s.total(task).Queued++
+	s.Unlock()
+}
+
+// record the outcome of a future handled by the named task.
+func (s *stats) record(task string, err error, canceled bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	count, ok := s.counts[task]
+	if !ok {
+		count = &api.TaskStats{Task: task}
+		s.counts[task] = count
+	}
+
+	total := s.total(task)
+	switch {
+	case canceled:
+		count.Canceled++
+		total.Canceled++
+	case err != nil:
+		count.Failed++
+		total.Failed++
+	default:
+		count.Succeeded++
+		total.Succeeded++
+	}
+}
+
+// info returns a copy of the totals of the named task.
+func (s *stats) info(task string) *api.TaskInfo {
+	s.RLock()
+	defer s.RUnlock()
+
+	info := &api.TaskInfo{Name: task}
+	if total, ok := s.totals[task]; ok {
+		info.Queued = total.Queued
+		info.Succeeded = total.Succeeded
+		info.Failed = total.Failed
+		info.Canceled = total.Canceled
+	}
+	return info
+}
+
+// snapshot appends a snapshot of the throughput since the last snapshot to the history,
+// overwriting the oldest snapshot if the history is full, and resets the counts.
+func (s *stats) snapshot(now time.Time, workers, queue int) {
+	s.Lock()
+	defer s.Unlock()
+
+	snap := &api.StatsSnapshot{
+		Timestamp: now.UnixNano(),
+		Workers:   int32(workers),
+		Queue:     uint64(queue),
+		Tasks:     make([]*api.TaskStats, 0, len(s.counts)),
+	}
+
+	for _, count := range s.counts {
+		snap.Tasks = append(snap.Tasks, count)
+	}
+	sort.Slice(snap.Tasks, func(i, j int) bool { return snap.Tasks[i].Task < snap.Tasks[j].Task })
+
+	s.counts = make(map[string]*api.TaskStats)
+	s.last = now
+	s.history[s.next] = snap
+	s.next = (s.next + 1) % len(s.history)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// throughput returns the number of futures handled per second since the last snapshot
+// and over the most recent snapshots in the window, or 0 if no futures were handled.
+func (s *stats) throughput(now time.Time, interval, window time.Duration) float64 {
+	s.RLock()
+	defer s.RUnlock()
+
+	var handled uint64
+	for _, count := range s.counts {
+		handled += count.Succeeded + count.Failed + count.Canceled
+	}
+
+	n := s.next
+	if s.full {
+		n = len(s.history)
+	}
+
+	elapsed := now.Sub(s.last)
+	for i := 1; i <= n && elapsed+interval <= window; i++ {
+		for _, count := range s.history[(s.next-i+len(s.history))%len(s.history)].Tasks {
+			handled += count.Succeeded + count.Failed + count.Canceled
+		}
+		elapsed += interval
+	}
+
+	if handled == 0 || elapsed <= 0 {
+		return 0
+	}
+	return float64(handled) / elapsed.Seconds()
+}
+
+// estimateWait returns the number of futures waiting in the queue and an estimate of how
+// long a future queued at the specified time waits for a worker based on the recent
+// throughput of the queue, or 0 if the throughput is not known.
+func (r *Radish) estimateWait(at time.Time) (length uint64, wait time.Duration) {
+	now := time.Now()
+	length = uint64(r.tasks.Len())
+	if at.After(now) {
+		wait = at.Sub(now)
+	}
+
+	if rate := r.stats.throughput(now, r.config.StatsInterval, throughputWindow); rate > 0 {
+		wait += time.Duration(float64(length) / rate * float64(time.Second))
+	}
+	return length, wait
+}
+
+// snapshots returns up to limit of the most recent snapshots from oldest to newest.
+func (s *stats) snapshots(limit int) []*api.StatsSnapshot {
+	s.RLock()
+	defer s.RUnlock()
+
+	n := s.next
+	if s.full {
+		n = len(s.history)
+	}
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+
+	snaps := make([]*api.StatsSnapshot, 0, limit)
+	for i := s.next - limit; i < s.next; i++ {
+		snaps = append(snaps, s.history[(i+len(s.history))%len(s.history)])
+	}
+	return snaps
+}
+
+// collector periodically records stats snapshots until the queue is shutdown.
+func (r *Radish) collector() {
+	ticker := time.NewTicker(r.config.StatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.shutdown:
+			return
+		case now := <-ticker.C:
+			r.stats.snapshot(now, r.NumWorkers(), r.tasks.Len())
+		}
+	}
+}
+
+// StatsHistory returns the recent throughput snapshots of the queue so that clients can
+// show trends in the number of workers, queue depth, and futures handled per task.
+func (r *Radish) StatsHistory(ctx context.Context, in *api.StatsHistoryRequest) (rep *api.StatsHistoryReply, err error) {
+	rep = &api.StatsHistoryReply{
+		Interval:  int64(r.config.StatsInterval),
+		Snapshots: r.stats.snapshots(int(in.Limit)),
+	}
+
+	if len(in.Tasks) > 0 {
+		include := make(map[string]bool, len(in.Tasks))
+		for _, task := range in.Tasks {
+			include[task] = true
+		}
+
+		for i, snap := range rep.Snapshots {
+			filtered := &api.StatsSnapshot{Timestamp: snap.Timestamp, Workers: snap.Workers, Queue: snap.Queue}
+			for _, count := range snap.Tasks {
+				if include[count.Task] {
+					filtered.Tasks = append(filtered.Tasks, count)
+				}
+			}
+			rep.Snapshots[i] = filtered
+		}
+	}
+
+	return rep, nil
+}
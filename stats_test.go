@@ -0,0 +1,135 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsHistory(t *testing.T) {
+	queue, err := New(&Config{Workers: 2, NoSignals: true, StatsInterval: 20 * time.Millisecond, StatsHistory: 3})
+	require.NoError(t, err)
+
+	wg := new(sync.WaitGroup)
+	good := &testTask{wg: wg, name: "good"}
+	bad := &testTask{wg: wg, name: "bad", onHandle: func(id uuid.UUID, params []byte) error { return errors.New("whoops!") }}
+	require.NoError(t, queue.Register(good))
+	require.NoError(t, queue.Register(bad))
+
+	wg.Add(5)
+	for i := 0; i < 3; i++ {
+		_, err = queue.Delay("good", nil, nil, nil)
+		require.NoError(t, err)
+	}
+	for i := 0; i < 2; i++ {
+		_, err = queue.Delay("bad", nil, nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	// Wait for the history to wrap around
+	time.Sleep(100 * time.Millisecond)
+	rep, err := queue.StatsHistory(context.Background(), &api.StatsHistoryRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int64(20*time.Millisecond), rep.Interval)
+	require.Len(t, rep.Snapshots, 3)
+
+	var succeeded, failed uint64
+	for i, snap := range rep.Snapshots {
+		require.Equal(t, int32(2), snap.Workers)
+		if i > 0 {
+			require.True(t, snap.Timestamp > rep.Snapshots[i-1].Timestamp)
+		}
+		for _, count := range snap.Tasks {
+			succeeded += count.Succeeded
+			failed += count.Failed
+		}
+	}
+
+	// The futures were handled before the snapshots that remain in the history
+	require.Zero(t, succeeded)
+	require.Zero(t, failed)
+
+	rep, err = queue.StatsHistory(context.Background(), &api.StatsHistoryRequest{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, rep.Snapshots, 1)
+
+	// Futures handled since the last snapshot are counted in the next snapshot
+	wg.Add(3)
+	for _, task := range []string{"good", "good", "bad"} {
+		_, err = queue.Delay(task, nil, nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+	time.Sleep(45 * time.Millisecond)
+
+	rep, err = queue.StatsHistory(context.Background(), &api.StatsHistoryRequest{Tasks: []string{"good"}})
+	require.NoError(t, err)
+	for _, snap := range rep.Snapshots {
+		for _, count := range snap.Tasks {
+			require.Equal(t, "good", count.Task)
+			succeeded += count.Succeeded
+			failed += count.Failed
+		}
+	}
+	require.Equal(t, uint64(2), succeeded)
+	require.Zero(t, failed)
+
+	// The totals since the queue started are listed with the registered tasks
+	tasks, err := queue.ListTasks(context.Background(), &api.ListTasksRequest{})
+	require.NoError(t, err)
+	require.Len(t, tasks.Tasks, 2)
+	require.Equal(t, &api.TaskInfo{Name: "bad", Queued: 3, Failed: 3}, tasks.Tasks[0])
+	require.Equal(t, &api.TaskInfo{Name: "good", Queued: 5, Succeeded: 5}, tasks.Tasks[1])
+}
+
+func TestQueueEstimate(t *testing.T) {
+	release := make(chan struct{}, 4)
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "slow", onHandle: func(id uuid.UUID, params []byte) error {
+		<-release
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// The wait cannot be estimated until futures have been handled
+	wg.Add(1)
+	rep, err := queue.Queue(context.Background(), &api.QueueRequest{Task: "slow"})
+	require.NoError(t, err)
+	require.Zero(t, rep.EstimatedWait)
+
+	release <- struct{}{}
+	wg.Wait()
+
+	// Futures queued behind a busy worker are estimated to wait longer the longer the queue
+	wg.Add(3)
+	var prev int64
+	for i := 0; i < 3; i++ {
+		rep, err = queue.Queue(context.Background(), &api.QueueRequest{Task: "slow"})
+		require.NoError(t, err)
+		require.True(t, rep.EstimatedWait >= prev)
+		prev = rep.EstimatedWait
+	}
+	require.True(t, rep.QueueLength >= 1)
+	require.True(t, rep.EstimatedWait > 0)
+
+	// Scheduled futures wait at least until they are queued
+	rep, err = queue.Queue(context.Background(), &api.QueueRequest{Task: "slow", Delay: int64(time.Hour)})
+	require.NoError(t, err)
+	require.True(t, rep.EstimatedWait > int64(59*time.Minute))
+
+	for i := 0; i < 3; i++ {
+		release <- struct{}{}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,236 @@
+package radish
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Storage options that select where queued futures are kept.
+const (
+	StorageMemory = "memory"
+	StorageBolt   = "bolt"
+	StorageWAL    = "wal"
+)
+
+// Default path of the BoltDB file used by the bolt storage.
+const defaultStoragePath = "radish.db"
+
+var futuresBucket = []byte("futures")
+
+// store persists futures from when they are accepted until they have completed so that
+// queued work is not lost if the process crashes or is restarted. Futures are only
+// deleted once their handlers have returned, so futures that were being handled when the
+// process crashed are delivered again when the queue restarts (at-least-once delivery).
+// The memory store does not persist futures at all, which is the default behavior.
+type store interface {
+	put(future *Future, at time.Time) error // persist the future, which is due at the specified time if it is scheduled
+	deliver(future *Future) error           // mark the future as delivered to a worker, persisting its attempts
+	delete(id uuid.UUID) error              // delete the future once it has completed
+	load() ([]*storedFuture, error)         // load all persisted futures in the order they were stored
+	durable() bool                          // if futures survive a restart
+	close() error                           // close the store, releasing any resources
+}
+
+// storedFuture is the serialized form of a future in a durable store.
+type storedFuture struct {
+	Future    *Future   `json:"future"`
+	Client    string    `json:"client,omitempty"`
+	At        time.Time `json:"at,omitempty"`
+	Stored    time.Time `json:"stored"`
+	Delivered bool      `json:"delivered,omitempty"` // if a worker was handling the future when the queue stopped
+}
+
+// openStore opens the store selected by the config.
+func (r *Radish) openStore(config *Config) (store, error) {
+	switch config.Storage {
+	case StorageBolt:
+		return openBoltStore(config.StoragePath, config.Cipher)
+	case StorageWAL:
+		return openWALStore(config.StoragePath, config.Cipher, config.CompactInterval, func(err error) {
+			r.logf(out.LevelWarn, "", "%s", err)
+		})
+	default:
+		return memoryStore{}, nil
+	}
+}
+
+// memoryStore keeps futures only in the task queue, so they are lost on restart.
+type memoryStore struct{}
+
+func (memoryStore) put(*Future, time.Time) error   { return nil }
+func (memoryStore) deliver(*Future) error          { return nil }
+func (memoryStore) delete(uuid.UUID) error         { return nil }
+func (memoryStore) load() ([]*storedFuture, error) { return nil, nil }
+func (memoryStore) durable() bool                  { return false }
+func (memoryStore) close() error                   { return nil }
+
+// boltStore persists futures to a local BoltDB file keyed by future ID. If a cipher is
+// specified, the payloads of the futures are encrypted before they are written to disk.
+type boltStore struct {
+	db     *bolt.DB
+	cipher Cipher
+}
+
+func openBoltStore(path string, cipher Cipher) (s *boltStore, err error) {
+	s = &boltStore{cipher: cipher}
+	if s.db, err = bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second}); err != nil {
+		return nil, Errorf(CodeStorage, "could not open bolt storage at %s: %s", path, err)
+	}
+
+	if err = s.db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(futuresBucket)
+		return err
+	}); err != nil {
+		s.db.Close()
+		return nil, Errorf(CodeStorage, "could not create futures bucket: %s", err)
+	}
+	return s, nil
+}
+
+func (s *boltStore) put(future *Future, at time.Time) (err error) {
+	var enc *Future
+	if enc, err = future.encrypt(s.cipher); err != nil {
+		return Errorf(CodeStorage, "could not encrypt future %s: %s", future.ID, err)
+	}
+
+	var data []byte
+	if data, err = json.Marshal(&storedFuture{Future: enc, Client: future.client, At: at, Stored: time.Now()}); err != nil {
+		return Errorf(CodeStorage, "could not serialize future %s: %s", future.ID, err)
+	}
+
+	if err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(futuresBucket).Put(future.ID, data)
+	}); err != nil {
+		return Errorf(CodeStorage, "could not persist future %s: %s", future.ID, err)
+	}
+	return nil
+}
+
+func (s *boltStore) deliver(future *Future) (err error) {
+	var enc *Future
+	if enc, err = future.encrypt(s.cipher); err != nil {
+		return Errorf(CodeStorage, "could not encrypt future %s: %s", future.ID, err)
+	}
+
+	if err = s.db.Update(func(tx *bolt.Tx) (err error) {
+		// Keep the time the future was stored so that it is restored in the same order
+		bucket := tx.Bucket(futuresBucket)
+		stored := &storedFuture{Stored: time.Now()}
+		if data := bucket.Get(future.ID); data != nil {
+			if err = json.Unmarshal(data, stored); err != nil {
+				return err
+			}
+		}
+
+		stored.Future, stored.Client, stored.Delivered = enc, future.client, true
+
+		var data []byte
+		if data, err = json.Marshal(stored); err != nil {
+			return err
+		}
+		return bucket.Put(future.ID, data)
+	}); err != nil {
+		return Errorf(CodeStorage, "could not mark future %s as delivered: %s", future.ID, err)
+	}
+	return nil
+}
+
+func (s *boltStore) delete(id uuid.UUID) (err error) {
+	if err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(futuresBucket).Delete(id)
+	}); err != nil {
+		return Errorf(CodeStorage, "could not delete future %s: %s", id, err)
+	}
+	return nil
+}
+
+func (s *boltStore) load() (futures []*storedFuture, err error) {
+	if err = s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(futuresBucket).ForEach(func(key, data []byte) (err error) {
+			stored := &storedFuture{}
+			if err = json.Unmarshal(data, stored); err != nil {
+				return Errorf(CodeStorage, "could not deserialize future %s: %s", uuid.UUID(key), err)
+			}
+
+			if stored.Future, err = stored.Future.decrypt(s.cipher); err != nil {
+				return Errorf(CodeStorage, "could not decrypt future %s: %s", uuid.UUID(key), err)
+			}
+
+			stored.Future.client = stored.Client
+			futures = append(futures, stored)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(futures, func(i, j int) bool {
+		return futures[i].Stored.Before(futures[j].Stored)
+	})
+	return futures, nil
+}
+
+func (s *boltStore) durable() bool {
+	return true
+}
+
+func (s *boltStore) close() error {
+	return s.db.Close()
+}
+
+// restore queues the futures that were persisted before the queue was last stopped,
+// scheduling any futures that are not yet due. Futures whose tasks are not registered
+// when they are restored are moved to the dead letter queue when they are dequeued.
+func (r *Radish) restore() error {
+	futures, err := r.store.load()
+	if err != nil {
+		return err
+	}
+
+	for _, stored := range futures {
+		future := stored.Future
+		if stored.Delivered {
+			future.Redelivered++
+			r.logf(out.LevelCaution, future.Task, "redelivering %s future %s that was being handled when the queue stopped", future.Task, future.ID)
+		}
+
+		r.inflight.enqueue(future.ID)
+		if stored.At.After(time.Now()) {
+			r.results.track(future, api.FutureState_SCHEDULED)
+			r.scheduled.add(future, stored.At)
+			continue
+		}
+
+		if err = r.requeue(context.Background(), future); err != nil {
+			r.inflight.drop(future.ID)
+			return err
+		}
+	}
+
+	if len(futures) > 0 {
+		r.logf(out.LevelStatus, "", "restored %d futures from %s storage", len(futures), r.config.Storage)
+	}
+	return nil
+}
+
+// delivered marks a future in the store as delivered before it is handled so that it is
+// redelivered if the queue stops before it completes.
+func (r *Radish) delivered(future *Future) {
+	if err := r.store.deliver(future); err != nil {
+		r.logf(out.LevelWarn, future.Task, "%s", err)
+	}
+}
+
+// unpersist deletes a future that has completed or been handed off from the store.
+func (r *Radish) unpersist(future *Future) {
+	if err := r.store.delete(future.ID); err != nil {
+		r.logf(out.LevelWarn, future.Task, "%s", err)
+	}
+}
@@ -0,0 +1,246 @@
+package radish_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "radish.db")
+	conf := func() *Config {
+		return &Config{Workers: 1, NoSignals: true, Storage: "bolt", StoragePath: path, EncryptionKey: bytes.Repeat([]byte{0x1}, 16)}
+	}
+
+	// Record how many times each future was handled by either queue
+	var mu sync.Mutex
+	handled := make(map[string]int)
+	onHandle := func(id uuid.UUID, params []byte) error {
+		mu.Lock()
+		handled[id.String()]++
+		mu.Unlock()
+		return nil
+	}
+
+	wg := new(sync.WaitGroup)
+	queue, err := New(conf(), &testTask{wg: wg, name: "durable", onHandle: onHandle})
+	require.NoError(t, err)
+
+	// Completed futures are removed from storage
+	wg.Add(1)
+	done, err := queue.Delay("durable", []byte("secret"), nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	// Stop the workers so that futures remain in the queue when it is shutdown
+	require.NoError(t, queue.SetWorkers(0))
+
+	ids := make([]uuid.UUID, 0, 4)
+	for i := 0; i < 3; i++ {
+		id, err := queue.Delay("durable", []byte("secret"), nil, nil)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	id, err := queue.DelayAfter("durable", 50*time.Millisecond, nil, nil, nil)
+	require.NoError(t, err)
+	ids = append(ids, id)
+	require.NoError(t, queue.Shutdown())
+
+	// The queued and scheduled futures are restored and handled when the queue restarts
+	wg = new(sync.WaitGroup)
+	wg.Add(len(ids))
+	queue, err = New(conf(), &testTask{wg: wg, name: "durable", onHandle: onHandle})
+	require.NoError(t, err)
+	wg.Wait()
+	require.NoError(t, queue.Shutdown())
+
+	require.Len(t, handled, len(ids)+1)
+	require.Equal(t, 1, handled[done.String()])
+	for _, id := range ids {
+		require.Equal(t, 1, handled[id.String()], "future %s was not handled exactly once", id)
+	}
+
+	// Invalid storage is rejected
+	_, err = New(&Config{Storage: "redis"})
+	require.EqualError(t, err, `[1] "redis" is an invalid storage, use memory, bolt, or wal`)
+}
+
+func TestWALStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "radish.wal")
+	conf := func() *Config {
+		return &Config{Workers: 1, NoSignals: true, Storage: "wal", StoragePath: path, CompactInterval: 10 * time.Millisecond}
+	}
+
+	var mu sync.Mutex
+	handled := make(map[string]int)
+	onHandle := func(id uuid.UUID, params []byte) error {
+		mu.Lock()
+		handled[id.String()]++
+		mu.Unlock()
+		return nil
+	}
+
+	wg := new(sync.WaitGroup)
+	queue, err := New(conf(), &testTask{wg: wg, name: "durable", onHandle: onHandle})
+	require.NoError(t, err)
+
+	// The log is compacted once completed futures are deleted
+	wg.Add(10)
+	for i := 0; i < 10; i++ {
+		_, err = queue.Delay("durable", []byte("params"), nil, nil)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		stat, err := os.Stat(path)
+		return err == nil && stat.Size() == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// Stop the workers so that futures remain in the log when the queue is shutdown
+	require.NoError(t, queue.SetWorkers(0))
+
+	ids := make([]uuid.UUID, 0, 4)
+	for i := 0; i < 3; i++ {
+		id, err := queue.Delay("durable", []byte("params"), nil, nil)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	id, err := queue.DelayAfter("durable", 50*time.Millisecond, nil, nil, nil)
+	require.NoError(t, err)
+	ids = append(ids, id)
+	require.NoError(t, queue.Shutdown())
+
+	// A record that was only partially written when the process stopped is discarded
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"op":"put","id":`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// The queued and scheduled futures are recovered and handled when the queue restarts
+	wg = new(sync.WaitGroup)
+	wg.Add(len(ids))
+	queue, err = New(conf(), &testTask{wg: wg, name: "durable", onHandle: onHandle})
+	require.NoError(t, err)
+	wg.Wait()
+	require.NoError(t, queue.Shutdown())
+
+	require.Len(t, handled, len(ids)+10)
+	for _, id := range ids {
+		require.Equal(t, 1, handled[id.String()], "future %s was not handled exactly once", id)
+	}
+
+	// A corrupt log is not silently discarded
+	require.NoError(t, os.WriteFile(path, []byte("not a record\n"), 0600))
+	_, err = New(conf())
+	require.EqualError(t, err, "[14] could not parse record 1 of the write-ahead log: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestRestoreFailure(t *testing.T) {
+	dir := t.TempDir()
+	conf := func(key byte) *Config {
+		return &Config{
+			Workers: 8, NoSignals: true, LogLevel: "silent", Storage: "bolt", StoragePath: filepath.Join(dir, "radish.db"),
+			EncryptionKey: bytes.Repeat([]byte{key}, 16), AuditLog: filepath.Join(dir, "audit.log"),
+		}
+	}
+
+	// Persist a future that cannot be decrypted with a different key
+	queue, err := New(conf(0x1), &testTask{name: "durable"})
+	require.NoError(t, err)
+	require.NoError(t, queue.Pause())
+	_, err = queue.Delay("durable", []byte("secret"), nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, queue.Shutdown())
+
+	fds := func() int {
+		entries, err := os.ReadDir("/proc/self/fd")
+		if err != nil {
+			t.Skip("open files can only be counted on linux")
+		}
+		return len(entries)
+	}
+
+	// The workers, storage, and audit log are closed if the futures cannot be restored
+	files, routines := fds(), runtime.NumGoroutine()
+	_, err = New(conf(0x2), &testTask{name: "durable"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "could not decrypt future")
+
+	require.Equal(t, files, fds())
+	for i := 0; i < 100 && runtime.NumGoroutine() > routines; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), routines)
+}
+
+func TestRedelivery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "radish.db")
+
+	// Crash a process while it is handling a future by running the crash helper
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRedeliveryCrash$")
+	cmd.Env = append(os.Environ(), "RADISH_CRASH_STORAGE="+path)
+	stdout, err := cmd.Output()
+	require.Error(t, err)
+	require.Equal(t, 3, cmd.ProcessState.ExitCode())
+
+	id := uuid.Parse(strings.TrimSpace(string(stdout)))
+	require.NotNil(t, id, "crash helper did not print the id of the future")
+
+	// The future is redelivered when the queue restarts
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	task := &testTask{wg: wg, name: "crash"}
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "silent", Storage: "bolt", StoragePath: path}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+	wg.Wait()
+
+	var info *api.FutureInfo
+	require.Eventually(t, func() bool {
+		rep, err := queue.GetFuture(context.Background(), &api.GetFutureRequest{Uuid: id})
+		require.NoError(t, err)
+		info = rep.Future
+		return rep.Success && info.State == api.FutureState_SUCCEEDED
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(1), info.Redelivered)
+	require.Equal(t, int32(2), info.Attempts)
+}
+
+// TestRedeliveryCrash is run in a separate process by TestRedelivery; it exits the
+// process while a future is being handled as if the process had crashed.
+func TestRedeliveryCrash(t *testing.T) {
+	path := os.Getenv("RADISH_CRASH_STORAGE")
+	if path == "" {
+		t.Skip("only run as a crash helper by TestRedelivery")
+	}
+
+	task := &testTask{name: "crash", onHandle: func(id uuid.UUID, params []byte) error {
+		os.Stdout.WriteString(id.String() + "\n")
+		os.Exit(3)
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "silent", Storage: "bolt", StoragePath: path}, task)
+	require.NoError(t, err)
+
+	_, err = queue.Delay(task.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Second)
+	t.Fatal("the crash helper did not exit while handling the future")
+}
@@ -0,0 +1,120 @@
+package radish
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/kansaslabs/x/out"
+)
+
+// Environment variables set by systemd for services with Type=notify and WatchdogSec.
+const (
+	envNotifySocket = "NOTIFY_SOCKET"
+	envWatchdogUsec = "WATCHDOG_USEC"
+	envWatchdogPID  = "WATCHDOG_PID"
+)
+
+// States sent to systemd via the notify socket.
+const (
+	sdReady    = "READY=1"
+	sdStopping = "STOPPING=1"
+	sdWatchdog = "WATCHDOG=1"
+)
+
+// sdNotify sends the state to the systemd notify socket. If radish is not running under
+// systemd (e.g. the NOTIFY_SOCKET environment variable is not set) this is a no-op.
+func sdNotify(state string) (err error) {
+	path := os.Getenv(envNotifySocket)
+	if path == "" {
+		return nil
+	}
+
+	var conn net.Conn
+	addr := &net.UnixAddr{Name: path, Net: "unixgram"}
+	if conn, err = net.DialUnix(addr.Net, nil, addr); err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// sdWatchdogInterval returns the interval at which watchdog pings should be sent to
+// systemd, which is half of the configured WatchdogSec, or 0 if the watchdog is not
+// enabled for this process.
+func sdWatchdogInterval() time.Duration {
+	if pid := os.Getenv(envWatchdogPID); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv(envWatchdogUsec), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// notifyReady tells systemd that radish is ready to receive requests and starts sending
+// watchdog pings if the watchdog is enabled. The returned function stops the watchdog
+// and notifies systemd that the server is stopping.
+func (r *Radish) notifyReady() (stopping func()) {
+	if err := sdNotify(sdReady); err != nil {
+		r.logf(out.LevelWarn, "", "could not notify systemd: %s", err)
+	}
+
+	done := make(chan struct{})
+	if interval := sdWatchdogInterval(); interval > 0 {
+		r.logf(out.LevelInfo, "", "sending systemd watchdog pings every %s", interval)
+		go r.watchdog(interval, done)
+	}
+
+	return func() {
+		close(done)
+		if err := sdNotify(sdStopping); err != nil {
+			r.logf(out.LevelWarn, "", "could not notify systemd: %s", err)
+		}
+	}
+}
+
+// watchdog pings systemd every interval as long as the workers are alive. If the
+// workers are wedged, the pings stop and systemd will restart the service once the
+// WatchdogSec timeout has elapsed.
+func (r *Radish) watchdog(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if !r.alive(2 * interval) {
+				r.logf(out.LevelWarn, "", "workers have not handled any tasks in %s, withholding systemd watchdog ping", 2*interval)
+				continue
+			}
+
+			if err := sdNotify(sdWatchdog); err != nil {
+				r.logf(out.LevelWarn, "", "could not send systemd watchdog ping: %s", err)
+			}
+		}
+	}
+}
+
+// alive reports whether the workers are making progress: if tasks are waiting in the
+// queue and workers are running, a worker must have dequeued a task within the timeout.
+// Note that long running tasks hold workers, so the systemd WatchdogSec should be longer
+// than the longest running task. A queue that has been scaled to zero workers is not
+// considered wedged since restarting the service would not cause it to make progress,
+// nor is a queue whose workers have been paused.
+func (r *Radish) alive(timeout time.Duration) bool {
+	if r.tasks.Len() == 0 || r.NumWorkers() == 0 || r.Paused() {
+		return true
+	}
+
+	dequeued := time.Unix(0, atomic.LoadInt64(&r.dequeued))
+	return time.Since(dequeued) < timeout
+}
@@ -0,0 +1,129 @@
+package radish_test
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdNotify(t *testing.T) {
+	notify := listenNotify(t)
+	t.Setenv("WATCHDOG_USEC", "40000")
+
+	// Block the only worker so that the queue can be wedged
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	task := &testTask{wg: wg, name: "wedge", onHandle: func(id uuid.UUID, params []byte) error {
+		if string(params) == "block" {
+			started <- struct{}{}
+			<-release
+		}
+		return nil
+	}}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent"}, task)
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	errc := make(chan error, 1)
+	go func() { errc <- queue.Serve(sock) }()
+
+	// systemd is notified that the server is ready, then pinged while the workers are alive
+	require.Equal(t, "READY=1", notify.next(time.Second))
+	require.Equal(t, "WATCHDOG=1", notify.next(time.Second))
+	require.Equal(t, "WATCHDOG=1", notify.next(time.Second))
+
+	// Pings are withheld while futures are waiting and the workers are wedged
+	wg.Add(2)
+	_, err = queue.Delay("wedge", []byte("block"), nil, nil)
+	require.NoError(t, err)
+	<-started
+	_, err = queue.Delay("wedge", nil, nil, nil)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	notify.drain()
+	require.Empty(t, notify.next(200*time.Millisecond))
+
+	// Pings resume once the workers make progress again
+	close(release)
+	wg.Wait()
+	require.Equal(t, "WATCHDOG=1", notify.next(time.Second))
+
+	// systemd is notified that the server is stopping when it is shutdown
+	require.NoError(t, queue.Shutdown())
+	require.NoError(t, <-errc)
+	for {
+		state := notify.next(time.Second)
+		require.NotEmpty(t, state, "systemd was not notified that the server is stopping")
+		if state == "STOPPING=1" {
+			break
+		}
+	}
+}
+
+func TestSystemdWatchdogPID(t *testing.T) {
+	notify := listenNotify(t)
+	t.Setenv("WATCHDOG_USEC", "20000")
+	t.Setenv("WATCHDOG_PID", "1")
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent"})
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	errc := make(chan error, 1)
+	go func() { errc <- queue.Serve(sock) }()
+
+	// The watchdog is enabled for another process so no pings are sent
+	require.Equal(t, "READY=1", notify.next(time.Second))
+	require.Empty(t, notify.next(100*time.Millisecond))
+
+	require.NoError(t, queue.Shutdown())
+	require.NoError(t, <-errc)
+	require.Equal(t, "STOPPING=1", notify.next(time.Second))
+}
+
+// notifySocket receives the states sent to a fake systemd notify socket.
+type notifySocket struct {
+	conn *net.UnixConn
+}
+
+// listenNotify listens on a notify socket and sets the NOTIFY_SOCKET for the test.
+func listenNotify(t *testing.T) *notifySocket {
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", path)
+	return &notifySocket{conn: conn}
+}
+
+// next returns the next state sent to the socket or an empty string after the timeout.
+func (s *notifySocket) next(timeout time.Duration) string {
+	buf := make([]byte, 1024)
+	s.conn.SetReadDeadline(time.Now().Add(timeout))
+	n, err := s.conn.Read(buf)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf[:n]))
+}
+
+// drain discards the states that have already been sent to the socket.
+func (s *notifySocket) drain() {
+	for s.next(time.Millisecond) != "" {
+	}
+}
@@ -1,6 +1,10 @@
 package radish
 
-import "github.com/pborman/uuid"
+import (
+	"context"
+
+	"github.com/pborman/uuid"
+)
 
 // Task specifies the interface for custom task types to be implemented.
 // When registring a task with the radish server, it is important to note that the task
@@ -16,11 +20,34 @@ type Task interface {
 	Failure(id uuid.UUID, err error, params []byte) // callback for when the task could not be completed with the error
 }
 
+// CallbackContextTask is implemented by tasks whose callbacks need the context of the
+// future, e.g. to read its metadata with MetadataFrom. Workers call SuccessContext and
+// FailureContext instead of Success and Failure.
+type CallbackContextTask interface {
+	SuccessContext(ctx context.Context, id uuid.UUID, params []byte)
+	FailureContext(ctx context.Context, id uuid.UUID, err error, params []byte)
+}
+
 // Future represents an enqueued task and its serialized parameters
 type Future struct {
-	ID      uuid.UUID // Task ID
-	Task    string    // Task type
-	Params  []byte    // the serialized parameters of the future
-	Success []byte    // the serialized parameters to pass to the success function
-	Failure []byte    // the serialized parameters to pass to the failure function on error
+	ID             uuid.UUID         // Task ID
+	Task           string            // Task type
+	Params         []byte            // the serialized parameters of the future
+	Success        []byte            // the serialized parameters to pass to the success function
+	Failure        []byte            // the serialized parameters to pass to the failure function on error
+	Metadata       map[string]string // request metadata copied from the context the future was delayed with
+	Callback       string            // the address of a RadishCallback service to notify when the future completes
+	Parent         uuid.UUID         // the id of the future that spawned this future, if any
+	Workflow       uuid.UUID         // the id of the workflow the future is a step of, if any
+	Step           string            // the name of the workflow step the future handles, if any
+	Group          uuid.UUID         // the id of the group the future is a member of, if any
+	Member         int               // the index of the future in the members of its group
+	Attempts       int               // the number of times the future has been handled, including the current attempt
+	Priority       int32             // futures with a higher priority are handled first (default 0)
+	IdempotencyKey string            // futures of the task with the same key are only queued once
+	PartitionKey   string            // futures with the same key are handled serially in the order they were queued
+	Redelivered    int               // the number of times the future was redelivered because the queue stopped while it was being handled
+	client         string            // the identity of the API client that queued the future, if any
+	result         []byte            // the result returned by a ResultTask handler, if any
+	canceled       bool              // if the future was canceled before or while it was handled
 }
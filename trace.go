@@ -0,0 +1,79 @@
+package radish
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// W3C trace context keys, used both as gRPC metadata (or HTTP header) keys of the Queue
+// RPC and as keys of the metadata of the futures that the requests create.
+const (
+	TraceParentKey = "traceparent"
+	TraceStateKey  = "tracestate"
+)
+
+// WithTraceParent returns a copy of the parent context with the W3C traceparent added to
+// its radish metadata, so that the future of a task delayed with the context is linked to
+// the trace of the request that created it.
+func WithTraceParent(parent context.Context, traceparent string) context.Context {
+	return WithMetadata(parent, TraceParentKey, traceparent)
+}
+
+// TraceParentFrom returns the W3C traceparent on the context, e.g. of the request that
+// created the future being handled, or an empty string if the context is not traced.
+func TraceParentFrom(ctx context.Context) string {
+	md, _ := ctx.Value(metadataKey).(map[string]string)
+	return md[TraceParentKey]
+}
+
+// withTraceContext copies a valid traceparent and its tracestate from the incoming gRPC
+// metadata of the request to the radish metadata on the context unless the request
+// already specified a traceparent in its metadata.
+func withTraceContext(ctx context.Context) context.Context {
+	if TraceParentFrom(ctx) != "" {
+		return ctx
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	vals := md.Get(TraceParentKey)
+	if len(vals) == 0 || !validTraceParent(vals[0]) {
+		return ctx
+	}
+
+	ctx = WithTraceParent(ctx, vals[0])
+	if state := md.Get(TraceStateKey); len(state) > 0 {
+		ctx = WithMetadata(ctx, TraceStateKey, strings.Join(state, ","))
+	}
+	return ctx
+}
+
+// validTraceParent checks that the traceparent has the version-traceid-parentid-flags
+// format of the W3C trace context spec with a non-zero trace and parent id.
+func validTraceParent(traceparent string) bool {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) < 4 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return false
+	}
+
+	for i, size := range []int{2, 32, 16, 2} {
+		if len(parts[i]) != size || !isHex(parts[i]) {
+			return false
+		}
+	}
+	return strings.Trim(parts[1], "0") != "" && strings.Trim(parts[2], "0") != ""
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
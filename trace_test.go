@@ -0,0 +1,73 @@
+package radish_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTraceContext(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	task := &traceTask{testTask: testTask{wg: wg, name: "traced"}, traces: make(chan map[string]string, 1)}
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn"}, task)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	queueTrace := func(ctx context.Context, in *api.QueueRequest) map[string]string {
+		wg.Add(1)
+		in.Task = "traced"
+		rep, err := queue.Queue(ctx, in)
+		require.NoError(t, err)
+		require.True(t, rep.Success)
+		wg.Wait()
+		return <-task.traces
+	}
+
+	// The traceparent and tracestate of the request are copied onto the future
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TraceParentKey, traceparent, TraceStateKey, "vendor=value"))
+	md := queueTrace(ctx, &api.QueueRequest{})
+	require.Equal(t, traceparent, md[TraceParentKey])
+	require.Equal(t, "vendor=value", md[TraceStateKey])
+
+	// A traceparent in the metadata of the request takes precedence
+	explicit := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00"
+	md = queueTrace(ctx, &api.QueueRequest{Metadata: map[string]string{TraceParentKey: explicit}})
+	require.Equal(t, explicit, md[TraceParentKey])
+	require.Empty(t, md[TraceStateKey])
+
+	// Invalid traceparents are ignored
+	for _, invalid := range []string{"", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"} {
+		ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(TraceParentKey, invalid))
+		md = queueTrace(ctx, &api.QueueRequest{})
+		require.Empty(t, md[TraceParentKey], invalid)
+	}
+
+	// Futures delayed with a traced context are linked to the trace
+	wg.Add(1)
+	_, err = queue.DelayContext(WithTraceParent(context.Background(), traceparent), "traced", nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+	require.Equal(t, traceparent, (<-task.traces)[TraceParentKey])
+}
+
+type traceTask struct {
+	testTask
+	traces chan map[string]string
+}
+
+func (t *traceTask) HandleContext(ctx context.Context, id uuid.UUID, params []byte) error {
+	md := MetadataFrom(ctx)
+	if md == nil {
+		md = make(map[string]string)
+	}
+	md[TraceParentKey] = TraceParentFrom(ctx)
+	t.traces <- md
+	return nil
+}
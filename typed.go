@@ -0,0 +1,68 @@
+package radish
+
+import (
+	"context"
+
+	"github.com/pborman/uuid"
+)
+
+// Typed is a task handler that decodes the params of each future into a value of type T
+// before calling a strongly typed handler, removing the marshal and unmarshal boilerplate
+// from every task. Params are decoded with the codec of the task (JSON unless another
+// codec is registered with RegisterCodec) unless an Unmarshal function is given; futures
+// whose params cannot be decoded fail with an ErrInvalidParams error. Use DelayTyped to
+// encode typed params with the codec of the task when delaying a future.
+//
+//	queue.Register(&radish.Typed[Email]{TaskName: "sendEmail", Handler: sendEmail})
+//	id, err := radish.DelayTyped(ctx, queue, "sendEmail", Email{To: "jdoe@example.com"}, nil, nil)
+type Typed[T any] struct {
+	TaskName  string                                                  // the unique name of the task
+	Handler   func(ctx context.Context, id uuid.UUID, params T) error // handles the decoded params of each future
+	OnSuccess func(id uuid.UUID, params []byte)                       // called when a future is handled successfully (optional)
+	OnFailure func(id uuid.UUID, err error, params []byte)            // called when a future could not be handled (optional)
+	Unmarshal func(data []byte, v interface{}) error                  // decodes the params of each future (default the codec of the task)
+}
+
+// Name implements the Task interface.
+func (t *Typed[T]) Name() string {
+	return t.TaskName
+}
+
+// Handle decodes the params and calls the typed handler.
+func (t *Typed[T]) Handle(id uuid.UUID, params []byte) error {
+	return t.HandleContext(context.Background(), id, params)
+}
+
+// HandleContext decodes the params and calls the typed handler with the context, which
+// is canceled if the future is canceled while it is being handled.
+func (t *Typed[T]) HandleContext(ctx context.Context, id uuid.UUID, params []byte) (err error) {
+	var value T
+	if t.Unmarshal != nil {
+		if err = t.Unmarshal(params, &value); err != nil {
+			return Errorf(CodeInvalidParams, "could not decode %s params: %s", t.TaskName, err)
+		}
+	} else if err = DecodeParams(ctx, params, &value); err != nil {
+		return err
+	}
+	return t.Handler(ctx, id, value)
+}
+
+// Success implements the Task interface, calling OnSuccess if it is specified.
+func (t *Typed[T]) Success(id uuid.UUID, params []byte) {
+	if t.OnSuccess != nil {
+		t.OnSuccess(id, params)
+	}
+}
+
+// Failure implements the Task interface, calling OnFailure if it is specified.
+func (t *Typed[T]) Failure(id uuid.UUID, err error, params []byte) {
+	if t.OnFailure != nil {
+		t.OnFailure(id, err, params)
+	}
+}
+
+// DelayTyped encodes the params with the codec of the task and delays a future of the
+// task with the options on the context as with DelayValue.
+func DelayTyped[T any](ctx context.Context, r *Radish, task string, params T, success, failure []byte) (id uuid.UUID, err error) {
+	return r.DelayValue(ctx, task, params, success, failure)
+}
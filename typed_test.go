@@ -0,0 +1,107 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type email struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+}
+
+func TestTyped(t *testing.T) {
+	queue, err := New(&Config{Workers: 1, NoSignals: true})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var sent []email
+	var errs []error
+	wg := new(sync.WaitGroup)
+
+	task := &Typed[email]{
+		TaskName: "sendEmail",
+		Handler: func(ctx context.Context, id uuid.UUID, msg email) error {
+			if msg.To == "" {
+				return errors.New("no recipient")
+			}
+			mu.Lock()
+			sent = append(sent, msg)
+			mu.Unlock()
+			return nil
+		},
+		OnSuccess: func(id uuid.UUID, params []byte) { wg.Done() },
+		OnFailure: func(id uuid.UUID, err error, params []byte) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			wg.Done()
+		},
+	}
+	require.NoError(t, queue.Register(task))
+
+	wg.Add(3)
+	_, err = DelayTyped(context.Background(), queue, "sendEmail", email{To: "jdoe@example.com", Subject: "hello"}, nil, nil)
+	require.NoError(t, err)
+	_, err = DelayTyped(context.Background(), queue, "sendEmail", email{Subject: "nobody"}, nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Delay("sendEmail", []byte("not json"), nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	require.Equal(t, []email{{To: "jdoe@example.com", Subject: "hello"}}, sent)
+	require.Len(t, errs, 2)
+	require.EqualError(t, errs[0], "no recipient")
+	require.EqualError(t, errs[1], "[9] could not decode sendEmail params as json: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestTypedCodec(t *testing.T) {
+	queue, err := New(&Config{Workers: 1, NoSignals: true, SuppressMetrics: true, LogLevel: "silent"})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	var mu sync.Mutex
+	var sent []notice
+	var errs []error
+	wg := new(sync.WaitGroup)
+
+	task := &Typed[notice]{
+		TaskName: "sendNotice",
+		Handler: func(ctx context.Context, id uuid.UUID, msg notice) error {
+			mu.Lock()
+			sent = append(sent, msg)
+			mu.Unlock()
+			return nil
+		},
+		OnSuccess: func(id uuid.UUID, params []byte) { wg.Done() },
+		OnFailure: func(id uuid.UUID, err error, params []byte) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			wg.Done()
+		},
+	}
+	require.NoError(t, queue.Register(task))
+	require.NoError(t, queue.RegisterCodec("sendNotice", GobCodec))
+
+	// The typed params are encoded and decoded with the codec registered for the task,
+	// so params encoded as JSON cannot be decoded
+	wg.Add(2)
+	_, err = DelayTyped(context.Background(), queue, "sendNotice", notice{To: "jdoe@example.com", Subject: "hello"}, nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Delay("sendNotice", []byte(`{"To": "jdoe@example.com"}`), nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []notice{{To: "jdoe@example.com", Subject: "hello"}}, sent)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), "[9] could not decode sendNotice params as gob")
+}
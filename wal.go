@@ -0,0 +1,304 @@
+package radish
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// Default path of the write-ahead log used by the wal storage and how often it is compacted.
+const (
+	defaultWALPath         = "radish.wal"
+	defaultCompactInterval = 1 * time.Minute
+)
+
+// Operations recorded in the write-ahead log.
+const (
+	walPut     = "put"
+	walDeliver = "deliver"
+	walDelete  = "delete"
+)
+
+// walRecord is a line of the write-ahead log.
+type walRecord struct {
+	Op     string        `json:"op"`
+	ID     uuid.UUID     `json:"id"`
+	Future *storedFuture `json:"future,omitempty"`
+}
+
+// walStore persists futures by appending a record to a write-ahead log whenever a future
+// is stored, delivered, or deleted, which is lighter weight than a KV store since every
+// write is a sequential append. Records of futures that are stored are synced to disk
+// before the future is queued; deliveries and deletions are not synced since losing them
+// only causes a future to be redelivered. The futures that have not been deleted are kept
+// in memory so that the log can be compacted by rewriting only their records, which
+// happens periodically and when the store is closed. On open the log is replayed to
+// recover the futures, truncating a partially written record at the end of the log.
+type walStore struct {
+	sync.Mutex
+	path    string
+	file    *os.File
+	cipher  Cipher
+	live    map[string]*storedFuture // futures that have not been deleted with encrypted payloads
+	records int                      // the number of records in the log
+	warn    func(error)              // logs errors of periodic compactions
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func openWALStore(path string, cipher Cipher, interval time.Duration, warn func(error)) (s *walStore, err error) {
+	s = &walStore{path: path, cipher: cipher, live: make(map[string]*storedFuture), warn: warn, stop: make(chan struct{}), done: make(chan struct{})}
+	if s.file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600); err != nil {
+		return nil, Errorf(CodeStorage, "could not open write-ahead log at %s: %s", path, err)
+	}
+
+	if err = s.replay(); err != nil {
+		s.file.Close()
+		return nil, err
+	}
+
+	go s.compactor(interval)
+	return s, nil
+}
+
+// replay the records of the log to recover the futures that have not been deleted.
+func (s *walStore) replay() (err error) {
+	var offset int64
+	reader := bufio.NewReader(s.file)
+	for line := 1; ; line++ {
+		var data []byte
+		if data, err = reader.ReadBytes('\n'); err != nil && err != io.EOF {
+			return Errorf(CodeStorage, "could not read write-ahead log: %s", err)
+		}
+
+		// A record without a newline was only partially written when the process stopped
+		if err == io.EOF {
+			break
+		}
+
+		record := &walRecord{}
+		if err = json.Unmarshal(data, record); err != nil {
+			return Errorf(CodeStorage, "could not parse record %d of the write-ahead log: %s", line, err)
+		}
+
+		s.apply(record)
+		offset += int64(len(data))
+	}
+
+	// Discard any partial record so that new records are appended after a complete one
+	if err = s.file.Truncate(offset); err != nil {
+		return Errorf(CodeStorage, "could not truncate write-ahead log: %s", err)
+	}
+	if _, err = s.file.Seek(offset, io.SeekStart); err != nil {
+		return Errorf(CodeStorage, "could not seek write-ahead log: %s", err)
+	}
+	return nil
+}
+
+// apply a record to the futures that have not been deleted. Must hold the lock.
+func (s *walStore) apply(record *walRecord) {
+	s.records++
+	switch record.Op {
+	case walPut, walDeliver:
+		s.live[record.ID.String()] = record.Future
+	case walDelete:
+		delete(s.live, record.ID.String())
+	}
+}
+
+// append a record to the log, syncing it to disk if specified. Must hold the lock.
+func (s *walStore) append(record *walRecord, sync bool) (err error) {
+	var data []byte
+	if data, err = json.Marshal(record); err != nil {
+		return err
+	}
+
+	if _, err = s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	if sync {
+		if err = s.file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	s.apply(record)
+	return nil
+}
+
+// encrypt returns a copy of the future with encrypted payloads, since the future may be
+// modified while it is being handled after it is stored.
+func (s *walStore) encrypt(future *Future) (enc *Future, err error) {
+	if enc, err = future.encrypt(s.cipher); err != nil {
+		return nil, Errorf(CodeStorage, "could not encrypt future %s: %s", future.ID, err)
+	}
+
+	cp := *enc
+	return &cp, nil
+}
+
+func (s *walStore) put(future *Future, at time.Time) (err error) {
+	var enc *Future
+	if enc, err = s.encrypt(future); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	record := &walRecord{Op: walPut, ID: future.ID, Future: &storedFuture{Future: enc, Client: future.client, At: at, Stored: time.Now()}}
+	if err = s.append(record, true); err != nil {
+		return Errorf(CodeStorage, "could not persist future %s: %s", future.ID, err)
+	}
+	return nil
+}
+
+func (s *walStore) deliver(future *Future) (err error) {
+	var enc *Future
+	if enc, err = s.encrypt(future); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	// Keep the time the future was stored so that it is restored in the same order
+	stored := &storedFuture{Stored: time.Now()}
+	if prev, ok := s.live[future.ID.String()]; ok {
+		stored.At, stored.Stored = prev.At, prev.Stored
+	}
+	stored.Future, stored.Client, stored.Delivered = enc, future.client, true
+
+	if err = s.append(&walRecord{Op: walDeliver, ID: future.ID, Future: stored}, false); err != nil {
+		return Errorf(CodeStorage, "could not mark future %s as delivered: %s", future.ID, err)
+	}
+	return nil
+}
+
+func (s *walStore) delete(id uuid.UUID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.live[id.String()]; !ok {
+		return nil
+	}
+
+	if err = s.append(&walRecord{Op: walDelete, ID: id}, false); err != nil {
+		return Errorf(CodeStorage, "could not delete future %s: %s", id, err)
+	}
+	return nil
+}
+
+func (s *walStore) load() (futures []*storedFuture, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	futures = make([]*storedFuture, 0, len(s.live))
+	for id, stored := range s.live {
+		restored := *stored
+		if restored.Future, err = stored.Future.decrypt(s.cipher); err != nil {
+			return nil, Errorf(CodeStorage, "could not decrypt future %s: %s", id, err)
+		}
+
+		restored.Future.client = stored.Client
+		futures = append(futures, &restored)
+	}
+
+	sort.SliceStable(futures, func(i, j int) bool {
+		return futures[i].Stored.Before(futures[j].Stored)
+	})
+	return futures, nil
+}
+
+func (s *walStore) durable() bool {
+	return true
+}
+
+func (s *walStore) close() (err error) {
+	close(s.stop)
+	<-s.done
+
+	s.Lock()
+	defer s.Unlock()
+	if err = s.compact(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+// compactor compacts the log on the interval until the store is closed.
+func (s *walStore) compactor(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Lock()
+			if err := s.compact(); err != nil {
+				s.warn(err)
+			}
+			s.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// compact rewrites the log with only the records of the futures that have not been
+// deleted, replacing the log atomically once the compacted log has been synced to disk.
+// Must hold the lock.
+func (s *walStore) compact() (err error) {
+	if s.records == len(s.live) {
+		return nil
+	}
+
+	tmp := s.path + ".compact"
+	var file *os.File
+	if file, err = os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600); err != nil {
+		return Errorf(CodeStorage, "could not compact write-ahead log: %s", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, stored := range s.live {
+		if err = encoder.Encode(&walRecord{Op: walPut, ID: stored.Future.ID, Future: stored}); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		if err = writer.Flush(); err == nil {
+			err = file.Sync()
+		}
+	}
+
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+
+	if err != nil {
+		os.Remove(tmp)
+		return Errorf(CodeStorage, "could not compact write-ahead log: %s", err)
+	}
+
+	// Append new records to the compacted log
+	s.file.Close()
+	if s.file, err = os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0600); err != nil {
+		return Errorf(CodeStorage, "could not reopen write-ahead log: %s", err)
+	}
+	s.records = len(s.live)
+	return nil
+}
@@ -0,0 +1,96 @@
+package radish
+
+import (
+	"context"
+
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// WASMRuntime compiles WebAssembly modules so that task logic authored in other
+// languages can be run inside a radish worker. Radish does not bundle a WebAssembly
+// runtime; applications supply one in the config, usually a small adapter around a
+// runtime such as wazero or wasmtime that instantiates the module as a WASI command.
+type WASMRuntime interface {
+	Compile(task string, module []byte) (WASMModule, error)
+}
+
+// WASMModule is a compiled WebAssembly module that is run once per future. The params
+// of the future are written to the module's stdin and the module's stdout is captured
+// as the result of the task. The module's exit status determines whether the task
+// succeeded (0) or failed (any other status); on failure stderr is used as the error
+// message. Modules must be sandboxed by the runtime (e.g. no filesystem or network
+// access) and Run must be safe to call from multiple workers concurrently. The context
+// passed to Run is canceled if the future is canceled or its worker is forced to stop,
+// so runtimes should stop the module once it is done.
+type WASMModule interface {
+	Run(ctx context.Context, stdin []byte) (stdout, stderr []byte, status int, err error)
+	Close() error
+}
+
+// RegisterWASM compiles the WebAssembly module with the runtime in the config and
+// registers it as the handler for the named task. Because modules do not implement
+// success or failure callbacks, the outcome of each future is logged instead.
+func (r *Radish) RegisterWASM(task string, module []byte) (err error) {
+	if r.config.WASMRuntime == nil {
+		return Errorf(CodeInvalidConfig, "cannot register %s: no wasm runtime configured", task)
+	}
+
+	var mod WASMModule
+	if mod, err = r.config.WASMRuntime.Compile(task, module); err != nil {
+		return Errorf(CodeInvalidConfig, "could not compile wasm module for %s: %s", task, err)
+	}
+
+	if err = r.Register(&wasmTask{name: task, module: mod, parent: r}); err != nil {
+		mod.Close()
+		return err
+	}
+	return nil
+}
+
+// wasmTask adapts a WASMModule to the Task interface.
+type wasmTask struct {
+	name   string
+	module WASMModule
+	parent *Radish
+}
+
+func (t *wasmTask) Name() string {
+	return t.name
+}
+
+func (t *wasmTask) Handle(id uuid.UUID, params []byte) error {
+	return t.HandleContext(context.Background(), id, params)
+}
+
+func (t *wasmTask) HandleContext(ctx context.Context, id uuid.UUID, params []byte) (err error) {
+	_, err = t.HandleResult(ctx, id, params)
+	return err
+}
+
+// HandleResult runs the module with the context so that the runtime can stop it if the
+// future is canceled, returning the stdout of the module as the result of the task.
+func (t *wasmTask) HandleResult(ctx context.Context, id uuid.UUID, params []byte) (result []byte, err error) {
+	stdout, stderr, status, err := t.module.Run(ctx, params)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, Errorf(CodeUnknown, "could not run wasm module for %s: %s", t.name, err)
+	}
+
+	if status != 0 {
+		return nil, Errorf(CodeUnknown, "wasm module for %s exited with status %d: %s", t.name, status, stderr)
+	}
+
+	t.parent.logf(out.LevelDebug, t.name, "%s task %s returned %d bytes", t.name, id, len(stdout))
+	return stdout, nil
+}
+
+func (t *wasmTask) Success(id uuid.UUID, params []byte) {
+	t.parent.logf(out.LevelDebug, t.name, "wasm task %s succeeded", id)
+}
+
+func (t *wasmTask) Failure(id uuid.UUID, err error, params []byte) {
+	t.parent.logf(out.LevelInfo, t.name, "wasm task %s failed: %s", id, err)
+}
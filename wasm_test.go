@@ -0,0 +1,106 @@
+package radish_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterWASM(t *testing.T) {
+	queue, err := New(&Config{Workers: 1, NoSignals: true})
+	require.NoError(t, err)
+	require.EqualError(t, queue.RegisterWASM("echo", []byte("\x00asm")), "[1] cannot register echo: no wasm runtime configured")
+
+	runtime := &mockRuntime{}
+	queue, err = New(&Config{Workers: 1, NoSignals: true, WASMRuntime: runtime})
+	require.NoError(t, err)
+
+	require.EqualError(t, queue.RegisterWASM("bad", []byte("not wasm")), "[1] could not compile wasm module for bad: invalid module")
+	require.NoError(t, queue.RegisterWASM("echo", []byte("\x00asm")))
+	require.EqualError(t, queue.RegisterWASM("echo", []byte("\x00asm")), `[2] task named "echo" has already been registered`)
+
+	_, err = queue.Delay("echo", []byte("hello"), nil, nil)
+	require.NoError(t, err)
+	_, err = queue.Delay("echo", []byte("fail"), nil, nil)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(runtime.inputs()) == 2 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, [][]byte{[]byte("hello"), []byte("fail")}, runtime.inputs())
+}
+
+func TestWASMResult(t *testing.T) {
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "silent", WASMRuntime: &mockRuntime{}})
+	require.NoError(t, err)
+	defer queue.Shutdown()
+	require.NoError(t, queue.RegisterWASM("echo", []byte("\x00asm")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// The stdout of the module is the result of the task
+	id, err := queue.Delay("echo", []byte("hello"), nil, nil)
+	require.NoError(t, err)
+	result, err := queue.Wait(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), result)
+
+	// Canceling the future stops the running module
+	started := make(chan struct{}, 1)
+	queue.OnEvent(func(e Event) {
+		if e.Type == EventStart && string(e.Future.Params) == "block" {
+			started <- struct{}{}
+		}
+	})
+
+	id, err = queue.Delay("echo", []byte("block"), nil, nil)
+	require.NoError(t, err)
+	<-started
+	require.NoError(t, queue.Cancel(id))
+	_, err = queue.Wait(ctx, id)
+	require.True(t, errors.Is(err, ErrCanceled), "expected the module to be canceled, got %v", err)
+}
+
+// mockRuntime "compiles" modules that echo their input and exit with status 1 if the
+// input is "fail" or run until they are stopped if the input is "block".
+type mockRuntime struct {
+	sync.Mutex
+	stdin [][]byte
+}
+
+func (r *mockRuntime) Compile(task string, module []byte) (WASMModule, error) {
+	if !bytes.HasPrefix(module, []byte("\x00asm")) {
+		return nil, errors.New("invalid module")
+	}
+	return r, nil
+}
+
+func (r *mockRuntime) Run(ctx context.Context, stdin []byte) (stdout, stderr []byte, status int, err error) {
+	r.Lock()
+	r.stdin = append(r.stdin, stdin)
+	r.Unlock()
+
+	switch string(stdin) {
+	case "fail":
+		return nil, []byte("failed"), 1, nil
+	case "block":
+		<-ctx.Done()
+		return nil, nil, 0, ctx.Err()
+	}
+	return stdin, nil, 0, nil
+}
+
+func (r *mockRuntime) Close() error {
+	return nil
+}
+
+func (r *mockRuntime) inputs() [][]byte {
+	r.Lock()
+	defer r.Unlock()
+	return r.stdin
+}
@@ -0,0 +1,118 @@
+package radish
+
+import (
+	"sync"
+
+	"github.com/kansaslabs/radish/api"
+)
+
+// The number of events buffered for each subscriber of the Watch RPC.
+const eventSubscriberBuf = 256
+
+// eventHub broadcasts the lifecycle events of futures to the subscribers of the Watch
+// RPC. Like the log hub, events are dropped for subscribers that cannot keep up so that
+// watching the queue never blocks the workers.
+type eventHub struct {
+	sync.RWMutex
+	subs map[chan *api.FutureEvent]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[chan *api.FutureEvent]struct{})}
+}
+
+// subscribe to the lifecycle events of all futures.
+func (h *eventHub) subscribe() chan *api.FutureEvent {
+	c := make(chan *api.FutureEvent, eventSubscriberBuf)
+	h.Lock()
+	h.subs[c] = struct{}{}
+	h.Unlock()
+	return c
+}
+
+func (h *eventHub) unsubscribe(c chan *api.FutureEvent) {
+	h.Lock()
+	delete(h.subs, c)
+	h.Unlock()
+}
+
+// publish the event to all subscribers. It is safe to publish to a nil hub.
+func (h *eventHub) publish(event *api.FutureEvent) {
+	if h == nil || event == nil {
+		return
+	}
+
+	h.RLock()
+	defer h.RUnlock()
+	for c := range h.subs {
+		select {
+		case c <- event:
+		default:
+		}
+	}
+}
+
+// event returns the lifecycle event of the record of the future for its current state.
+// The duration is the time spent queued for running events and the time spent running
+// for completed events. Must hold the records lock.
+func (r *record) event(id []byte) *api.FutureEvent {
+	info := r.info(id)
+	event := &api.FutureEvent{
+		Uuid:     info.Uuid,
+		Task:     info.Task,
+		State:    info.State,
+		Attempts: info.Attempts,
+		Error:    info.Error,
+	}
+
+	switch {
+	case r.finished():
+		event.Timestamp = unixNano(r.completed)
+		if !r.started.IsZero() {
+			event.Duration = int64(r.completed.Sub(r.started))
+		}
+	case r.state == api.FutureState_RUNNING:
+		event.Timestamp = unixNano(r.started)
+		event.Duration = int64(r.started.Sub(r.queued))
+	default:
+		event.Timestamp = unixNano(r.queued)
+	}
+	return event
+}
+
+// Watch implements the RadishServer interface, streaming the lifecycle events of futures
+// as they are queued or scheduled, started, and completed until the request is canceled
+// or the queue is shutdown. Events can be filtered by task and by state.
+func (r *Radish) Watch(in *api.WatchRequest, stream api.Radish_WatchServer) (err error) {
+	tasks := make(map[string]struct{}, len(in.Tasks))
+	for _, task := range in.Tasks {
+		tasks[task] = struct{}{}
+	}
+
+	states := make(map[api.FutureState]struct{}, len(in.States))
+	for _, state := range in.States {
+		states[state] = struct{}{}
+	}
+
+	events := r.events.subscribe()
+	defer r.events.unsubscribe(events)
+
+	for {
+		select {
+		case event := <-events:
+			if _, ok := tasks[event.Task]; len(tasks) > 0 && !ok {
+				continue
+			}
+			if _, ok := states[event.State]; len(states) > 0 && !ok {
+				continue
+			}
+			if err = stream.Send(event); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		case <-r.shutdown:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestWatch(t *testing.T) {
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+
+	good := &testTask{wg: wg, name: "good"}
+	bad := &testTask{wg: wg, name: "bad", onHandle: func(id uuid.UUID, params []byte) error { return errors.New("whoops!") }}
+
+	queue, err := New(&Config{Workers: 1, NoSignals: true, LogLevel: "warn"}, good, bad)
+	require.NoError(t, err)
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	api.RegisterRadishServer(srv, queue)
+	go srv.Serve(sock)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(sock.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := api.NewRadishClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Watch every lifecycle event of the bad task
+	stream, err := client.Watch(ctx, &api.WatchRequest{Tasks: []string{"bad"}})
+	require.NoError(t, err)
+
+	// Only watch the completion of futures of any task
+	done, err := client.Watch(ctx, &api.WatchRequest{States: []api.FutureState{api.FutureState_SUCCEEDED, api.FutureState_FAILED}})
+	require.NoError(t, err)
+
+	// Ensure the subscriptions have been made before the tasks are queued
+	time.Sleep(50 * time.Millisecond)
+	_, err = queue.Delay(good.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	id, err := queue.Delay(bad.Name(), nil, nil, nil)
+	require.NoError(t, err)
+	wg.Wait()
+
+	expected := []api.FutureState{api.FutureState_QUEUED, api.FutureState_RUNNING, api.FutureState_FAILED}
+	for _, state := range expected {
+		event, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, "bad", event.Task)
+		require.True(t, uuid.Equal(id, uuid.UUID(event.Uuid)))
+		require.Equal(t, state, event.State)
+		require.NotZero(t, event.Timestamp)
+	}
+
+	for _, task := range []string{"good", "bad"} {
+		event, err := done.Recv()
+		require.NoError(t, err)
+		require.Equal(t, task, event.Task)
+		require.Equal(t, int32(1), event.Attempts)
+		require.True(t, event.Duration > 0)
+
+		if task == "bad" {
+			require.Equal(t, api.FutureState_FAILED, event.State)
+			require.Equal(t, "whoops!", event.Error.Message)
+		} else {
+			require.Equal(t, api.FutureState_SUCCEEDED, event.State)
+			require.Nil(t, event.Error)
+		}
+	}
+
+	// The streams are closed when the queue is shutdown
+	require.NoError(t, queue.Shutdown())
+	_, err = stream.Recv()
+	require.Error(t, err)
+}
@@ -1,62 +1,363 @@
 package radish
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/kansaslabs/radish/api"
 	"github.com/kansaslabs/x/out"
 )
 
+// How long a worker waits before retrying after the broker fails to dequeue a future.
+const dequeueBackoff = 100 * time.Millisecond
+
 type worker struct {
-	parent *Radish   // the parent of the worker that has the tasks queue and the handlers
-	stop   chan bool // gracefully stop the worker, do not process any more tasks
+	sync.Mutex
+	id      uint64             // the id of the worker, unique for the lifetime of the queue
+	parent  *Radish            // the parent of the worker that has the tasks queue and the handlers
+	ctx     context.Context    // canceled when the worker is stopped to interrupt a dequeue
+	stop    context.CancelFunc // gracefully stop the worker, do not process any more tasks
+	done    chan struct{}      // closed when the worker has stopped
+	current *Future            // the future the worker is handling, if any
+	started time.Time          // when the worker started handling the current future
+	created time.Time          // when the worker was added
+	handled uint64             // the number of futures the worker has handled
+	busy    time.Duration      // the total time the worker has spent handling futures
+}
+
+func newWorker(parent *Radish, id uint64) *worker {
+	w := &worker{id: id, parent: parent, done: make(chan struct{}), created: time.Now()}
+	w.ctx, w.stop = context.WithCancel(context.Background())
+	return w
 }
 
 func (w *worker) run() {
-taskloop:
+	defer close(w.done)
 	for {
-		select {
-		case <-w.stop:
+		// Check if the worker has been stopped since Dequeue may return available futures
+		if w.ctx.Err() != nil {
 			return
-		case task := <-w.parent.tasks:
+		}
 
-			// Update the queue size and percent full
-			pmQueueSize.Set(float64(len(w.parent.tasks)))
-			pmPercentFull.Set(float64(len(w.parent.tasks)) / float64(w.parent.config.QueueSize) * 100)
+		task, err := w.dequeue()
+		if err != nil {
+			if w.ctx.Err() == nil && err != errWorkerPaused {
+				w.parent.logf(out.LevelWarn, "", "could not dequeue future: %s", err)
+				time.Sleep(dequeueBackoff)
+			}
+			continue
+		}
 
-			start := time.Now()
+		atomic.StoreInt64(&w.parent.dequeued, time.Now().UnixNano())
+		w.parent.releaseQuotas(task)
 
-			handler, err := w.parent.Handler(task.Task)
-			if err != nil {
-				// Unregistered task
-				out.Warn("cannot handle unregistered task %q -- not processing %s", task.Task, task.ID)
-				continue taskloop
-			}
+		// Park futures that are dequeued before the futures queued before them with the
+		// same partition key are done, they are requeued once it is their turn
+		if !w.parent.partitions.acquire(task) {
+			w.parent.ack(task)
+			continue
+		}
+		w.parent.hooks.emit(Event{Type: EventDequeue, Future: task})
+
+		// Update the queue size and percent full
+		pmQueueSize.Set(float64(w.parent.tasks.Len()))
+		pmPercentFull.Set(float64(w.parent.tasks.Len()) / float64(w.parent.config.QueueSize) * 100)
 
-			// Handle the task
-			if err := handler.Handle(task.ID, task.Params); err != nil {
-				// Task failure
-				out.Caution(err.Error())
-				handler.Failure(task.ID, err, task.Failure)
-
-				// Compute latency in milliseconds
-				latency := float64(time.Since(start)/1000) / 1000.0
-				pmTaskLatency.WithLabelValues(task.Task, "failed").Observe(latency)
-
-				// Update prometheus metrics with failed task
-				pmTasksFailed.WithLabelValues(task.Task).Inc()
-			} else {
-				// Task success
-				out.Debug("finished %s task %s", task.Task, task.ID)
-				handler.Success(task.ID, task.Success)
-
-				// Compute latency in milliseconds
-				latency := float64(time.Since(start)/1000) / 1000.0
-				pmTaskLatency.WithLabelValues(task.Task, "succeeded").Observe(latency)
-
-				// Update prometheus metrics with succeeded task
-				pmTasksSucceeded.WithLabelValues(task.Task).Inc()
+		// Coalesce the futures of batch tasks, the worker that leads a batch handles it.
+		// Futures waiting in the batcher are in flight and the leader is busy while the
+		// batch fills up.
+		if b := w.parent.batcher(task.Task); b != nil {
+			atomic.AddInt64(&w.parent.batched, 1)
+			done := w.parent.working(task.Task, 1)
+			batch := b.add(w.ctx, task)
+			done()
+
+			if len(batch) > 0 {
+				atomic.AddInt64(&w.parent.batched, -int64(len(batch)))
+				w.handleBatch(batch)
+			}
+			for _, future := range batch {
+				w.parent.ack(future)
 			}
+			continue
+		}
+
+		w.handle(task)
+		w.parent.ack(task)
+	}
+}
+
+// handle a dequeued future, calling the success or failure callback of its handler.
+func (w *worker) handle(task *Future) {
+	start := time.Now()
+	defer w.parent.working(task.Task, 1)()
+	w.setCurrent(task)
+	defer w.setCurrent(nil)
+	ctx, ok := w.parent.inflight.start(task.ID)
+
+	handler, err := w.parent.Handler(task.Task)
+	if err != nil {
+		// Unregistered task
+		w.parent.inflight.finish(task.ID)
+		w.parent.releasePartition(task)
+		w.parent.logf(out.LevelWarn, task.Task, "cannot handle unregistered task %q -- not processing %s", task.Task, task.ID)
+		w.parent.deadLetters.add(task, err)
+		w.parent.results.complete(task, err)
+		w.parent.unpersist(task)
+		w.parent.hooks.emit(Event{Type: EventFailure, Future: task, Err: err})
+		w.parent.auditOutcome(task, err)
+		w.parent.linkedCompleted(task, err)
+		return
+	}
+
+	if !ok {
+		// Future was canceled while it was queued
+		task.canceled = true
+		w.parent.complete(task, handler, Errorf(CodeCanceled, "%s future %s was canceled", task.Task, task.ID), start)
+		return
+	}
+
+	// Wait for a slot of the task and the shared resources it consumes to become available
+	releaseSlot := w.parent.acquireSlot(task.Task)
+	defer releaseSlot()
+
+	release := w.parent.acquireResources(handler)
+	defer release()
+
+	// Handle the task, passing a cancelable context to tasks that support cancellation
+	task.Attempts++
+	w.parent.delivered(task)
+	wait := w.parent.results.start(task)
+	pmQueueWait.WithLabelValues(task.Task).Observe(wait.Seconds())
+	w.parent.hooks.emit(Event{Type: EventStart, Future: task})
+	ctx = w.parent.withCodec(withFutureMetadata(ctx, task.Metadata), task.Task)
+	err = w.call(w.parent.withFutureLogger(ctx, task), handler, task)
+
+	w.finish(task, handler, err, start)
+}
+
+// finish a future once its handler has returned, retrying the future if it failed and
+// has attempts remaining, otherwise completing it.
+func (w *worker) finish(task *Future, handler Task, err error, start time.Time) {
+	// Failures of futures canceled while they were being handled are cancellations
+	if w.parent.inflight.finish(task.ID) && err != nil {
+		task.canceled = true
+		err = Errorf(CodeCanceled, "%s future %s was canceled: %s", task.Task, task.ID, err)
+	}
+
+	// Retry failed futures that have attempts remaining before calling Failure
+	if err != nil && !task.canceled && w.parent.retry(task, handler, err, start) {
+		return
+	}
+
+	// Release the partition of the future even if its completion is deferred so that
+	// children of the future with the same partition key can be handled
+	w.parent.releasePartition(task)
+
+	// Complete the task, deferring its completion if it is waiting for its children
+	if deferred, err := w.parent.families.handled(task, handler, err, start); !deferred {
+		w.parent.complete(task, handler, err, start)
+	}
+}
+
+// setCurrent records the future the worker is handling, or that the worker has finished
+// handling its future if the task is nil.
+func (w *worker) setCurrent(task *Future) {
+	w.Lock()
+	defer w.Unlock()
+
+	if task != nil {
+		w.current, w.started = task, time.Now()
+		return
+	}
+
+	if w.current != nil {
+		w.busy += time.Since(w.started)
+		w.handled++
+	}
+	w.current, w.started = nil, time.Time{}
+}
+
+// info returns the state of the worker and whether it is included, idle workers are
+// included as the empty task.
+func (w *worker) info(include func(task string) bool) (*api.WorkerInfo, bool) {
+	w.Lock()
+	defer w.Unlock()
+
+	info := &api.WorkerInfo{Id: w.id, Handled: w.handled, Busy: int64(w.busy), Created: w.created.UnixNano()}
+	if w.current == nil {
+		return info, include("")
+	}
+
+	info.Task, info.Future, info.Started = w.current.Task, w.current.ID, w.started.UnixNano()
+	info.Busy += int64(time.Since(w.started))
+	return info, include(w.current.Task)
+}
 
+// cancel the future the worker is handling, if any, returning false if the worker is
+// not handling a future.
+func (w *worker) cancel() bool {
+	w.Lock()
+	defer w.Unlock()
+	if w.current == nil {
+		return false
+	}
+	return w.parent.inflight.cancel(w.current.ID)
+}
+
+// working marks a worker as busy handling the number of futures of the task, e.g. a
+// batch, updating the worker state metrics, until the returned function is called once
+// the worker is idle again.
+func (r *Radish) working(task string, futures int) func() {
+	atomic.AddInt64(&r.busy, 1)
+	inFlight := pmTasksInFlight.WithLabelValues(task)
+	inFlight.Add(float64(futures))
+	r.NumWorkers() // refresh the worker gauges
+
+	return func() {
+		atomic.AddInt64(&r.busy, -1)
+		inFlight.Sub(float64(futures))
+		r.NumWorkers() // refresh the worker gauges
+	}
+}
+
+// observeWorkers sets the gauges of the total, busy, and idle workers. Workers that were
+// removed while handling a future are still busy until they stop, so idle is never
+// negative.
+func (r *Radish) observeWorkers(workers int) {
+	busy := int(atomic.LoadInt64(&r.busy))
+	idle := workers - busy
+	if idle < 0 {
+		idle = 0
+	}
+
+	pmWorkers.Set(float64(workers))
+	pmWorkersBusy.Set(float64(busy))
+	pmWorkersIdle.Set(float64(idle))
+}
+
+// call the handler of the task wrapped by any middleware, recovering from any panic in
+// the middleware or the handler as a failure.
+func (w *worker) call(ctx context.Context, handler Task, task *Future) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = Errorf(CodeUnknown, "%s task panicked: %v", task.Task, r)
 		}
+	}()
+
+	return w.parent.chain(func(ctx context.Context, task *Future) (err error) {
+		if remote, ok := handler.(*remoteTask); ok {
+			task.result, err = remote.handle(ctx, task)
+			return err
+		}
+
+		if resTask, ok := handler.(ResultTask); ok {
+			task.result, err = resTask.HandleResult(ctx, task.ID, task.Params)
+			return err
+		}
+
+		if ctxTask, ok := handler.(ContextTask); ok {
+			return ctxTask.HandleContext(ctx, task.ID, task.Params)
+		}
+		return handler.Handle(task.ID, task.Params)
+	})(ctx, task)
+}
+
+// complete a future that has been handled by calling the success or failure callback of
+// its handler, recording metrics and the outcome of the future, and notifying the parent
+// of the future if it was spawned by another task.
+func (r *Radish) complete(task *Future, handler Task, err error, start time.Time) {
+	switch {
+	case task.canceled:
+		// Task cancellation
+		r.logf(out.LevelInfo, task.Task, "%s", err)
+		callFailure(handler, task, err)
+
+		// Compute latency in milliseconds
+		latency := float64(time.Since(start)/1000) / 1000.0
+		pmTaskLatency.WithLabelValues(task.Task, "canceled").Observe(latency)
+
+		// Update prometheus metrics with canceled task
+		pmTasksCanceled.WithLabelValues(task.Task).Inc()
+	case err != nil:
+		// Task failure
+		r.logf(out.LevelCaution, task.Task, "%s", err)
+		callFailure(handler, task, err)
+
+		// Compute latency in milliseconds
+		latency := float64(time.Since(start)/1000) / 1000.0
+		pmTaskLatency.WithLabelValues(task.Task, "failed").Observe(latency)
+
+		// Update prometheus metrics with failed task
+		pmTasksFailed.WithLabelValues(task.Task).Inc()
+	default:
+		// Task success
+		r.logf(out.LevelDebug, task.Task, "finished %s task %s", task.Task, task.ID)
+		callSuccess(handler, task)
+
+		// Compute latency in milliseconds
+		latency := float64(time.Since(start)/1000) / 1000.0
+		pmTaskLatency.WithLabelValues(task.Task, "succeeded").Observe(latency)
+
+		// Update prometheus metrics with succeeded task
+		pmTasksSucceeded.WithLabelValues(task.Task).Inc()
+	}
+
+	r.releasePartition(task)
+	if n := r.results.complete(task, err); n > 0 {
+		pmRecordsEvicted.Add(float64(n))
+	}
+	if err != nil && !task.canceled {
+		r.deadLetters.add(task, err)
+	}
+	r.unpersist(task)
+	r.stats.record(task.Task, err, task.canceled)
+	if err != nil {
+		r.hooks.emit(Event{Type: EventFailure, Future: task, Err: err})
+	} else {
+		r.hooks.emit(Event{Type: EventSuccess, Future: task})
+	}
+	r.auditOutcome(task, err)
+
+	if task.Callback != "" {
+		go r.notifyCallback(task, err)
+	}
+
+	if task.Parent != nil {
+		r.families.childCompleted(task, err)
+	}
+	r.linkedCompleted(task, err)
+}
+
+// linkedCompleted notifies the workflow or group that the future belongs to, if any,
+// that the future has completed.
+func (r *Radish) linkedCompleted(task *Future, err error) {
+	if task.Workflow != nil {
+		r.workflows.completed(task, err)
+	}
+	if task.Group != nil {
+		r.groups.completed(task, err)
+	}
+}
+
+// callSuccess calls the success callback of the handler with the metadata of the future on
+// the context if the handler implements CallbackContextTask.
+func callSuccess(handler Task, task *Future) {
+	if cbTask, ok := handler.(CallbackContextTask); ok {
+		cbTask.SuccessContext(withFutureMetadata(context.Background(), task.Metadata), task.ID, task.Success)
+		return
+	}
+	handler.Success(task.ID, task.Success)
+}
+
+// callFailure calls the failure callback of the handler with the metadata of the future on
+// the context if the handler implements CallbackContextTask.
+func callFailure(handler Task, task *Future, err error) {
+	if cbTask, ok := handler.(CallbackContextTask); ok {
+		cbTask.FailureContext(withFutureMetadata(context.Background(), task.Metadata), task.ID, err, task.Failure)
+		return
 	}
+	handler.Failure(task.ID, err, task.Failure)
 }
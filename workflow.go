@@ -0,0 +1,406 @@
+package radish
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kansaslabs/radish/api"
+	"github.com/kansaslabs/x/out"
+	"github.com/pborman/uuid"
+)
+
+// WorkflowStep is a future of a workflow that is queued once all of the steps it depends
+// on have succeeded.
+type WorkflowStep struct {
+	Name    string   // the unique name of the step in the workflow
+	Task    string   // the name of the task to handle the step
+	Params  []byte   // the serialized parameters of the future
+	Success []byte   // the serialized parameters to pass to the success function
+	Failure []byte   // the serialized parameters to pass to the failure function on error
+	After   []string // the names of the steps that must succeed before the step is queued
+}
+
+// Workflow is a directed acyclic graph of steps, e.g. extract then transform then load,
+// that is run as one unit by RunWorkflow. Steps without prerequisites are queued when the
+// workflow is run and every other step is queued once all of the steps it depends on
+// have succeeded. If a step fails, the steps that depend on it are canceled.
+type Workflow struct {
+	steps []WorkflowStep
+}
+
+// NewWorkflow returns an empty workflow to add steps to.
+func NewWorkflow() *Workflow {
+	return &Workflow{}
+}
+
+// Step adds a step that is handled by the task with the params once the steps named in
+// after have succeeded, returning the workflow so that steps can be chained.
+func (w *Workflow) Step(name, task string, params []byte, after ...string) *Workflow {
+	return w.AddStep(WorkflowStep{Name: name, Task: task, Params: params, After: after})
+}
+
+// AddStep adds a step to the workflow, returning the workflow so that steps can be chained.
+func (w *Workflow) AddStep(step WorkflowStep) *Workflow {
+	w.steps = append(w.steps, step)
+	return w
+}
+
+// validate that the workflow has steps with unique names whose prerequisites exist and
+// do not form a cycle.
+func (w *Workflow) validate() error {
+	if len(w.steps) == 0 {
+		return Errorf(CodeInvalidWorkflow, "workflow has no steps")
+	}
+
+	waiting := make(map[string]int, len(w.steps))
+	dependents := make(map[string][]string, len(w.steps))
+	for _, step := range w.steps {
+		if step.Name == "" {
+			return Errorf(CodeInvalidWorkflow, "workflow steps must have a name")
+		}
+		if _, ok := waiting[step.Name]; ok {
+			return Errorf(CodeInvalidWorkflow, "workflow has more than one step named %q", step.Name)
+		}
+		waiting[step.Name] = len(step.After)
+	}
+
+	for _, step := range w.steps {
+		for _, after := range step.After {
+			if _, ok := waiting[after]; !ok {
+				return Errorf(CodeInvalidWorkflow, "step %q depends on unknown step %q", step.Name, after)
+			}
+			dependents[after] = append(dependents[after], step.Name)
+		}
+	}
+
+	// Remove steps without prerequisites until none remain, any left over are in a cycle
+	ready := make([]string, 0, len(w.steps))
+	for name, n := range waiting {
+		if n == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	visited := 0
+	for len(ready) > 0 {
+		name := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		visited++
+
+		for _, dependent := range dependents[name] {
+			if waiting[dependent]--; waiting[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if visited < len(w.steps) {
+		return Errorf(CodeInvalidWorkflow, "workflow steps have a cyclic dependency")
+	}
+	return nil
+}
+
+// workflowRef identifies the workflow step that a future handles.
+type workflowRef struct {
+	id   uuid.UUID
+	step string
+}
+
+// workflowFrom returns the workflow step on the context, if any.
+func workflowFrom(ctx context.Context) workflowRef {
+	ref, _ := ctx.Value(workflowKey).(workflowRef)
+	return ref
+}
+
+// RunWorkflow validates the workflow and queues the steps that have no prerequisites,
+// returning the id of the workflow, which can be used to query its state with
+// GetWorkflow. The options on the context, e.g. metadata and priority, are applied to
+// every step of the workflow. If a step cannot be queued the error is returned and the
+// workflow fails, though steps that were already queued are still handled. The state
+// of the workflow is kept until the ResultTTL after its last step has completed.
+func (r *Radish) RunWorkflow(ctx context.Context, workflow *Workflow) (id uuid.UUID, err error) {
+	if err = workflow.validate(); err != nil {
+		return nil, err
+	}
+
+	for _, step := range workflow.steps {
+		if _, err = r.Handler(step.Task); err != nil {
+			return nil, Errorf(CodeTaskNotRegistered, "could not run workflow step %q: %s", step.Name, err)
+		}
+	}
+
+	if err = r.accepting(); err != nil {
+		return nil, err
+	}
+
+	run := r.workflows.add(ctx, r.config.IDs.NewID(), workflow)
+	ready := make([]string, 0)
+	for _, step := range workflow.steps {
+		if len(step.After) == 0 {
+			ready = append(ready, step.Name)
+		}
+	}
+
+	if err = r.queueSteps(run, ready); err != nil {
+		return nil, err
+	}
+
+	r.logf(out.LevelDebug, "", "running workflow %s with %d steps", run.id, len(workflow.steps))
+	return run.id, nil
+}
+
+// queueSteps delays the futures of the steps of the workflow that are ready to be
+// queued, returning the first error if any step could not be queued, in which case the
+// step fails and the steps that depend on it are canceled.
+func (r *Radish) queueSteps(run *workflowRun, ready []string) (err error) {
+	for _, name := range ready {
+		step := run.steps[name]
+		ctx := context.WithValue(context.Background(), workflowKey, workflowRef{id: run.id, step: name})
+		ctx = context.WithValue(ctx, metadataKey, run.metadata)
+		ctx = WithPriority(withClient(ctx, run.client), run.priority)
+
+		id, serr := r.DelayContext(ctx, step.Task, step.Params, step.Success, step.Failure)
+		r.workflows.queued(run, name, id, serr)
+		if serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+// workflowRun is the state of a workflow that has been run.
+type workflowRun struct {
+	id       uuid.UUID
+	order    []string                 // the names of the steps in the order they were added
+	steps    map[string]*workflowNode // the steps of the workflow by name
+	state    api.FutureState          // running until every step has completed
+	created  time.Time                // when the workflow was run
+	finished time.Time                // when the last step completed
+	expires  time.Time                // when the workflow is evicted by the sweeper
+	metadata map[string]string        // metadata copied onto the future of every step
+	priority int32                    // the priority of the future of every step
+	client   string                   // the API client that ran the workflow, if any
+}
+
+// workflowNode is the state of a step of a workflow.
+type workflowNode struct {
+	WorkflowStep
+	waiting    int             // the number of prerequisites that have not succeeded
+	dependents []string        // the steps that depend on this step
+	future     uuid.UUID       // the id of the future of the step once it has been queued
+	state      api.FutureState // pending until the step is queued, then the final state of its future
+	err        error           // the error if the step failed or was canceled
+}
+
+// finished returns true if the future of the step has completed or it was canceled.
+func (n *workflowNode) finished() bool {
+	switch n.state {
+	case api.FutureState_SUCCEEDED, api.FutureState_FAILED, api.FutureState_CANCELED:
+		return true
+	default:
+		return false
+	}
+}
+
+// workflows tracks the state of workflows that are running or completed recently.
+type workflows struct {
+	sync.RWMutex
+	parent *Radish
+	ttl    time.Duration
+	runs   map[string]*workflowRun
+}
+
+func newWorkflows(r *Radish, ttl time.Duration) *workflows {
+	return &workflows{parent: r, ttl: ttl, runs: make(map[string]*workflowRun)}
+}
+
+// add a workflow that is being run with the options on the context.
+func (w *workflows) add(ctx context.Context, id uuid.UUID, workflow *Workflow) *workflowRun {
+	run := &workflowRun{
+		id:       id,
+		order:    make([]string, 0, len(workflow.steps)),
+		steps:    make(map[string]*workflowNode, len(workflow.steps)),
+		state:    api.FutureState_RUNNING,
+		created:  time.Now(),
+		metadata: MetadataFrom(ctx),
+		priority: PriorityFrom(ctx),
+		client:   clientFrom(ctx),
+	}
+
+	for _, step := range workflow.steps {
+		node := &workflowNode{WorkflowStep: step, waiting: len(step.After), state: api.FutureState_PENDING}
+		if node.waiting == 0 {
+			node.state = api.FutureState_QUEUED
+		}
+
+		run.order = append(run.order, step.Name)
+		run.steps[step.Name] = node
+	}
+
+	for _, step := range workflow.steps {
+		for _, after := range step.After {
+			run.steps[after].dependents = append(run.steps[after].dependents, step.Name)
+		}
+	}
+
+	w.Lock()
+	w.runs[id.String()] = run
+	w.Unlock()
+	return run
+}
+
+// queued records the future of a step once it has been delayed, failing the step if it
+// could not be queued.
+func (w *workflows) queued(run *workflowRun, name string, id uuid.UUID, err error) {
+	w.Lock()
+	defer w.Unlock()
+
+	node := run.steps[name]
+	if err != nil {
+		w.fail(run, node, api.FutureState_FAILED, err)
+		w.finish(run)
+		return
+	}
+	node.future = id
+}
+
+// completed is called when the future of a workflow step completes, queueing the steps
+// that depend on it once all of their prerequisites have succeeded or canceling them if
+// the step failed.
+func (w *workflows) completed(future *Future, err error) {
+	w.Lock()
+	run, ok := w.runs[future.Workflow.String()]
+	if !ok {
+		w.Unlock()
+		return
+	}
+
+	node, ok := run.steps[future.Step]
+	if !ok || node.finished() {
+		w.Unlock()
+		return
+	}
+
+	ready := make([]string, 0, len(node.dependents))
+	switch {
+	case future.canceled:
+		w.fail(run, node, api.FutureState_CANCELED, err)
+	case err != nil:
+		w.fail(run, node, api.FutureState_FAILED, err)
+	default:
+		node.state = api.FutureState_SUCCEEDED
+		for _, name := range node.dependents {
+			if dependent := run.steps[name]; dependent.state == api.FutureState_PENDING {
+				if dependent.waiting--; dependent.waiting == 0 {
+					dependent.state = api.FutureState_QUEUED
+					ready = append(ready, name)
+				}
+			}
+		}
+	}
+
+	w.finish(run)
+	w.Unlock()
+
+	// Queue the ready steps without blocking the worker if the queue is full
+	if len(ready) > 0 {
+		go w.parent.queueSteps(run, ready)
+	}
+}
+
+// fail the step and cancel the steps that depend on it. Must hold the lock.
+func (w *workflows) fail(run *workflowRun, node *workflowNode, state api.FutureState, err error) {
+	node.state, node.err = state, err
+	for _, name := range node.dependents {
+		if dependent := run.steps[name]; dependent.state == api.FutureState_PENDING {
+			w.fail(run, dependent, api.FutureState_CANCELED, Errorf(CodeCanceled, "workflow step %q was canceled because step %q did not succeed", name, node.Name))
+		}
+	}
+}
+
+// finish the workflow if all of its steps have completed. Must hold the lock.
+func (w *workflows) finish(run *workflowRun) {
+	if run.state != api.FutureState_RUNNING {
+		return
+	}
+
+	state := api.FutureState_SUCCEEDED
+	for _, node := range run.steps {
+		if !node.finished() {
+			return
+		}
+		if node.state != api.FutureState_SUCCEEDED {
+			state = api.FutureState_FAILED
+		}
+	}
+
+	run.state, run.finished = state, time.Now()
+	run.expires = run.finished.Add(w.ttl)
+	w.parent.logf(out.LevelDebug, "", "workflow %s completed: %s", run.id, state)
+}
+
+// sweep evicts all workflows that have expired, returning the number of evicted workflows.
+func (w *workflows) sweep(now time.Time) (n int) {
+	w.Lock()
+	defer w.Unlock()
+
+	for id, run := range w.runs {
+		if !run.expires.IsZero() && now.After(run.expires) {
+			delete(w.runs, id)
+			n++
+		}
+	}
+	return n
+}
+
+// info returns the protocol buffer representation of the workflow with the id. The
+// state of steps that are queued is looked up from the records of their futures.
+func (w *workflows) info(id uuid.UUID) (*api.WorkflowInfo, bool) {
+	w.RLock()
+	defer w.RUnlock()
+
+	run, ok := w.runs[id.String()]
+	if !ok {
+		return nil, false
+	}
+
+	info := &api.WorkflowInfo{
+		Uuid:     run.id,
+		State:    run.state,
+		Created:  unixNano(run.created),
+		Finished: unixNano(run.finished),
+		Steps:    make([]*api.WorkflowStepInfo, 0, len(run.order)),
+	}
+
+	for _, name := range run.order {
+		node := run.steps[name]
+		step := &api.WorkflowStepInfo{Name: node.Name, Task: node.Task, After: node.After, Uuid: node.future, State: node.state}
+		if node.state == api.FutureState_QUEUED && node.future != nil {
+			if rec, ok := w.parent.results.get(node.future); ok {
+				step.State = rec.state
+			}
+		}
+
+		if node.err != nil {
+			var ok bool
+			if step.Error, ok = apiError(node.err); !ok {
+				step.Error = &api.Error{Code: CodeUnknown, Message: node.err.Error()}
+			}
+		}
+		info.Steps = append(info.Steps, step)
+	}
+	return info, true
+}
+
+// GetWorkflow returns the state of the workflow with the specified id and each of its
+// steps. Workflows are only known until they are evicted after the ResultTTL once their
+// last step has completed.
+func (r *Radish) GetWorkflow(ctx context.Context, in *api.GetWorkflowRequest) (rep *api.GetWorkflowReply, err error) {
+	info, ok := r.workflows.info(uuid.UUID(in.Uuid))
+	if !ok {
+		err = Errorf(CodeNotFound, "workflow %s not found", uuid.UUID(in.Uuid))
+		return &api.GetWorkflowReply{Success: false, Error: err.(*api.Error)}, nil
+	}
+	return &api.GetWorkflowReply{Success: true, Workflow: info}, nil
+}
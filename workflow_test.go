@@ -0,0 +1,123 @@
+package radish_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kansaslabs/radish"
+	"github.com/kansaslabs/radish/api"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflow(t *testing.T) {
+	var mu sync.Mutex
+	order := make([]string, 0)
+	wg := new(sync.WaitGroup)
+	record := func(id uuid.UUID, params []byte) error {
+		mu.Lock()
+		order = append(order, string(params))
+		mu.Unlock()
+		return nil
+	}
+
+	good := &testTask{wg: wg, name: "good", onHandle: record}
+	bad := &testTask{wg: wg, name: "bad", onHandle: func(id uuid.UUID, params []byte) error { return errors.New("whoops!") }}
+
+	queue, err := New(&Config{Workers: 4, NoSignals: true, LogLevel: "warn"}, good, bad)
+	require.NoError(t, err)
+	defer queue.Shutdown()
+
+	// Invalid workflows are not run
+	ctx := context.Background()
+	_, err = queue.RunWorkflow(ctx, NewWorkflow())
+	require.EqualError(t, err, "[16] workflow has no steps")
+
+	_, err = queue.RunWorkflow(ctx, NewWorkflow().Step("a", "good", nil).Step("a", "good", nil))
+	require.EqualError(t, err, "[16] workflow has more than one step named \"a\"")
+
+	_, err = queue.RunWorkflow(ctx, NewWorkflow().Step("a", "good", nil, "b"))
+	require.EqualError(t, err, "[16] step \"a\" depends on unknown step \"b\"")
+
+	_, err = queue.RunWorkflow(ctx, NewWorkflow().Step("a", "good", nil, "c").Step("b", "good", nil, "a").Step("c", "good", nil, "b"))
+	require.EqualError(t, err, "[16] workflow steps have a cyclic dependency")
+
+	_, err = queue.RunWorkflow(ctx, NewWorkflow().Step("a", "unknown", nil))
+	require.EqualError(t, err, "[3] could not run workflow step \"a\": [3] unknown task \"unknown\"")
+
+	// Steps are queued once all of the steps they depend on have succeeded
+	wg.Add(4)
+	workflow := NewWorkflow().
+		Step("extract", "good", []byte("extract")).
+		Step("left", "good", []byte("left"), "extract").
+		Step("right", "good", []byte("right"), "extract").
+		Step("load", "good", []byte("load"), "left", "right")
+
+	id, err := queue.RunWorkflow(ctx, workflow)
+	require.NoError(t, err)
+	wg.Wait()
+
+	require.Len(t, order, 4)
+	require.Equal(t, "extract", order[0])
+	require.ElementsMatch(t, []string{"left", "right"}, order[1:3])
+	require.Equal(t, "load", order[3])
+
+	var info *api.WorkflowInfo
+	require.Eventually(t, func() bool {
+		rep, err := queue.GetWorkflow(ctx, &api.GetWorkflowRequest{Uuid: id})
+		require.NoError(t, err)
+		require.True(t, rep.Success)
+		info = rep.Workflow
+		return info.State == api.FutureState_SUCCEEDED
+	}, time.Second, 10*time.Millisecond)
+
+	require.NotZero(t, info.Finished)
+	require.Len(t, info.Steps, 4)
+	for _, step := range info.Steps {
+		require.Equal(t, api.FutureState_SUCCEEDED, step.State)
+		require.NotEmpty(t, step.Uuid)
+	}
+	require.Equal(t, []string{"left", "right"}, info.Steps[3].After)
+
+	// The steps that depend on a failed step are canceled
+	wg.Add(3)
+	workflow = NewWorkflow().
+		Step("extract", "good", []byte("extract")).
+		Step("transform", "bad", nil, "extract").
+		Step("load", "good", []byte("load"), "transform").
+		Step("audit", "good", []byte("audit"), "extract")
+
+	id, err = queue.RunWorkflow(ctx, workflow)
+	require.NoError(t, err)
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		rep, err := queue.GetWorkflow(ctx, &api.GetWorkflowRequest{Uuid: id})
+		require.NoError(t, err)
+		info = rep.Workflow
+		return info.State == api.FutureState_FAILED
+	}, time.Second, 10*time.Millisecond)
+
+	states := make(map[string]api.FutureState)
+	for _, step := range info.Steps {
+		states[step.Name] = step.State
+	}
+	require.Equal(t, map[string]api.FutureState{
+		"extract":   api.FutureState_SUCCEEDED,
+		"transform": api.FutureState_FAILED,
+		"load":      api.FutureState_CANCELED,
+		"audit":     api.FutureState_SUCCEEDED,
+	}, states)
+	require.Equal(t, "whoops!", info.Steps[1].Error.Message)
+	require.Equal(t, "workflow step \"load\" was canceled because step \"transform\" did not succeed", info.Steps[2].Error.Message)
+	require.Empty(t, info.Steps[2].Uuid)
+
+	// Unknown workflows are not found
+	rep, err := queue.GetWorkflow(ctx, &api.GetWorkflowRequest{Uuid: uuid.NewRandom()})
+	require.NoError(t, err)
+	require.False(t, rep.Success)
+	require.Equal(t, CodeNotFound, rep.Error.Code)
+}